@@ -10,6 +10,7 @@ import (
 
 	vmv1alpha1 "github.com/vmware-tanzu/vm-operator/api/v1alpha1"
 	_ "go.uber.org/automaxprocs"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -17,14 +18,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha2"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	addressgroupcontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/addressgroup"
+	egressipcontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/egressip"
+	ipaddressallocationcontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/ipaddressallocation"
 	ippool2 "github.com/vmware-tanzu/nsx-operator/pkg/controllers/ippool"
 	namespacecontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/namespace"
+	natrulecontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/natrule"
 	networkpolicycontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/networkpolicy"
+	networkquotacontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/networkquota"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/node"
 	nsxserviceaccountcontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/nsxserviceaccount"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/pod"
@@ -35,17 +42,23 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/subnetport"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/subnetset"
 	vpccontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/vpc"
+	vpcpeeringcontroller "github.com/vmware-tanzu/nsx-operator/pkg/controllers/vpcpeering"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/egressip"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/ipaddressallocation"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/ippool"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/natrule"
 	nodeservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/node"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/nsxserviceaccount"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/staticroute"
 	subnetservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
 	subnetportservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetport"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpcpeering"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 )
 
@@ -59,6 +72,7 @@ var (
 func init() {
 	var err error
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 	utilruntime.Must(v1alpha2.AddToScheme(scheme))
 	utilruntime.Must(vmv1alpha1.AddToScheme(scheme))
@@ -105,7 +119,7 @@ func StartNSXServiceAccountController(mgr ctrl.Manager, commonService common.Ser
 	}
 }
 
-func StartIPPoolController(mgr ctrl.Manager, ipPoolService *ippool.IPPoolService, vpcService common.VPCServiceProvider) {
+func StartIPPoolController(mgr ctrl.Manager, ipPoolService *ippool.IPPoolService, vpcService common.VPCServiceProvider, enableWebhook bool) {
 	ippoolReconcile := &ippool2.IPPoolReconciler{
 		Client:     mgr.GetClient(),
 		Scheme:     mgr.GetScheme(),
@@ -114,23 +128,39 @@ func StartIPPoolController(mgr ctrl.Manager, ipPoolService *ippool.IPPoolService
 		Recorder:   mgr.GetEventRecorderFor("ippool-controller"),
 	}
 
-	if err := ippoolReconcile.Start(mgr); err != nil {
+	if err := ippoolReconcile.Start(mgr, enableWebhook); err != nil {
 		log.Error(err, "failed to create controller", "controller", "IPPool")
 		os.Exit(1)
 	}
 }
 
-func StartVPCController(mgr ctrl.Manager, vpcService *vpc.VPCService) {
+func StartVPCController(mgr ctrl.Manager, vpcService *vpc.VPCService, subnetService *subnetservice.SubnetService,
+	subnetPortService *subnetportservice.SubnetPortService, staticRouteService *staticroute.StaticRouteService,
+	vpcPeeringService *vpcpeering.VPCPeeringService) {
 	vpcReconciler := &vpccontroller.VPCReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("vpc-controller"),
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		Recorder:           mgr.GetEventRecorderFor("vpc-controller"),
+		SubnetService:      subnetService,
+		SubnetPortService:  subnetPortService,
+		StaticRouteService: staticRouteService,
+		VPCPeeringService:  vpcPeeringService,
 	}
 	vpcReconciler.Service = vpcService
 	if err := vpcReconciler.Start(mgr); err != nil {
 		log.Error(err, "failed to create vpc controller", "controller", "VPC")
 		os.Exit(1)
 	}
+	ncReconciler := &vpccontroller.VPCNetworkConfigurationReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Service:  vpcService,
+		Recorder: mgr.GetEventRecorderFor("vpcnetworkconfiguration-controller"),
+	}
+	if err := ncReconciler.Start(mgr); err != nil {
+		log.Error(err, "failed to create vpcnetworkconfiguration controller", "controller", "VPCNetworkConfiguration")
+		os.Exit(1)
+	}
 }
 
 func StartNamespaceController(mgr ctrl.Manager, cf *config.NSXOperatorConfig, vpcService common.VPCServiceProvider) {
@@ -149,14 +179,27 @@ func StartNamespaceController(mgr ctrl.Manager, cf *config.NSXOperatorConfig, vp
 
 func main() {
 	log.Info("starting NSX Operator")
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	enableWebhook := true
+	if _, err := os.Stat(config.WebhookCertDir); errors.Is(err, os.ErrNotExist) {
+		log.Error(err, "server cert not found, disabling webhook server", "cert", config.WebhookCertDir)
+		enableWebhook = false
+	}
+	options := ctrl.Options{
 		Scheme:                  scheme,
 		HealthProbeBindAddress:  config.ProbeAddr,
 		Metrics:                 metricsserver.Options{BindAddress: config.MetricsAddr},
 		LeaderElection:          cf.HAEnabled(),
 		LeaderElectionNamespace: nsxOperatorNamespace,
 		LeaderElectionID:        "nsx-operator",
-	})
+	}
+	if enableWebhook {
+		// The webhook server is shared by the validating webhooks of all the CRDs and the IPPool conversion webhook.
+		options.WebhookServer = webhook.NewServer(webhook.Options{
+			Port:    config.WebhookServerPort,
+			CertDir: config.WebhookCertDir,
+		})
+	}
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), options)
 	if err != nil {
 		log.Error(err, "failed to init manager")
 		os.Exit(1)
@@ -217,32 +260,64 @@ func main() {
 			log.Error(err, "failed to initialize staticroute commonService", "controller", "StaticRoute")
 			os.Exit(1)
 		}
+		ipAddressAllocationService, err := ipaddressallocation.InitializeIPAddressAllocation(commonService, vpcService)
+		if err != nil {
+			log.Error(err, "failed to initialize ipaddressallocation commonService", "controller", "IPAddressAllocation")
+			os.Exit(1)
+		}
+		natRuleService, err := natrule.InitializeNATRule(commonService, vpcService)
+		if err != nil {
+			log.Error(err, "failed to initialize natrule commonService", "controller", "NATRule")
+			os.Exit(1)
+		}
+		egressIPService, err := egressip.InitializeEgressIP(commonService, vpcService)
+		if err != nil {
+			log.Error(err, "failed to initialize egressip commonService", "controller", "EgressIP")
+			os.Exit(1)
+		}
+		vpcPeeringService, err := vpcpeering.InitializeVPCPeering(commonService)
+		if err != nil {
+			log.Error(err, "failed to initialize vpcpeering commonService", "controller", "VPCPeering")
+			os.Exit(1)
+		}
 		// Start controllers which only supports VPC
-		StartVPCController(mgr, vpcService)
+		StartVPCController(mgr, vpcService, subnetService, subnetPortService, staticRouteService, vpcPeeringService)
 		StartNamespaceController(mgr, cf, vpcService)
 		// Start subnet/subnetset controller.
 		if err := subnet.StartSubnetController(mgr, subnetService, subnetPortService, vpcService); err != nil {
 			os.Exit(1)
 		}
-		enableWebhook := true
-		if _, err := os.Stat(config.WebhookCertDir); errors.Is(err, os.ErrNotExist) {
-			log.Error(err, "server cert not found, disabling webhook server", "cert", config.WebhookCertDir)
-			enableWebhook = false
-		}
 		if err := subnetset.StartSubnetSetController(mgr, subnetService, subnetPortService, vpcService, enableWebhook); err != nil {
 			os.Exit(1)
 		}
 
 		node.StartNodeController(mgr, nodeService)
 		staticroutecontroller.StartStaticRouteController(mgr, staticRouteService)
-		subnetport.StartSubnetPortController(mgr, subnetPortService, subnetService, vpcService)
+		ipaddressallocationcontroller.StartIPAddressAllocationController(mgr, ipAddressAllocationService)
+		natrulecontroller.StartNATRuleController(mgr, natRuleService)
+		egressipcontroller.StartEgressIPController(mgr, egressIPService)
+		vpcpeeringcontroller.StartVPCPeeringController(mgr, vpcPeeringService, vpcService)
+		networkquotacontroller.StartNetworkQuotaController(mgr, cf, vpcService, enableWebhook)
+		subnetport.StartSubnetPortController(mgr, subnetPortService, subnetService, vpcService, nodeService)
 		pod.StartPodController(mgr, subnetPortService, subnetService, vpcService, nodeService)
-		StartIPPoolController(mgr, ipPoolService, vpcService)
+		StartIPPoolController(mgr, ipPoolService, vpcService, enableWebhook)
 		networkpolicycontroller.StartNetworkPolicyController(mgr, commonService, vpcService)
 		service.StartServiceLbController(mgr, commonService)
 	}
 	// Start controllers which can run in non-VPC mode
-	securitypolicycontroller.StartSecurityPolicyController(mgr, commonService, vpcService)
+	securitypolicycontroller.StartSecurityPolicyController(mgr, commonService, vpcService, enableWebhook)
+	addressgroupcontroller.StartAddressGroupController(mgr, commonService, vpcService)
+	if config.DebugAddr != "" {
+		simulationServer := &securitypolicycontroller.SimulationServer{
+			Addr:    config.DebugAddr,
+			Client:  mgr.GetClient(),
+			Service: securitypolicy.GetSecurityService(commonService, vpcService),
+		}
+		if err := mgr.Add(simulationServer); err != nil {
+			log.Error(err, "failed to add debug server")
+			os.Exit(1)
+		}
+	}
 
 	// Start the NSXServiceAccount controller.
 	if cf.EnableAntreaNSXInterworking {
@@ -0,0 +1,69 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AddressGroupSpec defines the members of AddressGroup.
+type AddressGroupSpec struct {
+	// Members selects the workloads and IP blocks in the AddressGroup.
+	Members []AddressGroupMember `json:"members,omitempty"`
+	// NSXGroupPaths are the paths of existing NSX groups included in the AddressGroup,
+	// e.g. /infra/domains/default/groups/corporate-proxies.
+	NSXGroupPaths []string `json:"nsxGroupPaths,omitempty"`
+	// SharedNamespaceSelector selects the Namespaces which can reference the AddressGroup
+	// in addition to the Namespace of the AddressGroup.
+	// In VPC network, the NSX group is shared with the VPCs of the selected Namespaces.
+	SharedNamespaceSelector *metav1.LabelSelector `json:"sharedNamespaceSelector,omitempty"`
+}
+
+// AddressGroupMember selects workloads or IP blocks, it works the same as SecurityPolicyPeer.
+type AddressGroupMember struct {
+	// VMSelector uses label selector to select VMs.
+	VMSelector *metav1.LabelSelector `json:"vmSelector,omitempty"`
+	// PodSelector uses label selector to select Pods.
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// NamespaceSelector uses label selector to select Namespaces.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// IPBlocks is a list of IP CIDRs.
+	IPBlocks []IPBlock `json:"ipBlocks,omitempty"`
+}
+
+// AddressGroupStatus defines the observed state of AddressGroup.
+type AddressGroupStatus struct {
+	Conditions []Condition `json:"conditions,omitempty"`
+	// NSXResourcePath is the path of the NSX group realized for the AddressGroup.
+	NSXResourcePath string `json:"nsxResourcePath,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// AddressGroup is a named group of workloads and IP blocks which can be referenced by the
+// rules of SecurityPolicies in multiple Namespaces.
+// +kubebuilder:printcolumn:name="NSXResourcePath",type=string,JSONPath=`.status.nsxResourcePath`,description="Path of the NSX group"
+type AddressGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AddressGroupSpec   `json:"spec,omitempty"`
+	Status AddressGroupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// AddressGroupList contains a list of AddressGroup.
+type AddressGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AddressGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AddressGroup{}, &AddressGroupList{})
+}
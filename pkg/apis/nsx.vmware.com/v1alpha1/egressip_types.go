@@ -0,0 +1,64 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EgressIPSpec defines the desired state of EgressIP.
+type EgressIPSpec struct {
+	// VPCName is the name of the VPC CR in the Namespace to allocate the egress IP from.
+	// The default VPC of the Namespace is used if it's not set.
+	// +optional
+	VPCName string `json:"vpcName,omitempty"`
+	// PodSelector selects the Pods in the Namespace which leave the VPC with the egress IP.
+	// All the Pods of the Namespace are selected if it's not set. A Pod can't be selected by
+	// more than one EgressIP, the oldest EgressIP selecting it is applied and the others are
+	// rejected.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// EgressIPStatus defines the observed state of EgressIP.
+type EgressIPStatus struct {
+	// Conditions defines current state of the EgressIP.
+	Conditions []Condition `json:"conditions,omitempty"`
+	// EgressIP is the IP allocated from the VPC external IP blocks as the source IP of the
+	// egress traffic of the selected Pods.
+	EgressIP string `json:"egressIP,omitempty"`
+	// GroupPath is the NSX path of the group of the selected Pods.
+	GroupPath string `json:"groupPath,omitempty"`
+	// NATRulePath is the NSX path of the SNAT rule, it's empty if no Pod is selected.
+	NATRulePath string `json:"natRulePath,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// EgressIP is the Schema for the egressips API.
+// +kubebuilder:printcolumn:name="EgressIP",type=string,JSONPath=`.status.egressIP`,description="Source IP of the egress traffic"
+// +kubebuilder:printcolumn:name="VPC",type=string,JSONPath=`.spec.vpcName`,description="Name of the VPC"
+type EgressIP struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   EgressIPSpec   `json:"spec,omitempty"`
+	Status EgressIPStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// EgressIPList contains a list of EgressIP.
+type EgressIPList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EgressIP `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&EgressIP{}, &EgressIPList{})
+}
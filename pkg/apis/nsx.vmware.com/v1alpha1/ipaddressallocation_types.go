@@ -0,0 +1,72 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type IPAddressVisibility string
+
+const (
+	IPAddressVisibilityExternal IPAddressVisibility = "External"
+	IPAddressVisibilityPrivate  IPAddressVisibility = "Private"
+)
+
+// IPAddressAllocationSpec defines the desired state of IPAddressAllocation.
+type IPAddressAllocationSpec struct {
+	// IPAddressBlockVisibility specifies the VPC IP blocks to allocate the IPs from, External or Private.
+	// It's ignored if IPPool is specified, then the IPs are allocated from the CIDRs of the IPPool.
+	// +kubebuilder:validation:Enum=External;Private
+	// +kubebuilder:default=Private
+	// +optional
+	IPAddressBlockVisibility IPAddressVisibility `json:"ipAddressBlockVisibility,omitempty"`
+	// IPPool is the name of the IPPool in the same Namespace to allocate the IPs from.
+	// +optional
+	IPPool string `json:"ipPool,omitempty"`
+	// AllocationSize is the count of IPs to allocate.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +kubebuilder:default=1
+	// +optional
+	AllocationSize int `json:"allocationSize,omitempty"`
+}
+
+// IPAddressAllocationStatus defines the observed state of IPAddressAllocation.
+type IPAddressAllocationStatus struct {
+	// AllocationIPs are the IPs allocated by NSX.
+	AllocationIPs []string `json:"allocationIPs,omitempty"`
+	// Conditions defines current state of the IPAddressAllocation.
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// IPAddressAllocation is the Schema for the ipaddressallocations API.
+// +kubebuilder:printcolumn:name="IPAddressBlockVisibility",type=string,JSONPath=`.spec.ipAddressBlockVisibility`,description="IP blocks to allocate the IPs from"
+// +kubebuilder:printcolumn:name="IPPool",type=string,JSONPath=`.spec.ipPool`,description="IPPool to allocate the IPs from"
+// +kubebuilder:printcolumn:name="AllocationIPs",type=string,JSONPath=`.status.allocationIPs[*]`,description="IPs allocated by NSX"
+type IPAddressAllocation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IPAddressAllocationSpec   `json:"spec,omitempty"`
+	Status IPAddressAllocationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IPAddressAllocationList contains a list of IPAddressAllocation.
+type IPAddressAllocationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPAddressAllocation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IPAddressAllocation{}, &IPAddressAllocationList{})
+}
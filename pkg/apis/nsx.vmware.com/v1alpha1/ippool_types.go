@@ -34,6 +34,11 @@ type IPPoolSpec struct {
 	// Subnets defines set of subnets need to be allocated.
 	// +optional
 	Subnets []SubnetRequest `json:"subnets"`
+	// VPCName is the name of the VPC CR in the Namespace to allocate the subnets from.
+	// The default VPC of the Namespace is used if not set.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="vpcName is immutable"
+	// +optional
+	VPCName string `json:"vpcName,omitempty"`
 }
 
 // IPPoolStatus defines the observed state of IPPool.
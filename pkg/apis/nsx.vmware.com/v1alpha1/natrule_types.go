@@ -0,0 +1,90 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type NATAction string
+
+const (
+	NATActionSNAT      NATAction = "SNAT"
+	NATActionDNAT      NATAction = "DNAT"
+	NATActionReflexive NATAction = "REFLEXIVE"
+)
+
+// NATRuleSpec defines the desired state of NATRule.
+type NATRuleSpec struct {
+	// VPCName is the name of the VPC CR in the Namespace to create the NAT rule in.
+	// The default VPC of the Namespace is used if it's not set.
+	// +optional
+	VPCName string `json:"vpcName,omitempty"`
+	// Action of the NAT rule, SNAT, DNAT or REFLEXIVE.
+	// +kubebuilder:validation:Enum=SNAT;DNAT;REFLEXIVE
+	Action NATAction `json:"action"`
+	// SourceNetwork is the IP or CIDR of the source, it's required for REFLEXIVE.
+	// +optional
+	SourceNetwork string `json:"sourceNetwork,omitempty"`
+	// DestinationNetwork is the IP or CIDR of the destination. For DNAT, it's the external IP
+	// to translate, which must be within the VPC external IP blocks, and an IP is allocated from
+	// the VPC external IP blocks if it's not set. It's not allowed for REFLEXIVE.
+	// +optional
+	DestinationNetwork string `json:"destinationNetwork,omitempty"`
+	// TranslatedNetwork is the IP or CIDR to translate to. For SNAT and REFLEXIVE, it must be within
+	// the VPC external IP blocks, and an IP is allocated from the VPC external IP blocks if it's not set.
+	// It's required for DNAT.
+	// +optional
+	TranslatedNetwork string `json:"translatedNetwork,omitempty"`
+	// SequenceNumber defines the order of the NAT rules of the VPC, the rule with the smaller
+	// sequence number is evaluated first. The sequence number 0 is reserved for the SNAT rules
+	// of EgressIP, which are evaluated before the NATRules.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	SequenceNumber int64 `json:"sequenceNumber,omitempty"`
+}
+
+// NATRuleStatus defines the observed state of NATRule.
+type NATRuleStatus struct {
+	// Conditions defines current state of the NATRule.
+	Conditions []Condition `json:"conditions,omitempty"`
+	// NSXResourcePath is the NSX path of the NAT rule.
+	NSXResourcePath string `json:"nsxResourcePath,omitempty"`
+	// AllocatedIP is the IP allocated from the VPC external IP blocks, which is used as the
+	// translated network of SNAT/REFLEXIVE or the destination network of DNAT.
+	AllocatedIP string `json:"allocatedIP,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// NATRule is the Schema for the natrules API.
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=`.spec.action`,description="Action of the NAT rule"
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=`.spec.sourceNetwork`,description="Source network"
+// +kubebuilder:printcolumn:name="Destination",type=string,JSONPath=`.spec.destinationNetwork`,description="Destination network"
+// +kubebuilder:printcolumn:name="Translated",type=string,JSONPath=`.spec.translatedNetwork`,description="Translated network"
+// +kubebuilder:printcolumn:name="AllocatedIP",type=string,JSONPath=`.status.allocatedIP`,description="IP allocated from the VPC external IP blocks"
+type NATRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NATRuleSpec   `json:"spec,omitempty"`
+	Status NATRuleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NATRuleList contains a list of NATRule.
+type NATRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NATRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NATRule{}, &NATRuleList{})
+}
@@ -12,6 +12,7 @@ type AccessMode string
 // SubnetSpec defines the desired state of Subnet.
 type SubnetSpec struct {
 	// Size of Subnet based upon estimated workload count.
+	// It can be increased to expand the existing Subnet if the CIDRs are not specified.
 	// +kubebuilder:validation:Maximum:=65536
 	// +kubebuilder:validation:Minimum:=16
 	IPv4SubnetSize int `json:"ipv4SubnetSize,omitempty"`
@@ -19,19 +20,47 @@ type SubnetSpec struct {
 	// +kubebuilder:validation:Enum=Private;Public
 	AccessMode AccessMode `json:"accessMode,omitempty"`
 	// Subnet CIDRS.
+	// A CIDR can be added to expand the existing Subnet, but cannot be removed.
 	// +kubebuilder:validation:MinItems=0
 	// +kubebuilder:validation:MaxItems=2
 	IPAddresses []string `json:"ipAddresses,omitempty"`
 	// Subnet advanced configuration.
 	AdvancedConfig AdvancedConfig `json:"advancedConfig,omitempty"`
 	// DHCPConfig DHCP configuration.
+	// NSX VPC Subnet doesn't support the DHCP options other than the DNS servers, such as the router, NTP
+	// servers, domain name and classless static routes, nor the lease time.
+	// An invalid DHCP relay config path or DNS server IP is not applied, and is reported by the
+	// DHCPConfigInvalid condition.
 	DHCPConfig DHCPConfig `json:"DHCPConfig,omitempty"`
+	// VPCName is the name of the VPC CR in the Namespace to create the Subnet in.
+	// The default VPC of the Namespace is used if not set.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="vpcName is immutable"
+	VPCName string `json:"vpcName,omitempty"`
+	// NSXSubnetPath is the policy path of an existing NSX Subnet created outside Kubernetes, e.g.
+	// /orgs/default/projects/proj-1/vpcs/vpc-1/subnets/subnet-1. If it's set, the NSX Subnet in the VPC
+	// is imported instead of creating a new one, the other Subnet configurations are ignored, and the
+	// NSX Subnet is never updated or deleted by the operator. An NSX Subnet can be imported by one Subnet
+	// only, the newer Subnets importing the same NSX Subnet are rejected.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="nsxSubnetPath is immutable"
+	// +optional
+	NSXSubnetPath string `json:"nsxSubnetPath,omitempty"`
+}
+
+// IPUsage defines the IP capacity and utilization of Subnet.
+type IPUsage struct {
+	// TotalIPs is the count of IPs which can be allocated in the Subnet.
+	TotalIPs int64 `json:"totalIPs"`
+	// AllocatedIPs is the count of IPs allocated in the Subnet.
+	AllocatedIPs int64 `json:"allocatedIPs"`
+	// AvailableIPs is the count of IPs available for allocation in the Subnet.
+	AvailableIPs int64 `json:"availableIPs"`
 }
 
 // SubnetStatus defines the observed state of Subnet.
 type SubnetStatus struct {
 	NSXResourcePath string      `json:"nsxResourcePath,omitempty"`
 	IPAddresses     []string    `json:"ipAddresses,omitempty"`
+	IPUsage         *IPUsage    `json:"ipUsage,omitempty"`
 	Conditions      []Condition `json:"conditions,omitempty"`
 }
 
@@ -44,6 +73,7 @@ type SubnetStatus struct {
 // +kubebuilder:printcolumn:name="AccessMode",type=string,JSONPath=`.spec.accessMode`,description="Access mode of Subnet"
 // +kubebuilder:printcolumn:name="IPv4SubnetSize",type=string,JSONPath=`.spec.ipv4SubnetSize`,description="Size of Subnet"
 // +kubebuilder:printcolumn:name="IPAddresses",type=string,JSONPath=`.status.ipAddresses[*]`,description="CIDRs for the Subnet"
+// +kubebuilder:printcolumn:name="AvailableIPs",type=integer,JSONPath=`.status.ipUsage.availableIPs`,description="Available IPs in the Subnet"
 type Subnet struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -79,6 +109,7 @@ type DHCPConfig struct {
 	// +kubebuilder:default:=false
 	EnableDHCP bool `json:"enableDHCP,omitempty"`
 	// DHCPRelayConfigPath is policy path of DHCP-relay-config.
+	// It can be configured only if DHCP is enabled.
 	DHCPRelayConfigPath string `json:"dhcpRelayConfigPath,omitempty"`
 	// DHCPV4PoolSize IPs in % to be reserved for DHCP ranges.
 	// By default, 80% of IPv4 IPs will be reserved for DHCP.
@@ -90,7 +121,9 @@ type DHCPConfig struct {
 	// DHCPV6PoolSize number of IPs to be reserved for DHCP ranges.
 	// By default, 2000 IPv6 IPs will be reserved for DHCP.
 	// +kubebuilder:default:=2000
-	DHCPV6PoolSize  int             `json:"dhcpV6PoolSize,omitempty"`
+	DHCPV6PoolSize int `json:"dhcpV6PoolSize,omitempty"`
+	// DNSClientConfig is the DNS servers offered by DHCP.
+	// It can be configured only if DHCP is enabled.
 	DNSClientConfig DNSClientConfig `json:"dnsClientConfig,omitempty"`
 }
 
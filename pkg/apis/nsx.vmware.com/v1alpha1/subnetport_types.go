@@ -13,6 +13,23 @@ type SubnetPortSpec struct {
 	Subnet string `json:"subnet,omitempty"`
 	// SubnetSet defines the parent SubnetSet name of the SubnetPort.
 	SubnetSet string `json:"subnetSet,omitempty"`
+	// AddressBindings defines the static IP address and MAC address of the SubnetPort.
+	// The addresses not specified are allocated by NSX.
+	// +kubebuilder:validation:MaxItems=1
+	AddressBindings []AddressBinding `json:"addressBindings,omitempty"`
+	// VPCName is the name of the VPC CR in the Namespace to create the SubnetPort in.
+	// The parent Subnet or SubnetSet must be in the VPC, and must be specified if the
+	// VPC is not the default VPC of the Namespace.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="vpcName is immutable"
+	VPCName string `json:"vpcName,omitempty"`
+}
+
+// AddressBinding defines the IP address and MAC address requested for the SubnetPort.
+type AddressBinding struct {
+	// IPAddress is the IP address requested from the static IP pool of the Subnet.
+	IPAddress string `json:"ipAddress,omitempty"`
+	// MACAddress is the MAC address requested for the SubnetPort.
+	MACAddress string `json:"macAddress,omitempty"`
 }
 
 // SubnetPortStatus defines the observed state of SubnetPort.
@@ -16,6 +16,13 @@ import (
 // +kubebuilder:printcolumn:name="PrivateIPv4CIDRs",type=string,JSONPath=`.status.privateIPv4CIDRs`,description="Private IPv4 CIDRs"
 // +kubebuilder:printcolumn:name="SNATIP",type=string,JSONPath=`.status.defaultSNATIP`,description="Default SNAT IP for Private Subnets"
 // +kubebuilder:printcolumn:name="LBSubnetCIDR",type=string,JSONPath=`.status.lbSubnetCIDR`,description="CIDR for the load balancer Subnet"
+// +kubebuilder:printcolumn:name="RealizedState",type=string,JSONPath=`.status.realizedState`,description="Realization state of the NSX VPC"
+// +kubebuilder:printcolumn:name="Subnets",type=integer,JSONPath=`.status.inventory.subnets`,description="Number of NSX Subnets in the VPC"
+// +kubebuilder:printcolumn:name="SubnetPorts",type=integer,JSONPath=`.status.inventory.subnetPorts`,description="Number of NSX SubnetPorts in the VPC",priority=1
+// +kubebuilder:printcolumn:name="StaticRoutes",type=integer,JSONPath=`.status.inventory.staticRoutes`,description="Number of NSX StaticRoutes in the VPC",priority=1
+// +kubebuilder:printcolumn:name="ShortID",type=string,JSONPath=`.status.shortID`,description="Short ID of the NSX VPC",priority=1
+// +kubebuilder:printcolumn:name="Gateway",type=string,JSONPath=`.status.gatewayPath`,description="NSX path of the gateway connected by the VPC",priority=1
+// +kubebuilder:printcolumn:name="EdgeCluster",type=string,JSONPath=`.status.edgeClusterPath`,description="NSX path of the edge cluster of the VPC",priority=1
 type VPC struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -35,6 +42,12 @@ type VPCList struct {
 
 // VPCSpec defines VPC configuration
 type VPCSpec struct {
+	// NSXVPCPath is the policy path of an existing NSX VPC, e.g. /orgs/default/projects/proj-1/vpcs/vpc-1.
+	// If it's set, the NSX VPC is adopted instead of creating a new one from the network config,
+	// the operator tags it for the cluster but doesn't add the AVI allow rule to it and never deletes it.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="nsxVPCPath is immutable"
+	// +optional
+	NSXVPCPath string `json:"nsxVPCPath,omitempty"`
 }
 
 // VPCStatus defines the observed state of VPC
@@ -50,6 +63,30 @@ type VPCStatus struct {
 	LBSubnetCIDR string `json:"lbSubnetCIDR"`
 	// Private CIDRs used for the VPC.
 	PrivateIPv4CIDRs []string `json:"privateIPv4CIDRs"`
+	// Short ID of the NSX VPC.
+	ShortID string `json:"shortID,omitempty"`
+	// NSX PolicyPath of the Tier-0 gateway or transit gateway connected by the VPC.
+	GatewayPath string `json:"gatewayPath,omitempty"`
+	// NSX PolicyPath of the edge cluster used by the VPC.
+	EdgeClusterPath string `json:"edgeClusterPath,omitempty"`
+	// NSX PolicyPaths of the external IPv4 blocks used by the VPC.
+	ExternalIPv4Blocks []string `json:"externalIPv4Blocks,omitempty"`
+	// Realization state of the RealizedLogicalRouter of the NSX VPC, e.g. REALIZED, IN_PROGRESS or ERROR.
+	RealizedState string `json:"realizedState,omitempty"`
+	// Last realization error of the NSX VPC, it's empty if the VPC is realized.
+	RealizedError string `json:"realizedError,omitempty"`
+	// Inventory of the NSX resources in the VPC, counted when the VPC CR is reconciled and refreshed periodically.
+	Inventory VPCInventory `json:"inventory,omitempty"`
+}
+
+// VPCInventory is the count of the NSX resources in the VPC.
+type VPCInventory struct {
+	// Number of the NSX Subnets in the VPC.
+	Subnets int `json:"subnets"`
+	// Number of the NSX SubnetPorts in the VPC.
+	SubnetPorts int `json:"subnetPorts"`
+	// Number of the NSX StaticRoutes in the VPC, including the routes injected by VPCPeering.
+	StaticRoutes int `json:"staticRoutes"`
 }
 
 func init() {
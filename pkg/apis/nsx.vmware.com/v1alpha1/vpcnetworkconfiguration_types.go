@@ -47,6 +47,32 @@ type VPCNetworkConfigurationSpec struct {
 type VPCNetworkConfigurationStatus struct {
 	// Conditions describes current state of VPCNetworkConfiguration.
 	Conditions []Condition `json:"conditions"`
+	// Namespaces lists the Namespaces bound to the VPCNetworkConfiguration.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// VPCs lists the VPCs created from the VPCNetworkConfiguration.
+	VPCs []VPCInfo `json:"vpcs,omitempty"`
+	// FieldErrors lists the fields of the VPCNetworkConfiguration failed
+	// to be applied to the VPCs.
+	FieldErrors []FieldError `json:"fieldErrors,omitempty"`
+}
+
+// VPCInfo defines a VPC created from the VPCNetworkConfiguration.
+type VPCInfo struct {
+	// Name of the VPC CR, in the format of <namespace>/<name>.
+	Name string `json:"name"`
+	// NSX policy path of the VPC.
+	VPCPath string `json:"vpcPath,omitempty"`
+}
+
+// FieldError defines an error occurred while applying a field of the
+// VPCNetworkConfiguration to a VPC.
+type FieldError struct {
+	// Field is the json name of the spec field, e.g. defaultGatewayPath.
+	Field string `json:"field"`
+	// VPCPath is the NSX policy path of the VPC the field failed to be applied to.
+	VPCPath string `json:"vpcPath"`
+	// Message is the error message.
+	Message string `json:"message"`
 }
 
 // +genclient
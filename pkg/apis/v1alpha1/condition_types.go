@@ -9,6 +9,15 @@ type ConditionType string
 
 const (
 	Ready ConditionType = "Ready"
+	// IPUtilizationHigh is True when the IP utilization of Subnet or SubnetSet exceeds the threshold.
+	IPUtilizationHigh ConditionType = "IPUtilizationHigh"
+	// ExpansionFailed is True when the requested size or CIDRs of Subnet cannot be applied to the existing NSX Subnet.
+	ExpansionFailed ConditionType = "ExpansionFailed"
+	// DHCPConfigInvalid is True when the DHCP relay or the DNS servers of Subnet or SubnetSet are invalid and
+	// not applied to the NSX Subnets.
+	DHCPConfigInvalid ConditionType = "DHCPConfigInvalid"
+	// NetworkReady is True on the VPC CR when the VPC and the default SubnetSets of the Namespace are realized.
+	NetworkReady ConditionType = "NetworkReady"
 )
 
 // Condition defines condition of custom resource.
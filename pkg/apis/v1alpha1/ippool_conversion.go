@@ -0,0 +1,36 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ipPoolHub is implemented by the hub version of IPPool. The conversion is done by the hub version as it
+// imports this package for the Condition.
+type ipPoolHub interface {
+	conversion.Hub
+	ConvertFromV1alpha1(src *IPPool) error
+	ConvertToV1alpha1(dst *IPPool) error
+}
+
+// ConvertTo converts this IPPool to the hub version.
+func (src *IPPool) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(ipPoolHub)
+	if !ok {
+		return fmt.Errorf("unsupported hub type %T for IPPool", dstRaw)
+	}
+	return dst.ConvertFromV1alpha1(src)
+}
+
+// ConvertFrom converts from the hub version to this IPPool.
+func (dst *IPPool) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(ipPoolHub)
+	if !ok {
+		return fmt.Errorf("unsupported hub type %T for IPPool", srcRaw)
+	}
+	return src.ConvertToV1alpha1(dst)
+}
@@ -0,0 +1,90 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NetworkResourceName is the name of the network resource limited by NetworkQuota.
+type NetworkResourceName string
+
+const (
+	NetworkResourceSubnets          NetworkResourceName = "subnets"
+	NetworkResourceSubnetSets       NetworkResourceName = "subnetsets"
+	NetworkResourceSubnetPorts      NetworkResourceName = "subnetports"
+	NetworkResourceStaticRoutes     NetworkResourceName = "staticroutes"
+	NetworkResourceSecurityPolicies NetworkResourceName = "securitypolicies"
+	// NetworkResourcePrivateIPs is the total count of IPs of the private Subnets, including the Subnets of
+	// the private SubnetSets.
+	NetworkResourcePrivateIPs NetworkResourceName = "privateIPs"
+)
+
+// NetworkResourceList is the count of each network resource.
+type NetworkResourceList map[NetworkResourceName]int64
+
+// NetworkQuotaSpec defines the desired state of NetworkQuota.
+type NetworkQuotaSpec struct {
+	// Hard is the maximum count of each network resource in the Namespace, the supported
+	// resources are subnets, subnetsets, subnetports, staticroutes, securitypolicies and privateIPs.
+	// The resources not listed are not limited.
+	// +optional
+	Hard NetworkResourceList `json:"hard,omitempty"`
+}
+
+// NetworkQuotaCharge is the network resources charged to the NetworkQuota by an admitted request.
+type NetworkQuotaCharge struct {
+	// Resource is the plural name of the charged resource, e.g. subnets.
+	Resource NetworkResourceName `json:"resource"`
+	// Name is the name of the charged resource.
+	Name string `json:"name,omitempty"`
+	// Generation is the generation of the charged resource once the request is applied.
+	Generation int64 `json:"generation,omitempty"`
+	// Requested is the count of each network resource charged by the request.
+	Requested NetworkResourceList `json:"requested,omitempty"`
+	// ChargedTime is the time the request was admitted.
+	ChargedTime metav1.Time `json:"chargedTime,omitempty"`
+}
+
+// NetworkQuotaStatus defines the observed state of NetworkQuota.
+type NetworkQuotaStatus struct {
+	// Conditions defines current state of the NetworkQuota.
+	Conditions []Condition `json:"conditions,omitempty"`
+	// Hard is the enforced maximum count of each network resource.
+	Hard NetworkResourceList `json:"hard,omitempty"`
+	// Used is the current count of each limited network resource in the Namespace, including the
+	// pending charges.
+	Used NetworkResourceList `json:"used,omitempty"`
+	// PendingCharges are the charges of the admitted requests whose resources are not counted yet.
+	// A charge is removed once its resource is observed at the charged generation, or when it expires
+	// as the request may be rejected after the admission.
+	PendingCharges []NetworkQuotaCharge `json:"pendingCharges,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// NetworkQuota is the Schema for the networkquotas API.
+type NetworkQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NetworkQuotaSpec   `json:"spec,omitempty"`
+	Status NetworkQuotaStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NetworkQuotaList contains a list of NetworkQuota.
+type NetworkQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NetworkQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NetworkQuota{}, &NetworkQuotaList{})
+}
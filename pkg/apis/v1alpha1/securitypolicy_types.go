@@ -48,6 +48,10 @@ type SecurityPolicySpec struct {
 	AppliedTo []SecurityPolicyTarget `json:"appliedTo,omitempty"`
 	// Rules is a list of policy rules.
 	Rules []SecurityPolicyRule `json:"rules,omitempty"`
+	// Schedule defines the time windows during which the policy rules are enabled.
+	// Rule level 'Schedule' will take precedence over policy level.
+	// The rules are always enabled if no schedule is set.
+	Schedule *SecurityPolicySchedule `json:"schedule,omitempty"`
 }
 
 // SecurityPolicyRule defines a rule of SecurityPolicy.
@@ -67,6 +71,37 @@ type SecurityPolicyRule struct {
 	Ports []SecurityPolicyPort `json:"ports,omitempty"`
 	// Name is the display name of this rule.
 	Name string `json:"name,omitempty"`
+	// Schedule defines the time windows during which the rule is enabled.
+	Schedule *SecurityPolicySchedule `json:"schedule,omitempty"`
+}
+
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Mon;Tue;Wed;Thu;Fri;Sat;Sun
+type Weekday string
+
+// SecurityPolicySchedule defines the time windows during which rules are enabled.
+// Rules are disabled in NSX outside of the windows.
+type SecurityPolicySchedule struct {
+	// TimeZone is the IANA time zone name of the windows, e.g. "America/Los_Angeles".
+	// It is UTC by default.
+	TimeZone string `json:"timeZone,omitempty"`
+	// Windows is a list of time windows.
+	// +kubebuilder:validation:MinItems=1
+	Windows []ScheduleWindow `json:"windows"`
+}
+
+// ScheduleWindow defines a time range starting on the given days of the week.
+type ScheduleWindow struct {
+	// Days is a list of days of the week on which the window starts.
+	// The window starts every day if it is empty.
+	Days []Weekday `json:"days,omitempty"`
+	// Start is the start time of the window in the format of "HH:MM".
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+	// End is the end time of the window in the format of "HH:MM".
+	// The window ends on the next day if End is not later than Start.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
 }
 
 // SecurityPolicyTarget defines the target endpoints to apply SecurityPolicy.
@@ -87,6 +122,18 @@ type SecurityPolicyPeer struct {
 	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 	// IPBlocks is a list of IP CIDRs.
 	IPBlocks []IPBlock `json:"ipBlocks,omitempty"`
+	// AddressGroupRef references an AddressGroup, no other field can be set in the peer with it.
+	AddressGroupRef *AddressGroupReference `json:"addressGroupRef,omitempty"`
+}
+
+// AddressGroupReference references an AddressGroup by name.
+type AddressGroupReference struct {
+	// Name of the AddressGroup.
+	Name string `json:"name"`
+	// Namespace of the AddressGroup, defaults to the Namespace of the SecurityPolicy.
+	// The AddressGroup must select the Namespace of the SecurityPolicy by sharedNamespaceSelector
+	// if it's in another Namespace.
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // IPBlock describes a particular CIDR that is allowed or denied to/from the workloads matched by an AppliedTo.
@@ -111,6 +158,28 @@ type SecurityPolicyPort struct {
 type SecurityPolicyStatus struct {
 	// Conditions describes current state of security policy.
 	Conditions []Condition `json:"conditions"`
+	// RuleStatistics is the traffic statistics of each rule, keyed by rule name.
+	// It is only populated when rule statistics collection is enabled.
+	// +optional
+	RuleStatistics []SecurityPolicyRuleStatistics `json:"ruleStatistics,omitempty"`
+}
+
+// SecurityPolicyRuleStatistics describes the traffic statistics of a SecurityPolicy rule.
+type SecurityPolicyRuleStatistics struct {
+	// Name is the name of the rule. If the rule has no name, it is the
+	// policy name suffixed with the rule index.
+	Name string `json:"name"`
+	// HitCount is the aggregated number of hits received by the rule.
+	HitCount int64 `json:"hitCount"`
+	// PacketCount is the aggregated number of packets processed by the rule.
+	PacketCount int64 `json:"packetCount"`
+	// ByteCount is the aggregated number of bytes processed by the rule.
+	ByteCount int64 `json:"byteCount"`
+	// SessionCount is the aggregated number of sessions processed by the rule.
+	SessionCount int64 `json:"sessionCount"`
+	// LastHitTime is the last time the hit count of the rule was observed to increase.
+	// +optional
+	LastHitTime *metav1.Time `json:"lastHitTime,omitempty"`
 }
 
 // +genclient
@@ -7,6 +7,20 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// SubnetSelectionStrategy defines how a Subnet is selected from the SubnetSet for a new port.
+type SubnetSelectionStrategy string
+
+const (
+	// SubnetSelectionFirstFit selects the first Subnet with available IPs.
+	SubnetSelectionFirstFit SubnetSelectionStrategy = "FirstFit"
+	// SubnetSelectionLeastUtilized selects the Subnet with the lowest IP utilization.
+	SubnetSelectionLeastUtilized SubnetSelectionStrategy = "LeastUtilized"
+	// SubnetSelectionSpread selects the Subnets with available IPs in turn.
+	SubnetSelectionSpread SubnetSelectionStrategy = "Spread"
+	// SubnetSelectionNodeAffinity selects the Subnet with the most ports on the node of the Pod.
+	SubnetSelectionNodeAffinity SubnetSelectionStrategy = "NodeAffinity"
+)
+
 // SubnetSetSpec defines the desired state of SubnetSet.
 type SubnetSetSpec struct {
 	// Size of Subnet based upon estimated workload count.
@@ -18,20 +32,41 @@ type SubnetSetSpec struct {
 	AccessMode AccessMode `json:"accessMode,omitempty"`
 	// Subnet advanced configuration.
 	AdvancedConfig AdvancedConfig `json:"advancedConfig,omitempty"`
-	// DHCPConfig DHCP configuration.
+	// DHCPConfig DHCP configuration of the Subnets.
+	// The DHCP options other than the DNS servers and the lease time are not supported, as for Subnet.
+	// An invalid DHCP relay config path or DNS server IP is not applied, and is reported by the
+	// DHCPConfigInvalid condition.
 	DHCPConfig DHCPConfig `json:"DHCPConfig,omitempty"`
+	// SubnetSelectionStrategy is the strategy to select a Subnet for a new port, FirstFit by default.
+	// +kubebuilder:validation:Enum=FirstFit;LeastUtilized;Spread;NodeAffinity
+	SubnetSelectionStrategy SubnetSelectionStrategy `json:"subnetSelectionStrategy,omitempty"`
+	// MinSubnets is the minimum count of Subnets kept in the SubnetSet when the empty Subnets are reclaimed.
+	// +kubebuilder:validation:Minimum:=0
+	MinSubnets int `json:"minSubnets,omitempty"`
+	// VPCName is the name of the VPC CR in the Namespace to create the Subnets in.
+	// The default VPC of the Namespace is used if not set.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="vpcName is immutable"
+	VPCName string `json:"vpcName,omitempty"`
+	// ImportedSubnets are the names of the Subnet CRs in the Namespace importing NSX Subnets of the same VPC.
+	// The imported NSX Subnets are used for the ports along with the Subnets created for the SubnetSet,
+	// and they are never deleted with the SubnetSet.
+	// +optional
+	ImportedSubnets []string `json:"importedSubnets,omitempty"`
 }
 
 // SubnetInfo defines the observed state of a single Subnet of a SubnetSet.
 type SubnetInfo struct {
 	NSXResourcePath string   `json:"nsxResourcePath"`
 	IPAddresses     []string `json:"ipAddresses"`
+	IPUsage         *IPUsage `json:"ipUsage,omitempty"`
 }
 
 // SubnetSetStatus defines the observed state of SubnetSet.
 type SubnetSetStatus struct {
 	Conditions []Condition  `json:"conditions,omitempty"`
 	Subnets    []SubnetInfo `json:"subnets,omitempty"`
+	// IPUsage is the sum of the IP usage of the Subnets.
+	IPUsage *IPUsage `json:"ipUsage,omitempty"`
 }
 
 // +genclient
@@ -43,6 +78,7 @@ type SubnetSetStatus struct {
 // +kubebuilder:printcolumn:name="AccessMode",type=string,JSONPath=`.spec.accessMode`,description="Access mode of Subnet"
 // +kubebuilder:printcolumn:name="IPv4SubnetSize",type=string,JSONPath=`.spec.ipv4SubnetSize`,description="Size of Subnet"
 // +kubebuilder:printcolumn:name="IPAddresses",type=string,JSONPath=`.status.subnets[*].ipAddresses[*]`,description="CIDRs for the Subnet"
+// +kubebuilder:printcolumn:name="AvailableIPs",type=integer,JSONPath=`.status.ipUsage.availableIPs`,description="Available IPs in the Subnets"
 type SubnetSet struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -0,0 +1,120 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VPCPeeringSpec defines the desired state of VPCPeering.
+type VPCPeeringSpec struct {
+	// VPCName is the name of the local VPC CR in the Namespace of the VPCPeering.
+	// The default VPC of the Namespace is used if it's not set.
+	// +optional
+	VPCName string `json:"vpcName,omitempty"`
+	// PeerVPC is the VPC to connect with the local VPC.
+	PeerVPC PeerVPCReference `json:"peerVPC"`
+	// NextHops are the IP addresses of the gateway connecting the two VPCs, e.g. the Tier-0
+	// or transit gateway uplink. They are used as the next hops of the routes injected into
+	// the local VPC, and into the peer VPC if PeerNextHops is not set.
+	// +kubebuilder:validation:MinItems=1
+	NextHops []NextHop `json:"nextHops"`
+	// PeerNextHops are the next hops of the routes injected into the peer VPC, NextHops is used if it's not set.
+	// +optional
+	PeerNextHops []NextHop `json:"peerNextHops,omitempty"`
+	// Firewall scopes the traffic allowed between the two VPCs, all the traffic is allowed if it's not set.
+	// +optional
+	Firewall *VPCPeeringFirewall `json:"firewall,omitempty"`
+}
+
+// PeerVPCReference refers to a VPC CR.
+type PeerVPCReference struct {
+	// Namespace of the peer VPC, defaults to the Namespace of the VPCPeering.
+	// The VPC of another Namespace is peered only if that Namespace has a VPCPeering peering back with the
+	// same VPCs. The NSX routes of the pair are created by the VPCPeering in the Namespace sorted first.
+	// The two VPCPeerings must use the same next hops for the routes injected into each VPC and the same
+	// firewall, otherwise both of them are not ready with the reason VPCPeeringConflict and the NSX
+	// resources are not updated.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name of the peer VPC CR, the default VPC of the Namespace is used if it's not set.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// VPCPeeringFirewall defines the traffic allowed between the peered VPCs.
+type VPCPeeringFirewall struct {
+	// Ports allowed from one VPC to the other, the other traffic between the VPCs is dropped.
+	// +kubebuilder:validation:MinItems=1
+	Ports []VPCPeeringPort `json:"ports"`
+}
+
+// VPCPeeringPort defines a port allowed between the peered VPCs.
+type VPCPeeringPort struct {
+	// Protocol of the port, TCP or UDP.
+	// +kubebuilder:validation:Enum=TCP;UDP
+	// +kubebuilder:default=TCP
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+	// Port number.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+}
+
+// VPCPeeringRoute is a route injected into a VPC for the peering.
+type VPCPeeringRoute struct {
+	// VPCPath is the NSX path of the VPC the route is injected into.
+	VPCPath string `json:"vpcPath"`
+	// Network is the destination CIDR of the route.
+	Network string `json:"network"`
+	// Path is the NSX path of the static route.
+	Path string `json:"path"`
+	// RealizationState of the static route in NSX, e.g. REALIZED, UNREALIZED or ERROR.
+	RealizationState string `json:"realizationState,omitempty"`
+}
+
+// VPCPeeringStatus defines the observed state of VPCPeering.
+type VPCPeeringStatus struct {
+	// Conditions defines current state of the VPCPeering.
+	Conditions []Condition `json:"conditions,omitempty"`
+	// VPCPath is the NSX path of the local VPC.
+	VPCPath string `json:"vpcPath,omitempty"`
+	// PeerVPCPath is the NSX path of the peer VPC.
+	PeerVPCPath string `json:"peerVPCPath,omitempty"`
+	// Routes are the routes injected into the two VPCs.
+	Routes []VPCPeeringRoute `json:"routes,omitempty"`
+}
+
+// +genclient
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+
+// VPCPeering is the Schema for the vpcpeerings API, it connects two VPCs through the gateway of the NSX Project.
+// +kubebuilder:printcolumn:name="VPC",type=string,JSONPath=`.spec.vpcName`,description="Local VPC"
+// +kubebuilder:printcolumn:name="PeerNamespace",type=string,JSONPath=`.spec.peerVPC.namespace`,description="Namespace of the peer VPC"
+// +kubebuilder:printcolumn:name="PeerVPC",type=string,JSONPath=`.spec.peerVPC.name`,description="Peer VPC"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`,description="Whether the peering is ready"
+type VPCPeering struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VPCPeeringSpec   `json:"spec,omitempty"`
+	Status VPCPeeringStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// VPCPeeringList contains a list of VPCPeering.
+type VPCPeeringList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VPCPeering `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VPCPeering{}, &VPCPeeringList{})
+}
@@ -0,0 +1,91 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha2
+
+import (
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+const (
+	// IPPoolTypeAnnotation keeps the Type of the IPPool when it's converted to v1alpha1, which has no Type,
+	// so that the Type is restored when the IPPool is converted back to v1alpha2.
+	IPPoolTypeAnnotation = "nsx.vmware.com/ippool-type"
+	// IPPoolTypeDefault is the Type of the IPPool converted from v1alpha1. The IPPools in v1alpha1 are always
+	// allocated from the private IP blocks.
+	IPPoolTypeDefault = "Private"
+)
+
+// Hub marks v1alpha2 as the hub version of IPPool, the other versions are converted to and from it.
+func (*IPPool) Hub() {}
+
+// ConvertFromV1alpha1 converts the v1alpha1 IPPool to this version. It's called by the v1alpha1 IPPool as
+// v1alpha1 can't import this package.
+func (dst *IPPool) ConvertFromV1alpha1(src *v1alpha1.IPPool) error {
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec.Type = IPPoolTypeDefault
+	if t, ok := dst.Annotations[IPPoolTypeAnnotation]; ok {
+		dst.Spec.Type = t
+		delete(dst.Annotations, IPPoolTypeAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+	dst.Spec.Subnets = nil
+	if src.Spec.Subnets != nil {
+		dst.Spec.Subnets = make([]SubnetRequest, len(src.Spec.Subnets))
+		for i, subnet := range src.Spec.Subnets {
+			dst.Spec.Subnets[i] = SubnetRequest{PrefixLength: subnet.PrefixLength, IPFamily: subnet.IPFamily, Name: subnet.Name}
+		}
+	}
+	dst.Spec.VPCName = src.Spec.VPCName
+	dst.Status.Subnets = nil
+	if src.Status.Subnets != nil {
+		dst.Status.Subnets = make([]SubnetResult, len(src.Status.Subnets))
+		for i, subnet := range src.Status.Subnets {
+			dst.Status.Subnets[i] = SubnetResult{CIDR: subnet.CIDR, Name: subnet.Name}
+		}
+	}
+	dst.Status.Conditions = copyConditions(src.Status.Conditions)
+	return nil
+}
+
+// ConvertToV1alpha1 converts this IPPool to v1alpha1. The Type is kept in the annotation unless it's the
+// default one.
+func (src *IPPool) ConvertToV1alpha1(dst *v1alpha1.IPPool) error {
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	if src.Spec.Type != IPPoolTypeDefault {
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[IPPoolTypeAnnotation] = src.Spec.Type
+	}
+	dst.Spec.Subnets = nil
+	if src.Spec.Subnets != nil {
+		dst.Spec.Subnets = make([]v1alpha1.SubnetRequest, len(src.Spec.Subnets))
+		for i, subnet := range src.Spec.Subnets {
+			dst.Spec.Subnets[i] = v1alpha1.SubnetRequest{PrefixLength: subnet.PrefixLength, IPFamily: subnet.IPFamily, Name: subnet.Name}
+		}
+	}
+	dst.Spec.VPCName = src.Spec.VPCName
+	dst.Status.Subnets = nil
+	if src.Status.Subnets != nil {
+		dst.Status.Subnets = make([]v1alpha1.SubnetResult, len(src.Status.Subnets))
+		for i, subnet := range src.Status.Subnets {
+			dst.Status.Subnets[i] = v1alpha1.SubnetResult{CIDR: subnet.CIDR, Name: subnet.Name}
+		}
+	}
+	dst.Status.Conditions = copyConditions(src.Status.Conditions)
+	return nil
+}
+
+func copyConditions(conditions []v1alpha1.Condition) []v1alpha1.Condition {
+	if conditions == nil {
+		return nil
+	}
+	copied := make([]v1alpha1.Condition, len(conditions))
+	for i := range conditions {
+		conditions[i].DeepCopyInto(&copied[i])
+	}
+	return copied
+}
@@ -0,0 +1,100 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package v1alpha2
+
+import (
+	"math/rand"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+const fuzzIterations = 1000
+
+func newFuzzer(t *testing.T) interface{ Fuzz(obj interface{}) } {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fuzzer.FuzzerFor(metafuzzer.Funcs, rand.NewSource(rand.Int63()), serializer.NewCodecFactory(scheme))
+}
+
+func TestIPPoolConversionFuzzRoundTrip(t *testing.T) {
+	f := newFuzzer(t)
+	t.Run("hub-spoke-hub", func(t *testing.T) {
+		for i := 0; i < fuzzIterations; i++ {
+			hub := &IPPool{}
+			f.Fuzz(hub)
+			spoke := &v1alpha1.IPPool{}
+			if err := spoke.ConvertFrom(hub.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+			restored := &IPPool{}
+			if err := spoke.ConvertTo(restored); err != nil {
+				t.Fatal(err)
+			}
+			if !apiequality.Semantic.DeepEqual(hub, restored) {
+				t.Fatalf("IPPool changed after round trip: %s", diff.ObjectReflectDiff(hub, restored))
+			}
+		}
+	})
+	t.Run("spoke-hub-spoke", func(t *testing.T) {
+		for i := 0; i < fuzzIterations; i++ {
+			spoke := &v1alpha1.IPPool{}
+			f.Fuzz(spoke)
+			hub := &IPPool{}
+			if err := spoke.DeepCopy().ConvertTo(hub); err != nil {
+				t.Fatal(err)
+			}
+			restored := &v1alpha1.IPPool{}
+			if err := restored.ConvertFrom(hub); err != nil {
+				t.Fatal(err)
+			}
+			if !apiequality.Semantic.DeepEqual(spoke, restored) {
+				t.Fatalf("IPPool changed after round trip: %s", diff.ObjectReflectDiff(spoke, restored))
+			}
+		}
+	})
+}
+
+func TestIPPoolConversionDefaultType(t *testing.T) {
+	spoke := &v1alpha1.IPPool{Spec: v1alpha1.IPPoolSpec{Subnets: []v1alpha1.SubnetRequest{{Name: "subnet1", PrefixLength: 24}}}}
+	hub := &IPPool{}
+	if err := spoke.ConvertTo(hub); err != nil {
+		t.Fatal(err)
+	}
+	if hub.Spec.Type != IPPoolTypeDefault {
+		t.Errorf("expected Type %s, got %s", IPPoolTypeDefault, hub.Spec.Type)
+	}
+	if len(hub.Spec.Subnets) != 1 || hub.Spec.Subnets[0].Name != "subnet1" || hub.Spec.Subnets[0].PrefixLength != 24 {
+		t.Errorf("unexpected Subnets %v", hub.Spec.Subnets)
+	}
+
+	// The Type is kept in the annotation of v1alpha1 IPPool unless it's the default one.
+	hub.Spec.Type = "Public"
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if spoke.Annotations[IPPoolTypeAnnotation] != "Public" {
+		t.Errorf("expected annotation %s to be Public, got %v", IPPoolTypeAnnotation, spoke.Annotations)
+	}
+	hub.Spec.Type = IPPoolTypeDefault
+	spoke = &v1alpha1.IPPool{}
+	if err := spoke.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := spoke.Annotations[IPPoolTypeAnnotation]; ok {
+		t.Errorf("unexpected annotation %s for the default Type", IPPoolTypeAnnotation)
+	}
+}
@@ -16,12 +16,16 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/egressip"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/ipaddressallocation"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/ippool"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/natrule"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
 	sr "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/staticroute"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetport"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpcpeering"
 	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 )
 
@@ -142,6 +146,30 @@ func InitializeCleanupService(cf *config.NSXOperatorConfig, nsxClient *nsx.Clien
 		}
 	}
 
+	wrapInitializeIPAddressAllocation := func(service common.Service) cleanupFunc {
+		return func() (cleanup, error) {
+			return ipaddressallocation.InitializeIPAddressAllocation(service, vpcService)
+		}
+	}
+
+	wrapInitializeNATRule := func(service common.Service) cleanupFunc {
+		return func() (cleanup, error) {
+			return natrule.InitializeNATRule(service, vpcService)
+		}
+	}
+
+	wrapInitializeEgressIP := func(service common.Service) cleanupFunc {
+		return func() (cleanup, error) {
+			return egressip.InitializeEgressIP(service, vpcService)
+		}
+	}
+
+	wrapInitializeVPCPeering := func(service common.Service) cleanupFunc {
+		return func() (cleanup, error) {
+			return vpcpeering.InitializeVPCPeering(service)
+		}
+	}
+
 	wrapInitializeSubnetPort := func(service common.Service) cleanupFunc {
 		return func() (cleanup, error) {
 			return subnetport.InitializeSubnetPort(service)
@@ -154,6 +182,10 @@ func InitializeCleanupService(cf *config.NSXOperatorConfig, nsxClient *nsx.Clien
 		AddCleanupService(wrapInitializeSecurityPolicy(commonService)).
 		AddCleanupService(wrapInitializeIPPool(commonService)).
 		AddCleanupService(wrapInitializeStaticRoute(commonService)).
+		AddCleanupService(wrapInitializeIPAddressAllocation(commonService)).
+		AddCleanupService(wrapInitializeNATRule(commonService)).
+		AddCleanupService(wrapInitializeEgressIP(commonService)).
+		AddCleanupService(wrapInitializeVPCPeering(commonService)).
 		AddCleanupService(wrapInitializeVPC(commonService))
 
 	return cleanupService, nil
@@ -0,0 +1,128 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeAddressGroups implements AddressGroupInterface
+type FakeAddressGroups struct {
+	Fake *FakeNsxV1alpha1
+	ns   string
+}
+
+var addressgroupsResource = v1alpha1.SchemeGroupVersion.WithResource("addressgroups")
+
+var addressgroupsKind = v1alpha1.SchemeGroupVersion.WithKind("AddressGroup")
+
+// Get takes name of the addressGroup, and returns the corresponding addressGroup object, and an error if there is any.
+func (c *FakeAddressGroups) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.AddressGroup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(addressgroupsResource, c.ns, name), &v1alpha1.AddressGroup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.AddressGroup), err
+}
+
+// List takes label and field selectors, and returns the list of AddressGroups that match those selectors.
+func (c *FakeAddressGroups) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.AddressGroupList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(addressgroupsResource, addressgroupsKind, c.ns, opts), &v1alpha1.AddressGroupList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.AddressGroupList{ListMeta: obj.(*v1alpha1.AddressGroupList).ListMeta}
+	for _, item := range obj.(*v1alpha1.AddressGroupList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested addressGroups.
+func (c *FakeAddressGroups) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(addressgroupsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a addressGroup and creates it.  Returns the server's representation of the addressGroup, and an error, if there is any.
+func (c *FakeAddressGroups) Create(ctx context.Context, addressGroup *v1alpha1.AddressGroup, opts v1.CreateOptions) (result *v1alpha1.AddressGroup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(addressgroupsResource, c.ns, addressGroup), &v1alpha1.AddressGroup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.AddressGroup), err
+}
+
+// Update takes the representation of a addressGroup and updates it. Returns the server's representation of the addressGroup, and an error, if there is any.
+func (c *FakeAddressGroups) Update(ctx context.Context, addressGroup *v1alpha1.AddressGroup, opts v1.UpdateOptions) (result *v1alpha1.AddressGroup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(addressgroupsResource, c.ns, addressGroup), &v1alpha1.AddressGroup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.AddressGroup), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeAddressGroups) UpdateStatus(ctx context.Context, addressGroup *v1alpha1.AddressGroup, opts v1.UpdateOptions) (*v1alpha1.AddressGroup, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(addressgroupsResource, "status", c.ns, addressGroup), &v1alpha1.AddressGroup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.AddressGroup), err
+}
+
+// Delete takes name of the addressGroup and deletes it. Returns an error if one occurs.
+func (c *FakeAddressGroups) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(addressgroupsResource, c.ns, name, opts), &v1alpha1.AddressGroup{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeAddressGroups) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(addressgroupsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.AddressGroupList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched addressGroup.
+func (c *FakeAddressGroups) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.AddressGroup, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(addressgroupsResource, c.ns, name, pt, data, subresources...), &v1alpha1.AddressGroup{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.AddressGroup), err
+}
@@ -0,0 +1,128 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeEgressIPs implements EgressIPInterface
+type FakeEgressIPs struct {
+	Fake *FakeNsxV1alpha1
+	ns   string
+}
+
+var egressipsResource = v1alpha1.SchemeGroupVersion.WithResource("egressips")
+
+var egressipsKind = v1alpha1.SchemeGroupVersion.WithKind("EgressIP")
+
+// Get takes name of the egressIP, and returns the corresponding egressIP object, and an error if there is any.
+func (c *FakeEgressIPs) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.EgressIP, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(egressipsResource, c.ns, name), &v1alpha1.EgressIP{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.EgressIP), err
+}
+
+// List takes label and field selectors, and returns the list of EgressIPs that match those selectors.
+func (c *FakeEgressIPs) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.EgressIPList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(egressipsResource, egressipsKind, c.ns, opts), &v1alpha1.EgressIPList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.EgressIPList{ListMeta: obj.(*v1alpha1.EgressIPList).ListMeta}
+	for _, item := range obj.(*v1alpha1.EgressIPList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested egressIPs.
+func (c *FakeEgressIPs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(egressipsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a egressIP and creates it.  Returns the server's representation of the egressIP, and an error, if there is any.
+func (c *FakeEgressIPs) Create(ctx context.Context, egressIP *v1alpha1.EgressIP, opts v1.CreateOptions) (result *v1alpha1.EgressIP, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(egressipsResource, c.ns, egressIP), &v1alpha1.EgressIP{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.EgressIP), err
+}
+
+// Update takes the representation of a egressIP and updates it. Returns the server's representation of the egressIP, and an error, if there is any.
+func (c *FakeEgressIPs) Update(ctx context.Context, egressIP *v1alpha1.EgressIP, opts v1.UpdateOptions) (result *v1alpha1.EgressIP, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(egressipsResource, c.ns, egressIP), &v1alpha1.EgressIP{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.EgressIP), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeEgressIPs) UpdateStatus(ctx context.Context, egressIP *v1alpha1.EgressIP, opts v1.UpdateOptions) (*v1alpha1.EgressIP, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(egressipsResource, "status", c.ns, egressIP), &v1alpha1.EgressIP{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.EgressIP), err
+}
+
+// Delete takes name of the egressIP and deletes it. Returns an error if one occurs.
+func (c *FakeEgressIPs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(egressipsResource, c.ns, name, opts), &v1alpha1.EgressIP{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeEgressIPs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(egressipsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.EgressIPList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched egressIP.
+func (c *FakeEgressIPs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.EgressIP, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(egressipsResource, c.ns, name, pt, data, subresources...), &v1alpha1.EgressIP{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.EgressIP), err
+}
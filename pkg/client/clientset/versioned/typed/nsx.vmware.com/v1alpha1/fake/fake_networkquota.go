@@ -0,0 +1,128 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeNetworkQuotas implements NetworkQuotaInterface
+type FakeNetworkQuotas struct {
+	Fake *FakeNsxV1alpha1
+	ns   string
+}
+
+var networkquotasResource = v1alpha1.SchemeGroupVersion.WithResource("networkquotas")
+
+var networkquotasKind = v1alpha1.SchemeGroupVersion.WithKind("NetworkQuota")
+
+// Get takes name of the networkQuota, and returns the corresponding networkQuota object, and an error if there is any.
+func (c *FakeNetworkQuotas) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.NetworkQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(networkquotasResource, c.ns, name), &v1alpha1.NetworkQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NetworkQuota), err
+}
+
+// List takes label and field selectors, and returns the list of NetworkQuotas that match those selectors.
+func (c *FakeNetworkQuotas) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.NetworkQuotaList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(networkquotasResource, networkquotasKind, c.ns, opts), &v1alpha1.NetworkQuotaList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.NetworkQuotaList{ListMeta: obj.(*v1alpha1.NetworkQuotaList).ListMeta}
+	for _, item := range obj.(*v1alpha1.NetworkQuotaList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested networkQuotas.
+func (c *FakeNetworkQuotas) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(networkquotasResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a networkQuota and creates it.  Returns the server's representation of the networkQuota, and an error, if there is any.
+func (c *FakeNetworkQuotas) Create(ctx context.Context, networkQuota *v1alpha1.NetworkQuota, opts v1.CreateOptions) (result *v1alpha1.NetworkQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(networkquotasResource, c.ns, networkQuota), &v1alpha1.NetworkQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NetworkQuota), err
+}
+
+// Update takes the representation of a networkQuota and updates it. Returns the server's representation of the networkQuota, and an error, if there is any.
+func (c *FakeNetworkQuotas) Update(ctx context.Context, networkQuota *v1alpha1.NetworkQuota, opts v1.UpdateOptions) (result *v1alpha1.NetworkQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(networkquotasResource, c.ns, networkQuota), &v1alpha1.NetworkQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NetworkQuota), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeNetworkQuotas) UpdateStatus(ctx context.Context, networkQuota *v1alpha1.NetworkQuota, opts v1.UpdateOptions) (*v1alpha1.NetworkQuota, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(networkquotasResource, "status", c.ns, networkQuota), &v1alpha1.NetworkQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NetworkQuota), err
+}
+
+// Delete takes name of the networkQuota and deletes it. Returns an error if one occurs.
+func (c *FakeNetworkQuotas) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(networkquotasResource, c.ns, name, opts), &v1alpha1.NetworkQuota{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeNetworkQuotas) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(networkquotasResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.NetworkQuotaList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched networkQuota.
+func (c *FakeNetworkQuotas) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.NetworkQuota, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(networkquotasResource, c.ns, name, pt, data, subresources...), &v1alpha1.NetworkQuota{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.NetworkQuota), err
+}
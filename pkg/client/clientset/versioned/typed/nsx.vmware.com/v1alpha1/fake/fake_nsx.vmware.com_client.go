@@ -15,14 +15,34 @@ type FakeNsxV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeNsxV1alpha1) AddressGroups(namespace string) v1alpha1.AddressGroupInterface {
+	return &FakeAddressGroups{c, namespace}
+}
+
+func (c *FakeNsxV1alpha1) EgressIPs(namespace string) v1alpha1.EgressIPInterface {
+	return &FakeEgressIPs{c, namespace}
+}
+
+func (c *FakeNsxV1alpha1) IPAddressAllocations(namespace string) v1alpha1.IPAddressAllocationInterface {
+	return &FakeIPAddressAllocations{c, namespace}
+}
+
 func (c *FakeNsxV1alpha1) IPPools(namespace string) v1alpha1.IPPoolInterface {
 	return &FakeIPPools{c, namespace}
 }
 
+func (c *FakeNsxV1alpha1) NATRules(namespace string) v1alpha1.NATRuleInterface {
+	return &FakeNATRules{c, namespace}
+}
+
 func (c *FakeNsxV1alpha1) NSXServiceAccounts(namespace string) v1alpha1.NSXServiceAccountInterface {
 	return &FakeNSXServiceAccounts{c, namespace}
 }
 
+func (c *FakeNsxV1alpha1) NetworkQuotas(namespace string) v1alpha1.NetworkQuotaInterface {
+	return &FakeNetworkQuotas{c, namespace}
+}
+
 func (c *FakeNsxV1alpha1) SecurityPolicies(namespace string) v1alpha1.SecurityPolicyInterface {
 	return &FakeSecurityPolicies{c, namespace}
 }
@@ -51,6 +71,10 @@ func (c *FakeNsxV1alpha1) VPCNetworkConfigurations() v1alpha1.VPCNetworkConfigur
 	return &FakeVPCNetworkConfigurations{c}
 }
 
+func (c *FakeNsxV1alpha1) VPCPeerings(namespace string) v1alpha1.VPCPeeringInterface {
+	return &FakeVPCPeerings{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeNsxV1alpha1) RESTClient() rest.Interface {
@@ -0,0 +1,128 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeVPCPeerings implements VPCPeeringInterface
+type FakeVPCPeerings struct {
+	Fake *FakeNsxV1alpha1
+	ns   string
+}
+
+var vpcpeeringsResource = v1alpha1.SchemeGroupVersion.WithResource("vpcpeerings")
+
+var vpcpeeringsKind = v1alpha1.SchemeGroupVersion.WithKind("VPCPeering")
+
+// Get takes name of the vPCPeering, and returns the corresponding vPCPeering object, and an error if there is any.
+func (c *FakeVPCPeerings) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.VPCPeering, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(vpcpeeringsResource, c.ns, name), &v1alpha1.VPCPeering{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VPCPeering), err
+}
+
+// List takes label and field selectors, and returns the list of VPCPeerings that match those selectors.
+func (c *FakeVPCPeerings) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.VPCPeeringList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(vpcpeeringsResource, vpcpeeringsKind, c.ns, opts), &v1alpha1.VPCPeeringList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.VPCPeeringList{ListMeta: obj.(*v1alpha1.VPCPeeringList).ListMeta}
+	for _, item := range obj.(*v1alpha1.VPCPeeringList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested vPCPeerings.
+func (c *FakeVPCPeerings) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(vpcpeeringsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a vPCPeering and creates it.  Returns the server's representation of the vPCPeering, and an error, if there is any.
+func (c *FakeVPCPeerings) Create(ctx context.Context, vPCPeering *v1alpha1.VPCPeering, opts v1.CreateOptions) (result *v1alpha1.VPCPeering, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(vpcpeeringsResource, c.ns, vPCPeering), &v1alpha1.VPCPeering{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VPCPeering), err
+}
+
+// Update takes the representation of a vPCPeering and updates it. Returns the server's representation of the vPCPeering, and an error, if there is any.
+func (c *FakeVPCPeerings) Update(ctx context.Context, vPCPeering *v1alpha1.VPCPeering, opts v1.UpdateOptions) (result *v1alpha1.VPCPeering, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(vpcpeeringsResource, c.ns, vPCPeering), &v1alpha1.VPCPeering{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VPCPeering), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeVPCPeerings) UpdateStatus(ctx context.Context, vPCPeering *v1alpha1.VPCPeering, opts v1.UpdateOptions) (*v1alpha1.VPCPeering, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(vpcpeeringsResource, "status", c.ns, vPCPeering), &v1alpha1.VPCPeering{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VPCPeering), err
+}
+
+// Delete takes name of the vPCPeering and deletes it. Returns an error if one occurs.
+func (c *FakeVPCPeerings) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(vpcpeeringsResource, c.ns, name, opts), &v1alpha1.VPCPeering{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeVPCPeerings) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(vpcpeeringsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.VPCPeeringList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched vPCPeering.
+func (c *FakeVPCPeerings) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.VPCPeering, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(vpcpeeringsResource, c.ns, name, pt, data, subresources...), &v1alpha1.VPCPeering{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.VPCPeering), err
+}
@@ -5,10 +5,20 @@
 
 package v1alpha1
 
+type AddressGroupExpansion interface{}
+
+type EgressIPExpansion interface{}
+
+type IPAddressAllocationExpansion interface{}
+
 type IPPoolExpansion interface{}
 
+type NATRuleExpansion interface{}
+
 type NSXServiceAccountExpansion interface{}
 
+type NetworkQuotaExpansion interface{}
+
 type SecurityPolicyExpansion interface{}
 
 type StaticRouteExpansion interface{}
@@ -22,3 +32,5 @@ type SubnetSetExpansion interface{}
 type VPCExpansion interface{}
 
 type VPCNetworkConfigurationExpansion interface{}
+
+type VPCPeeringExpansion interface{}
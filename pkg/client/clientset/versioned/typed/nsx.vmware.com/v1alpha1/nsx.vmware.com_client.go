@@ -15,8 +15,13 @@ import (
 
 type NsxV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	AddressGroupsGetter
+	EgressIPsGetter
+	IPAddressAllocationsGetter
 	IPPoolsGetter
+	NATRulesGetter
 	NSXServiceAccountsGetter
+	NetworkQuotasGetter
 	SecurityPoliciesGetter
 	StaticRoutesGetter
 	SubnetsGetter
@@ -24,6 +29,7 @@ type NsxV1alpha1Interface interface {
 	SubnetSetsGetter
 	VPCsGetter
 	VPCNetworkConfigurationsGetter
+	VPCPeeringsGetter
 }
 
 // NsxV1alpha1Client is used to interact with features provided by the nsx.vmware.com group.
@@ -31,14 +37,34 @@ type NsxV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *NsxV1alpha1Client) AddressGroups(namespace string) AddressGroupInterface {
+	return newAddressGroups(c, namespace)
+}
+
+func (c *NsxV1alpha1Client) EgressIPs(namespace string) EgressIPInterface {
+	return newEgressIPs(c, namespace)
+}
+
+func (c *NsxV1alpha1Client) IPAddressAllocations(namespace string) IPAddressAllocationInterface {
+	return newIPAddressAllocations(c, namespace)
+}
+
 func (c *NsxV1alpha1Client) IPPools(namespace string) IPPoolInterface {
 	return newIPPools(c, namespace)
 }
 
+func (c *NsxV1alpha1Client) NATRules(namespace string) NATRuleInterface {
+	return newNATRules(c, namespace)
+}
+
 func (c *NsxV1alpha1Client) NSXServiceAccounts(namespace string) NSXServiceAccountInterface {
 	return newNSXServiceAccounts(c, namespace)
 }
 
+func (c *NsxV1alpha1Client) NetworkQuotas(namespace string) NetworkQuotaInterface {
+	return newNetworkQuotas(c, namespace)
+}
+
 func (c *NsxV1alpha1Client) SecurityPolicies(namespace string) SecurityPolicyInterface {
 	return newSecurityPolicies(c, namespace)
 }
@@ -67,6 +93,10 @@ func (c *NsxV1alpha1Client) VPCNetworkConfigurations() VPCNetworkConfigurationIn
 	return newVPCNetworkConfigurations(c)
 }
 
+func (c *NsxV1alpha1Client) VPCPeerings(namespace string) VPCPeeringInterface {
+	return newVPCPeerings(c, namespace)
+}
+
 // NewForConfig creates a new NsxV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
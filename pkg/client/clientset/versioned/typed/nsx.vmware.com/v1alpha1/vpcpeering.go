@@ -0,0 +1,182 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	scheme "github.com/vmware-tanzu/nsx-operator/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// VPCPeeringsGetter has a method to return a VPCPeeringInterface.
+// A group's client should implement this interface.
+type VPCPeeringsGetter interface {
+	VPCPeerings(namespace string) VPCPeeringInterface
+}
+
+// VPCPeeringInterface has methods to work with VPCPeering resources.
+type VPCPeeringInterface interface {
+	Create(ctx context.Context, vPCPeering *v1alpha1.VPCPeering, opts v1.CreateOptions) (*v1alpha1.VPCPeering, error)
+	Update(ctx context.Context, vPCPeering *v1alpha1.VPCPeering, opts v1.UpdateOptions) (*v1alpha1.VPCPeering, error)
+	UpdateStatus(ctx context.Context, vPCPeering *v1alpha1.VPCPeering, opts v1.UpdateOptions) (*v1alpha1.VPCPeering, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.VPCPeering, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.VPCPeeringList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.VPCPeering, err error)
+	VPCPeeringExpansion
+}
+
+// vPCPeerings implements VPCPeeringInterface
+type vPCPeerings struct {
+	client rest.Interface
+	ns     string
+}
+
+// newVPCPeerings returns a VPCPeerings
+func newVPCPeerings(c *NsxV1alpha1Client, namespace string) *vPCPeerings {
+	return &vPCPeerings{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the vPCPeering, and returns the corresponding vPCPeering object, and an error if there is any.
+func (c *vPCPeerings) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.VPCPeering, err error) {
+	result = &v1alpha1.VPCPeering{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("vpcpeerings").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of VPCPeerings that match those selectors.
+func (c *vPCPeerings) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.VPCPeeringList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.VPCPeeringList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("vpcpeerings").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested vPCPeerings.
+func (c *vPCPeerings) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("vpcpeerings").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a vPCPeering and creates it.  Returns the server's representation of the vPCPeering, and an error, if there is any.
+func (c *vPCPeerings) Create(ctx context.Context, vPCPeering *v1alpha1.VPCPeering, opts v1.CreateOptions) (result *v1alpha1.VPCPeering, err error) {
+	result = &v1alpha1.VPCPeering{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("vpcpeerings").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(vPCPeering).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a vPCPeering and updates it. Returns the server's representation of the vPCPeering, and an error, if there is any.
+func (c *vPCPeerings) Update(ctx context.Context, vPCPeering *v1alpha1.VPCPeering, opts v1.UpdateOptions) (result *v1alpha1.VPCPeering, err error) {
+	result = &v1alpha1.VPCPeering{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("vpcpeerings").
+		Name(vPCPeering.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(vPCPeering).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *vPCPeerings) UpdateStatus(ctx context.Context, vPCPeering *v1alpha1.VPCPeering, opts v1.UpdateOptions) (result *v1alpha1.VPCPeering, err error) {
+	result = &v1alpha1.VPCPeering{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("vpcpeerings").
+		Name(vPCPeering.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(vPCPeering).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the vPCPeering and deletes it. Returns an error if one occurs.
+func (c *vPCPeerings) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("vpcpeerings").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *vPCPeerings) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("vpcpeerings").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched vPCPeering.
+func (c *vPCPeerings) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.VPCPeering, err error) {
+	result = &v1alpha1.VPCPeering{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("vpcpeerings").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
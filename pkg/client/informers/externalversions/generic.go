@@ -41,10 +41,20 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=nsx.vmware.com, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("addressgroups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().AddressGroups().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("egressips"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().EgressIPs().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("ipaddressallocations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().IPAddressAllocations().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("ippools"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().IPPools().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("natrules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().NATRules().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("nsxserviceaccounts"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().NSXServiceAccounts().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("networkquotas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().NetworkQuotas().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("securitypolicies"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().SecurityPolicies().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("staticroutes"):
@@ -59,6 +69,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().VPCs().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("vpcnetworkconfigurations"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().VPCNetworkConfigurations().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("vpcpeerings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Nsx().V1alpha1().VPCPeerings().Informer()}, nil
 
 		// Group=nsx.vmware.com, Version=v1alpha2
 	case v1alpha2.SchemeGroupVersion.WithResource("ippools"):
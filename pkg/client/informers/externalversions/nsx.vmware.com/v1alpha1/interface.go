@@ -11,10 +11,20 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// AddressGroups returns a AddressGroupInformer.
+	AddressGroups() AddressGroupInformer
+	// EgressIPs returns a EgressIPInformer.
+	EgressIPs() EgressIPInformer
+	// IPAddressAllocations returns a IPAddressAllocationInformer.
+	IPAddressAllocations() IPAddressAllocationInformer
 	// IPPools returns a IPPoolInformer.
 	IPPools() IPPoolInformer
+	// NATRules returns a NATRuleInformer.
+	NATRules() NATRuleInformer
 	// NSXServiceAccounts returns a NSXServiceAccountInformer.
 	NSXServiceAccounts() NSXServiceAccountInformer
+	// NetworkQuotas returns a NetworkQuotaInformer.
+	NetworkQuotas() NetworkQuotaInformer
 	// SecurityPolicies returns a SecurityPolicyInformer.
 	SecurityPolicies() SecurityPolicyInformer
 	// StaticRoutes returns a StaticRouteInformer.
@@ -29,6 +39,8 @@ type Interface interface {
 	VPCs() VPCInformer
 	// VPCNetworkConfigurations returns a VPCNetworkConfigurationInformer.
 	VPCNetworkConfigurations() VPCNetworkConfigurationInformer
+	// VPCPeerings returns a VPCPeeringInformer.
+	VPCPeerings() VPCPeeringInformer
 }
 
 type version struct {
@@ -42,16 +54,41 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// AddressGroups returns a AddressGroupInformer.
+func (v *version) AddressGroups() AddressGroupInformer {
+	return &addressGroupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// EgressIPs returns a EgressIPInformer.
+func (v *version) EgressIPs() EgressIPInformer {
+	return &egressIPInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// IPAddressAllocations returns a IPAddressAllocationInformer.
+func (v *version) IPAddressAllocations() IPAddressAllocationInformer {
+	return &iPAddressAllocationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // IPPools returns a IPPoolInformer.
 func (v *version) IPPools() IPPoolInformer {
 	return &iPPoolInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// NATRules returns a NATRuleInformer.
+func (v *version) NATRules() NATRuleInformer {
+	return &nATRuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // NSXServiceAccounts returns a NSXServiceAccountInformer.
 func (v *version) NSXServiceAccounts() NSXServiceAccountInformer {
 	return &nSXServiceAccountInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// NetworkQuotas returns a NetworkQuotaInformer.
+func (v *version) NetworkQuotas() NetworkQuotaInformer {
+	return &networkQuotaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // SecurityPolicies returns a SecurityPolicyInformer.
 func (v *version) SecurityPolicies() SecurityPolicyInformer {
 	return &securityPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -86,3 +123,8 @@ func (v *version) VPCs() VPCInformer {
 func (v *version) VPCNetworkConfigurations() VPCNetworkConfigurationInformer {
 	return &vPCNetworkConfigurationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
+
+// VPCPeerings returns a VPCPeeringInformer.
+func (v *version) VPCPeerings() VPCPeeringInformer {
+	return &vPCPeeringInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -0,0 +1,86 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// AddressGroupLister helps list AddressGroups.
+// All objects returned here must be treated as read-only.
+type AddressGroupLister interface {
+	// List lists all AddressGroups in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.AddressGroup, err error)
+	// AddressGroups returns an object that can list and get AddressGroups.
+	AddressGroups(namespace string) AddressGroupNamespaceLister
+	AddressGroupListerExpansion
+}
+
+// addressGroupLister implements the AddressGroupLister interface.
+type addressGroupLister struct {
+	indexer cache.Indexer
+}
+
+// NewAddressGroupLister returns a new AddressGroupLister.
+func NewAddressGroupLister(indexer cache.Indexer) AddressGroupLister {
+	return &addressGroupLister{indexer: indexer}
+}
+
+// List lists all AddressGroups in the indexer.
+func (s *addressGroupLister) List(selector labels.Selector) (ret []*v1alpha1.AddressGroup, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.AddressGroup))
+	})
+	return ret, err
+}
+
+// AddressGroups returns an object that can list and get AddressGroups.
+func (s *addressGroupLister) AddressGroups(namespace string) AddressGroupNamespaceLister {
+	return addressGroupNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// AddressGroupNamespaceLister helps list and get AddressGroups.
+// All objects returned here must be treated as read-only.
+type AddressGroupNamespaceLister interface {
+	// List lists all AddressGroups in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.AddressGroup, err error)
+	// Get retrieves the AddressGroup from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.AddressGroup, error)
+	AddressGroupNamespaceListerExpansion
+}
+
+// addressGroupNamespaceLister implements the AddressGroupNamespaceLister
+// interface.
+type addressGroupNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all AddressGroups in the indexer for a given namespace.
+func (s addressGroupNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.AddressGroup, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.AddressGroup))
+	})
+	return ret, err
+}
+
+// Get retrieves the AddressGroup from the indexer for a given namespace and name.
+func (s addressGroupNamespaceLister) Get(name string) (*v1alpha1.AddressGroup, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("addressgroup"), name)
+	}
+	return obj.(*v1alpha1.AddressGroup), nil
+}
@@ -0,0 +1,86 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// EgressIPLister helps list EgressIPs.
+// All objects returned here must be treated as read-only.
+type EgressIPLister interface {
+	// List lists all EgressIPs in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.EgressIP, err error)
+	// EgressIPs returns an object that can list and get EgressIPs.
+	EgressIPs(namespace string) EgressIPNamespaceLister
+	EgressIPListerExpansion
+}
+
+// egressIPLister implements the EgressIPLister interface.
+type egressIPLister struct {
+	indexer cache.Indexer
+}
+
+// NewEgressIPLister returns a new EgressIPLister.
+func NewEgressIPLister(indexer cache.Indexer) EgressIPLister {
+	return &egressIPLister{indexer: indexer}
+}
+
+// List lists all EgressIPs in the indexer.
+func (s *egressIPLister) List(selector labels.Selector) (ret []*v1alpha1.EgressIP, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.EgressIP))
+	})
+	return ret, err
+}
+
+// EgressIPs returns an object that can list and get EgressIPs.
+func (s *egressIPLister) EgressIPs(namespace string) EgressIPNamespaceLister {
+	return egressIPNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// EgressIPNamespaceLister helps list and get EgressIPs.
+// All objects returned here must be treated as read-only.
+type EgressIPNamespaceLister interface {
+	// List lists all EgressIPs in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.EgressIP, err error)
+	// Get retrieves the EgressIP from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.EgressIP, error)
+	EgressIPNamespaceListerExpansion
+}
+
+// egressIPNamespaceLister implements the EgressIPNamespaceLister
+// interface.
+type egressIPNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all EgressIPs in the indexer for a given namespace.
+func (s egressIPNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.EgressIP, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.EgressIP))
+	})
+	return ret, err
+}
+
+// Get retrieves the EgressIP from the indexer for a given namespace and name.
+func (s egressIPNamespaceLister) Get(name string) (*v1alpha1.EgressIP, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("egressip"), name)
+	}
+	return obj.(*v1alpha1.EgressIP), nil
+}
@@ -5,6 +5,30 @@
 
 package v1alpha1
 
+// AddressGroupListerExpansion allows custom methods to be added to
+// AddressGroupLister.
+type AddressGroupListerExpansion interface{}
+
+// AddressGroupNamespaceListerExpansion allows custom methods to be added to
+// AddressGroupNamespaceLister.
+type AddressGroupNamespaceListerExpansion interface{}
+
+// EgressIPListerExpansion allows custom methods to be added to
+// EgressIPLister.
+type EgressIPListerExpansion interface{}
+
+// EgressIPNamespaceListerExpansion allows custom methods to be added to
+// EgressIPNamespaceLister.
+type EgressIPNamespaceListerExpansion interface{}
+
+// IPAddressAllocationListerExpansion allows custom methods to be added to
+// IPAddressAllocationLister.
+type IPAddressAllocationListerExpansion interface{}
+
+// IPAddressAllocationNamespaceListerExpansion allows custom methods to be added to
+// IPAddressAllocationNamespaceLister.
+type IPAddressAllocationNamespaceListerExpansion interface{}
+
 // IPPoolListerExpansion allows custom methods to be added to
 // IPPoolLister.
 type IPPoolListerExpansion interface{}
@@ -13,6 +37,14 @@ type IPPoolListerExpansion interface{}
 // IPPoolNamespaceLister.
 type IPPoolNamespaceListerExpansion interface{}
 
+// NATRuleListerExpansion allows custom methods to be added to
+// NATRuleLister.
+type NATRuleListerExpansion interface{}
+
+// NATRuleNamespaceListerExpansion allows custom methods to be added to
+// NATRuleNamespaceLister.
+type NATRuleNamespaceListerExpansion interface{}
+
 // NSXServiceAccountListerExpansion allows custom methods to be added to
 // NSXServiceAccountLister.
 type NSXServiceAccountListerExpansion interface{}
@@ -21,6 +53,14 @@ type NSXServiceAccountListerExpansion interface{}
 // NSXServiceAccountNamespaceLister.
 type NSXServiceAccountNamespaceListerExpansion interface{}
 
+// NetworkQuotaListerExpansion allows custom methods to be added to
+// NetworkQuotaLister.
+type NetworkQuotaListerExpansion interface{}
+
+// NetworkQuotaNamespaceListerExpansion allows custom methods to be added to
+// NetworkQuotaNamespaceLister.
+type NetworkQuotaNamespaceListerExpansion interface{}
+
 // SecurityPolicyListerExpansion allows custom methods to be added to
 // SecurityPolicyLister.
 type SecurityPolicyListerExpansion interface{}
@@ -72,3 +112,11 @@ type VPCNamespaceListerExpansion interface{}
 // VPCNetworkConfigurationListerExpansion allows custom methods to be added to
 // VPCNetworkConfigurationLister.
 type VPCNetworkConfigurationListerExpansion interface{}
+
+// VPCPeeringListerExpansion allows custom methods to be added to
+// VPCPeeringLister.
+type VPCPeeringListerExpansion interface{}
+
+// VPCPeeringNamespaceListerExpansion allows custom methods to be added to
+// VPCPeeringNamespaceLister.
+type VPCPeeringNamespaceListerExpansion interface{}
@@ -0,0 +1,86 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NATRuleLister helps list NATRules.
+// All objects returned here must be treated as read-only.
+type NATRuleLister interface {
+	// List lists all NATRules in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.NATRule, err error)
+	// NATRules returns an object that can list and get NATRules.
+	NATRules(namespace string) NATRuleNamespaceLister
+	NATRuleListerExpansion
+}
+
+// nATRuleLister implements the NATRuleLister interface.
+type nATRuleLister struct {
+	indexer cache.Indexer
+}
+
+// NewNATRuleLister returns a new NATRuleLister.
+func NewNATRuleLister(indexer cache.Indexer) NATRuleLister {
+	return &nATRuleLister{indexer: indexer}
+}
+
+// List lists all NATRules in the indexer.
+func (s *nATRuleLister) List(selector labels.Selector) (ret []*v1alpha1.NATRule, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.NATRule))
+	})
+	return ret, err
+}
+
+// NATRules returns an object that can list and get NATRules.
+func (s *nATRuleLister) NATRules(namespace string) NATRuleNamespaceLister {
+	return nATRuleNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// NATRuleNamespaceLister helps list and get NATRules.
+// All objects returned here must be treated as read-only.
+type NATRuleNamespaceLister interface {
+	// List lists all NATRules in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.NATRule, err error)
+	// Get retrieves the NATRule from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.NATRule, error)
+	NATRuleNamespaceListerExpansion
+}
+
+// nATRuleNamespaceLister implements the NATRuleNamespaceLister
+// interface.
+type nATRuleNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all NATRules in the indexer for a given namespace.
+func (s nATRuleNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.NATRule, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.NATRule))
+	})
+	return ret, err
+}
+
+// Get retrieves the NATRule from the indexer for a given namespace and name.
+func (s nATRuleNamespaceLister) Get(name string) (*v1alpha1.NATRule, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("natrule"), name)
+	}
+	return obj.(*v1alpha1.NATRule), nil
+}
@@ -0,0 +1,86 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NetworkQuotaLister helps list NetworkQuotas.
+// All objects returned here must be treated as read-only.
+type NetworkQuotaLister interface {
+	// List lists all NetworkQuotas in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.NetworkQuota, err error)
+	// NetworkQuotas returns an object that can list and get NetworkQuotas.
+	NetworkQuotas(namespace string) NetworkQuotaNamespaceLister
+	NetworkQuotaListerExpansion
+}
+
+// networkQuotaLister implements the NetworkQuotaLister interface.
+type networkQuotaLister struct {
+	indexer cache.Indexer
+}
+
+// NewNetworkQuotaLister returns a new NetworkQuotaLister.
+func NewNetworkQuotaLister(indexer cache.Indexer) NetworkQuotaLister {
+	return &networkQuotaLister{indexer: indexer}
+}
+
+// List lists all NetworkQuotas in the indexer.
+func (s *networkQuotaLister) List(selector labels.Selector) (ret []*v1alpha1.NetworkQuota, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.NetworkQuota))
+	})
+	return ret, err
+}
+
+// NetworkQuotas returns an object that can list and get NetworkQuotas.
+func (s *networkQuotaLister) NetworkQuotas(namespace string) NetworkQuotaNamespaceLister {
+	return networkQuotaNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// NetworkQuotaNamespaceLister helps list and get NetworkQuotas.
+// All objects returned here must be treated as read-only.
+type NetworkQuotaNamespaceLister interface {
+	// List lists all NetworkQuotas in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.NetworkQuota, err error)
+	// Get retrieves the NetworkQuota from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.NetworkQuota, error)
+	NetworkQuotaNamespaceListerExpansion
+}
+
+// networkQuotaNamespaceLister implements the NetworkQuotaNamespaceLister
+// interface.
+type networkQuotaNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all NetworkQuotas in the indexer for a given namespace.
+func (s networkQuotaNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.NetworkQuota, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.NetworkQuota))
+	})
+	return ret, err
+}
+
+// Get retrieves the NetworkQuota from the indexer for a given namespace and name.
+func (s networkQuotaNamespaceLister) Get(name string) (*v1alpha1.NetworkQuota, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("networkquota"), name)
+	}
+	return obj.(*v1alpha1.NetworkQuota), nil
+}
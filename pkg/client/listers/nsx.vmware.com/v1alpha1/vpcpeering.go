@@ -0,0 +1,86 @@
+/* Copyright © 2023 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware-tanzu/nsx-operator/pkg/apis/nsx.vmware.com/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// VPCPeeringLister helps list VPCPeerings.
+// All objects returned here must be treated as read-only.
+type VPCPeeringLister interface {
+	// List lists all VPCPeerings in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.VPCPeering, err error)
+	// VPCPeerings returns an object that can list and get VPCPeerings.
+	VPCPeerings(namespace string) VPCPeeringNamespaceLister
+	VPCPeeringListerExpansion
+}
+
+// vPCPeeringLister implements the VPCPeeringLister interface.
+type vPCPeeringLister struct {
+	indexer cache.Indexer
+}
+
+// NewVPCPeeringLister returns a new VPCPeeringLister.
+func NewVPCPeeringLister(indexer cache.Indexer) VPCPeeringLister {
+	return &vPCPeeringLister{indexer: indexer}
+}
+
+// List lists all VPCPeerings in the indexer.
+func (s *vPCPeeringLister) List(selector labels.Selector) (ret []*v1alpha1.VPCPeering, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VPCPeering))
+	})
+	return ret, err
+}
+
+// VPCPeerings returns an object that can list and get VPCPeerings.
+func (s *vPCPeeringLister) VPCPeerings(namespace string) VPCPeeringNamespaceLister {
+	return vPCPeeringNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// VPCPeeringNamespaceLister helps list and get VPCPeerings.
+// All objects returned here must be treated as read-only.
+type VPCPeeringNamespaceLister interface {
+	// List lists all VPCPeerings in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.VPCPeering, err error)
+	// Get retrieves the VPCPeering from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.VPCPeering, error)
+	VPCPeeringNamespaceListerExpansion
+}
+
+// vPCPeeringNamespaceLister implements the VPCPeeringNamespaceLister
+// interface.
+type vPCPeeringNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all VPCPeerings in the indexer for a given namespace.
+func (s vPCPeeringNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.VPCPeering, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.VPCPeering))
+	})
+	return ret, err
+}
+
+// Get retrieves the VPCPeering from the indexer for a given namespace and name.
+func (s vPCPeeringNamespaceLister) Get(name string) (*v1alpha1.VPCPeering, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("vpcpeering"), name)
+	}
+	return obj.(*v1alpha1.VPCPeering), nil
+}
@@ -25,7 +25,13 @@ const (
 	// LicenseInterval is the timeout for checking license status
 	LicenseInterval = 86400
 	// LicenseIntervalForDFW is the timeout for checking license status while no DFW license enabled
-	LicenseIntervalForDFW  = 1800
+	LicenseIntervalForDFW = 1800
+	// RuleStatisticsInterval is the default interval for collecting rule statistics
+	RuleStatisticsInterval = 300
+	// RuleStatisticsRateLimit is the default max number of rule statistics requests sent to NSX per second
+	RuleStatisticsRateLimit = 1
+	// IPUtilizationThreshold is the default percentage of IP utilization for the IPUtilizationHigh condition
+	IPUtilizationThreshold = 80
 	defaultWebhookPort     = 9981
 	defaultWebhookCertPath = "/tmp/k8s-webhook-server/serving-certs"
 )
@@ -33,6 +39,7 @@ const (
 var (
 	LogLevel               int
 	ProbeAddr, MetricsAddr string
+	DebugAddr              string
 	WebhookServerPort      int
 	WebhookCertDir         string
 	configFilePath         = ""
@@ -113,6 +120,12 @@ type NsxConfig struct {
 	EnvoyHost                 string   `ini:"envoy_host"`
 	EnvoyPort                 int      `ini:"envoy_port"`
 	LicenseValidationInterval int      `ini:"license_validation_interval"`
+	EnableRuleStatistics      bool     `ini:"enable_rule_statistics"`
+	RuleStatisticsInterval    int      `ini:"rule_statistics_interval"`
+	RuleStatisticsRateLimit   int      `ini:"rule_statistics_rate_limit"`
+	IPUtilizationThreshold    int      `ini:"ip_utilization_threshold"`
+	// SubnetPortPoolSize is the number of pre-created subnet ports for each node and Pod SubnetSet, 0 disables the pool.
+	SubnetPortPoolSize int `ini:"subnetport_pool_size"`
 }
 
 type K8sConfig struct {
@@ -139,6 +152,31 @@ type HAConfig struct {
 	EnableHA *bool `ini:"enable"`
 }
 
+// GetRuleStatisticsInterval returns the interval in seconds for collecting rule statistics.
+func (nsxConfig *NsxConfig) GetRuleStatisticsInterval() int {
+	if nsxConfig.RuleStatisticsInterval <= 0 {
+		return RuleStatisticsInterval
+	}
+	return nsxConfig.RuleStatisticsInterval
+}
+
+// GetRuleStatisticsRateLimit returns the max number of rule statistics requests sent to NSX per second.
+func (nsxConfig *NsxConfig) GetRuleStatisticsRateLimit() int {
+	if nsxConfig.RuleStatisticsRateLimit <= 0 {
+		return RuleStatisticsRateLimit
+	}
+	return nsxConfig.RuleStatisticsRateLimit
+}
+
+// GetIPUtilizationThreshold returns the percentage of IP utilization above which Subnet and SubnetSet are
+// marked with the IPUtilizationHigh condition.
+func (nsxConfig *NsxConfig) GetIPUtilizationThreshold() int {
+	if nsxConfig.IPUtilizationThreshold <= 0 || nsxConfig.IPUtilizationThreshold > 100 {
+		return IPUtilizationThreshold
+	}
+	return nsxConfig.IPUtilizationThreshold
+}
+
 type Validate interface {
 	validate() error
 }
@@ -151,6 +189,7 @@ func AddFlags() {
 	flag.StringVar(&configFilePath, "nsxconfig", nsxOperatorDefaultConf, "NSX Operator configuration file path")
 	flag.StringVar(&ProbeAddr, "health-probe-bind-address", ":8384", "The address the probe endpoint binds to.")
 	flag.StringVar(&MetricsAddr, "metrics-bind-address", ":8093", "The address the metrics endpoint binds to.")
+	flag.StringVar(&DebugAddr, "debug-bind-address", "", "The address the debug endpoint binds to. The debug endpoint is disabled if empty.")
 	flag.IntVar(&LogLevel, "log-level", 0, "Use zap-core log system.")
 	flag.IntVar(&WebhookServerPort, "webhook-server-port", defaultWebhookPort, "Port number to expose the controller webhook server")
 	flag.StringVar(&WebhookCertDir, "webhook-cert-dir", defaultWebhookCertPath, "Directory for certificate for webhook server")
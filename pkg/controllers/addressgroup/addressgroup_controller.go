@@ -0,0 +1,362 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package addressgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+var (
+	log                     = logger.Log
+	ResultNormal            = common.ResultNormal
+	ResultRequeue           = common.ResultRequeue
+	ResultRequeueAfter10sec = common.ResultRequeueAfter10sec
+	MetricResType           = common.MetricResTypeAddressGroup
+)
+
+// AddressGroupReconciler reconciles an AddressGroup object
+type AddressGroupReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Service  *securitypolicy.SecurityPolicyService
+	Recorder record.EventRecorder
+}
+
+func updateFail(r *AddressGroupReconciler, c *context.Context, o *v1alpha1.AddressGroup, e *error) {
+	r.setAddressGroupReadyStatusFalse(c, o, metav1.Now(), e)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, fmt.Sprintf("%v", *e))
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+}
+
+func deleteFail(r *AddressGroupReconciler, c *context.Context, o *v1alpha1.AddressGroup, e *error) {
+	r.setAddressGroupReadyStatusFalse(c, o, metav1.Now(), e)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, fmt.Sprintf("%v", *e))
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+}
+
+func updateSuccess(r *AddressGroupReconciler, c *context.Context, o *v1alpha1.AddressGroup, path string) {
+	o.Status.NSXResourcePath = path
+	r.setAddressGroupReadyStatusTrue(c, o, metav1.Now())
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulUpdate, "AddressGroup CR has been successfully updated")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+}
+
+func deleteSuccess(r *AddressGroupReconciler, _ *context.Context, o *v1alpha1.AddressGroup) {
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulDelete, "AddressGroup CR has been successfully deleted")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+}
+
+func (r *AddressGroupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := &v1alpha1.AddressGroup{}
+	log.Info("reconciling addressgroup CR", "addressgroup", req.NamespacedName)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "unable to fetch address group CR", "req", req.NamespacedName)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+
+	if obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+		if !controllerutil.ContainsFinalizer(obj, servicecommon.AddressGroupFinalizerName) {
+			controllerutil.AddFinalizer(obj, servicecommon.AddressGroupFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "add finalizer", "addressgroup", req.NamespacedName)
+				updateFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			log.V(1).Info("added finalizer on addressgroup CR", "addressgroup", req.NamespacedName)
+		}
+
+		path, err := r.Service.CreateOrUpdateAddressGroup(obj)
+		if err != nil {
+			if errors.As(err, &nsxutil.RestrictionError{}) {
+				log.Error(err, err.Error(), "addressgroup", req.NamespacedName)
+				updateFail(r, &ctx, obj, &err)
+				return ResultNormal, nil
+			}
+			log.Error(err, "create or update failed, would retry exponentially", "addressgroup", req.NamespacedName)
+			updateFail(r, &ctx, obj, &err)
+			return ResultRequeue, err
+		}
+		updateSuccess(r, &ctx, obj, path)
+	} else {
+		if controllerutil.ContainsFinalizer(obj, servicecommon.AddressGroupFinalizerName) {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			// The NSX group can't be deleted while the rules of SecurityPolicies still use it,
+			// keep the finalizer until the SecurityPolicies stop referencing the AddressGroup.
+			referrers, err := r.listReferringSecurityPolicies(ctx, obj)
+			if err != nil {
+				log.Error(err, "failed to list SecurityPolicies referencing addressgroup, would retry exponentially", "addressgroup", req.NamespacedName)
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			if len(referrers) > 0 {
+				err = fmt.Errorf("AddressGroup is referenced by SecurityPolicies %v", referrers)
+				log.Info("deletion blocked, would retry", "addressgroup", req.NamespacedName, "securitypolicies", referrers)
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeueAfter10sec, nil
+			}
+			if err := r.Service.DeleteAddressGroup(obj.UID); err != nil {
+				log.Error(err, "deletion failed, would retry exponentially", "addressgroup", req.NamespacedName)
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			controllerutil.RemoveFinalizer(obj, servicecommon.AddressGroupFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "deletion failed, would retry exponentially", "addressgroup", req.NamespacedName)
+				deleteFail(r, &ctx, obj, &err)
+				return ResultRequeue, err
+			}
+			log.V(1).Info("removed finalizer", "addressgroup", req.NamespacedName)
+			deleteSuccess(r, &ctx, obj)
+		} else {
+			// only print a message because it's not a normal case
+			log.Info("finalizers cannot be recognized", "addressgroup", req.NamespacedName)
+		}
+	}
+
+	return ResultNormal, nil
+}
+
+// listReferringSecurityPolicies returns the namespaced names of the SecurityPolicies whose rule peers reference the AddressGroup.
+func (r *AddressGroupReconciler) listReferringSecurityPolicies(ctx context.Context, addressGroup *v1alpha1.AddressGroup) ([]string, error) {
+	spList := &v1alpha1.SecurityPolicyList{}
+	if err := r.Client.List(ctx, spList); err != nil {
+		return nil, err
+	}
+	var referrers []string
+	for i := range spList.Items {
+		sp := &spList.Items[i]
+		if securitypolicy.ReferencesAddressGroup(sp, addressGroup.Namespace, addressGroup.Name) {
+			referrers = append(referrers, types.NamespacedName{Namespace: sp.Namespace, Name: sp.Name}.String())
+		}
+	}
+	return referrers, nil
+}
+
+func (r *AddressGroupReconciler) setAddressGroupReadyStatusTrue(ctx *context.Context, addressGroup *v1alpha1.AddressGroup, transitionTime metav1.Time) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionTrue,
+			Message:            "NSX group has been successfully created/updated",
+			Reason:             "NSX API returned 200 response code for PATCH",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateAddressGroupStatusConditions(ctx, addressGroup, newConditions)
+}
+
+func (r *AddressGroupReconciler) setAddressGroupReadyStatusFalse(ctx *context.Context, addressGroup *v1alpha1.AddressGroup, transitionTime metav1.Time, err *error) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:    v1alpha1.Ready,
+			Status:  v1.ConditionFalse,
+			Message: "NSX group could not be created/updated",
+			Reason: fmt.Sprintf(
+				"error occurred while processing the AddressGroup CR. Error: %v",
+				*err,
+			),
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateAddressGroupStatusConditions(ctx, addressGroup, newConditions)
+}
+
+// updateAddressGroupStatusConditions updates the status if the conditions or the NSX resource path are changed.
+func (r *AddressGroupReconciler) updateAddressGroupStatusConditions(ctx *context.Context, addressGroup *v1alpha1.AddressGroup, newConditions []v1alpha1.Condition) {
+	existing := &v1alpha1.AddressGroup{}
+	pathUpdated := false
+	if err := r.Client.Get(*ctx, types.NamespacedName{Namespace: addressGroup.Namespace, Name: addressGroup.Name}, existing); err == nil {
+		pathUpdated = existing.Status.NSXResourcePath != addressGroup.Status.NSXResourcePath
+	}
+	conditionsUpdated := false
+	for i := range newConditions {
+		if r.mergeAddressGroupStatusCondition(addressGroup, &newConditions[i]) {
+			conditionsUpdated = true
+		}
+	}
+	if conditionsUpdated || pathUpdated {
+		r.Client.Status().Update(*ctx, addressGroup)
+		log.V(1).Info("updated AddressGroup", "Name", addressGroup.Name, "Namespace", addressGroup.Namespace,
+			"New Conditions", newConditions, "NSXResourcePath", addressGroup.Status.NSXResourcePath)
+	}
+}
+
+func (r *AddressGroupReconciler) mergeAddressGroupStatusCondition(addressGroup *v1alpha1.AddressGroup, newCondition *v1alpha1.Condition) bool {
+	matchedCondition := getExistingConditionOfType(newCondition.Type, addressGroup.Status.Conditions)
+
+	if reflect.DeepEqual(matchedCondition, newCondition) {
+		log.V(2).Info("conditions already match", "New Condition", newCondition, "Existing Condition", matchedCondition)
+		return false
+	}
+
+	if matchedCondition != nil {
+		matchedCondition.Reason = newCondition.Reason
+		matchedCondition.Message = newCondition.Message
+		matchedCondition.Status = newCondition.Status
+	} else {
+		addressGroup.Status.Conditions = append(addressGroup.Status.Conditions, *newCondition)
+	}
+	return true
+}
+
+func getExistingConditionOfType(conditionType v1alpha1.ConditionType, existingConditions []v1alpha1.Condition) *v1alpha1.Condition {
+	for i := range existingConditions {
+		if existingConditions[i].Type == conditionType {
+			return &existingConditions[i]
+		}
+	}
+	return nil
+}
+
+// requestsForNamespace enqueues the AddressGroups with sharedNamespaceSelector when a Namespace is
+// created or its labels are changed, since the Namespace may be added to or removed from the share.
+func (r *AddressGroupReconciler) requestsForNamespace(ctx context.Context, _ client.Object) []reconcile.Request {
+	addressGroupList := &v1alpha1.AddressGroupList{}
+	if err := r.Client.List(ctx, addressGroupList); err != nil {
+		log.Error(err, "failed to list AddressGroup CR")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, addressGroup := range addressGroupList.Items {
+		if addressGroup.Spec.SharedNamespaceSelector != nil {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: addressGroup.Namespace, Name: addressGroup.Name}})
+		}
+	}
+	return requests
+}
+
+var predicateFuncsNs = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+func (r *AddressGroupReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.AddressGroup{}).
+		WithEventFilter(predicate.Funcs{
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				// Suppress Delete events to avoid filtering them out in the Reconcile function
+				return false
+			},
+		}).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Watches(
+			&v1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForNamespace),
+			builder.WithPredicates(predicateFuncsNs),
+		).
+		Complete(r)
+}
+
+// Start setup manager and launch GC
+func (r *AddressGroupReconciler) Start(mgr ctrl.Manager) error {
+	err := r.setupWithManager(mgr)
+	if err != nil {
+		return err
+	}
+
+	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	return nil
+}
+
+// GarbageCollector collect the NSX groups of AddressGroups which have been removed from crd.
+// cancel is used to break the loop during UT
+func (r *AddressGroupReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("address group garbage collector started")
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		nsxAddressGroupSet := r.Service.ListAddressGroupID()
+		if len(nsxAddressGroupSet) == 0 {
+			continue
+		}
+		addressGroupList := &v1alpha1.AddressGroupList{}
+		err := r.Client.List(ctx, addressGroupList)
+		if err != nil {
+			log.Error(err, "failed to list AddressGroup CR")
+			continue
+		}
+
+		crdAddressGroupSet := sets.New[string]()
+		for _, addressGroup := range addressGroupList.Items {
+			crdAddressGroupSet.Insert(string(addressGroup.UID))
+		}
+
+		for elem := range nsxAddressGroupSet {
+			if crdAddressGroupSet.Has(elem) {
+				continue
+			}
+			log.Info("GC collected AddressGroup CR", "UID", elem)
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			err = r.Service.DeleteAddressGroup(types.UID(elem))
+			if err != nil {
+				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+			} else {
+				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+			}
+		}
+	}
+}
+
+func StartAddressGroupController(mgr ctrl.Manager, commonService servicecommon.Service, vpcService servicecommon.VPCServiceProvider) {
+	addressGroupReconcile := AddressGroupReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("addressgroup-controller"),
+	}
+	addressGroupReconcile.Service = securitypolicy.GetSecurityService(commonService, vpcService)
+	if err := addressGroupReconcile.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "AddressGroup")
+		os.Exit(1)
+	}
+}
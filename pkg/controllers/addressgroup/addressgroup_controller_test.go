@@ -0,0 +1,183 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package addressgroup
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func newFakeReconciler(objs ...client.Object) *AddressGroupReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.AddressGroup{}).WithObjects(objs...).Build()
+	return &AddressGroupReconciler{
+		Client: fakeClient,
+		Scheme: scheme,
+		Service: &securitypolicy.SecurityPolicyService{
+			Service: common.Service{
+				Client: fakeClient,
+				NSXConfig: &config.NSXOperatorConfig{
+					NsxConfig: &config.NsxConfig{EnforcementPoint: "vmc-enforcementpoint"},
+					CoeConfig: &config.CoeConfig{Cluster: "k8scl-one"},
+				},
+			},
+		},
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestAddressGroupReconciler_Reconcile(t *testing.T) {
+	obj := &v1alpha1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "proxies", UID: "ag-uid"},
+		Spec: v1alpha1.AddressGroupSpec{
+			Members: []v1alpha1.AddressGroupMember{{IPBlocks: []v1alpha1.IPBlock{{CIDR: "192.168.10.0/24"}}}},
+		},
+	}
+	sp := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "egress"},
+		Spec: v1alpha1.SecurityPolicySpec{
+			Rules: []v1alpha1.SecurityPolicyRule{
+				{Destinations: []v1alpha1.SecurityPolicyPeer{{AddressGroupRef: &v1alpha1.AddressGroupReference{Name: "proxies"}}}},
+			},
+		},
+	}
+	r := newFakeReconciler(obj, sp)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "infra", Name: "proxies"}}
+
+	// Not found
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "infra", Name: "dummy"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// The NSX failure is retried.
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateAddressGroup", func(_ *securitypolicy.SecurityPolicyService, _ *v1alpha1.AddressGroup) (string, error) {
+		return "", errors.New("patch failed")
+	})
+	defer patches.Reset()
+	result, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, ResultRequeue, result)
+	updated := &v1alpha1.AddressGroup{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Contains(t, updated.Finalizers, common.AddressGroupFinalizerName)
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+
+	// The invalid spec is not retried.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateAddressGroup", func(_ *securitypolicy.SecurityPolicyService, _ *v1alpha1.AddressGroup) (string, error) {
+		return "", nsxutil.RestrictionError{Desc: "invalid member"}
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// The NSX group path is set in the status.
+	path := "/infra/domains/k8scl-one/groups/ag_ag-uid"
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateAddressGroup", func(_ *securitypolicy.SecurityPolicyService, _ *v1alpha1.AddressGroup) (string, error) {
+		return path, nil
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+	assert.Equal(t, path, updated.Status.NSXResourcePath)
+
+	// The deletion is blocked while the SecurityPolicy references the AddressGroup.
+	deleted := false
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteAddressGroup", func(_ *securitypolicy.SecurityPolicyService, _ types.UID) error {
+		deleted = true
+		return nil
+	})
+	assert.NoError(t, r.Client.Delete(ctx, updated))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultRequeueAfter10sec, result)
+	assert.False(t, deleted)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Contains(t, updated.Finalizers, common.AddressGroupFinalizerName)
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+	assert.Contains(t, updated.Status.Conditions[0].Reason, "infra/egress")
+
+	// Deletion fails.
+	assert.NoError(t, r.Client.Delete(ctx, sp))
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteAddressGroup", func(_ *securitypolicy.SecurityPolicyService, _ types.UID) error {
+		return errors.New("delete failed")
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, ResultRequeue, result)
+
+	// Deletion succeeds once the SecurityPolicy is removed.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteAddressGroup", func(_ *securitypolicy.SecurityPolicyService, uid types.UID) error {
+		assert.Equal(t, types.UID("ag-uid"), uid)
+		deleted = true
+		return nil
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.True(t, deleted)
+	assert.Error(t, r.Client.Get(ctx, req.NamespacedName, updated))
+}
+
+func TestAddressGroupReconciler_requestsForNamespace(t *testing.T) {
+	shared := &v1alpha1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "proxies"},
+		Spec: v1alpha1.AddressGroupSpec{
+			SharedNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+		},
+	}
+	local := &v1alpha1.AddressGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "local"}}
+	r := newFakeReconciler(shared, local)
+
+	requests := r.requestsForNamespace(context.TODO(), &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web"}})
+	assert.Len(t, requests, 1)
+	assert.Equal(t, types.NamespacedName{Namespace: "infra", Name: "proxies"}, requests[0].NamespacedName)
+}
+
+func TestAddressGroupReconciler_GarbageCollector(t *testing.T) {
+	obj := &v1alpha1.AddressGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "proxies", UID: "ag-uid-1"}}
+	r := newFakeReconciler(obj)
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "ListAddressGroupID", func(_ *securitypolicy.SecurityPolicyService) sets.Set[string] {
+		return sets.New[string]("ag-uid-1", "ag-uid-2")
+	})
+	defer patches.Reset()
+	deleted := sets.New[string]()
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteAddressGroup", func(_ *securitypolicy.SecurityPolicyService, uid types.UID) error {
+		deleted.Insert(string(uid))
+		return nil
+	})
+	cancel := make(chan bool)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		cancel <- true
+	}()
+	r.GarbageCollector(cancel, 100*time.Millisecond)
+	assert.Equal(t, []string{"ag-uid-2"}, deleted.UnsortedList())
+}
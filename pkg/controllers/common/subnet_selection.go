@@ -0,0 +1,223 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// reservedIPsPerSubnet is the count of IPs in a Subnet which can't be allocated to ports,
+// i.e. the network address, the gateway address and the broadcast address.
+const reservedIPsPerSubnet = 3
+
+var (
+	// subnetSetLocks holds a *SubnetSetLock for each SubnetSet UID, so that the allocations from
+	// different SubnetSets don't wait for each other. subnetSetLocksMu guards the map and the
+	// reference counts of the locks.
+	subnetSetLocks   = map[types.UID]*SubnetSetLock{}
+	subnetSetLocksMu sync.Mutex
+
+	subnetSelectors = map[v1alpha1.SubnetSelectionStrategy]SubnetSelector{
+		v1alpha1.SubnetSelectionFirstFit:      &firstFitSelector{},
+		v1alpha1.SubnetSelectionLeastUtilized: &leastUtilizedSelector{},
+		v1alpha1.SubnetSelectionSpread:        &spreadSelector{},
+		v1alpha1.SubnetSelectionNodeAffinity:  &nodeAffinitySelector{},
+	}
+)
+
+// SubnetCandidate is an NSX Subnet of the SubnetSet which has available IPs.
+type SubnetCandidate struct {
+	Subnet  *model.VpcSubnet
+	Ports   []*model.VpcSubnetPort
+	TotalIP int
+}
+
+// Utilization returns the ratio of the allocated IPs to the IPs which can be allocated.
+func (c *SubnetCandidate) Utilization() float64 {
+	return float64(len(c.Ports)) / float64(c.TotalIP-reservedIPsPerSubnet)
+}
+
+// SubnetSelector selects the Subnet for a new port from the candidates of the SubnetSet.
+// The candidates are sorted by path and are not empty. contextID is the ID of the node
+// transport node of the Pod, and is empty for the other ports.
+type SubnetSelector interface {
+	Select(subnetSet *v1alpha1.SubnetSet, candidates []*SubnetCandidate, contextID string) *SubnetCandidate
+}
+
+// GetSubnetSelector returns the SubnetSelector of the strategy, FirstFit is used if the strategy is unknown.
+func GetSubnetSelector(strategy v1alpha1.SubnetSelectionStrategy) SubnetSelector {
+	if selector, ok := subnetSelectors[strategy]; ok {
+		return selector
+	}
+	return subnetSelectors[v1alpha1.SubnetSelectionFirstFit]
+}
+
+// SubnetSetLock serializes the allocations and the reclaims of the Subnets of a SubnetSet.
+// refs counts the callers holding or waiting for the lock, the lock of a deleted SubnetSet
+// is removed when the last of them unlocks it. selectedAt records the last time each Subnet,
+// keyed by path, is allocated to a port, and is only accessed with the lock held.
+type SubnetSetLock struct {
+	mu         sync.Mutex
+	uid        types.UID
+	refs       int
+	removed    bool
+	selectedAt map[string]time.Time
+}
+
+// MarkSubnetSelected records that the Subnet is allocated to a new port at the time.
+func (l *SubnetSetLock) MarkSubnetSelected(path string, now time.Time) {
+	if l.selectedAt == nil {
+		l.selectedAt = map[string]time.Time{}
+	}
+	l.selectedAt[path] = now
+}
+
+// IsSubnetRecentlySelected returns true if the Subnet is allocated to a port within the grace period,
+// the port may not be created on it yet. The expired record is removed.
+func (l *SubnetSetLock) IsSubnetRecentlySelected(path string, now time.Time, gracePeriod time.Duration) bool {
+	selectedAt, ok := l.selectedAt[path]
+	if !ok {
+		return false
+	}
+	if now.Sub(selectedAt) < gracePeriod {
+		return true
+	}
+	delete(l.selectedAt, path)
+	return false
+}
+
+// LockSubnetSet locks the Subnets of the SubnetSet from being allocated or reclaimed, and returns the lock.
+func LockSubnetSet(uid types.UID) *SubnetSetLock {
+	subnetSetLocksMu.Lock()
+	lock, ok := subnetSetLocks[uid]
+	if !ok {
+		lock = &SubnetSetLock{uid: uid}
+		subnetSetLocks[uid] = lock
+	}
+	lock.refs++
+	subnetSetLocksMu.Unlock()
+
+	lock.mu.Lock()
+	return lock
+}
+
+// Unlock unlocks the SubnetSet, and removes the lock and the spread index of the SubnetSet if
+// the SubnetSet has been deleted and no one else holds or waits for the lock.
+func (l *SubnetSetLock) Unlock() {
+	l.mu.Unlock()
+
+	subnetSetLocksMu.Lock()
+	defer subnetSetLocksMu.Unlock()
+	l.refs--
+	if l.refs == 0 && l.removed {
+		delete(subnetSetLocks, l.uid)
+		spreadNext.Delete(l.uid)
+	}
+}
+
+// RemoveSubnetSetLock removes the allocation lock of the SubnetSet, it's called when the SubnetSet is deleted.
+// It waits for the ongoing allocation, and the lock is kept until the allocations waiting for it are done,
+// so that they are not run concurrently with a new lock of the same SubnetSet.
+func RemoveSubnetSetLock(uid types.UID) {
+	lock := LockSubnetSet(uid)
+	subnetSetLocksMu.Lock()
+	lock.removed = true
+	subnetSetLocksMu.Unlock()
+	lock.Unlock()
+}
+
+// listSubnetCandidates returns the Subnets of the SubnetSet with available IPs, including the imported
+// Subnets, sorted by path.
+func listSubnetCandidates(subnetSet *v1alpha1.SubnetSet, subnetService servicecommon.SubnetServiceProvider, subnetPortService servicecommon.SubnetPortServiceProvider) []*SubnetCandidate {
+	subnetList := subnetService.GetSubnetsByIndex(servicecommon.TagScopeSubnetSetCRUID, string(subnetSet.GetUID()))
+	subnetList = append(subnetList, subnetService.ListImportedSubnets(subnetSet)...)
+	var candidates []*SubnetCandidate
+	for _, nsxSubnet := range subnetList {
+		ports := subnetPortService.GetPortsOfSubnet(*nsxSubnet.Id)
+		totalIP := 0
+		if len(nsxSubnet.IpAddresses) > 0 {
+			// totalIP will be overrided if IpAddresses are specified.
+			totalIP, _ = util.CalculateIPFromCIDRs(nsxSubnet.IpAddresses)
+		} else if nsxSubnet.Ipv4SubnetSize != nil {
+			totalIP = int(*nsxSubnet.Ipv4SubnetSize)
+		}
+		if len(ports) < totalIP-reservedIPsPerSubnet {
+			candidates = append(candidates, &SubnetCandidate{Subnet: nsxSubnet, Ports: ports, TotalIP: totalIP})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return *candidates[i].Subnet.Path < *candidates[j].Subnet.Path
+	})
+	return candidates
+}
+
+type firstFitSelector struct{}
+
+func (s *firstFitSelector) Select(_ *v1alpha1.SubnetSet, candidates []*SubnetCandidate, _ string) *SubnetCandidate {
+	return candidates[0]
+}
+
+type leastUtilizedSelector struct{}
+
+func (s *leastUtilizedSelector) Select(_ *v1alpha1.SubnetSet, candidates []*SubnetCandidate, _ string) *SubnetCandidate {
+	selected := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.Utilization() < selected.Utilization() {
+			selected = candidate
+		}
+	}
+	return selected
+}
+
+// spreadNext holds the index of the next candidate for each SubnetSet UID.
+var spreadNext sync.Map
+
+type spreadSelector struct{}
+
+// Select selects the candidates in turn. It's called with the SubnetSet lock held, so the
+// index of the SubnetSet is not updated concurrently.
+func (s *spreadSelector) Select(subnetSet *v1alpha1.SubnetSet, candidates []*SubnetCandidate, _ string) *SubnetCandidate {
+	next := 0
+	if value, ok := spreadNext.Load(subnetSet.UID); ok {
+		next = value.(int)
+	}
+	selected := candidates[next%len(candidates)]
+	spreadNext.Store(subnetSet.UID, (next+1)%len(candidates))
+	return selected
+}
+
+type nodeAffinitySelector struct{}
+
+// Select selects the candidate with the most ports on the same node, so that the Pods on a node
+// share as few Subnets as possible. It falls back to FirstFit if no candidate has ports on the node.
+func (s *nodeAffinitySelector) Select(subnetSet *v1alpha1.SubnetSet, candidates []*SubnetCandidate, contextID string) *SubnetCandidate {
+	var selected *SubnetCandidate
+	maxCount := 0
+	if contextID != "" {
+		for _, candidate := range candidates {
+			count := 0
+			for _, port := range candidate.Ports {
+				if port.Attachment != nil && port.Attachment.ContextId != nil && *port.Attachment.ContextId == contextID {
+					count++
+				}
+			}
+			if count > maxCount {
+				selected, maxCount = candidate, count
+			}
+		}
+	}
+	if selected == nil {
+		return subnetSelectors[v1alpha1.SubnetSelectionFirstFit].Select(subnetSet, candidates, contextID)
+	}
+	return selected
+}
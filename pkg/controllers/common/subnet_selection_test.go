@@ -0,0 +1,260 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package common
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+type fakeVPCService struct {
+	servicecommon.VPCServiceProvider
+}
+
+func (f *fakeVPCService) ListVPCInfo(_ string) []servicecommon.VPCResourceInfo {
+	return []servicecommon.VPCResourceInfo{{OrgID: "default", ProjectID: "project", VPCID: "vpc"}}
+}
+
+func (f *fakeVPCService) GetVPCInfo(_ string, _ string) (servicecommon.VPCResourceInfo, bool) {
+	return servicecommon.VPCResourceInfo{OrgID: "default", ProjectID: "project", VPCID: "vpc"}, true
+}
+
+// fakeSubnetService keeps the NSX Subnets by SubnetSet UID, a new Subnet is created with 16 IPs.
+type fakeSubnetService struct {
+	mu      sync.Mutex
+	subnets map[string][]*model.VpcSubnet
+}
+
+func (f *fakeSubnetService) GetSubnetByKey(_ string) (*model.VpcSubnet, error) {
+	return nil, nil
+}
+
+func (f *fakeSubnetService) GetSubnetByPath(_ string) (*model.VpcSubnet, error) {
+	return nil, nil
+}
+
+func (f *fakeSubnetService) GetSubnetsByIndex(_, value string) []*model.VpcSubnet {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*model.VpcSubnet{}, f.subnets[value]...)
+}
+
+func (f *fakeSubnetService) ListImportedSubnets(_ *v1alpha1.SubnetSet) []*model.VpcSubnet {
+	return nil
+}
+
+func (f *fakeSubnetService) CreateOrUpdateSubnet(obj client.Object, _ servicecommon.VPCResourceInfo, _ []model.Tag) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	uid := string(obj.GetUID())
+	id := fmt.Sprintf("%s-%d", uid, len(f.subnets[uid]))
+	path := "/subnets/" + id
+	size := int64(16)
+	f.subnets[uid] = append(f.subnets[uid], &model.VpcSubnet{Id: &id, Path: &path, Ipv4SubnetSize: &size})
+	return path, nil
+}
+
+func (f *fakeSubnetService) GenerateSubnetNSTags(_ client.Object, _ string) []model.Tag {
+	return []model.Tag{}
+}
+
+// fakeSubnetPortService keeps the NSX SubnetPorts by Subnet ID.
+type fakeSubnetPortService struct {
+	mu    sync.Mutex
+	ports map[string][]*model.VpcSubnetPort
+}
+
+func (f *fakeSubnetPortService) GetPortsOfSubnet(nsxSubnetID string) []*model.VpcSubnetPort {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ports[nsxSubnetID]
+}
+
+func (f *fakeSubnetPortService) addPort(subnetPath, contextID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := subnetPath[len("/subnets/"):]
+	f.ports[id] = append(f.ports[id], &model.VpcSubnetPort{Attachment: &model.PortAttachment{ContextId: &contextID}})
+}
+
+func newSubnetCandidate(path string, ports int, totalIP int, contextIDs ...string) *SubnetCandidate {
+	candidate := &SubnetCandidate{Subnet: &model.VpcSubnet{Path: &path}, TotalIP: totalIP}
+	for i := 0; i < ports; i++ {
+		port := &model.VpcSubnetPort{Attachment: &model.PortAttachment{}}
+		if i < len(contextIDs) {
+			port.Attachment.ContextId = &contextIDs[i]
+		}
+		candidate.Ports = append(candidate.Ports, port)
+	}
+	return candidate
+}
+
+func TestSubnetSelectors(t *testing.T) {
+	subnetSet := &v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{UID: "subnetset-selectors"}}
+	defer RemoveSubnetSetLock(subnetSet.UID)
+	candidates := []*SubnetCandidate{
+		newSubnetCandidate("/subnets/a", 8, 16),
+		newSubnetCandidate("/subnets/b", 10, 64, "node-1", "node-1"),
+		newSubnetCandidate("/subnets/c", 2, 16, "node-2"),
+	}
+
+	selected := GetSubnetSelector(v1alpha1.SubnetSelectionFirstFit).Select(subnetSet, candidates, "")
+	assert.Equal(t, "/subnets/a", *selected.Subnet.Path)
+	selected = GetSubnetSelector("").Select(subnetSet, candidates, "")
+	assert.Equal(t, "/subnets/a", *selected.Subnet.Path)
+
+	selected = GetSubnetSelector(v1alpha1.SubnetSelectionLeastUtilized).Select(subnetSet, candidates, "")
+	assert.Equal(t, "/subnets/c", *selected.Subnet.Path)
+
+	var spread []string
+	for i := 0; i < 4; i++ {
+		spread = append(spread, *GetSubnetSelector(v1alpha1.SubnetSelectionSpread).Select(subnetSet, candidates, "").Subnet.Path)
+	}
+	assert.Equal(t, []string{"/subnets/a", "/subnets/b", "/subnets/c", "/subnets/a"}, spread)
+
+	nodeAffinity := GetSubnetSelector(v1alpha1.SubnetSelectionNodeAffinity)
+	assert.Equal(t, "/subnets/b", *nodeAffinity.Select(subnetSet, candidates, "node-1").Subnet.Path)
+	assert.Equal(t, "/subnets/c", *nodeAffinity.Select(subnetSet, candidates, "node-2").Subnet.Path)
+	assert.Equal(t, "/subnets/a", *nodeAffinity.Select(subnetSet, candidates, "node-3").Subnet.Path)
+	assert.Equal(t, "/subnets/a", *nodeAffinity.Select(subnetSet, candidates, "").Subnet.Path)
+}
+
+func TestAllocateSubnetFromSubnetSet(t *testing.T) {
+	subnetService := &fakeSubnetService{subnets: map[string][]*model.VpcSubnet{}}
+	subnetPortService := &fakeSubnetPortService{ports: map[string][]*model.VpcSubnetPort{}}
+	subnetSet := &v1alpha1.SubnetSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod-default", UID: "subnetset-allocate"},
+		Spec:       v1alpha1.SubnetSetSpec{SubnetSelectionStrategy: v1alpha1.SubnetSelectionLeastUtilized},
+	}
+	defer RemoveSubnetSetLock(subnetSet.UID)
+
+	// 13 ports fill a Subnet with 16 IPs, the 14th port creates a new Subnet.
+	for i := 0; i < 14; i++ {
+		path, err := AllocateSubnetFromSubnetSet(subnetSet, &fakeVPCService{}, subnetService, subnetPortService, "")
+		assert.NoError(t, err)
+		subnetPortService.addPort(path, "")
+	}
+	assert.Len(t, subnetService.subnets[string(subnetSet.UID)], 2)
+
+	// LeastUtilized selects the new Subnet until it has the same utilization.
+	path, err := AllocateSubnetFromSubnetSet(subnetSet, &fakeVPCService{}, subnetService, subnetPortService, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "/subnets/subnetset-allocate-1", path)
+
+	// The allocated Subnet is recorded for the compaction.
+	lock := LockSubnetSet(subnetSet.UID)
+	defer lock.Unlock()
+	assert.True(t, lock.IsSubnetRecentlySelected(path, time.Now(), time.Minute))
+	assert.False(t, lock.IsSubnetRecentlySelected(path, time.Now().Add(2*time.Minute), time.Minute))
+	assert.False(t, lock.IsSubnetRecentlySelected(path, time.Now(), time.Minute))
+}
+
+func TestRemoveSubnetSetLock(t *testing.T) {
+	subnetSet := &v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{UID: "subnetset-remove"}}
+	candidates := []*SubnetCandidate{newSubnetCandidate("/subnets/a", 0, 16), newSubnetCandidate("/subnets/b", 0, 16)}
+
+	held := LockSubnetSet(subnetSet.UID)
+	GetSubnetSelector(v1alpha1.SubnetSelectionSpread).Select(subnetSet, candidates, "")
+	removed := make(chan struct{})
+	go func() {
+		RemoveSubnetSetLock(subnetSet.UID)
+		close(removed)
+	}()
+	waiting := make(chan *SubnetSetLock)
+	go func() {
+		waiting <- LockSubnetSet(subnetSet.UID)
+	}()
+
+	// The removal waits for the allocation holding the lock.
+	select {
+	case <-removed:
+		t.Fatal("lock removed while it's held")
+	case <-time.After(100 * time.Millisecond):
+	}
+	held.Unlock()
+
+	// The waiting allocation gets the same lock, which is removed after both are done.
+	lock := <-waiting
+	assert.Same(t, held, lock)
+	subnetSetLocksMu.Lock()
+	assert.Contains(t, subnetSetLocks, subnetSet.UID)
+	subnetSetLocksMu.Unlock()
+	lock.Unlock()
+	<-removed
+
+	subnetSetLocksMu.Lock()
+	assert.NotContains(t, subnetSetLocks, subnetSet.UID)
+	subnetSetLocksMu.Unlock()
+	_, ok := spreadNext.Load(subnetSet.UID)
+	assert.False(t, ok)
+}
+
+func benchmarkAllocateSubnetFromSubnetSet(b *testing.B, strategy v1alpha1.SubnetSelectionStrategy) {
+	const subnetSetCount = 32
+	subnetService := &fakeSubnetService{subnets: map[string][]*model.VpcSubnet{}}
+	subnetPortService := &fakeSubnetPortService{ports: map[string][]*model.VpcSubnetPort{}}
+	var subnetSets []*v1alpha1.SubnetSet
+	for i := 0; i < subnetSetCount; i++ {
+		subnetSet := &v1alpha1.SubnetSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: fmt.Sprintf("ns%d", i), Name: "pod-default", UID: types.UID(fmt.Sprintf("%s-%d", strategy, i))},
+			Spec:       v1alpha1.SubnetSetSpec{SubnetSelectionStrategy: strategy},
+		}
+		subnetSets = append(subnetSets, subnetSet)
+		// Prepare some Subnets with free IPs in each SubnetSet.
+		for j := 0; j < 8; j++ {
+			path, _ := subnetService.CreateOrUpdateSubnet(subnetSet, servicecommon.VPCResourceInfo{}, nil)
+			for k := 0; k < j; k++ {
+				subnetPortService.addPort(path, fmt.Sprintf("node-%d", k%4))
+			}
+		}
+	}
+	b.Cleanup(func() {
+		for _, subnetSet := range subnetSets {
+			RemoveSubnetSetLock(subnetSet.UID)
+		}
+	})
+
+	var counter int
+	var counterLock sync.Mutex
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		counterLock.Lock()
+		subnetSet := subnetSets[counter%subnetSetCount]
+		contextID := fmt.Sprintf("node-%d", counter%4)
+		counter++
+		counterLock.Unlock()
+		for pb.Next() {
+			if _, err := AllocateSubnetFromSubnetSet(subnetSet, &fakeVPCService{}, subnetService, subnetPortService, contextID); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkAllocateSubnetFirstFit(b *testing.B) {
+	benchmarkAllocateSubnetFromSubnetSet(b, v1alpha1.SubnetSelectionFirstFit)
+}
+
+func BenchmarkAllocateSubnetLeastUtilized(b *testing.B) {
+	benchmarkAllocateSubnetFromSubnetSet(b, v1alpha1.SubnetSelectionLeastUtilized)
+}
+
+func BenchmarkAllocateSubnetSpread(b *testing.B) {
+	benchmarkAllocateSubnetFromSubnetSet(b, v1alpha1.SubnetSelectionSpread)
+}
+
+func BenchmarkAllocateSubnetNodeAffinity(b *testing.B) {
+	benchmarkAllocateSubnetFromSubnetSet(b, v1alpha1.SubnetSelectionNodeAffinity)
+}
@@ -7,20 +7,27 @@ import (
 )
 
 const (
-	MetricResTypeSecurityPolicy    = "securitypolicy"
-	MetricResTypeNetworkPolicy     = "networkpolicy"
-	MetricResTypeIPPool            = "ippool"
-	MetricResTypeNSXServiceAccount = "nsxserviceaccount"
-	MetricResTypeSubnetPort        = "subnetport"
-	MetricResTypeStaticRoute       = "staticroute"
-	MetricResTypeSubnet            = "subnet"
-	MetricResTypeSubnetSet         = "subnetset"
-	MetricResTypeVPC               = "vpc"
-	MetricResTypeNamespace         = "namespace"
-	MetricResTypePod               = "pod"
-	MetricResTypeNode              = "node"
-	MetricResTypeServiceLb         = "servicelb"
-	MaxConcurrentReconciles        = 8
+	MetricResTypeSecurityPolicy      = "securitypolicy"
+	MetricResTypeAddressGroup        = "addressgroup"
+	MetricResTypeNetworkPolicy       = "networkpolicy"
+	MetricResTypeIPPool              = "ippool"
+	MetricResTypeIPAddressAllocation = "ipaddressallocation"
+	MetricResTypeNSXServiceAccount   = "nsxserviceaccount"
+	MetricResTypeSubnetPort          = "subnetport"
+	MetricResTypeStaticRoute         = "staticroute"
+	MetricResTypeSubnet              = "subnet"
+	MetricResTypeSubnetSet           = "subnetset"
+	MetricResTypeVPC                 = "vpc"
+	MetricResTypeVPCPeering          = "vpcpeering"
+	MetricResTypeVPCNetworkConfig    = "vpcnetworkconfiguration"
+	MetricResTypeNamespace           = "namespace"
+	MetricResTypeNATRule             = "natrule"
+	MetricResTypeEgressIP            = "egressip"
+	MetricResTypeNetworkQuota        = "networkquota"
+	MetricResTypePod                 = "pod"
+	MetricResTypeNode                = "node"
+	MetricResTypeServiceLb           = "servicelb"
+	MaxConcurrentReconciles          = 8
 
 	LabelK8sMasterRole  = "node-role.kubernetes.io/master"
 	LabelK8sControlRole = "node-role.kubernetes.io/control-plane"
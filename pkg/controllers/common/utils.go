@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,42 +15,41 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
-	"github.com/vmware-tanzu/nsx-operator/pkg/util"
 )
 
 var (
-	log  = logger.Log
-	lock = &sync.Mutex{}
+	log = logger.Log
 )
 
-func AllocateSubnetFromSubnetSet(subnetSet *v1alpha1.SubnetSet, vpcService servicecommon.VPCServiceProvider, subnetService servicecommon.SubnetServiceProvider, subnetPortService servicecommon.SubnetPortServiceProvider) (string, error) {
-	// TODO: For now, this is a global lock. In the future, we need to narrow its scope down to improve the performance.
-	lock.Lock()
+// AllocateSubnetFromSubnetSet selects a Subnet with available IPs from the SubnetSet by the selection strategy
+// of the SubnetSet, or creates a new Subnet if there isn't one. contextID is the ID of the node transport node
+// of the Pod, it's empty for the ports not on a node.
+func AllocateSubnetFromSubnetSet(subnetSet *v1alpha1.SubnetSet, vpcService servicecommon.VPCServiceProvider, subnetService servicecommon.SubnetServiceProvider, subnetPortService servicecommon.SubnetPortServiceProvider, contextID string) (string, error) {
+	lock := LockSubnetSet(subnetSet.GetUID())
 	defer lock.Unlock()
-	subnetList := subnetService.GetSubnetsByIndex(servicecommon.TagScopeSubnetSetCRUID, string(subnetSet.GetUID()))
-	for _, nsxSubnet := range subnetList {
-		portNums := len(subnetPortService.GetPortsOfSubnet(*nsxSubnet.Id))
-		totalIP := int(*nsxSubnet.Ipv4SubnetSize)
-		if len(nsxSubnet.IpAddresses) > 0 {
-			// totalIP will be overrided if IpAddresses are specified.
-			totalIP, _ = util.CalculateIPFromCIDRs(nsxSubnet.IpAddresses)
-		}
-		if portNums < totalIP-3 {
-			return *nsxSubnet.Path, nil
-		}
+	candidates := listSubnetCandidates(subnetSet, subnetService, subnetPortService)
+	if len(candidates) > 0 {
+		selected := GetSubnetSelector(subnetSet.Spec.SubnetSelectionStrategy).Select(subnetSet, candidates, contextID)
+		lock.MarkSubnetSelected(*selected.Subnet.Path, time.Now())
+		return *selected.Subnet.Path, nil
 	}
 	tags := subnetService.GenerateSubnetNSTags(subnetSet, subnetSet.Namespace)
 	if tags == nil {
 		return "", errors.New("failed to generate subnet tags")
 	}
-	log.Info("the existing subnets are not available, creating new subnet", "subnetList", subnetList, "subnetSet.Name", subnetSet.Name, "subnetSet.Namespace", subnetSet.Namespace)
-	vpcInfoList := vpcService.ListVPCInfo(subnetSet.Namespace)
-	if len(vpcInfoList) == 0 {
-		err := errors.New("no VPC found")
+	log.Info("the existing subnets are not available, creating new subnet", "subnetSet.Name", subnetSet.Name, "subnetSet.Namespace", subnetSet.Namespace)
+	vpcInfo, found := vpcService.GetVPCInfo(subnetSet.Namespace, subnetSet.Spec.VPCName)
+	if !found {
+		err := fmt.Errorf("no VPC %q found", subnetSet.Spec.VPCName)
 		log.Error(err, "failed to allocate Subnet")
 		return "", err
 	}
-	return subnetService.CreateOrUpdateSubnet(subnetSet, vpcInfoList[0], tags)
+	path, err := subnetService.CreateOrUpdateSubnet(subnetSet, vpcInfo, tags)
+	if err != nil {
+		return "", err
+	}
+	lock.MarkSubnetSelected(path, time.Now())
+	return path, nil
 }
 
 func getSharedNamespaceAndVpcForNamespace(client k8sclient.Client, ctx context.Context, namespaceName string) (string, string, error) {
@@ -143,6 +142,42 @@ func GetVirtualMachineNameForSubnetPort(subnetPort *v1alpha1.SubnetPort) (string
 	return array[1], nil
 }
 
+// IsNamespaceNetworkReady returns true if the Namespace is annotated by the Namespace controller that its
+// network is ready.
+func IsNamespaceNetworkReady(namespace *v1.Namespace) bool {
+	return namespace.Annotations[servicecommon.AnnotationNetworkReady] == "true"
+}
+
+// HasPodSchedulingGate returns true if the Pod is gated until the network of its Namespace is ready.
+func HasPodSchedulingGate(pod *v1.Pod) bool {
+	for _, gate := range pod.Spec.SchedulingGates {
+		if gate.Name == servicecommon.PodSchedulingGateNetworkReady {
+			return true
+		}
+	}
+	return false
+}
+
+// RemovePodSchedulingGate removes the network ready scheduling gate from the Pod so that it can be scheduled.
+func RemovePodSchedulingGate(client k8sclient.Client, ctx context.Context, pod *v1.Pod) error {
+	if !HasPodSchedulingGate(pod) {
+		return nil
+	}
+	gates := []v1.PodSchedulingGate{}
+	for _, gate := range pod.Spec.SchedulingGates {
+		if gate.Name != servicecommon.PodSchedulingGateNetworkReady {
+			gates = append(gates, gate)
+		}
+	}
+	pod.Spec.SchedulingGates = gates
+	if err := client.Update(ctx, pod); err != nil {
+		log.Error(err, "failed to remove scheduling gate from pod", "pod.Name", pod.Name, "pod.Namespace", pod.Namespace)
+		return err
+	}
+	log.Info("removed scheduling gate from pod", "pod.Name", pod.Name, "pod.Namespace", pod.Namespace)
+	return nil
+}
+
 // NumReconcile now uses the fix number of concurrency
 func NumReconcile() int {
 	return MaxConcurrentReconciles
@@ -1,13 +1,18 @@
 package common
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 )
 
 func TestGetVirtualMachineNameForSubnetPort(t *testing.T) {
@@ -57,3 +62,29 @@ func TestGetVirtualMachineNameForSubnetPort(t *testing.T) {
 		}
 	}
 }
+
+func TestRemovePodSchedulingGate(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod1"},
+		Spec: v1.PodSpec{SchedulingGates: []v1.PodSchedulingGate{
+			{Name: "example.com/other"},
+			{Name: servicecommon.PodSchedulingGateNetworkReady},
+		}},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(pod).Build()
+	ctx := context.TODO()
+	assert.True(t, HasPodSchedulingGate(pod))
+	assert.NoError(t, RemovePodSchedulingGate(k8sClient, ctx, pod))
+
+	updated := &v1.Pod{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "pod1"}, updated))
+	assert.False(t, HasPodSchedulingGate(updated))
+	assert.Equal(t, []v1.PodSchedulingGate{{Name: "example.com/other"}}, updated.Spec.SchedulingGates)
+	// the Pod without the scheduling gate is not updated
+	assert.NoError(t, RemovePodSchedulingGate(k8sClient, ctx, updated))
+
+	assert.False(t, IsNamespaceNetworkReady(&v1.Namespace{}))
+	assert.True(t, IsNamespaceNetworkReady(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{servicecommon.AnnotationNetworkReady: "true"},
+	}}))
+}
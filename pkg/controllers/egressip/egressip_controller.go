@@ -0,0 +1,430 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package egressip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/egressip"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+var (
+	log                     = logger.Log
+	ResultNormal            = common.ResultNormal
+	ResultRequeue           = common.ResultRequeue
+	ResultRequeueAfter10sec = common.ResultRequeueAfter10sec
+	MetricResType           = common.MetricResTypeEgressIP
+)
+
+// EgressIPReconciler reconciles a EgressIP object
+type EgressIPReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Service  *egressip.EgressIPService
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=egressips,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=egressips/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=egressips/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+
+func (r *EgressIPReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := &v1alpha1.EgressIP{}
+	log.Info("reconciling egressip CR", "egressip", req.NamespacedName)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "unable to fetch egressip CR", "req", req.NamespacedName)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+
+	if obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+		if !controllerutil.ContainsFinalizer(obj, servicecommon.EgressIPFinalizerName) {
+			controllerutil.AddFinalizer(obj, servicecommon.EgressIPFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "add finalizer", "egressip", req.NamespacedName)
+				updateFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+			log.V(1).Info("added finalizer on egressip CR", "egressip", req.NamespacedName)
+		}
+
+		oldStatus := obj.Status.DeepCopy()
+		podIPs, err := r.listPodIPs(ctx, obj)
+		if err == nil {
+			err = r.checkOverlappedEgressIPs(ctx, obj, podIPs)
+		}
+		if err == nil {
+			err = r.Service.CreateOrUpdateEgressIP(obj, podIPs)
+		}
+		if err != nil {
+			if errors.As(err, &nsxutil.RestrictionError{}) {
+				log.Info("invalid egressip CR", "egressip", req.NamespacedName, "reason", err.Error())
+				// Remove the SNAT rule applied before the EgressIP became invalid.
+				if releaseErr := r.Service.ReleaseNATRule(obj); releaseErr != nil {
+					log.Error(releaseErr, "failed to release the SNAT rule, would retry exponentially", "egressip", req.NamespacedName)
+					updateFail(r, &ctx, obj, err.Error())
+					return ResultRequeue, releaseErr
+				}
+				if !reflect.DeepEqual(oldStatus, &obj.Status) {
+					if updateErr := r.Client.Status().Update(ctx, obj); updateErr != nil {
+						log.Error(updateErr, "failed to update egressip status", "egressip", req.NamespacedName)
+						return ResultRequeue, updateErr
+					}
+				}
+				updateFail(r, &ctx, obj, err.Error())
+				return ResultNormal, nil
+			}
+			log.Error(err, "operate failed, would retry exponentially", "egressip", req.NamespacedName)
+			updateFail(r, &ctx, obj, err.Error())
+			return ResultRequeue, err
+		}
+		if !reflect.DeepEqual(oldStatus, &obj.Status) {
+			// The conditions may not change, update the status explicitly.
+			if err := r.Client.Status().Update(ctx, obj); err != nil {
+				log.Error(err, "failed to update egressip status", "egressip", req.NamespacedName)
+				return ResultRequeue, err
+			}
+		}
+		updateSuccess(r, &ctx, obj)
+	} else {
+		if controllerutil.ContainsFinalizer(obj, servicecommon.EgressIPFinalizerName) {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := r.Service.DeleteEgressIP(obj.UID); err != nil {
+				log.Error(err, "deletion failed, would retry exponentially", "egressip", req.NamespacedName)
+				deleteFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+			controllerutil.RemoveFinalizer(obj, servicecommon.EgressIPFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "deletion failed, would retry exponentially", "egressip", req.NamespacedName)
+				deleteFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+			log.V(1).Info("removed finalizer", "egressip", req.NamespacedName)
+			deleteSuccess(r, &ctx, obj)
+		} else {
+			// only print a message because it's not a normal case
+			log.Info("finalizers cannot be recognized", "egressip", req.NamespacedName)
+		}
+	}
+	return ResultNormal, nil
+}
+
+func (r *EgressIPReconciler) setReadyStatusTrue(ctx *context.Context, obj *v1alpha1.EgressIP, transitionTime metav1.Time) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionTrue,
+			Message:            "NSX egress IP has been successfully created/updated",
+			Reason:             "EgressIPReady",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateStatusConditions(ctx, obj, newConditions)
+}
+
+func (r *EgressIPReconciler) setReadyStatusFalse(ctx *context.Context, obj *v1alpha1.EgressIP, transitionTime metav1.Time, msg string) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionFalse,
+			Message:            "NSX egress IP could not be created/updated/deleted",
+			Reason:             "EgressIPNotReady",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	if msg != "" {
+		newConditions[0].Message = msg
+	}
+	r.updateStatusConditions(ctx, obj, newConditions)
+}
+
+func (r *EgressIPReconciler) updateStatusConditions(ctx *context.Context, obj *v1alpha1.EgressIP, newConditions []v1alpha1.Condition) {
+	conditionsUpdated := false
+	for i := range newConditions {
+		if mergeStatusCondition(obj, &newConditions[i]) {
+			conditionsUpdated = true
+		}
+	}
+	if conditionsUpdated {
+		if err := r.Client.Status().Update(*ctx, obj); err != nil {
+			log.Error(err, "failed to update egressip status", "Name", obj.Name, "Namespace", obj.Namespace)
+		} else {
+			log.V(1).Info("updated egressip", "Name", obj.Name, "Namespace", obj.Namespace, "New Conditions", newConditions)
+		}
+	}
+}
+
+func mergeStatusCondition(obj *v1alpha1.EgressIP, newCondition *v1alpha1.Condition) bool {
+	for i := range obj.Status.Conditions {
+		matchedCondition := &obj.Status.Conditions[i]
+		if matchedCondition.Type != newCondition.Type {
+			continue
+		}
+		if reflect.DeepEqual(matchedCondition, newCondition) {
+			return false
+		}
+		matchedCondition.Reason = newCondition.Reason
+		matchedCondition.Message = newCondition.Message
+		matchedCondition.Status = newCondition.Status
+		return true
+	}
+	obj.Status.Conditions = append(obj.Status.Conditions, *newCondition)
+	return true
+}
+
+func updateFail(r *EgressIPReconciler, c *context.Context, o *v1alpha1.EgressIP, m string) {
+	r.setReadyStatusFalse(c, o, metav1.Now(), m)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, m)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+}
+
+func deleteFail(r *EgressIPReconciler, c *context.Context, o *v1alpha1.EgressIP, m string) {
+	r.setReadyStatusFalse(c, o, metav1.Now(), m)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, m)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+}
+
+func updateSuccess(r *EgressIPReconciler, c *context.Context, o *v1alpha1.EgressIP) {
+	r.setReadyStatusTrue(c, o, metav1.Now())
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulUpdate, "EgressIP CR has been successfully updated")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+}
+
+func deleteSuccess(r *EgressIPReconciler, _ *context.Context, o *v1alpha1.EgressIP) {
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulDelete, "EgressIP CR has been successfully deleted")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+}
+
+// isOlderEgressIP returns true if the EgressIP a is created before b, the one with the smaller name is
+// older if they are created at the same time.
+func isOlderEgressIP(a, b *v1alpha1.EgressIP) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// checkOverlappedEgressIPs returns a RestrictionError if any of the Pod IPs is selected by an older EgressIP
+// in the Namespace. The SNAT rules of EgressIP have the same sequence number and NSX doesn't define which
+// one is applied, so the oldest EgressIP selecting a Pod is applied and the newer ones are rejected.
+func (r *EgressIPReconciler) checkOverlappedEgressIPs(ctx context.Context, obj *v1alpha1.EgressIP, podIPs []string) error {
+	if len(podIPs) == 0 {
+		return nil
+	}
+	egressIPList := &v1alpha1.EgressIPList{}
+	if err := r.Client.List(ctx, egressIPList, client.InNamespace(obj.Namespace)); err != nil {
+		return err
+	}
+	podIPSet := sets.New[string](podIPs...)
+	for i := range egressIPList.Items {
+		other := &egressIPList.Items[i]
+		if other.UID == obj.UID || !other.DeletionTimestamp.IsZero() || !isOlderEgressIP(other, obj) {
+			continue
+		}
+		otherPodIPs, err := r.listPodIPs(ctx, other)
+		if err != nil {
+			if errors.As(err, &nsxutil.RestrictionError{}) {
+				// The older EgressIP is invalid and selects no Pod.
+				continue
+			}
+			return err
+		}
+		if overlapped := podIPSet.Intersection(sets.New[string](otherPodIPs...)); overlapped.Len() > 0 {
+			return nsxutil.RestrictionError{Desc: fmt.Sprintf("Pod IPs %v are already selected by the older EgressIP %s", sets.List(overlapped), other.Name)}
+		}
+	}
+	return nil
+}
+
+// listPodIPs returns the IPv4 addresses of the running Pods selected by the EgressIP CR.
+func (r *EgressIPReconciler) listPodIPs(ctx context.Context, obj *v1alpha1.EgressIP) ([]string, error) {
+	selector := labels.Everything()
+	if obj.Spec.PodSelector != nil {
+		s, err := metav1.LabelSelectorAsSelector(obj.Spec.PodSelector)
+		if err != nil {
+			return nil, nsxutil.RestrictionError{Desc: err.Error()}
+		}
+		selector = s
+	}
+	podList := &v1.PodList{}
+	if err := r.Client.List(ctx, podList, client.InNamespace(obj.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	podIPs := make([]string, 0)
+	for _, pod := range podList.Items {
+		if pod.Spec.HostNetwork || !pod.DeletionTimestamp.IsZero() ||
+			pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			if ip := net.ParseIP(podIP.IP); ip != nil && ip.To4() != nil {
+				podIPs = append(podIPs, podIP.IP)
+			}
+		}
+	}
+	return podIPs, nil
+}
+
+// requestsForNamespace enqueues the EgressIPs in the Namespace of the object, so that the SNAT rules are
+// updated when the Pods are added, removed or relabeled, and the EgressIPs rejected for the Pods selected
+// by an older EgressIP are retried when it's updated or removed.
+func (r *EgressIPReconciler) requestsForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	egressIPList := &v1alpha1.EgressIPList{}
+	if err := r.Client.List(ctx, egressIPList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "failed to list egressip CR")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, egressIP := range egressIPList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: egressIP.Namespace, Name: egressIP.Name}})
+	}
+	return requests
+}
+
+// predicatePod filters out the Pod updates which don't change the IPs or the labels.
+var predicatePod = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldPod, okOld := e.ObjectOld.(*v1.Pod)
+		newPod, okNew := e.ObjectNew.(*v1.Pod)
+		if !okOld || !okNew {
+			return true
+		}
+		return !reflect.DeepEqual(oldPod.Status.PodIPs, newPod.Status.PodIPs) ||
+			!reflect.DeepEqual(oldPod.Labels, newPod.Labels) ||
+			oldPod.Status.Phase != newPod.Status.Phase ||
+			oldPod.DeletionTimestamp.IsZero() != newPod.DeletionTimestamp.IsZero()
+	},
+}
+
+// predicateEgressIPChanged filters the EgressIP events which may change the Pods selected by it.
+var predicateEgressIPChanged = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+			e.ObjectOld.GetDeletionTimestamp().IsZero() != e.ObjectNew.GetDeletionTimestamp().IsZero()
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return true
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+func (r *EgressIPReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.EgressIP{},
+			builder.WithPredicates(predicate.Funcs{
+				DeleteFunc: func(e event.DeleteEvent) bool {
+					// Suppress Delete events to avoid filtering them out in the Reconcile function
+					return false
+				},
+			})).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Watches(
+			&v1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForNamespace),
+			builder.WithPredicates(predicatePod)).
+		Watches(
+			&v1alpha1.EgressIP{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForNamespace),
+			builder.WithPredicates(predicateEgressIPChanged)).
+		Complete(r)
+}
+
+// Start setup manager and launch GC
+func (r *EgressIPReconciler) Start(mgr ctrl.Manager) error {
+	if err := r.setupWithManager(mgr); err != nil {
+		return err
+	}
+	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	return nil
+}
+
+// GarbageCollector deletes the NSX resources of the EgressIP CRs which have been removed.
+// cancel is used to break the loop during UT
+func (r *EgressIPReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("egressip garbage collector started")
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		nsxEgressIPSet := r.Service.ListEgressIPID()
+		if len(nsxEgressIPSet) == 0 {
+			continue
+		}
+		egressIPList := &v1alpha1.EgressIPList{}
+		if err := r.Client.List(ctx, egressIPList); err != nil {
+			log.Error(err, "failed to list egressip CR")
+			continue
+		}
+		crEgressIPSet := sets.New[string]()
+		for _, egressIP := range egressIPList.Items {
+			crEgressIPSet.Insert(string(egressIP.UID))
+		}
+		for uid := range nsxEgressIPSet.Difference(crEgressIPSet) {
+			log.Info("GC collected egressip CR", "UID", uid)
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := r.Service.DeleteEgressIP(types.UID(uid)); err != nil {
+				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+			} else {
+				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+			}
+		}
+	}
+}
+
+func StartEgressIPController(mgr ctrl.Manager, egressIPService *egressip.EgressIPService) {
+	reconciler := &EgressIPReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Service:  egressIPService,
+		Recorder: mgr.GetEventRecorderFor("egressip-controller"),
+	}
+	if err := reconciler.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "EgressIP")
+		os.Exit(1)
+	}
+}
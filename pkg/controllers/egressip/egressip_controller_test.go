@@ -0,0 +1,235 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package egressip
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/egressip"
+)
+
+func newFakeReconciler(objs ...client.Object) *EgressIPReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.EgressIP{}).WithObjects(objs...).Build()
+	return &EgressIPReconciler{
+		Client: fakeClient,
+		Scheme: scheme,
+		Service: &egressip.EgressIPService{
+			Service: common.Service{
+				NSXConfig: &config.NSXOperatorConfig{
+					NsxConfig: &config.NsxConfig{EnforcementPoint: "vmc-enforcementpoint"},
+					CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test"},
+				},
+			},
+		},
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func newPod(name string, labels map[string]string, phase v1.PodPhase, ips ...string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: name, Labels: labels},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+	for _, ip := range ips {
+		pod.Status.PodIPs = append(pod.Status.PodIPs, v1.PodIP{IP: ip})
+	}
+	return pod
+}
+
+func TestEgressIPReconciler_Reconcile(t *testing.T) {
+	obj := &v1alpha1.EgressIP{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web", UID: "egressip-uid-1"},
+		Spec: v1alpha1.EgressIPSpec{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	}
+	web := map[string]string{"app": "web"}
+	r := newFakeReconciler(obj,
+		newPod("web-1", web, v1.PodRunning, "10.0.0.3", "fd00::3"),
+		newPod("web-2", web, v1.PodSucceeded, "10.0.0.4"),
+		newPod("db-1", map[string]string{"app": "db"}, v1.PodRunning, "10.0.0.5"),
+	)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "web"}}
+
+	// Not found
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "dummy"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// The NSX failure is retried.
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateEgressIP", func(_ *egressip.EgressIPService, _ *v1alpha1.EgressIP, _ []string) error {
+		return errors.New("patch failed")
+	})
+	defer patches.Reset()
+	result, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, ResultRequeue, result)
+	updated := &v1alpha1.EgressIP{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Contains(t, updated.Finalizers, common.EgressIPFinalizerName)
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+
+	// Only the IPv4 addresses of the running Pods selected are translated.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateEgressIP", func(_ *egressip.EgressIPService, obj *v1alpha1.EgressIP, podIPs []string) error {
+		assert.Equal(t, []string{"10.0.0.3"}, podIPs)
+		obj.Status.EgressIP = "192.168.0.1"
+		obj.Status.NATRulePath = "/orgs/default/projects/p1/vpcs/vpc1/nat/USER/nat-rules/egressip_egressip-uid-1"
+		return nil
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+	assert.Equal(t, "192.168.0.1", updated.Status.EgressIP)
+
+	// The status is updated while the EgressIP stays ready.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateEgressIP", func(_ *egressip.EgressIPService, obj *v1alpha1.EgressIP, _ []string) error {
+		obj.Status.NATRulePath = ""
+		return nil
+	})
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Empty(t, updated.Status.NATRulePath)
+
+	// The invalid Pod selector is not retried.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "ReleaseNATRule", func(_ *egressip.EgressIPService, obj *v1alpha1.EgressIP) error {
+		obj.Status.NATRulePath = ""
+		return nil
+	})
+	updated.Spec.PodSelector.MatchExpressions = []metav1.LabelSelectorRequirement{{Key: "tier", Operator: "Unknown"}}
+	assert.NoError(t, r.Client.Update(ctx, updated))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// The EgressIPs of the Namespace are enqueued for the Pod changes.
+	requests := r.requestsForNamespace(ctx, newPod("web-3", web, v1.PodRunning))
+	assert.Equal(t, req.NamespacedName, requests[0].NamespacedName)
+
+	// Deletion fails.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteEgressIP", func(_ *egressip.EgressIPService, _ types.UID) error {
+		return errors.New("delete failed")
+	})
+	assert.NoError(t, r.Client.Delete(ctx, updated))
+	_, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+
+	// Deletion succeeds.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteEgressIP", func(_ *egressip.EgressIPService, _ types.UID) error {
+		return nil
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.Error(t, r.Client.Get(ctx, req.NamespacedName, updated))
+}
+
+func TestEgressIPReconciler_OverlappedEgressIPs(t *testing.T) {
+	now := metav1.Now()
+	web := &v1alpha1.EgressIP{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web", UID: "egressip-uid-1", CreationTimestamp: now},
+		Spec:       v1alpha1.EgressIPSpec{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+	}
+	all := &v1alpha1.EgressIP{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "all", UID: "egressip-uid-2", CreationTimestamp: metav1.NewTime(now.Add(time.Minute))},
+		Status:     v1alpha1.EgressIPStatus{NATRulePath: "/orgs/default/projects/p1/vpcs/vpc1/nat/USER/nat-rules/egressip_egressip-uid-2"},
+	}
+	r := newFakeReconciler(web, all,
+		newPod("web-1", map[string]string{"app": "web"}, v1.PodRunning, "10.0.0.3"),
+		newPod("db-1", map[string]string{"app": "db"}, v1.PodRunning, "10.0.0.5"),
+	)
+	ctx := context.TODO()
+	applied := sets.New[string]()
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateEgressIP", func(_ *egressip.EgressIPService, obj *v1alpha1.EgressIP, _ []string) error {
+		applied.Insert(obj.Name)
+		return nil
+	})
+	defer patches.Reset()
+	released := sets.New[string]()
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "ReleaseNATRule", func(_ *egressip.EgressIPService, obj *v1alpha1.EgressIP) error {
+		released.Insert(obj.Name)
+		obj.Status.NATRulePath = ""
+		return nil
+	})
+
+	// The newer EgressIP is rejected and its SNAT rule is removed, whatever the reconcile order is.
+	for _, name := range []string{"all", "web"} {
+		result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: name}})
+		assert.NoError(t, err)
+		assert.Equal(t, ResultNormal, result)
+	}
+	assert.Equal(t, []string{"web"}, applied.UnsortedList())
+	assert.Equal(t, []string{"all"}, released.UnsortedList())
+	updated := &v1alpha1.EgressIP{}
+	assert.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "all"}, updated))
+	assert.Empty(t, updated.Status.NATRulePath)
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+	assert.Contains(t, updated.Status.Conditions[0].Message, "older EgressIP web")
+
+	// The newer EgressIP is applied once the older one doesn't select the Pod.
+	assert.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "web"}, updated))
+	updated.Spec.PodSelector.MatchLabels["app"] = "none"
+	assert.NoError(t, r.Client.Update(ctx, updated))
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "all"}})
+	assert.NoError(t, err)
+	assert.True(t, applied.Has("all"))
+}
+
+func TestPredicatePod(t *testing.T) {
+	oldPod := newPod("web-1", map[string]string{"app": "web"}, v1.PodPending)
+	newPod := oldPod.DeepCopy()
+	newPod.ResourceVersion = "2"
+	assert.False(t, predicatePod.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod}))
+	newPod.Status.PodIPs = []v1.PodIP{{IP: "10.0.0.3"}}
+	assert.True(t, predicatePod.Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod}))
+	assert.True(t, predicatePod.Delete(event.DeleteEvent{Object: oldPod}))
+}
+
+func TestEgressIPReconciler_GarbageCollector(t *testing.T) {
+	obj := &v1alpha1.EgressIP{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web", UID: "egressip-uid-1"}}
+	r := newFakeReconciler(obj)
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "ListEgressIPID", func(_ *egressip.EgressIPService) sets.Set[string] {
+		return sets.New[string]("egressip-uid-1", "egressip-uid-2")
+	})
+	defer patches.Reset()
+	deleted := sets.New[string]()
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteEgressIP", func(_ *egressip.EgressIPService, uid types.UID) error {
+		deleted.Insert(string(uid))
+		return nil
+	})
+	cancel := make(chan bool)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		cancel <- true
+	}()
+	r.GarbageCollector(cancel, 100*time.Millisecond)
+	assert.Equal(t, []string{"egressip-uid-2"}, deleted.UnsortedList())
+}
@@ -0,0 +1,283 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package ipaddressallocation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha2"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/ipaddressallocation"
+)
+
+var (
+	log                     = logger.Log
+	ResultNormal            = common.ResultNormal
+	ResultRequeue           = common.ResultRequeue
+	ResultRequeueAfter10sec = common.ResultRequeueAfter10sec
+	MetricResType           = common.MetricResTypeIPAddressAllocation
+)
+
+// IPAddressAllocationReconciler reconciles a IPAddressAllocation object
+type IPAddressAllocationReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Service  *ipaddressallocation.IPAddressAllocationService
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=ipaddressallocations,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=ipaddressallocations/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=ipaddressallocations/finalizers,verbs=update
+
+func (r *IPAddressAllocationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := &v1alpha1.IPAddressAllocation{}
+	log.Info("reconciling ipaddressallocation CR", "ipaddressallocation", req.NamespacedName)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "unable to fetch ipaddressallocation CR", "req", req.NamespacedName)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+
+	if obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+		if !controllerutil.ContainsFinalizer(obj, servicecommon.IPAddressAllocationFinalizerName) {
+			controllerutil.AddFinalizer(obj, servicecommon.IPAddressAllocationFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "add finalizer", "ipaddressallocation", req.NamespacedName)
+				updateFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+			log.V(1).Info("added finalizer on ipaddressallocation CR", "ipaddressallocation", req.NamespacedName)
+		}
+
+		var ipPool *v1alpha2.IPPool
+		if obj.Spec.IPPool != "" {
+			ipPool = &v1alpha2.IPPool{}
+			if err := r.Client.Get(ctx, types.NamespacedName{Namespace: obj.Namespace, Name: obj.Spec.IPPool}, ipPool); err != nil {
+				if apierrors.IsNotFound(err) {
+					log.Info("IPPool not found, would retry after 10 seconds", "ipaddressallocation", req.NamespacedName, "ippool", obj.Spec.IPPool)
+					updateFail(r, &ctx, obj, fmt.Sprintf("IPPool %s not found", obj.Spec.IPPool))
+					return ResultRequeueAfter10sec, nil
+				}
+				log.Error(err, "failed to get IPPool", "ipaddressallocation", req.NamespacedName)
+				updateFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+		}
+
+		allocationIPs, err := r.Service.CreateOrUpdateIPAddressAllocation(obj, ipPool)
+		if err != nil {
+			log.Error(err, "operate failed, would retry exponentially", "ipaddressallocation", req.NamespacedName)
+			updateFail(r, &ctx, obj, err.Error())
+			return ResultRequeue, err
+		}
+		obj.Status.AllocationIPs = allocationIPs
+		updateSuccess(r, &ctx, obj)
+	} else {
+		if controllerutil.ContainsFinalizer(obj, servicecommon.IPAddressAllocationFinalizerName) {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := r.Service.DeleteIPAddressAllocation(obj.UID); err != nil {
+				log.Error(err, "deletion failed, would retry exponentially", "ipaddressallocation", req.NamespacedName)
+				deleteFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+			controllerutil.RemoveFinalizer(obj, servicecommon.IPAddressAllocationFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "deletion failed, would retry exponentially", "ipaddressallocation", req.NamespacedName)
+				deleteFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+			log.V(1).Info("removed finalizer", "ipaddressallocation", req.NamespacedName)
+			deleteSuccess(r, &ctx, obj)
+		} else {
+			// only print a message because it's not a normal case
+			log.Info("finalizers cannot be recognized", "ipaddressallocation", req.NamespacedName)
+		}
+	}
+	return ResultNormal, nil
+}
+
+func (r *IPAddressAllocationReconciler) setReadyStatusTrue(ctx *context.Context, obj *v1alpha1.IPAddressAllocation, transitionTime metav1.Time) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionTrue,
+			Message:            "NSX IP address allocation has been successfully created/updated",
+			Reason:             "IPAddressAllocated",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateStatusConditions(ctx, obj, newConditions)
+}
+
+func (r *IPAddressAllocationReconciler) setReadyStatusFalse(ctx *context.Context, obj *v1alpha1.IPAddressAllocation, transitionTime metav1.Time, msg string) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionFalse,
+			Message:            "NSX IP address allocation could not be created/updated/deleted",
+			Reason:             "IPAddressNotAllocated",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	if msg != "" {
+		newConditions[0].Message = msg
+	}
+	r.updateStatusConditions(ctx, obj, newConditions)
+}
+
+func (r *IPAddressAllocationReconciler) updateStatusConditions(ctx *context.Context, obj *v1alpha1.IPAddressAllocation, newConditions []v1alpha1.Condition) {
+	conditionsUpdated := false
+	for i := range newConditions {
+		if mergeStatusCondition(obj, &newConditions[i]) {
+			conditionsUpdated = true
+		}
+	}
+	if conditionsUpdated {
+		if err := r.Client.Status().Update(*ctx, obj); err != nil {
+			log.Error(err, "failed to update ipaddressallocation status", "Name", obj.Name, "Namespace", obj.Namespace)
+		} else {
+			log.V(1).Info("updated ipaddressallocation", "Name", obj.Name, "Namespace", obj.Namespace, "New Conditions", newConditions)
+		}
+	}
+}
+
+func mergeStatusCondition(obj *v1alpha1.IPAddressAllocation, newCondition *v1alpha1.Condition) bool {
+	for i := range obj.Status.Conditions {
+		matchedCondition := &obj.Status.Conditions[i]
+		if matchedCondition.Type != newCondition.Type {
+			continue
+		}
+		if reflect.DeepEqual(matchedCondition, newCondition) {
+			return false
+		}
+		matchedCondition.Reason = newCondition.Reason
+		matchedCondition.Message = newCondition.Message
+		matchedCondition.Status = newCondition.Status
+		return true
+	}
+	obj.Status.Conditions = append(obj.Status.Conditions, *newCondition)
+	return true
+}
+
+func updateFail(r *IPAddressAllocationReconciler, c *context.Context, o *v1alpha1.IPAddressAllocation, m string) {
+	r.setReadyStatusFalse(c, o, metav1.Now(), m)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, m)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+}
+
+func deleteFail(r *IPAddressAllocationReconciler, c *context.Context, o *v1alpha1.IPAddressAllocation, m string) {
+	r.setReadyStatusFalse(c, o, metav1.Now(), m)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, m)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+}
+
+func updateSuccess(r *IPAddressAllocationReconciler, c *context.Context, o *v1alpha1.IPAddressAllocation) {
+	r.setReadyStatusTrue(c, o, metav1.Now())
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulUpdate, "IPAddressAllocation CR has been successfully updated")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+}
+
+func deleteSuccess(r *IPAddressAllocationReconciler, _ *context.Context, o *v1alpha1.IPAddressAllocation) {
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulDelete, "IPAddressAllocation CR has been successfully deleted")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+}
+
+func (r *IPAddressAllocationReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.IPAddressAllocation{}).
+		WithEventFilter(predicate.Funcs{
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				// Suppress Delete events to avoid filtering them out in the Reconcile function
+				return false
+			},
+		}).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Complete(r)
+}
+
+// Start setup manager and launch GC
+func (r *IPAddressAllocationReconciler) Start(mgr ctrl.Manager) error {
+	if err := r.setupWithManager(mgr); err != nil {
+		return err
+	}
+	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	return nil
+}
+
+// GarbageCollector releases the IPs of the IPAddressAllocation CRs which have been removed.
+// cancel is used to break the loop during UT
+func (r *IPAddressAllocationReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("ipaddressallocation garbage collector started")
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		nsxAllocationSet := r.Service.ListIPAddressAllocationID()
+		if len(nsxAllocationSet) == 0 {
+			continue
+		}
+		allocationList := &v1alpha1.IPAddressAllocationList{}
+		if err := r.Client.List(ctx, allocationList); err != nil {
+			log.Error(err, "failed to list ipaddressallocation CR")
+			continue
+		}
+		crAllocationSet := sets.New[string]()
+		for _, allocation := range allocationList.Items {
+			crAllocationSet.Insert(string(allocation.UID))
+		}
+		for uid := range nsxAllocationSet.Difference(crAllocationSet) {
+			log.Info("GC collected ipaddressallocation CR", "UID", uid)
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := r.Service.DeleteIPAddressAllocation(types.UID(uid)); err != nil {
+				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+			} else {
+				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+			}
+		}
+	}
+}
+
+func StartIPAddressAllocationController(mgr ctrl.Manager, ipAddressAllocationService *ipaddressallocation.IPAddressAllocationService) {
+	reconciler := &IPAddressAllocationReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Service:  ipAddressAllocationService,
+		Recorder: mgr.GetEventRecorderFor("ipaddressallocation-controller"),
+	}
+	if err := reconciler.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "IPAddressAllocation")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,139 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package ipaddressallocation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha2"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/ipaddressallocation"
+)
+
+func newFakeReconciler(objs ...*v1alpha1.IPAddressAllocation) *IPAddressAllocationReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = v1alpha2.AddToScheme(scheme)
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.IPAddressAllocation{})
+	for _, obj := range objs {
+		builder = builder.WithObjects(obj)
+	}
+	return &IPAddressAllocationReconciler{
+		Client: builder.Build(),
+		Scheme: scheme,
+		Service: &ipaddressallocation.IPAddressAllocationService{
+			Service: common.Service{
+				NSXConfig: &config.NSXOperatorConfig{
+					NsxConfig: &config.NsxConfig{EnforcementPoint: "vmc-enforcementpoint"},
+					CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test"},
+				},
+			},
+		},
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestIPAddressAllocationReconciler_Reconcile(t *testing.T) {
+	obj := &v1alpha1.IPAddressAllocation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "ipa1", UID: "ipa-uid-1"},
+		Spec:       v1alpha1.IPAddressAllocationSpec{IPPool: "pool1", AllocationSize: 1},
+	}
+	r := newFakeReconciler(obj)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "ipa1"}}
+
+	// Not found
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "dummy"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// The IPPool doesn't exist.
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultRequeueAfter10sec, result)
+	updated := &v1alpha1.IPAddressAllocation{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Contains(t, updated.Finalizers, common.IPAddressAllocationFinalizerName)
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+
+	// Allocation succeeds.
+	assert.NoError(t, r.Client.Create(ctx, &v1alpha2.IPPool{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pool1"}}))
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateIPAddressAllocation", func(_ *ipaddressallocation.IPAddressAllocationService, _ *v1alpha1.IPAddressAllocation, ipPool *v1alpha2.IPPool) ([]string, error) {
+		assert.Equal(t, "pool1", ipPool.Name)
+		return []string{"172.16.0.1"}, nil
+	})
+	result, err = r.Reconcile(ctx, req)
+	patches.Reset()
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, []string{"172.16.0.1"}, updated.Status.AllocationIPs)
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+
+	// Allocation fails.
+	patches = gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateIPAddressAllocation", func(_ *ipaddressallocation.IPAddressAllocationService, _ *v1alpha1.IPAddressAllocation, _ *v1alpha2.IPPool) ([]string, error) {
+		return nil, errors.New("no available IP")
+	})
+	result, err = r.Reconcile(ctx, req)
+	patches.Reset()
+	assert.Error(t, err)
+	assert.Equal(t, ResultRequeue, result)
+
+	// Deletion releases the IPs and removes the finalizer.
+	deleted := false
+	patches = gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "DeleteIPAddressAllocation", func(_ *ipaddressallocation.IPAddressAllocationService, uid types.UID) error {
+		assert.Equal(t, obj.UID, uid)
+		deleted = true
+		return nil
+	})
+	defer patches.Reset()
+	assert.NoError(t, r.Client.Delete(ctx, updated))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.True(t, deleted)
+	assert.Error(t, r.Client.Get(ctx, req.NamespacedName, updated))
+}
+
+func TestIPAddressAllocationReconciler_GarbageCollector(t *testing.T) {
+	r := newFakeReconciler(&v1alpha1.IPAddressAllocation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "ipa1", UID: "ipa-uid-1"},
+	})
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "ListIPAddressAllocationID", func(_ *ipaddressallocation.IPAddressAllocationService) sets.Set[string] {
+		return sets.New[string]("ipa-uid-1", "ipa-uid-2")
+	})
+	defer patches.Reset()
+	var deletedUIDs []types.UID
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteIPAddressAllocation", func(_ *ipaddressallocation.IPAddressAllocationService, uid types.UID) error {
+		deletedUIDs = append(deletedUIDs, uid)
+		return nil
+	})
+
+	cancel := make(chan bool)
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		cancel <- true
+	}()
+	r.GarbageCollector(cancel, 100*time.Millisecond)
+	assert.Equal(t, []types.UID{"ipa-uid-2"}, deletedUIDs)
+}
@@ -22,6 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha2"
@@ -255,11 +256,19 @@ func (r *IPPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 // Start setup manager and launch GC
-func (r *IPPoolReconciler) Start(mgr ctrl.Manager) error {
+func (r *IPPoolReconciler) Start(mgr ctrl.Manager, enableWebhook bool) error {
 	err := r.SetupWithManager(mgr)
 	if err != nil {
 		return err
 	}
+	if enableWebhook {
+		// The conversion webhook converts the IPPool between v1alpha1 and the hub version v1alpha2.
+		mgr.GetWebhookServer().Register("/convert", conversion.NewWebhookHandler(mgr.GetScheme()))
+		// The IPPools are rewritten in the storage version only if they could be converted.
+		if err := mgr.Add(&StorageVersionMigrator{Client: mgr.GetClient(), APIReader: mgr.GetAPIReader()}); err != nil {
+			return err
+		}
+	}
 	go r.IPPoolGarbageCollector(make(chan bool), servicecommon.GCInterval)
 	return nil
 }
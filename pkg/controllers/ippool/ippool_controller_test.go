@@ -321,6 +321,6 @@ func TestReconciler_Start(t *testing.T) {
 		Scheme:  nil,
 		Service: service,
 	}
-	err := r.Start(mgr)
+	err := r.Start(mgr, false)
 	assert.NotEqual(t, err, nil)
 }
@@ -0,0 +1,95 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package ippool
+
+import (
+	"context"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha2"
+)
+
+const ipPoolCRDName = "ippools.nsx.vmware.com"
+
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=update
+
+// StorageVersionMigrator rewrites the IPPools stored in v1alpha1 to the storage version v1alpha2, then removes
+// v1alpha1 from the stored versions of the IPPool CRD, so that v1alpha1 could be dropped from the CRD later.
+// It runs once after the manager is elected as the leader, and only when the IPPool conversion webhook is registered.
+type StorageVersionMigrator struct {
+	Client client.Client
+	// APIReader reads from the API server directly, as the CRDs are not cached by the manager.
+	APIReader client.Reader
+}
+
+// Start implements manager.Runnable. The failure is only logged so that the manager keeps running, the
+// migration is retried when nsx-operator restarts.
+func (m *StorageVersionMigrator) Start(ctx context.Context) error {
+	if err := m.Migrate(ctx); err != nil {
+		log.Error(err, "failed to migrate the storage version of IPPool")
+	}
+	return nil
+}
+
+// Migrate rewrites all the IPPools if the IPPool CRD has stored versions other than the storage version.
+// The IPPools are not rewritten unless the CRD converts them by the webhook, otherwise the fields only
+// in v1alpha1 would be dropped when the IPPools stored in v1alpha1 are rewritten.
+func (m *StorageVersionMigrator) Migrate(ctx context.Context) error {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := m.APIReader.Get(ctx, types.NamespacedName{Name: ipPoolCRDName}, crd); err != nil {
+		return err
+	}
+	storageVersion := v1alpha2.GroupVersion.Version
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == storageVersion {
+		return nil
+	}
+	if crd.Spec.Conversion == nil || crd.Spec.Conversion.Strategy != apiextensionsv1.WebhookConverter {
+		log.Info("skip migrating the storage version of IPPool as the conversion webhook is not set in the CRD", "storedVersions", crd.Status.StoredVersions)
+		return nil
+	}
+	log.Info("migrating the storage version of IPPool", "storedVersions", crd.Status.StoredVersions, "storageVersion", storageVersion)
+
+	ipPoolList := &v1alpha2.IPPoolList{}
+	if err := m.APIReader.List(ctx, ipPoolList); err != nil {
+		return err
+	}
+	for i := range ipPoolList.Items {
+		if err := m.rewriteIPPool(ctx, &ipPoolList.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := m.APIReader.Get(ctx, types.NamespacedName{Name: ipPoolCRDName}, crd); err != nil {
+			return err
+		}
+		crd.Status.StoredVersions = []string{storageVersion}
+		return m.Client.Status().Update(ctx, crd)
+	})
+}
+
+// rewriteIPPool updates the IPPool without change, the API server writes it in the storage version.
+func (m *StorageVersionMigrator) rewriteIPPool(ctx context.Context, ipPool *v1alpha2.IPPool) error {
+	key := types.NamespacedName{Namespace: ipPool.Namespace, Name: ipPool.Name}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &v1alpha2.IPPool{}
+		if err := m.APIReader.Get(ctx, key, latest); err != nil {
+			return err
+		}
+		return m.Client.Update(ctx, latest)
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err == nil {
+		log.V(1).Info("migrated IPPool", "IPPool", key)
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package ippool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha2"
+)
+
+func TestStorageVersionMigrator_Migrate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = v1alpha2.AddToScheme(scheme)
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: ipPoolCRDName},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Conversion: &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1alpha1", "v1alpha2"}},
+	}
+	ipPool1 := &v1alpha2.IPPool{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pool1"}}
+	ipPool2 := &v1alpha2.IPPool{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "pool2"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(crd).WithObjects(crd, ipPool1, ipPool2).Build()
+	m := &StorageVersionMigrator{Client: fakeClient, APIReader: fakeClient}
+	ctx := context.TODO()
+
+	resourceVersions := map[types.NamespacedName]string{}
+	for _, ipPool := range []*v1alpha2.IPPool{ipPool1, ipPool2} {
+		key := types.NamespacedName{Namespace: ipPool.Namespace, Name: ipPool.Name}
+		existing := &v1alpha2.IPPool{}
+		assert.NoError(t, fakeClient.Get(ctx, key, existing))
+		resourceVersions[key] = existing.ResourceVersion
+	}
+
+	// The IPPools are not rewritten without the conversion webhook.
+	assert.NoError(t, m.Migrate(ctx))
+	for key, resourceVersion := range resourceVersions {
+		existing := &v1alpha2.IPPool{}
+		assert.NoError(t, fakeClient.Get(ctx, key, existing))
+		assert.Equal(t, resourceVersion, existing.ResourceVersion)
+	}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: ipPoolCRDName}, crd))
+	assert.Equal(t, []string{"v1alpha1", "v1alpha2"}, crd.Status.StoredVersions)
+
+	crd.Spec.Conversion.Strategy = apiextensionsv1.WebhookConverter
+	assert.NoError(t, fakeClient.Update(ctx, crd))
+	assert.NoError(t, m.Migrate(ctx))
+	// The IPPools are rewritten.
+	for key, resourceVersion := range resourceVersions {
+		migrated := &v1alpha2.IPPool{}
+		assert.NoError(t, fakeClient.Get(ctx, key, migrated))
+		assert.NotEqual(t, resourceVersion, migrated.ResourceVersion)
+		resourceVersions[key] = migrated.ResourceVersion
+	}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: ipPoolCRDName}, crd))
+	assert.Equal(t, []string{"v1alpha2"}, crd.Status.StoredVersions)
+
+	// The IPPools are not rewritten again once the migration is done.
+	assert.NoError(t, m.Migrate(ctx))
+	for key, resourceVersion := range resourceVersions {
+		migrated := &v1alpha2.IPPool{}
+		assert.NoError(t, fakeClient.Get(ctx, key, migrated))
+		assert.Equal(t, resourceVersion, migrated.ResourceVersion)
+	}
+
+	// The failure is not returned by Start so that the manager keeps running.
+	m = &StorageVersionMigrator{Client: fakeClient, APIReader: fake.NewClientBuilder().WithScheme(scheme).Build()}
+	assert.Error(t, m.Migrate(ctx))
+	assert.NoError(t, m.Start(ctx))
+}
@@ -6,8 +6,8 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 )
 
-func BuildVPCCR(ns string, ncName string, vpcName *string) *v1alpha1.VPC {
-	log.V(2).Info("building vpc", "ns", ns, "nc", ncName, "VPC", vpcName)
+func BuildVPCCR(ns string, ncName string, vpcName *string, nsxVPCPath string) *v1alpha1.VPC {
+	log.V(2).Info("building vpc", "ns", ns, "nc", ncName, "VPC", vpcName, "NSXVPCPath", nsxVPCPath)
 	vpc := &v1alpha1.VPC{}
 	if vpcName == nil {
 		vpc.Name = "vpc-" + uuid.New().String()
@@ -16,5 +16,6 @@ func BuildVPCCR(ns string, ncName string, vpcName *string) *v1alpha1.VPC {
 	}
 
 	vpc.Namespace = ns
+	vpc.Spec.NSXVPCPath = nsxVPCPath
 	return vpc
 }
@@ -10,17 +10,19 @@ import (
 func TestBuildVPCCR(t *testing.T) {
 	vpcName := "fake-vpc"
 	tests := []struct {
-		name    string
-		ns      string
-		nc      string
-		vpcName *string
+		name       string
+		ns         string
+		nc         string
+		vpcName    *string
+		nsxVPCPath string
 	}{
-		{"1", "test-ns1", "test-nc1", nil},
-		{"2", "test-ns2", "test-nc2", &vpcName},
+		{"1", "test-ns1", "test-nc1", nil, ""},
+		{"2", "test-ns2", "test-nc2", &vpcName, ""},
+		{"3", "test-ns3", "test-nc3", nil, "/orgs/default/projects/p1/vpcs/vpc1"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			vpc := BuildVPCCR(tt.ns, tt.nc, tt.vpcName)
+			vpc := BuildVPCCR(tt.ns, tt.nc, tt.vpcName, tt.nsxVPCPath)
 			if tt.vpcName == nil {
 				assert.True(t, strings.Contains(vpc.Name, "vpc-"))
 			} else {
@@ -28,6 +30,7 @@ func TestBuildVPCCR(t *testing.T) {
 			}
 
 			assert.Equal(t, tt.ns, vpc.Namespace)
+			assert.Equal(t, tt.nsxVPCPath, vpc.Spec.NSXVPCPath)
 		})
 	}
 }
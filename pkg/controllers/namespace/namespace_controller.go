@@ -14,8 +14,11 @@ import (
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/config"
@@ -51,14 +54,21 @@ func (r *NamespaceReconciler) getDefaultNetworkConfigName() (string, error) {
 	return nc.Name, nil
 }
 
-func (r *NamespaceReconciler) createVPCCR(ctx *context.Context, obj client.Object, ns string, ncName string, vpcName *string) (*v1alpha1.VPC, error) {
+func (r *NamespaceReconciler) createVPCCR(ctx *context.Context, obj client.Object, ns string, ncName string, vpcName *string, nsxVPCPath string) (*v1alpha1.VPC, error) {
 	// check if vpc cr already exist under this namespace
 	vpcs := &v1alpha1.VPCList{}
 	r.Client.List(*ctx, vpcs, client.InNamespace(ns))
 	if len(vpcs.Items) > 0 {
-		// if there is already one vpc exist under this ns, return this vpc.
-		log.Info("vpc cr already exist, skip creating", "VPC", vpcs.Items[0].Name)
-		return &vpcs.Items[0], nil
+		// if there are already vpcs exist under this ns, return the default one, which is the
+		// first vpc created in the ns.
+		defaultVPC := &vpcs.Items[0]
+		for i := range vpcs.Items {
+			if vpcs.Items[i].CreationTimestamp.Before(&defaultVPC.CreationTimestamp) {
+				defaultVPC = &vpcs.Items[i]
+			}
+		}
+		log.Info("vpc cr already exist, skip creating", "VPC", defaultVPC.Name)
+		return defaultVPC, nil
 	}
 	nc, ncExist := r.VPCService.GetVPCNetworkConfig(ncName)
 	if !ncExist {
@@ -66,7 +76,8 @@ func (r *NamespaceReconciler) createVPCCR(ctx *context.Context, obj client.Objec
 		r.namespaceError(ctx, obj, message, nil)
 		return nil, errors.New(message)
 	}
-	if !r.VPCService.ValidateNetworkConfig(nc) {
+	// the private cidrs of the network config are not used by the adopted NSX VPC
+	if nsxVPCPath == "" && !r.VPCService.ValidateNetworkConfig(nc) {
 		// if network config is not valid, no need to retry, skip processing
 		message := fmt.Sprintf("invalid network config %s for namespace %s, missing private cidr", ncName, ns)
 		r.namespaceError(ctx, obj, message, nil)
@@ -74,7 +85,7 @@ func (r *NamespaceReconciler) createVPCCR(ctx *context.Context, obj client.Objec
 	}
 
 	// create vpc cr with existing vpc network config
-	vpcCR := BuildVPCCR(ns, ncName, vpcName)
+	vpcCR := BuildVPCCR(ns, ncName, vpcName, nsxVPCPath)
 	err := r.Client.Create(*ctx, vpcCR)
 	if err != nil {
 		message := "failed to create VPC CR"
@@ -187,6 +198,13 @@ We suppose namespace should have following annotations:
     VPC will locate the network config with the CR name, and create VPC using its config.
   - If the ns do not have either of the annotation above, then we believe it is using default VPC, try to search
     default VPC in network config CR store. The default VPC network config CR's name is "default".
+  - "nsx.vmware.com/nsx_vpc_path": "<NSX VPC Path>"
+    If the ns contains this annotation, the VPC CR adopts the pre-provisioned NSX VPC instead of creating
+    a new one, the NSX VPC is never deleted by the operator.
+
+When the VPC and the default SubnetSets are ready, the NetworkReady condition of the VPC CR is set to True,
+the ns is annotated with "nsx.vmware.com/network_ready": "true", and the scheduling gate
+"nsx.vmware.com/network-ready" is removed from the Pods of the ns.
 */
 func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	obj := &v1.Namespace{}
@@ -231,7 +249,7 @@ func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 			if ns != res[0] {
 				log.Info("name space is using shared vpc, with vpc name anno", "VPCNAME", vpcName, "Namespace", ns)
-				return common.ResultNormal, nil
+				return r.updateSharedNetworkReady(ctx, obj, res[0], res[1])
 			}
 			createVpcName = &res[1]
 			log.Info("creating vpc using customer defined vpc name", "VPCName", res[1])
@@ -247,13 +265,15 @@ func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			}
 		}
 
-		if _, err := r.createVPCCR(&ctx, obj, ns, ncName, createVpcName); err != nil {
+		nsxVPCPath := annotations[types.AnnotationNSXVPCPath]
+		vpcCR, err := r.createVPCCR(&ctx, obj, ns, ncName, createVpcName, nsxVPCPath)
+		if err != nil {
 			return common.ResultRequeueAfter10sec, nil
 		}
 		if err := r.createDefaultSubnetSet(ns); err != nil {
 			return common.ResultRequeueAfter10sec, nil
 		}
-		return common.ResultNormal, nil
+		return r.updateNetworkReady(ctx, obj, vpcCR)
 	} else {
 		log.Info("skip ns deletion event for ns", "Namespace", ns)
 		metrics.CounterInc(r.NSXConfig, metrics.ControllerDeleteTotal, common.MetricResTypeNamespace)
@@ -269,6 +289,12 @@ func (r *NamespaceReconciler) setupWithManager(mgr ctrl.Manager) error {
 			controller.Options{
 				MaxConcurrentReconciles: common.NumReconcile(),
 			}).
+		Watches(&v1alpha1.VPC{}, handler.EnqueueRequestsFromMapFunc(objectToNamespace)).
+		Watches(&v1alpha1.SubnetSet{}, handler.EnqueueRequestsFromMapFunc(objectToNamespace),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				_, ok := obj.GetLabels()[types.LabelDefaultSubnetSet]
+				return ok
+			}))).
 		Complete(r)
 }
 
@@ -135,7 +135,7 @@ func TestCreateVPCCR(t *testing.T) {
 		list.(*v1alpha1.VPCList).Items = vpcList1.Items
 		return nil
 	})
-	target, err := r.createVPCCR(&ctx, &namespace, "test-ns", "test-nc", nil)
+	target, err := r.createVPCCR(&ctx, &namespace, "test-ns", "test-nc", nil, "")
 	assert.Equal(t, target.Name, "fake-name1")
 	assert.Nil(t, err)
 
@@ -149,7 +149,7 @@ func TestCreateVPCCR(t *testing.T) {
 	})
 	patch2 := gomonkey.ApplyPrivateMethod(reflect.TypeOf(r), "namespaceError", func(_ *NamespaceReconciler, _ *context.Context, _ client.Object, _ string, _ error) {
 	})
-	target, err = r.createVPCCR(&ctx, &namespace, "test-ns", "test-nc", nil)
+	target, err = r.createVPCCR(&ctx, &namespace, "test-ns", "test-nc", nil, "")
 	assert.Nil(t, target)
 	assert.NotNil(t, err)
 	patch1.Reset()
@@ -168,7 +168,7 @@ func TestCreateVPCCR(t *testing.T) {
 	patch3 := gomonkey.ApplyMethod(reflect.TypeOf(r.VPCService), "ValidateNetworkConfig", func(_ *vpc.VPCService, nc common.VPCNetworkConfigInfo) bool {
 		return false
 	})
-	target, err = r.createVPCCR(&ctx, &namespace, "test-ns", "test-nc", nil)
+	target, err = r.createVPCCR(&ctx, &namespace, "test-ns", "test-nc", nil, "")
 	assert.Nil(t, target)
 	assert.NotNil(t, err)
 	patch1.Reset()
@@ -195,7 +195,7 @@ func TestCreateVPCCR(t *testing.T) {
 	patch3 = gomonkey.ApplyMethod(reflect.TypeOf(r.VPCService), "ValidateNetworkConfig", func(_ *vpc.VPCService, nc common.VPCNetworkConfigInfo) bool {
 		return true
 	})
-	target, err = r.createVPCCR(&ctx, &namespace, "test-ns", "test-nc", nil)
+	target, err = r.createVPCCR(&ctx, &namespace, "test-ns", "test-nc", nil, "")
 	assert.Nil(t, target)
 	assert.NotNil(t, err)
 	patch1.Reset()
@@ -219,7 +219,7 @@ func TestCreateVPCCR(t *testing.T) {
 		return true
 	})
 	patchUtil := gomonkey.ApplyFuncReturn(util.UpdateK8sResourceAnnotation, nil)
-	target, err = r.createVPCCR(&ctx, &namespace, "test-ns", "test-nc", nil)
+	target, err = r.createVPCCR(&ctx, &namespace, "test-ns", "test-nc", nil, "")
 	assert.Equal(t, target.GetName(), "test-vpc")
 	assert.Nil(t, err)
 
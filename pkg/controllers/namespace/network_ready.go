@@ -0,0 +1,172 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package namespace
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	types "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+func isConditionTrue(conditions []v1alpha1.Condition, conditionType v1alpha1.ConditionType) bool {
+	for _, condition := range conditions {
+		if condition.Type == conditionType {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getNetworkReadiness checks if the VPC, including its AVI rule, and the default SubnetSets of the Namespace
+// are realized, and returns the reason if they are not.
+func (r *NamespaceReconciler) getNetworkReadiness(ctx context.Context, vpcCR *v1alpha1.VPC) (bool, string, error) {
+	if !isConditionTrue(vpcCR.Status.Conditions, v1alpha1.Ready) {
+		return false, fmt.Sprintf("VPC %s is not ready", vpcCR.Name), nil
+	}
+	for _, subnetSetType := range []string{types.LabelDefaultVMSubnetSet, types.LabelDefaultPodSubnetSet} {
+		list := &v1alpha1.SubnetSetList{}
+		label := client.MatchingLabels{types.LabelDefaultSubnetSet: subnetSetType}
+		if err := r.Client.List(ctx, list, label, client.InNamespace(vpcCR.Namespace)); err != nil {
+			return false, "", err
+		}
+		if len(list.Items) == 0 {
+			return false, fmt.Sprintf("default %s SubnetSet is not created", subnetSetType), nil
+		}
+		if !isConditionTrue(list.Items[0].Status.Conditions, v1alpha1.Ready) {
+			return false, fmt.Sprintf("default %s SubnetSet %s is not ready", subnetSetType, list.Items[0].Name), nil
+		}
+	}
+	return true, "", nil
+}
+
+// updateNetworkReady sets the NetworkReady condition on the VPC CR and the network ready annotation on the
+// Namespace, and removes the scheduling gate from the Pods of the Namespace once the network is ready.
+func (r *NamespaceReconciler) updateNetworkReady(ctx context.Context, obj *v1.Namespace, vpcCR *v1alpha1.VPC) (ctrl.Result, error) {
+	ready, reason, err := r.getNetworkReadiness(ctx, vpcCR)
+	if err != nil {
+		log.Error(err, "failed to check network readiness", "Namespace", obj.Name)
+		return common.ResultRequeue, err
+	}
+	condition := v1alpha1.Condition{
+		Type:               v1alpha1.NetworkReady,
+		Status:             v1.ConditionTrue,
+		Reason:             "NetworkReady",
+		Message:            "VPC and default SubnetSets of the Namespace are ready",
+		LastTransitionTime: metav1.Now(),
+	}
+	if !ready {
+		condition.Status = v1.ConditionFalse
+		condition.Reason = "NetworkNotReady"
+		condition.Message = reason
+	}
+	if err := r.setVPCNetworkReadyCondition(ctx, vpcCR, condition); err != nil {
+		log.Error(err, "failed to update NetworkReady condition of VPC", "VPC", vpcCR.Name, "Namespace", vpcCR.Namespace)
+		return common.ResultRequeue, err
+	}
+	return r.updateNamespaceNetworkReady(ctx, obj, ready)
+}
+
+// updateSharedNetworkReady sets the network readiness of the Namespace sharing the VPC of another Namespace
+// by the NetworkReady condition of the shared VPC CR.
+func (r *NamespaceReconciler) updateSharedNetworkReady(ctx context.Context, obj *v1.Namespace, vpcNamespace, vpcName string) (ctrl.Result, error) {
+	vpcCR := &v1alpha1.VPC{}
+	if err := r.Client.Get(ctx, k8stypes.NamespacedName{Namespace: vpcNamespace, Name: vpcName}, vpcCR); client.IgnoreNotFound(err) != nil {
+		log.Error(err, "failed to get shared VPC", "VPC", vpcName, "Namespace", vpcNamespace)
+		return common.ResultRequeue, err
+	}
+	ready := isConditionTrue(vpcCR.Status.Conditions, v1alpha1.NetworkReady)
+	if _, err := r.updateNamespaceNetworkReady(ctx, obj, ready); err != nil {
+		return common.ResultRequeue, err
+	}
+	// the shared VPC CR is not watched for the Namespace, check it again later
+	if !ready {
+		return common.ResultRequeueAfter10sec, nil
+	}
+	return common.ResultNormal, nil
+}
+
+func (r *NamespaceReconciler) updateNamespaceNetworkReady(ctx context.Context, obj *v1.Namespace, ready bool) (ctrl.Result, error) {
+	value := strconv.FormatBool(ready)
+	if obj.Annotations[types.AnnotationNetworkReady] != value {
+		changes := map[string]string{types.AnnotationNetworkReady: value}
+		if err := util.UpdateK8sResourceAnnotation(r.Client, &ctx, obj, changes); err != nil {
+			log.Error(err, "failed to update network ready annotation", "Namespace", obj.Name)
+			return common.ResultRequeue, err
+		}
+		log.Info("updated network readiness of namespace", "Namespace", obj.Name, "Ready", ready)
+	}
+	if !ready {
+		return common.ResultNormal, nil
+	}
+	if err := r.removePodSchedulingGates(ctx, obj.Name); err != nil {
+		return common.ResultRequeue, err
+	}
+	return common.ResultNormal, nil
+}
+
+func (r *NamespaceReconciler) setVPCNetworkReadyCondition(ctx context.Context, vpcCR *v1alpha1.VPC, condition v1alpha1.Condition) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		for i := range vpcCR.Status.Conditions {
+			existing := &vpcCR.Status.Conditions[i]
+			if existing.Type != condition.Type {
+				continue
+			}
+			if existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+				return nil
+			}
+			// LastTransitionTime is only refreshed when the status is changed
+			if existing.Status == condition.Status {
+				condition.LastTransitionTime = existing.LastTransitionTime
+			}
+			*existing = condition
+			return r.updateVPCStatus(ctx, vpcCR)
+		}
+		vpcCR.Status.Conditions = append(vpcCR.Status.Conditions, condition)
+		return r.updateVPCStatus(ctx, vpcCR)
+	})
+}
+
+func (r *NamespaceReconciler) updateVPCStatus(ctx context.Context, vpcCR *v1alpha1.VPC) error {
+	err := r.Client.Status().Update(ctx, vpcCR)
+	if err != nil {
+		// read the VPC CR again to retry on the latest status
+		if getErr := r.Client.Get(ctx, client.ObjectKeyFromObject(vpcCR), vpcCR); getErr != nil {
+			return getErr
+		}
+	}
+	return err
+}
+
+func (r *NamespaceReconciler) removePodSchedulingGates(ctx context.Context, ns string) error {
+	pods := &v1.PodList{}
+	if err := r.Client.List(ctx, pods, client.InNamespace(ns)); err != nil {
+		log.Error(err, "failed to list pods", "Namespace", ns)
+		return err
+	}
+	for i := range pods.Items {
+		if err := common.RemovePodSchedulingGate(r.Client, ctx, &pods.Items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// objectToNamespace maps the VPC CRs and the default SubnetSets to their Namespace, so that the network
+// readiness of the Namespace is updated when they are changed.
+func objectToNamespace(_ context.Context, obj client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: k8stypes.NamespacedName{Name: obj.GetNamespace()}}}
+}
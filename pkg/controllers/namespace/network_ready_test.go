@@ -0,0 +1,107 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package namespace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	controllercommon "github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func readyConditions() []v1alpha1.Condition {
+	return []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}}
+}
+
+func TestUpdateNetworkReady(t *testing.T) {
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}
+	vpcCR := &v1alpha1.VPC{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vpc1"}}
+	vmSubnetSet := &v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns1", Name: common.DefaultVMSubnetSet,
+		Labels: map[string]string{common.LabelDefaultSubnetSet: common.LabelDefaultVMSubnetSet},
+	}}
+	podSubnetSet := &v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns1", Name: common.DefaultPodSubnetSet,
+		Labels: map[string]string{common.LabelDefaultSubnetSet: common.LabelDefaultPodSubnetSet},
+	}}
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod1"},
+		Spec:       v1.PodSpec{SchedulingGates: []v1.PodSchedulingGate{{Name: common.PodSchedulingGateNetworkReady}}},
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.VPC{}, &v1alpha1.SubnetSet{}).
+		WithObjects(ns, vpcCR, vmSubnetSet, podSubnetSet, pod).Build()
+	r := &NamespaceReconciler{Client: k8sClient, Scheme: scheme}
+	ctx := context.TODO()
+
+	getNetworkReady := func() (*v1alpha1.Condition, string) {
+		updatedVPC := &v1alpha1.VPC{}
+		assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(vpcCR), updatedVPC))
+		updatedNS := &v1.Namespace{}
+		assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(ns), updatedNS))
+		for i := range updatedVPC.Status.Conditions {
+			if updatedVPC.Status.Conditions[i].Type == v1alpha1.NetworkReady {
+				return &updatedVPC.Status.Conditions[i], updatedNS.Annotations[common.AnnotationNetworkReady]
+			}
+		}
+		return nil, updatedNS.Annotations[common.AnnotationNetworkReady]
+	}
+
+	// The VPC is not ready.
+	_, err := r.updateNetworkReady(ctx, ns, vpcCR)
+	assert.NoError(t, err)
+	condition, annotation := getNetworkReady()
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, "VPC vpc1 is not ready", condition.Message)
+	assert.Equal(t, "false", annotation)
+
+	// The default Pod SubnetSet is not ready.
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(vpcCR), vpcCR))
+	vpcCR.Status.Conditions = append(vpcCR.Status.Conditions, readyConditions()...)
+	assert.NoError(t, k8sClient.Status().Update(ctx, vpcCR))
+	vmSubnetSet.Status.Conditions = readyConditions()
+	assert.NoError(t, k8sClient.Status().Update(ctx, vmSubnetSet))
+	_, err = r.updateNetworkReady(ctx, ns, vpcCR)
+	assert.NoError(t, err)
+	condition, annotation = getNetworkReady()
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Contains(t, condition.Message, "SubnetSet pod-default is not ready")
+	assert.Equal(t, "false", annotation)
+	gatedPod := &v1.Pod{}
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), gatedPod))
+	assert.True(t, controllercommon.HasPodSchedulingGate(gatedPod))
+
+	// The network is ready, and the scheduling gate is removed from the Pod.
+	podSubnetSet.Status.Conditions = readyConditions()
+	assert.NoError(t, k8sClient.Status().Update(ctx, podSubnetSet))
+	_, err = r.updateNetworkReady(ctx, ns, vpcCR)
+	assert.NoError(t, err)
+	condition, annotation = getNetworkReady()
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, "true", annotation)
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), gatedPod))
+	assert.False(t, controllercommon.HasPodSchedulingGate(gatedPod))
+
+	// The Namespace sharing the VPC follows the NetworkReady condition of the shared VPC.
+	sharedNS := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}}
+	assert.NoError(t, k8sClient.Create(ctx, sharedNS))
+	result, err := r.updateSharedNetworkReady(ctx, sharedNS, "ns1", "vpc1")
+	assert.NoError(t, err)
+	assert.Equal(t, controllercommon.ResultNormal, result)
+	assert.NoError(t, k8sClient.Get(ctx, k8stypes.NamespacedName{Name: "ns2"}, sharedNS))
+	assert.Equal(t, "true", sharedNS.Annotations[common.AnnotationNetworkReady])
+}
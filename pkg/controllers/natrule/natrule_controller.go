@@ -0,0 +1,280 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package natrule
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/natrule"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+var (
+	log                     = logger.Log
+	ResultNormal            = common.ResultNormal
+	ResultRequeue           = common.ResultRequeue
+	ResultRequeueAfter10sec = common.ResultRequeueAfter10sec
+	MetricResType           = common.MetricResTypeNATRule
+)
+
+// NATRuleReconciler reconciles a NATRule object
+type NATRuleReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Service  *natrule.NATRuleService
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=natrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=natrules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=natrules/finalizers,verbs=update
+
+func (r *NATRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := &v1alpha1.NATRule{}
+	log.Info("reconciling natrule CR", "natrule", req.NamespacedName)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "unable to fetch natrule CR", "req", req.NamespacedName)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+
+	if obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+		if !controllerutil.ContainsFinalizer(obj, servicecommon.NATRuleFinalizerName) {
+			controllerutil.AddFinalizer(obj, servicecommon.NATRuleFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "add finalizer", "natrule", req.NamespacedName)
+				updateFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+			log.V(1).Info("added finalizer on natrule CR", "natrule", req.NamespacedName)
+		}
+
+		rule, allocatedIP, err := r.Service.CreateOrUpdateNATRule(obj)
+		if err != nil {
+			if errors.As(err, &nsxutil.RestrictionError{}) {
+				log.Info("invalid natrule CR", "natrule", req.NamespacedName, "reason", err.Error())
+				updateFail(r, &ctx, obj, err.Error())
+				return ResultNormal, nil
+			}
+			log.Error(err, "operate failed, would retry exponentially", "natrule", req.NamespacedName)
+			updateFail(r, &ctx, obj, err.Error())
+			return ResultRequeue, err
+		}
+		if obj.Status.NSXResourcePath != *rule.Path || obj.Status.AllocatedIP != allocatedIP {
+			obj.Status.NSXResourcePath = *rule.Path
+			obj.Status.AllocatedIP = allocatedIP
+			// The conditions may not change, update the status explicitly.
+			if err := r.Client.Status().Update(ctx, obj); err != nil {
+				log.Error(err, "failed to update natrule status", "natrule", req.NamespacedName)
+				return ResultRequeue, err
+			}
+		}
+		updateSuccess(r, &ctx, obj)
+	} else {
+		if controllerutil.ContainsFinalizer(obj, servicecommon.NATRuleFinalizerName) {
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := r.Service.DeleteNATRule(obj.UID); err != nil {
+				log.Error(err, "deletion failed, would retry exponentially", "natrule", req.NamespacedName)
+				deleteFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+			controllerutil.RemoveFinalizer(obj, servicecommon.NATRuleFinalizerName)
+			if err := r.Client.Update(ctx, obj); err != nil {
+				log.Error(err, "deletion failed, would retry exponentially", "natrule", req.NamespacedName)
+				deleteFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+			log.V(1).Info("removed finalizer", "natrule", req.NamespacedName)
+			deleteSuccess(r, &ctx, obj)
+		} else {
+			// only print a message because it's not a normal case
+			log.Info("finalizers cannot be recognized", "natrule", req.NamespacedName)
+		}
+	}
+	return ResultNormal, nil
+}
+
+func (r *NATRuleReconciler) setReadyStatusTrue(ctx *context.Context, obj *v1alpha1.NATRule, transitionTime metav1.Time) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionTrue,
+			Message:            "NSX NAT rule has been successfully created/updated",
+			Reason:             "NATRuleReady",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateStatusConditions(ctx, obj, newConditions)
+}
+
+func (r *NATRuleReconciler) setReadyStatusFalse(ctx *context.Context, obj *v1alpha1.NATRule, transitionTime metav1.Time, msg string) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionFalse,
+			Message:            "NSX NAT rule could not be created/updated/deleted",
+			Reason:             "NATRuleNotReady",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	if msg != "" {
+		newConditions[0].Message = msg
+	}
+	r.updateStatusConditions(ctx, obj, newConditions)
+}
+
+func (r *NATRuleReconciler) updateStatusConditions(ctx *context.Context, obj *v1alpha1.NATRule, newConditions []v1alpha1.Condition) {
+	conditionsUpdated := false
+	for i := range newConditions {
+		if mergeStatusCondition(obj, &newConditions[i]) {
+			conditionsUpdated = true
+		}
+	}
+	if conditionsUpdated {
+		if err := r.Client.Status().Update(*ctx, obj); err != nil {
+			log.Error(err, "failed to update natrule status", "Name", obj.Name, "Namespace", obj.Namespace)
+		} else {
+			log.V(1).Info("updated natrule", "Name", obj.Name, "Namespace", obj.Namespace, "New Conditions", newConditions)
+		}
+	}
+}
+
+func mergeStatusCondition(obj *v1alpha1.NATRule, newCondition *v1alpha1.Condition) bool {
+	for i := range obj.Status.Conditions {
+		matchedCondition := &obj.Status.Conditions[i]
+		if matchedCondition.Type != newCondition.Type {
+			continue
+		}
+		if reflect.DeepEqual(matchedCondition, newCondition) {
+			return false
+		}
+		matchedCondition.Reason = newCondition.Reason
+		matchedCondition.Message = newCondition.Message
+		matchedCondition.Status = newCondition.Status
+		return true
+	}
+	obj.Status.Conditions = append(obj.Status.Conditions, *newCondition)
+	return true
+}
+
+func updateFail(r *NATRuleReconciler, c *context.Context, o *v1alpha1.NATRule, m string) {
+	r.setReadyStatusFalse(c, o, metav1.Now(), m)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, m)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+}
+
+func deleteFail(r *NATRuleReconciler, c *context.Context, o *v1alpha1.NATRule, m string) {
+	r.setReadyStatusFalse(c, o, metav1.Now(), m)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, m)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+}
+
+func updateSuccess(r *NATRuleReconciler, c *context.Context, o *v1alpha1.NATRule) {
+	r.setReadyStatusTrue(c, o, metav1.Now())
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulUpdate, "NATRule CR has been successfully updated")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+}
+
+func deleteSuccess(r *NATRuleReconciler, _ *context.Context, o *v1alpha1.NATRule) {
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulDelete, "NATRule CR has been successfully deleted")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+}
+
+func (r *NATRuleReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.NATRule{}).
+		WithEventFilter(predicate.Funcs{
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				// Suppress Delete events to avoid filtering them out in the Reconcile function
+				return false
+			},
+		}).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Complete(r)
+}
+
+// Start setup manager and launch GC
+func (r *NATRuleReconciler) Start(mgr ctrl.Manager) error {
+	if err := r.setupWithManager(mgr); err != nil {
+		return err
+	}
+	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	return nil
+}
+
+// GarbageCollector deletes the NSX NAT rules of the NATRule CRs which have been removed.
+// cancel is used to break the loop during UT
+func (r *NATRuleReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("natrule garbage collector started")
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		nsxRuleSet := r.Service.ListNATRuleID()
+		if len(nsxRuleSet) == 0 {
+			continue
+		}
+		ruleList := &v1alpha1.NATRuleList{}
+		if err := r.Client.List(ctx, ruleList); err != nil {
+			log.Error(err, "failed to list natrule CR")
+			continue
+		}
+		crRuleSet := sets.New[string]()
+		for _, rule := range ruleList.Items {
+			crRuleSet.Insert(string(rule.UID))
+		}
+		for uid := range nsxRuleSet.Difference(crRuleSet) {
+			log.Info("GC collected natrule CR", "UID", uid)
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := r.Service.DeleteNATRule(types.UID(uid)); err != nil {
+				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+			} else {
+				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+			}
+		}
+	}
+}
+
+func StartNATRuleController(mgr ctrl.Manager, natRuleService *natrule.NATRuleService) {
+	reconciler := &NATRuleReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Service:  natRuleService,
+		Recorder: mgr.GetEventRecorderFor("natrule-controller"),
+	}
+	if err := reconciler.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "NATRule")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,148 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package natrule
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/natrule"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func newFakeReconciler(objs ...client.Object) *NATRuleReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.NATRule{}).WithObjects(objs...).Build()
+	return &NATRuleReconciler{
+		Client: fakeClient,
+		Scheme: scheme,
+		Service: &natrule.NATRuleService{
+			Service: common.Service{
+				NSXConfig: &config.NSXOperatorConfig{
+					NsxConfig: &config.NsxConfig{EnforcementPoint: "vmc-enforcementpoint"},
+					CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test"},
+				},
+			},
+		},
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestNATRuleReconciler_Reconcile(t *testing.T) {
+	obj := &v1alpha1.NATRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "snat1", UID: "snat-uid-1"},
+		Spec:       v1alpha1.NATRuleSpec{Action: v1alpha1.NATActionSNAT, SourceNetwork: "10.0.0.0/24"},
+	}
+	r := newFakeReconciler(obj)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "snat1"}}
+
+	// Not found
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "dummy"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// The invalid NATRule is not retried.
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateNATRule", func(_ *natrule.NATRuleService, _ *v1alpha1.NATRule) (*model.PolicyVpcNatRule, string, error) {
+		return nil, "", nsxutil.RestrictionError{Desc: "invalid IP or CIDR"}
+	})
+	defer patches.Reset()
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	updated := &v1alpha1.NATRule{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Contains(t, updated.Finalizers, common.NATRuleFinalizerName)
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+
+	// The NSX failure is retried.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateNATRule", func(_ *natrule.NATRuleService, _ *v1alpha1.NATRule) (*model.PolicyVpcNatRule, string, error) {
+		return nil, "", errors.New("patch failed")
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+	assert.Equal(t, ResultRequeue, result)
+
+	// The NAT rule is realized with the allocated IP.
+	path := "/orgs/default/projects/p1/vpcs/vpc1/nat/USER/nat-rules/natrule_snat-uid-1"
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateNATRule", func(_ *natrule.NATRuleService, _ *v1alpha1.NATRule) (*model.PolicyVpcNatRule, string, error) {
+		return &model.PolicyVpcNatRule{Path: &path}, "192.168.0.1", nil
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+	assert.Equal(t, path, updated.Status.NSXResourcePath)
+	assert.Equal(t, "192.168.0.1", updated.Status.AllocatedIP)
+
+	// The allocated IP is released while the NATRule stays ready.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateNATRule", func(_ *natrule.NATRuleService, _ *v1alpha1.NATRule) (*model.PolicyVpcNatRule, string, error) {
+		return &model.PolicyVpcNatRule{Path: &path}, "", nil
+	})
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Empty(t, updated.Status.AllocatedIP)
+
+	// Deletion fails.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteNATRule", func(_ *natrule.NATRuleService, _ types.UID) error {
+		return errors.New("delete failed")
+	})
+	assert.NoError(t, r.Client.Delete(ctx, updated))
+	_, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+
+	// Deletion succeeds.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteNATRule", func(_ *natrule.NATRuleService, _ types.UID) error {
+		return nil
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.Error(t, r.Client.Get(ctx, req.NamespacedName, updated))
+}
+
+func TestNATRuleReconciler_GarbageCollector(t *testing.T) {
+	obj := &v1alpha1.NATRule{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "snat1", UID: "snat-uid-1"}}
+	r := newFakeReconciler(obj)
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "ListNATRuleID", func(_ *natrule.NATRuleService) sets.Set[string] {
+		return sets.New[string]("snat-uid-1", "snat-uid-2")
+	})
+	defer patches.Reset()
+	deleted := sets.New[string]()
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteNATRule", func(_ *natrule.NATRuleService, uid types.UID) error {
+		deleted.Insert(string(uid))
+		return nil
+	})
+	cancel := make(chan bool)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		cancel <- true
+	}()
+	r.GarbageCollector(cancel, 100*time.Millisecond)
+	assert.Equal(t, []string{"snat-uid-2"}, deleted.UnsortedList())
+}
@@ -5,13 +5,16 @@ package networkpolicy
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -21,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
@@ -142,6 +146,9 @@ func (r *NetworkPolicyReconciler) Start(mgr ctrl.Manager) error {
 	}
 
 	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	if r.Service.NSXConfig.EnableRuleStatistics {
+		go r.CollectRuleStatistics(make(chan bool), time.Duration(r.Service.NSXConfig.GetRuleStatisticsInterval())*time.Second)
+	}
 	return nil
 }
 
@@ -189,6 +196,66 @@ func (r *NetworkPolicyReconciler) GarbageCollector(cancel chan bool, timeout tim
 	}
 }
 
+// CollectRuleStatistics periodically reads the NSX rule statistics of NetworkPolicies and publishes them
+// in the NetworkPolicy annotation nsx.vmware.com/rule_statistics.
+// cancel is used to break the loop during UT
+func (r *NetworkPolicyReconciler) CollectRuleStatistics(cancel chan bool, interval time.Duration) {
+	ctx := context.Background()
+	log.Info("rule statistics collector started", "interval", interval)
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		policyList := &networkingv1.NetworkPolicyList{}
+		if err := r.Client.List(ctx, policyList); err != nil {
+			log.Error(err, "failed to list NetworkPolicy")
+			continue
+		}
+		for i := range policyList.Items {
+			policy := &policyList.Items[i]
+			if !policy.ObjectMeta.DeletionTimestamp.IsZero() {
+				continue
+			}
+			if err := r.updateRuleStatistics(ctx, policy); err != nil {
+				log.Error(err, "failed to update rule statistics", "networkpolicy", types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name})
+			}
+		}
+	}
+}
+
+func (r *NetworkPolicyReconciler) updateRuleStatistics(ctx context.Context, networkPolicy *networkingv1.NetworkPolicy) error {
+	ruleStatistics, err := r.Service.GetRuleStatistics(networkPolicy)
+	if err != nil {
+		return err
+	}
+	var existing []v1alpha1.SecurityPolicyRuleStatistics
+	if value, ok := networkPolicy.Annotations[servicecommon.AnnotationRuleStatistics]; ok {
+		if err := json.Unmarshal([]byte(value), &existing); err != nil {
+			log.Info("ignored invalid rule statistics annotation", "networkpolicy", networkPolicy.Name, "error", err.Error())
+		}
+	}
+	ruleStatistics = securitypolicy.MergeRuleStatistics(existing, ruleStatistics, metav1.Now())
+	if reflect.DeepEqual(existing, ruleStatistics) {
+		return nil
+	}
+	value, err := json.Marshal(ruleStatistics)
+	if err != nil {
+		return err
+	}
+	patch := client.MergeFrom(networkPolicy.DeepCopy())
+	if networkPolicy.Annotations == nil {
+		networkPolicy.Annotations = map[string]string{}
+	}
+	networkPolicy.Annotations[servicecommon.AnnotationRuleStatistics] = string(value)
+	if err := r.Client.Patch(ctx, networkPolicy, patch); err != nil {
+		return err
+	}
+	log.V(1).Info("updated NetworkPolicy rule statistics", "Name", networkPolicy.Name, "Namespace", networkPolicy.Namespace)
+	return nil
+}
+
 func StartNetworkPolicyController(mgr ctrl.Manager, commonService servicecommon.Service, vpcService servicecommon.VPCServiceProvider) {
 	networkPolicyReconcile := NetworkPolicyReconciler{
 		Client:   mgr.GetClient(),
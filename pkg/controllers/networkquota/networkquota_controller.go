@@ -0,0 +1,236 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package networkquota
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+var (
+	log           = logger.Log
+	ResultNormal  = common.ResultNormal
+	ResultRequeue = common.ResultRequeue
+	MetricResType = common.MetricResTypeNetworkQuota
+)
+
+// NetworkQuotaReconciler reconciles a NetworkQuota object, it reports the usage of the network resources of
+// the Namespace in the status. The quota is enforced by NetworkQuotaValidator.
+type NetworkQuotaReconciler struct {
+	Client     client.Client
+	Scheme     *apimachineryruntime.Scheme
+	NSXConfig  *config.NSXOperatorConfig
+	VPCService servicecommon.VPCServiceProvider
+}
+
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=networkquotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=networkquotas/status,verbs=get;update;patch
+
+func (r *NetworkQuotaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := &v1alpha1.NetworkQuota{}
+	log.Info("reconciling networkquota CR", "networkquota", req.NamespacedName)
+	metrics.CounterInc(r.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "unable to fetch networkquota CR", "req", req.NamespacedName)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ResultNormal, nil
+	}
+
+	metrics.CounterInc(r.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+	used, err := computeUsage(ctx, r.Client, r.VPCService, obj.Namespace)
+	if err != nil {
+		log.Error(err, "failed to compute network resource usage, would retry exponentially", "networkquota", req.NamespacedName)
+		metrics.CounterInc(r.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+		return ResultRequeue, err
+	}
+	// The charges of the requests admitted but not counted yet are kept, so that used is not lowered
+	// below the charges while the resources are being created.
+	now := time.Now()
+	pending, err := pendingCharges(ctx, r.Client, obj, now)
+	if err != nil {
+		log.Error(err, "failed to check networkquota charges, would retry exponentially", "networkquota", req.NamespacedName)
+		metrics.CounterInc(r.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+		return ResultRequeue, err
+	}
+	used = addCharges(used, pending)
+	newStatus := obj.Status.DeepCopy()
+	newStatus.Hard = v1alpha1.NetworkResourceList{}
+	newStatus.Used = v1alpha1.NetworkResourceList{}
+	newStatus.PendingCharges = pending
+	var unsupported []string
+	for name, hard := range obj.Spec.Hard {
+		if !isSupportedResource(name) {
+			unsupported = append(unsupported, string(name))
+			continue
+		}
+		newStatus.Hard[name] = hard
+		newStatus.Used[name] = used[name]
+	}
+	condition := v1alpha1.Condition{
+		Type:    v1alpha1.Ready,
+		Status:  v1.ConditionTrue,
+		Reason:  "NetworkQuotaReady",
+		Message: "NetworkQuota is enforced",
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		condition.Status = v1.ConditionFalse
+		condition.Reason = "NetworkQuotaNotReady"
+		condition.Message = fmt.Sprintf("unsupported network resources %s are not limited", strings.Join(unsupported, ","))
+	}
+	mergeStatusCondition(newStatus, &condition)
+	if !reflect.DeepEqual(&obj.Status, newStatus) {
+		obj.Status = *newStatus
+		if err := r.Client.Status().Update(ctx, obj); err != nil {
+			log.Error(err, "failed to update networkquota status, would retry exponentially", "networkquota", req.NamespacedName)
+			metrics.CounterInc(r.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+			return ResultRequeue, err
+		}
+		log.V(1).Info("updated networkquota status", "networkquota", req.NamespacedName, "used", newStatus.Used)
+	}
+	metrics.CounterInc(r.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+	if len(pending) > 0 {
+		// Reconcile again to remove the charges of the requests rejected after the admission once they expire.
+		return ctrl.Result{RequeueAfter: pending[0].ChargedTime.Add(chargeTTL).Sub(now) + time.Second}, nil
+	}
+	return ResultNormal, nil
+}
+
+func mergeStatusCondition(status *v1alpha1.NetworkQuotaStatus, newCondition *v1alpha1.Condition) {
+	for i := range status.Conditions {
+		matchedCondition := &status.Conditions[i]
+		if matchedCondition.Type != newCondition.Type {
+			continue
+		}
+		if matchedCondition.Status != newCondition.Status {
+			matchedCondition.LastTransitionTime = metav1.Now()
+		}
+		matchedCondition.Reason = newCondition.Reason
+		matchedCondition.Message = newCondition.Message
+		matchedCondition.Status = newCondition.Status
+		return
+	}
+	newCondition.LastTransitionTime = metav1.Now()
+	status.Conditions = append(status.Conditions, *newCondition)
+}
+
+// objectToNetworkQuotas enqueues the NetworkQuotas in the Namespace of the network resource, so that the
+// usage is updated when the network resources are created or deleted.
+func (r *NetworkQuotaReconciler) objectToNetworkQuotas(ctx context.Context, obj client.Object) []reconcile.Request {
+	quotaList := &v1alpha1.NetworkQuotaList{}
+	if err := r.Client.List(ctx, quotaList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "failed to list networkquota CR")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, quota := range quotaList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: quota.Namespace, Name: quota.Name}})
+	}
+	return requests
+}
+
+// predicateChargeAdded passes the status updates of the NetworkQuota adding charges, the controller
+// requeues the NetworkQuota until the charges are observed or expired.
+var predicateChargeAdded = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldObj, okOld := e.ObjectOld.(*v1alpha1.NetworkQuota)
+		newObj, okNew := e.ObjectNew.(*v1alpha1.NetworkQuota)
+		if !okOld || !okNew {
+			return false
+		}
+		existing := sets.New[string]()
+		for _, charge := range oldObj.Status.PendingCharges {
+			existing.Insert(chargeKey(&charge))
+		}
+		for _, charge := range newObj.Status.PendingCharges {
+			if !existing.Has(chargeKey(&charge)) {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+func chargeKey(charge *v1alpha1.NetworkQuotaCharge) string {
+	return fmt.Sprintf("%s/%s/%d/%s", charge.Resource, charge.Name, charge.Generation, charge.ChargedTime.UTC().Format(time.RFC3339))
+}
+
+func (r *NetworkQuotaReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// The status updates by the controller itself are filtered out, as the usage is recounted
+		// from the cache which may not have the resources being admitted yet.
+		For(&v1alpha1.NetworkQuota{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicateChargeAdded))).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Watches(&v1alpha1.Subnet{}, handler.EnqueueRequestsFromMapFunc(r.objectToNetworkQuotas)).
+		Watches(&v1alpha1.SubnetSet{}, handler.EnqueueRequestsFromMapFunc(r.objectToNetworkQuotas)).
+		Watches(&v1alpha1.SubnetPort{}, handler.EnqueueRequestsFromMapFunc(r.objectToNetworkQuotas)).
+		Watches(&v1alpha1.StaticRoute{}, handler.EnqueueRequestsFromMapFunc(r.objectToNetworkQuotas)).
+		Watches(&v1alpha1.SecurityPolicy{}, handler.EnqueueRequestsFromMapFunc(r.objectToNetworkQuotas)).
+		Complete(r)
+}
+
+// Start setup manager and register the webhook enforcing the quota
+func (r *NetworkQuotaReconciler) Start(mgr ctrl.Manager, enableWebhook bool) error {
+	if err := r.setupWithManager(mgr); err != nil {
+		return err
+	}
+	if enableWebhook {
+		mgr.GetWebhookServer().Register("/validate-nsx-vmware-com-v1alpha1-networkquota",
+			&webhook.Admission{
+				Handler: &NetworkQuotaValidator{
+					Client:     mgr.GetClient(),
+					VPCService: r.VPCService,
+					decoder:    admission.NewDecoder(mgr.GetScheme()),
+				},
+			})
+	}
+	return nil
+}
+
+func StartNetworkQuotaController(mgr ctrl.Manager, nsxConfig *config.NSXOperatorConfig, vpcService servicecommon.VPCServiceProvider, enableWebhook bool) {
+	reconciler := &NetworkQuotaReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		NSXConfig:  nsxConfig,
+		VPCService: vpcService,
+	}
+	if err := reconciler.Start(mgr, enableWebhook); err != nil {
+		log.Error(err, "failed to create controller", "controller", "NetworkQuota")
+		os.Exit(1)
+	}
+}
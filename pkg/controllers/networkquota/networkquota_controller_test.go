@@ -0,0 +1,102 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package networkquota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+)
+
+func TestNetworkQuotaReconciler_Reconcile(t *testing.T) {
+	quota := &v1alpha1.NetworkQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "quota1"},
+		Spec: v1alpha1.NetworkQuotaSpec{Hard: v1alpha1.NetworkResourceList{
+			v1alpha1.NetworkResourceSubnets:    10,
+			v1alpha1.NetworkResourcePrivateIPs: 1024,
+		}},
+	}
+	r := &NetworkQuotaReconciler{
+		Client: newFakeClient(quota,
+			&v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet1", Generation: 1}},
+			&v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet2"}, Spec: v1alpha1.SubnetSpec{IPv4SubnetSize: 64}},
+			&v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "subnet3"}},
+		),
+		NSXConfig:  &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{}},
+		VPCService: &fakeVPCService{},
+	}
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "quota1"}}
+
+	// Not found
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "dummy"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// The usage of the limited resources in the Namespace is reported.
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	updated := &v1alpha1.NetworkQuota{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1alpha1.NetworkResourceList{v1alpha1.NetworkResourceSubnets: 10, v1alpha1.NetworkResourcePrivateIPs: 1024}, updated.Status.Hard)
+	assert.Equal(t, v1alpha1.NetworkResourceList{v1alpha1.NetworkResourceSubnets: 2, v1alpha1.NetworkResourcePrivateIPs: 96}, updated.Status.Used)
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+
+	// The pending charges are kept in the usage until the resources are observed.
+	updated.Status.PendingCharges = []v1alpha1.NetworkQuotaCharge{
+		{Resource: v1alpha1.NetworkResourceSubnets, Name: "subnet4", Generation: 1, ChargedTime: metav1.Now(),
+			Requested: v1alpha1.NetworkResourceList{v1alpha1.NetworkResourceSubnets: 1, v1alpha1.NetworkResourcePrivateIPs: 32}},
+		{Resource: v1alpha1.NetworkResourceSubnets, Name: "subnet1", Generation: 1, ChargedTime: metav1.Now(),
+			Requested: v1alpha1.NetworkResourceList{v1alpha1.NetworkResourceSubnets: 1, v1alpha1.NetworkResourcePrivateIPs: 32}},
+	}
+	assert.NoError(t, r.Client.Status().Update(ctx, updated))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.True(t, result.RequeueAfter > chargeTTL-time.Second)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1alpha1.NetworkResourceList{v1alpha1.NetworkResourceSubnets: 3, v1alpha1.NetworkResourcePrivateIPs: 128}, updated.Status.Used)
+	assert.Len(t, updated.Status.PendingCharges, 1)
+	assert.Equal(t, "subnet4", updated.Status.PendingCharges[0].Name)
+
+	// The expired charges are removed.
+	updated.Status.PendingCharges[0].ChargedTime = metav1.NewTime(time.Now().Add(-2 * chargeTTL))
+	assert.NoError(t, r.Client.Status().Update(ctx, updated))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1alpha1.NetworkResourceList{v1alpha1.NetworkResourceSubnets: 2, v1alpha1.NetworkResourcePrivateIPs: 96}, updated.Status.Used)
+	assert.Empty(t, updated.Status.PendingCharges)
+
+	// Only the status updates adding charges are reconciled.
+	charged := updated.DeepCopy()
+	charged.Status.PendingCharges = []v1alpha1.NetworkQuotaCharge{{Resource: v1alpha1.NetworkResourceSubnets, Name: "subnet4"}}
+	assert.True(t, predicateChargeAdded.Update(event.UpdateEvent{ObjectOld: updated, ObjectNew: charged}))
+	assert.False(t, predicateChargeAdded.Update(event.UpdateEvent{ObjectOld: charged, ObjectNew: updated}))
+
+	// The unsupported resources are reported in the condition.
+	updated.Spec.Hard["loadbalancers"] = 1
+	assert.NoError(t, r.Client.Update(ctx, updated))
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+	assert.Equal(t, "unsupported network resources loadbalancers are not limited", updated.Status.Conditions[0].Message)
+	assert.NotContains(t, updated.Status.Hard, v1alpha1.NetworkResourceName("loadbalancers"))
+
+	// The NetworkQuotas of the Namespace are enqueued for the changes of the network resources.
+	requests := r.objectToNetworkQuotas(ctx, &v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet4"}})
+	assert.Equal(t, []ctrl.Request{req}, requests)
+	assert.Empty(t, r.objectToNetworkQuotas(ctx, &v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "subnet4"}}))
+}
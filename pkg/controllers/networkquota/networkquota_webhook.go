@@ -0,0 +1,231 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package networkquota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/subnetset"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// log is for logging in this package.
+var networkquotalog = logf.Log.WithName("networkquota-webhook")
+
+//+kubebuilder:webhook:path=/validate-nsx-vmware-com-v1alpha1-networkquota,mutating=false,failurePolicy=fail,sideEffects=None,groups=nsx.vmware.com,resources=subnets;subnetsets;subnetports;staticroutes;securitypolicies,verbs=create;update,versions=v1alpha1,name=networkquota.validating.nsx.vmware.com,admissionReviewVersions=v1
+
+// NetworkQuotaValidator denies the creation of the network resources exceeding the NetworkQuotas of the
+// Namespace, and the growth of the private Subnets and SubnetSets exceeding the quota of private IPs.
+// The admitted requests are added to status.pendingCharges of the NetworkQuotas with a conflict-checked
+// update, so that concurrent requests can't exceed the quota together. The charges are counted until their
+// resources are observed by the cache or they expire, the NetworkQuota controller doesn't remove them before.
+type NetworkQuotaValidator struct {
+	Client     client.Client
+	VPCService servicecommon.VPCServiceProvider
+	decoder    *admission.Decoder
+}
+
+// requestedResources returns the count of each network resource the request adds to the Namespace.
+func (v *NetworkQuotaValidator) requestedResources(req admission.Request) (v1alpha1.NetworkResourceList, error) {
+	requested := v1alpha1.NetworkResourceList{}
+	switch v1alpha1.NetworkResourceName(req.Resource.Resource) {
+	case v1alpha1.NetworkResourceSubnets:
+		vpcNetworkConfig := v.VPCService.GetVPCNetworkConfigByNamespace(req.Namespace)
+		subnet := &v1alpha1.Subnet{}
+		if err := v.decoder.Decode(req, subnet); err != nil {
+			return nil, err
+		}
+		privateIPs := subnetCRPrivateIPs(subnet, vpcNetworkConfig)
+		if req.Operation == admissionv1.Create {
+			requested[v1alpha1.NetworkResourceSubnets] = 1
+		} else {
+			oldSubnet := &v1alpha1.Subnet{}
+			if err := v.decoder.DecodeRaw(req.OldObject, oldSubnet); err != nil {
+				return nil, err
+			}
+			privateIPs -= subnetCRPrivateIPs(oldSubnet, vpcNetworkConfig)
+		}
+		requested[v1alpha1.NetworkResourcePrivateIPs] = privateIPs
+	case v1alpha1.NetworkResourceSubnetSets:
+		if req.Operation == admissionv1.Create {
+			requested[v1alpha1.NetworkResourceSubnetSets] = 1
+			break
+		}
+		// The existing Subnets of the SubnetSet are not resized, but the next Subnet of the grown size
+		// should fit in the quota.
+		vpcNetworkConfig := v.VPCService.GetVPCNetworkConfigByNamespace(req.Namespace)
+		subnetSet, oldSubnetSet := &v1alpha1.SubnetSet{}, &v1alpha1.SubnetSet{}
+		if err := v.decoder.Decode(req, subnetSet); err != nil {
+			return nil, err
+		}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldSubnetSet); err != nil {
+			return nil, err
+		}
+		size := subnetPrivateIPs(subnetSet.Spec.AccessMode, subnetSet.Spec.IPv4SubnetSize, nil, vpcNetworkConfig)
+		if size > subnetPrivateIPs(oldSubnetSet.Spec.AccessMode, oldSubnetSet.Spec.IPv4SubnetSize, nil, vpcNetworkConfig) {
+			requested[v1alpha1.NetworkResourcePrivateIPs] = size
+		}
+	case v1alpha1.NetworkResourceSubnetPorts, v1alpha1.NetworkResourceStaticRoutes, v1alpha1.NetworkResourceSecurityPolicies:
+		if req.Operation == admissionv1.Create {
+			requested[v1alpha1.NetworkResourceName(req.Resource.Resource)] = 1
+		}
+	}
+	return requested, nil
+}
+
+func formatResources(resources v1alpha1.NetworkResourceList, names []v1alpha1.NetworkResourceName) string {
+	items := make([]string, 0, len(names))
+	for _, name := range names {
+		items = append(items, fmt.Sprintf("%s=%d", name, resources[name]))
+	}
+	return strings.Join(items, ",")
+}
+
+// Handle handles admission requests.
+func (v *NetworkQuotaValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	// The resources created by nsx-operator itself, e.g. the default SubnetSets, are not limited.
+	if req.UserInfo.Username == subnetset.NSXOperatorSA {
+		return admission.Allowed("")
+	}
+	quotaList := &v1alpha1.NetworkQuotaList{}
+	if err := v.Client.List(ctx, quotaList, client.InNamespace(req.Namespace)); err != nil {
+		networkquotalog.Error(err, "failed to list NetworkQuota", "Namespace", req.Namespace)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if len(quotaList.Items) == 0 {
+		return admission.Allowed("")
+	}
+	requested, err := v.requestedResources(req)
+	if err != nil {
+		networkquotalog.Error(err, "error while decoding object", "Resource", req.Resource.Resource, "Object", req.Namespace+"/"+req.Name)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	var requestedNames []v1alpha1.NetworkResourceName
+	for name, count := range requested {
+		if count > 0 {
+			requestedNames = append(requestedNames, name)
+		}
+	}
+	if len(requestedNames) == 0 {
+		return admission.Allowed("")
+	}
+	sort.Slice(requestedNames, func(i, j int) bool { return requestedNames[i] < requestedNames[j] })
+	generation, err := chargedGeneration(req)
+	if err != nil {
+		networkquotalog.Error(err, "error while decoding object", "Resource", req.Resource.Resource, "Object", req.Namespace+"/"+req.Name)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	charge := v1alpha1.NetworkQuotaCharge{
+		Resource:    v1alpha1.NetworkResourceName(req.Resource.Resource),
+		Name:        req.Name,
+		Generation:  generation,
+		Requested:   requested,
+		ChargedTime: metav1.Now(),
+	}
+
+	used, err := computeUsage(ctx, v.Client, v.VPCService, req.Namespace)
+	if err != nil {
+		networkquotalog.Error(err, "failed to compute network resource usage", "Namespace", req.Namespace)
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	for i := range quotaList.Items {
+		msg, err := v.charge(ctx, &quotaList.Items[i], used, charge, requestedNames)
+		if err != nil {
+			networkquotalog.Error(err, "failed to charge NetworkQuota", "NetworkQuota", req.Namespace+"/"+quotaList.Items[i].Name)
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if msg != "" {
+			networkquotalog.Info("denied request", "Resource", req.Resource.Resource, "Object", req.Namespace+"/"+req.Name, "reason", msg)
+			return admission.Denied(msg)
+		}
+	}
+	return admission.Allowed("")
+}
+
+// chargedGeneration returns the generation of the requested object once the request is applied. The API
+// server sets the generation before the validating webhooks are called, it's set here in case it's not.
+func chargedGeneration(req admission.Request) (int64, error) {
+	obj := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(req.Object.Raw, obj); err != nil {
+		return 0, err
+	}
+	if obj.Generation > 0 {
+		return obj.Generation, nil
+	}
+	if req.Operation == admissionv1.Create {
+		return 1, nil
+	}
+	oldObj := &metav1.PartialObjectMetadata{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldObj); err != nil {
+		return 0, err
+	}
+	return oldObj.Generation + 1, nil
+}
+
+// charge adds the charge of the request to status.pendingCharges of the NetworkQuota if it's within the quota.
+// The usage is the count of the existing resources and the pending charges of the other requests. It returns
+// the reason if the quota is exceeded. The NetworkQuota is read again and charged until there is no
+// conflicting update by another request or the controller.
+func (v *NetworkQuotaValidator) charge(ctx context.Context, quota *v1alpha1.NetworkQuota, used v1alpha1.NetworkResourceList, charge v1alpha1.NetworkQuotaCharge, requestedNames []v1alpha1.NetworkResourceName) (string, error) {
+	var msg string
+	requested := charge.Requested
+	first := true
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			if err := v.Client.Get(ctx, client.ObjectKeyFromObject(quota), quota); err != nil {
+				return err
+			}
+		}
+		first = false
+		pending, err := pendingCharges(ctx, v.Client, quota, charge.ChargedTime.Time)
+		if err != nil {
+			return err
+		}
+		usage := addCharges(used, pending)
+		var limited, exceeded []v1alpha1.NetworkResourceName
+		for _, name := range requestedNames {
+			hard, ok := quota.Spec.Hard[name]
+			if !ok {
+				continue
+			}
+			if usage[name]+requested[name] > hard {
+				exceeded = append(exceeded, name)
+			}
+			limited = append(limited, name)
+		}
+		if len(exceeded) > 0 {
+			msg = fmt.Sprintf("exceeded quota: %s, requested: %s, used: %s, limited: %s", quota.Name,
+				formatResources(requested, exceeded), formatResources(usage, exceeded), formatResources(quota.Spec.Hard, exceeded))
+			return nil
+		}
+		if len(limited) == 0 {
+			return nil
+		}
+		if quota.Status.Used == nil {
+			quota.Status.Used = v1alpha1.NetworkResourceList{}
+		}
+		for _, name := range limited {
+			quota.Status.Used[name] = usage[name] + requested[name]
+		}
+		quota.Status.PendingCharges = append(pending, charge)
+		return v.Client.Status().Update(ctx, quota)
+	})
+	return msg, err
+}
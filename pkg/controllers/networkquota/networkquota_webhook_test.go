@@ -0,0 +1,201 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package networkquota
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/subnetset"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+type fakeVPCService struct {
+	servicecommon.VPCServiceProvider
+}
+
+func (f *fakeVPCService) GetVPCNetworkConfigByNamespace(_ string) *servicecommon.VPCNetworkConfigInfo {
+	return &servicecommon.VPCNetworkConfigInfo{DefaultSubnetAccessMode: v1alpha1.AccessModePrivate, DefaultIPv4SubnetSize: 32}
+}
+
+func newFakeClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.NetworkQuota{}).WithObjects(objs...).Build()
+}
+
+func newRequest(t *testing.T, operation admissionv1.Operation, resource string, obj, oldObj runtime.Object) admission.Request {
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: operation,
+		Namespace: "ns1",
+		Resource:  metav1.GroupVersionResource{Group: "nsx.vmware.com", Version: "v1alpha1", Resource: resource},
+	}}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		req.Name = accessor.GetName()
+	}
+	raw, err := json.Marshal(obj)
+	assert.NoError(t, err)
+	req.Object.Raw = raw
+	if oldObj != nil {
+		raw, err = json.Marshal(oldObj)
+		assert.NoError(t, err)
+		req.OldObject.Raw = raw
+	}
+	return req
+}
+
+func TestNetworkQuotaValidator_Handle(t *testing.T) {
+	quota := &v1alpha1.NetworkQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "quota1"},
+		Spec: v1alpha1.NetworkQuotaSpec{Hard: v1alpha1.NetworkResourceList{
+			v1alpha1.NetworkResourceSubnets:      2,
+			v1alpha1.NetworkResourceStaticRoutes: 1,
+			v1alpha1.NetworkResourcePrivateIPs:   64,
+		}},
+	}
+	existingSubnet := &v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet1"}}
+	validator := &NetworkQuotaValidator{
+		Client:     newFakeClient(quota, existingSubnet, &v1alpha1.StaticRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "route1"}}),
+		VPCService: &fakeVPCService{},
+		decoder:    admission.NewDecoder(clientgoscheme.Scheme),
+	}
+	ctx := context.TODO()
+
+	// The default size of the private Subnet is within the quota, and it's charged to the quota.
+	subnet := &v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet2"}}
+	resp := validator.Handle(ctx, newRequest(t, admissionv1.Create, "subnets", subnet, nil))
+	assert.True(t, resp.Allowed)
+	updated := &v1alpha1.NetworkQuota{}
+	assert.NoError(t, validator.Client.Get(ctx, client.ObjectKeyFromObject(quota), updated))
+	assert.Equal(t, v1alpha1.NetworkResourceList{v1alpha1.NetworkResourceSubnets: 2, v1alpha1.NetworkResourcePrivateIPs: 64}, updated.Status.Used)
+	assert.Len(t, updated.Status.PendingCharges, 1)
+	assert.Equal(t, "subnet2", updated.Status.PendingCharges[0].Name)
+	assert.Equal(t, int64(1), updated.Status.PendingCharges[0].Generation)
+
+	// The Subnet being created is counted by another request.
+	subnet.Name = "subnet3"
+	resp = validator.Handle(ctx, newRequest(t, admissionv1.Create, "subnets", subnet, nil))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "exceeded quota: quota1, requested: privateIPs=32,subnets=1, used: privateIPs=64,subnets=2, limited: privateIPs=64,subnets=2", resp.Result.Message)
+
+	// The charge expires as subnet2 is not created.
+	updated.Status.PendingCharges[0].ChargedTime = metav1.NewTime(time.Now().Add(-2 * chargeTTL))
+	assert.NoError(t, validator.Client.Status().Update(ctx, updated))
+
+	// The private IPs exceed the quota.
+	subnet.Spec.IPv4SubnetSize = 64
+	resp = validator.Handle(ctx, newRequest(t, admissionv1.Create, "subnets", subnet, nil))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "exceeded quota: quota1, requested: privateIPs=64, used: privateIPs=32, limited: privateIPs=64", resp.Result.Message)
+
+	// The public Subnet doesn't consume the private IPs.
+	subnet.Spec.AccessMode = v1alpha1.AccessMode(v1alpha1.AccessModePublic)
+	resp = validator.Handle(ctx, newRequest(t, admissionv1.Create, "subnets", subnet, nil))
+	assert.True(t, resp.Allowed)
+
+	// The Subnet can't grow beyond the quota.
+	grown := existingSubnet.DeepCopy()
+	grown.Spec.IPv4SubnetSize = 128
+	resp = validator.Handle(ctx, newRequest(t, admissionv1.Update, "subnets", grown, existingSubnet))
+	assert.False(t, resp.Allowed)
+
+	// The count of StaticRoutes exceeds the quota.
+	route := &v1alpha1.StaticRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "route2"}}
+	resp = validator.Handle(ctx, newRequest(t, admissionv1.Create, "staticroutes", route, nil))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "exceeded quota: quota1, requested: staticroutes=1, used: staticroutes=1, limited: staticroutes=1", resp.Result.Message)
+
+	// The update of StaticRoute isn't limited.
+	resp = validator.Handle(ctx, newRequest(t, admissionv1.Update, "staticroutes", route, route))
+	assert.True(t, resp.Allowed)
+
+	// The resources not limited by the quota are allowed.
+	resp = validator.Handle(ctx, newRequest(t, admissionv1.Create, "securitypolicies", &v1alpha1.SecurityPolicy{}, nil))
+	assert.True(t, resp.Allowed)
+
+	// The SubnetSet can't grow beyond the quota.
+	subnetSet := &v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnetset1"}, Spec: v1alpha1.SubnetSetSpec{IPv4SubnetSize: 16}}
+	grownSubnetSet := subnetSet.DeepCopy()
+	grownSubnetSet.Spec.IPv4SubnetSize = 64
+	resp = validator.Handle(ctx, newRequest(t, admissionv1.Update, "subnetsets", grownSubnetSet, subnetSet))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "exceeded quota: quota1, requested: privateIPs=64, used: privateIPs=32, limited: privateIPs=64", resp.Result.Message)
+	grownSubnetSet.Spec.IPv4SubnetSize = 32
+	resp = validator.Handle(ctx, newRequest(t, admissionv1.Update, "subnetsets", grownSubnetSet, subnetSet))
+	assert.True(t, resp.Allowed)
+	resp = validator.Handle(ctx, newRequest(t, admissionv1.Update, "subnetsets", subnetSet, grownSubnetSet))
+	assert.True(t, resp.Allowed)
+
+	// The resources created by nsx-operator are allowed.
+	req := newRequest(t, admissionv1.Create, "staticroutes", route, nil)
+	req.UserInfo.Username = subnetset.NSXOperatorSA
+	resp = validator.Handle(ctx, req)
+	assert.True(t, resp.Allowed)
+}
+
+func TestSubnetSetPrivateIPs(t *testing.T) {
+	subnetSet := &v1alpha1.SubnetSet{
+		Spec: v1alpha1.SubnetSetSpec{IPv4SubnetSize: 16},
+		Status: v1alpha1.SubnetSetStatus{Subnets: []v1alpha1.SubnetInfo{
+			{IPAddresses: []string{"10.0.0.0/28"}},
+			{IPAddresses: []string{"10.0.0.16/28", "10.0.1.0/27"}},
+		}},
+	}
+	vpcNetworkConfig := &servicecommon.VPCNetworkConfigInfo{DefaultSubnetAccessMode: v1alpha1.AccessModePrivate}
+	assert.Equal(t, int64(64), subnetSetPrivateIPs(subnetSet, nil, vpcNetworkConfig))
+	// The imported Subnets are not counted.
+	subnetSet.Status.Subnets[0].NSXResourcePath = "/orgs/default/projects/p1/vpcs/vpc1/subnets/imported"
+	assert.Equal(t, int64(48), subnetSetPrivateIPs(subnetSet, sets.New[string](subnetSet.Status.Subnets[0].NSXResourcePath), vpcNetworkConfig))
+	subnetSet.Spec.AccessMode = v1alpha1.AccessMode(v1alpha1.AccessModePublic)
+	assert.Equal(t, int64(0), subnetSetPrivateIPs(subnetSet, nil, vpcNetworkConfig))
+}
+
+func TestNetworkQuotaValidator_ChargeConflict(t *testing.T) {
+	quota := &v1alpha1.NetworkQuota{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "quota1"},
+		Spec:       v1alpha1.NetworkQuotaSpec{Hard: v1alpha1.NetworkResourceList{v1alpha1.NetworkResourceStaticRoutes: 2}},
+	}
+	validator := &NetworkQuotaValidator{Client: newFakeClient(quota), VPCService: &fakeVPCService{}}
+	ctx := context.TODO()
+	stale := &v1alpha1.NetworkQuota{}
+	assert.NoError(t, validator.Client.Get(ctx, client.ObjectKeyFromObject(quota), stale))
+	newCharge := func(name string) v1alpha1.NetworkQuotaCharge {
+		return v1alpha1.NetworkQuotaCharge{
+			Resource:    v1alpha1.NetworkResourceStaticRoutes,
+			Name:        name,
+			Generation:  1,
+			Requested:   v1alpha1.NetworkResourceList{v1alpha1.NetworkResourceStaticRoutes: 1},
+			ChargedTime: metav1.Now(),
+		}
+	}
+	names := []v1alpha1.NetworkResourceName{v1alpha1.NetworkResourceStaticRoutes}
+
+	// Two requests are charged concurrently.
+	msg, err := validator.charge(ctx, quota.DeepCopy(), v1alpha1.NetworkResourceList{}, newCharge("route1"), names)
+	assert.NoError(t, err)
+	assert.Empty(t, msg)
+	msg, err = validator.charge(ctx, stale.DeepCopy(), v1alpha1.NetworkResourceList{}, newCharge("route2"), names)
+	assert.NoError(t, err)
+	assert.Empty(t, msg)
+
+	// The third one is denied though it read the NetworkQuota before the charges.
+	msg, err = validator.charge(ctx, stale.DeepCopy(), v1alpha1.NetworkResourceList{}, newCharge("route3"), names)
+	assert.NoError(t, err)
+	assert.Equal(t, "exceeded quota: quota1, requested: staticroutes=1, used: staticroutes=2, limited: staticroutes=2", msg)
+}
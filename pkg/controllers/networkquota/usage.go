@@ -0,0 +1,199 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package networkquota
+
+import (
+	"context"
+	"net"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// supportedResources are the network resources which can be limited by NetworkQuota.
+var supportedResources = []v1alpha1.NetworkResourceName{
+	v1alpha1.NetworkResourceSubnets,
+	v1alpha1.NetworkResourceSubnetSets,
+	v1alpha1.NetworkResourceSubnetPorts,
+	v1alpha1.NetworkResourceStaticRoutes,
+	v1alpha1.NetworkResourceSecurityPolicies,
+	v1alpha1.NetworkResourcePrivateIPs,
+}
+
+// chargeTTL is how long a charge is kept if its resource is not observed, e.g. the request is rejected
+// by another admission webhook after it's charged.
+const chargeTTL = time.Minute
+
+func isSupportedResource(name v1alpha1.NetworkResourceName) bool {
+	for _, resource := range supportedResources {
+		if resource == name {
+			return true
+		}
+	}
+	return false
+}
+
+// countCIDRIPs returns the count of IPs in the CIDRs.
+func countCIDRIPs(cidrs []string) int64 {
+	var count int64
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		ones, bits := ipNet.Mask.Size()
+		count += int64(1) << (bits - ones)
+	}
+	return count
+}
+
+// subnetPrivateIPs returns the count of IPs consumed by a Subnet from the private CIDRs of the VPC. The access
+// mode and the size of the Subnet fall back to the defaults of the VPCNetworkConfiguration if they are not set.
+func subnetPrivateIPs(accessMode v1alpha1.AccessMode, size int, ipAddresses []string, vpcNetworkConfig *servicecommon.VPCNetworkConfigInfo) int64 {
+	if accessMode == "" && vpcNetworkConfig != nil {
+		accessMode = v1alpha1.AccessMode(vpcNetworkConfig.DefaultSubnetAccessMode)
+	}
+	if string(accessMode) != v1alpha1.AccessModePrivate {
+		return 0
+	}
+	if len(ipAddresses) > 0 {
+		return countCIDRIPs(ipAddresses)
+	}
+	if size == 0 && vpcNetworkConfig != nil {
+		size = vpcNetworkConfig.DefaultIPv4SubnetSize
+	}
+	return int64(size)
+}
+
+// subnetCRPrivateIPs returns the count of IPs consumed by the Subnet CR, the imported NSX Subnet
+// doesn't consume the private CIDRs as it is not created by the operator.
+func subnetCRPrivateIPs(subnet *v1alpha1.Subnet, vpcNetworkConfig *servicecommon.VPCNetworkConfigInfo) int64 {
+	if subnet.Spec.NSXSubnetPath != "" {
+		return 0
+	}
+	return subnetPrivateIPs(subnet.Spec.AccessMode, subnet.Spec.IPv4SubnetSize, subnet.Spec.IPAddresses, vpcNetworkConfig)
+}
+
+// subnetSetPrivateIPs returns the count of IPs consumed by the Subnets of the SubnetSet, except the
+// imported Subnets in importedPaths.
+func subnetSetPrivateIPs(subnetSet *v1alpha1.SubnetSet, importedPaths sets.Set[string], vpcNetworkConfig *servicecommon.VPCNetworkConfigInfo) int64 {
+	var count int64
+	for _, subnetInfo := range subnetSet.Status.Subnets {
+		if importedPaths.Has(subnetInfo.NSXResourcePath) {
+			continue
+		}
+		count += subnetPrivateIPs(subnetSet.Spec.AccessMode, subnetSet.Spec.IPv4SubnetSize, subnetInfo.IPAddresses, vpcNetworkConfig)
+	}
+	return count
+}
+
+// computeUsage returns the count of each supported network resource in the Namespace.
+func computeUsage(ctx context.Context, c client.Client, vpcService servicecommon.VPCServiceProvider, namespace string) (v1alpha1.NetworkResourceList, error) {
+	vpcNetworkConfig := vpcService.GetVPCNetworkConfigByNamespace(namespace)
+	used := v1alpha1.NetworkResourceList{}
+
+	subnetList := &v1alpha1.SubnetList{}
+	if err := c.List(ctx, subnetList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	used[v1alpha1.NetworkResourceSubnets] = int64(len(subnetList.Items))
+	var privateIPs int64
+	importedPaths := sets.New[string]()
+	for i := range subnetList.Items {
+		subnet := &subnetList.Items[i]
+		if subnet.Spec.NSXSubnetPath != "" {
+			importedPaths.Insert(subnet.Spec.NSXSubnetPath)
+		}
+		privateIPs += subnetCRPrivateIPs(subnet, vpcNetworkConfig)
+	}
+
+	subnetSetList := &v1alpha1.SubnetSetList{}
+	if err := c.List(ctx, subnetSetList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	used[v1alpha1.NetworkResourceSubnetSets] = int64(len(subnetSetList.Items))
+	for i := range subnetSetList.Items {
+		privateIPs += subnetSetPrivateIPs(&subnetSetList.Items[i], importedPaths, vpcNetworkConfig)
+	}
+	used[v1alpha1.NetworkResourcePrivateIPs] = privateIPs
+
+	subnetPortList := &v1alpha1.SubnetPortList{}
+	if err := c.List(ctx, subnetPortList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	used[v1alpha1.NetworkResourceSubnetPorts] = int64(len(subnetPortList.Items))
+
+	staticRouteList := &v1alpha1.StaticRouteList{}
+	if err := c.List(ctx, staticRouteList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	used[v1alpha1.NetworkResourceStaticRoutes] = int64(len(staticRouteList.Items))
+
+	securityPolicyList := &v1alpha1.SecurityPolicyList{}
+	if err := c.List(ctx, securityPolicyList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	used[v1alpha1.NetworkResourceSecurityPolicies] = int64(len(securityPolicyList.Items))
+	return used, nil
+}
+
+// newChargedObject returns an empty object of the charged resource.
+func newChargedObject(resource v1alpha1.NetworkResourceName) client.Object {
+	switch resource {
+	case v1alpha1.NetworkResourceSubnets:
+		return &v1alpha1.Subnet{}
+	case v1alpha1.NetworkResourceSubnetSets:
+		return &v1alpha1.SubnetSet{}
+	case v1alpha1.NetworkResourceSubnetPorts:
+		return &v1alpha1.SubnetPort{}
+	case v1alpha1.NetworkResourceStaticRoutes:
+		return &v1alpha1.StaticRoute{}
+	case v1alpha1.NetworkResourceSecurityPolicies:
+		return &v1alpha1.SecurityPolicy{}
+	}
+	return nil
+}
+
+// pendingCharges returns the charges of the NetworkQuota which are neither expired nor observed, a charge is
+// observed once its resource is found at the charged generation, then the resource is counted by computeUsage.
+func pendingCharges(ctx context.Context, c client.Client, quota *v1alpha1.NetworkQuota, now time.Time) ([]v1alpha1.NetworkQuotaCharge, error) {
+	var pending []v1alpha1.NetworkQuotaCharge
+	for _, charge := range quota.Status.PendingCharges {
+		if now.Sub(charge.ChargedTime.Time) > chargeTTL {
+			continue
+		}
+		obj := newChargedObject(charge.Resource)
+		if obj != nil && charge.Name != "" {
+			err := c.Get(ctx, types.NamespacedName{Namespace: quota.Namespace, Name: charge.Name}, obj)
+			if err == nil && obj.GetGeneration() >= charge.Generation {
+				continue
+			}
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		pending = append(pending, charge)
+	}
+	return pending, nil
+}
+
+// addCharges returns the sum of the usage and the resources requested by the charges.
+func addCharges(used v1alpha1.NetworkResourceList, charges []v1alpha1.NetworkQuotaCharge) v1alpha1.NetworkResourceList {
+	total := v1alpha1.NetworkResourceList{}
+	for name, count := range used {
+		total[name] = count
+	}
+	for _, charge := range charges {
+		for name, count := range charge.Requested {
+			total[name] += count
+		}
+	}
+	return total
+}
@@ -0,0 +1,238 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package pod
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// primaryInterface is the name of the Pod interface attached to the default Pod SubnetSet.
+const primaryInterface = "eth0"
+
+// networkSelection is an additional network of the Pod requested in the annotation k8s.v1.cni.cncf.io/networks.
+// Name is the name of a Subnet or a SubnetSet in the Namespace of the Pod.
+type networkSelection struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Interface string `json:"interface,omitempty"`
+}
+
+// networkStatus is the status of a Pod interface in the annotation k8s.v1.cni.cncf.io/network-status.
+type networkStatus struct {
+	Name      string   `json:"name"`
+	Interface string   `json:"interface,omitempty"`
+	IPs       []string `json:"ips,omitempty"`
+	MAC       string   `json:"mac,omitempty"`
+	Default   bool     `json:"default,omitempty"`
+	Gateway   []string `json:"gateway,omitempty"`
+}
+
+// parsePodNetworks parses the additional networks of the Pod. Both the JSON list and the comma-separated
+// "<namespace>/<name>@<interface>" formats are supported, the interfaces are named net1, net2... by default.
+func parsePodNetworks(pod *v1.Pod) ([]networkSelection, error) {
+	value := strings.TrimSpace(pod.Annotations[servicecommon.AnnotationPodNetworks])
+	if value == "" {
+		return nil, nil
+	}
+	var networks []networkSelection
+	if strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &networks); err != nil {
+			return nil, fmt.Errorf("invalid annotation %s: %w", servicecommon.AnnotationPodNetworks, err)
+		}
+	} else {
+		for _, item := range strings.Split(value, ",") {
+			network := networkSelection{}
+			item = strings.TrimSpace(item)
+			if i := strings.LastIndex(item, "@"); i >= 0 {
+				item, network.Interface = item[:i], item[i+1:]
+			}
+			if i := strings.Index(item, "/"); i >= 0 {
+				network.Namespace, item = item[:i], item[i+1:]
+			}
+			network.Name = item
+			networks = append(networks, network)
+		}
+	}
+
+	interfaces := sets.New[string](primaryInterface)
+	for i := range networks {
+		network := &networks[i]
+		if network.Name == "" {
+			return nil, fmt.Errorf("empty network name in annotation %s", servicecommon.AnnotationPodNetworks)
+		}
+		if network.Namespace == "" {
+			network.Namespace = pod.Namespace
+		}
+		if network.Namespace != pod.Namespace {
+			return nil, fmt.Errorf("network %s/%s is not in the Namespace of the Pod", network.Namespace, network.Name)
+		}
+		if network.Interface == "" {
+			network.Interface = fmt.Sprintf("net%d", i+1)
+		}
+		if interfaces.Has(network.Interface) {
+			return nil, fmt.Errorf("duplicate interface %s in annotation %s", network.Interface, servicecommon.AnnotationPodNetworks)
+		}
+		interfaces.Insert(network.Interface)
+	}
+	return networks, nil
+}
+
+// getSubnetPortName returns the name of the SubnetPort created for the Pod interface.
+func getSubnetPortName(pod *v1.Pod, iface string) string {
+	return fmt.Sprintf("%s-%s", pod.Name, iface)
+}
+
+// buildSubnetPortSpec returns the SubnetPort spec of the network, which is either a Subnet or a SubnetSet.
+func (r *PodReconciler) buildSubnetPortSpec(ctx context.Context, network networkSelection) (v1alpha1.SubnetPortSpec, error) {
+	key := types.NamespacedName{Namespace: network.Namespace, Name: network.Name}
+	if err := r.Client.Get(ctx, key, &v1alpha1.Subnet{}); err == nil {
+		return v1alpha1.SubnetPortSpec{Subnet: network.Name}, nil
+	} else if !apierrors.IsNotFound(err) {
+		return v1alpha1.SubnetPortSpec{}, err
+	}
+	if err := r.Client.Get(ctx, key, &v1alpha1.SubnetSet{}); err == nil {
+		return v1alpha1.SubnetPortSpec{SubnetSet: network.Name}, nil
+	} else if !apierrors.IsNotFound(err) {
+		return v1alpha1.SubnetPortSpec{}, err
+	}
+	return v1alpha1.SubnetPortSpec{}, fmt.Errorf("neither Subnet nor SubnetSet %s is found", key)
+}
+
+// reconcileSecondaryPorts creates a SubnetPort owned by the Pod for each additional network, and deletes the SubnetPorts
+// of the networks removed from the annotation. It returns the status of the interfaces and whether all of them are ready.
+func (r *PodReconciler) reconcileSecondaryPorts(ctx context.Context, pod *v1.Pod, networks []networkSelection) ([]networkStatus, bool, error) {
+	var statuses []networkStatus
+	allReady := true
+	portNames := sets.New[string]()
+	for _, network := range networks {
+		name := getSubnetPortName(pod, network.Interface)
+		portNames.Insert(name)
+		subnetPort := &v1alpha1.SubnetPort{}
+		err := r.Client.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: name}, subnetPort)
+		if apierrors.IsNotFound(err) {
+			spec, err := r.buildSubnetPortSpec(ctx, network)
+			if err != nil {
+				return nil, false, err
+			}
+			subnetPort = &v1alpha1.SubnetPort{
+				ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: name},
+				Spec:       spec,
+			}
+			if err := controllerutil.SetControllerReference(pod, subnetPort, r.Scheme); err != nil {
+				return nil, false, err
+			}
+			if err := r.Client.Create(ctx, subnetPort); err != nil {
+				return nil, false, err
+			}
+			log.Info("created SubnetPort for pod interface", "pod.Name", pod.Name, "interface", network.Interface, "subnetPort", name)
+		} else if err != nil {
+			return nil, false, err
+		} else if !metav1.IsControlledBy(subnetPort, pod) {
+			return nil, false, fmt.Errorf("SubnetPort %s/%s already exists and is not owned by the Pod", pod.Namespace, name)
+		}
+
+		status, ready := getSubnetPortNetworkStatus(subnetPort, network)
+		if !ready {
+			allReady = false
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+
+	subnetPortList := &v1alpha1.SubnetPortList{}
+	if err := r.Client.List(ctx, subnetPortList, client.InNamespace(pod.Namespace)); err != nil {
+		return nil, false, err
+	}
+	for i := range subnetPortList.Items {
+		subnetPort := &subnetPortList.Items[i]
+		if !metav1.IsControlledBy(subnetPort, pod) || portNames.Has(subnetPort.Name) {
+			continue
+		}
+		if err := r.Client.Delete(ctx, subnetPort); client.IgnoreNotFound(err) != nil {
+			return nil, false, err
+		}
+		log.Info("deleted SubnetPort of removed pod interface", "pod.Name", pod.Name, "subnetPort", subnetPort.Name)
+	}
+	return statuses, allReady, nil
+}
+
+// deleteSecondaryPorts deletes the SubnetPorts owned by the Pod. They are deleted explicitly because the
+// completed Pods are not removed by the garbage collector.
+func (r *PodReconciler) deleteSecondaryPorts(ctx context.Context, pod *v1.Pod) error {
+	subnetPortList := &v1alpha1.SubnetPortList{}
+	if err := r.Client.List(ctx, subnetPortList, client.InNamespace(pod.Namespace)); err != nil {
+		return err
+	}
+	for i := range subnetPortList.Items {
+		subnetPort := &subnetPortList.Items[i]
+		if !metav1.IsControlledBy(subnetPort, pod) {
+			continue
+		}
+		if err := r.Client.Delete(ctx, subnetPort); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.Info("deleted SubnetPort of pod interface", "pod.Name", pod.Name, "subnetPort", subnetPort.Name)
+	}
+	return nil
+}
+
+// getSubnetPortNetworkStatus returns the interface status of the SubnetPort, and whether the SubnetPort is ready.
+func getSubnetPortNetworkStatus(subnetPort *v1alpha1.SubnetPort, network networkSelection) (networkStatus, bool) {
+	status := networkStatus{
+		Name:      fmt.Sprintf("%s/%s", network.Namespace, network.Name),
+		Interface: network.Interface,
+		MAC:       subnetPort.Status.MACAddress,
+	}
+	ready := false
+	for _, condition := range subnetPort.Status.Conditions {
+		if condition.Type == v1alpha1.Ready && condition.Status == v1.ConditionTrue {
+			ready = true
+		}
+	}
+	if !ready || len(subnetPort.Status.IPAddresses) == 0 || subnetPort.Status.IPAddresses[0].IP == "" {
+		return status, false
+	}
+	for _, address := range subnetPort.Status.IPAddresses {
+		status.IPs = append(status.IPs, address.IP)
+		if address.Gateway != "" {
+			status.Gateway = append(status.Gateway, address.Gateway)
+		}
+	}
+	return status, true
+}
+
+// setNetworkStatusAnnotation adds the network-status annotation to the annotation changes of the Pod, or removes
+// the annotation key from the Pod if all the additional networks are removed.
+func setNetworkStatusAnnotation(pod *v1.Pod, changes map[string]string, value string) {
+	if value != "" {
+		changes[servicecommon.AnnotationPodNetworkStatus] = value
+		return
+	}
+	delete(changes, servicecommon.AnnotationPodNetworkStatus)
+	delete(pod.Annotations, servicecommon.AnnotationPodNetworkStatus)
+}
+
+// buildNetworkStatusAnnotation returns the network-status annotation of the primary interface and the additional interfaces.
+func buildNetworkStatusAnnotation(pod *v1.Pod, primary networkStatus, secondaries []networkStatus) (string, error) {
+	statuses := append([]networkStatus{primary}, secondaries...)
+	value, err := json.Marshal(statuses)
+	if err != nil {
+		return "", fmt.Errorf("failed to build network status of pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,164 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package pod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func newPod(networks string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns1",
+			Name:        "pod1",
+			UID:         "pod-uid",
+			Annotations: map[string]string{servicecommon.AnnotationPodNetworks: networks},
+		},
+	}
+}
+
+func TestParsePodNetworks(t *testing.T) {
+	tests := []struct {
+		name     string
+		networks string
+		expected []networkSelection
+		hasError bool
+	}{
+		{name: "empty", networks: ""},
+		{
+			name:     "comma-separated",
+			networks: "subnet1, ns1/subnetset1@data",
+			expected: []networkSelection{
+				{Name: "subnet1", Namespace: "ns1", Interface: "net1"},
+				{Name: "subnetset1", Namespace: "ns1", Interface: "data"},
+			},
+		},
+		{
+			name:     "json",
+			networks: `[{"name":"subnet1","interface":"storage"},{"name":"subnet2"}]`,
+			expected: []networkSelection{
+				{Name: "subnet1", Namespace: "ns1", Interface: "storage"},
+				{Name: "subnet2", Namespace: "ns1", Interface: "net2"},
+			},
+		},
+		{name: "invalid json", networks: `[{"name":`, hasError: true},
+		{name: "other namespace", networks: "ns2/subnet1", hasError: true},
+		{name: "duplicate interface", networks: "subnet1@net2,subnet2", hasError: true},
+		{name: "primary interface", networks: "subnet1@eth0", hasError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networks, err := parsePodNetworks(newPod(tt.networks))
+			if tt.hasError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, networks)
+		})
+	}
+}
+
+func TestReconcileSecondaryPorts(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	pod := newPod("subnet1,subnetset1@data")
+	staleCondition := []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}}
+	stalePort := &v1alpha1.SubnetPort{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod1-old"},
+		Status:     v1alpha1.SubnetPortStatus{Conditions: staleCondition},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		pod,
+		&v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet1"}},
+		&v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnetset1"}},
+	).Build()
+	r := &PodReconciler{Client: k8sClient, Scheme: scheme}
+	ctx := context.TODO()
+	assert.NoError(t, controllerutil.SetControllerReference(pod, stalePort, scheme))
+	assert.NoError(t, k8sClient.Create(ctx, stalePort))
+
+	networks, err := parsePodNetworks(pod)
+	assert.NoError(t, err)
+	statuses, allReady, err := r.reconcileSecondaryPorts(ctx, pod, networks)
+	assert.NoError(t, err)
+	assert.False(t, allReady)
+	assert.Empty(t, statuses)
+
+	subnetPort := &v1alpha1.SubnetPort{}
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "pod1-net1"}, subnetPort))
+	assert.Equal(t, "subnet1", subnetPort.Spec.Subnet)
+	assert.True(t, metav1.IsControlledBy(subnetPort, pod))
+	assert.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "pod1-data"}, subnetPort))
+	assert.Equal(t, "subnetset1", subnetPort.Spec.SubnetSet)
+	err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "pod1-old"}, &v1alpha1.SubnetPort{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// The network status is returned when the SubnetPort is realized.
+	subnetPort.Status = v1alpha1.SubnetPortStatus{
+		Conditions:  staleCondition,
+		MACAddress:  "04:50:56:00:94:01",
+		IPAddresses: []v1alpha1.SubnetPortIPAddress{{IP: "10.0.1.5", Gateway: "10.0.1.1"}},
+	}
+	assert.NoError(t, k8sClient.Update(ctx, subnetPort))
+	statuses, allReady, err = r.reconcileSecondaryPorts(ctx, pod, networks)
+	assert.NoError(t, err)
+	assert.False(t, allReady)
+	assert.Equal(t, []networkStatus{{
+		Name:      "ns1/subnetset1",
+		Interface: "data",
+		IPs:       []string{"10.0.1.5"},
+		MAC:       "04:50:56:00:94:01",
+		Gateway:   []string{"10.0.1.1"},
+	}}, statuses)
+
+	// A network not found is an error.
+	_, _, err = r.reconcileSecondaryPorts(ctx, pod, []networkSelection{{Name: "missing", Namespace: "ns1", Interface: "net3"}})
+	assert.Error(t, err)
+
+	assert.NoError(t, r.deleteSecondaryPorts(ctx, pod))
+	subnetPortList := &v1alpha1.SubnetPortList{}
+	assert.NoError(t, k8sClient.List(ctx, subnetPortList))
+	assert.Empty(t, subnetPortList.Items)
+}
+
+func TestBuildNetworkStatusAnnotation(t *testing.T) {
+	pod := newPod("subnet1")
+	value, err := buildNetworkStatusAnnotation(pod,
+		networkStatus{Name: "ns1/pod-default", Interface: "eth0", IPs: []string{"10.0.0.5"}, MAC: "04:50:56:00:94:00", Default: true},
+		[]networkStatus{{Name: "ns1/subnet1", Interface: "net1", IPs: []string{"10.0.1.5"}, MAC: "04:50:56:00:94:01", Gateway: []string{"10.0.1.1"}}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"name":"ns1/pod-default","interface":"eth0","ips":["10.0.0.5"],"mac":"04:50:56:00:94:00","default":true},`+
+		`{"name":"ns1/subnet1","interface":"net1","ips":["10.0.1.5"],"mac":"04:50:56:00:94:01","gateway":["10.0.1.1"]}]`, value)
+}
+
+func TestSetNetworkStatusAnnotation(t *testing.T) {
+	pod := newPod("subnet1")
+	changes := map[string]string{}
+	setNetworkStatusAnnotation(pod, changes, `[{"name":"ns1/pod-default"}]`)
+	assert.Equal(t, `[{"name":"ns1/pod-default"}]`, changes[servicecommon.AnnotationPodNetworkStatus])
+
+	// The annotation key is removed when all the additional networks are removed.
+	pod.Annotations[servicecommon.AnnotationPodNetworkStatus] = `[{"name":"ns1/pod-default"}]`
+	changes = map[string]string{}
+	setNetworkStatusAnnotation(pod, changes, "")
+	assert.NotContains(t, changes, servicecommon.AnnotationPodNetworkStatus)
+	assert.NotContains(t, pod.Annotations, servicecommon.AnnotationPodNetworkStatus)
+}
@@ -23,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
@@ -62,6 +63,9 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		log.Info("skipping handling hostnetwork pod", "pod", req.NamespacedName)
 		return common.ResultNormal, nil
 	}
+	if common.HasPodSchedulingGate(pod) {
+		return r.removeSchedulingGate(ctx, pod)
+	}
 	if len(pod.Spec.NodeName) == 0 {
 		log.Info("pod is not scheduled on node yet, skipping", "pod", req.NamespacedName)
 		return common.ResultNormal, nil
@@ -79,12 +83,6 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			log.Info("added finalizer on pod", "pod", req.NamespacedName)
 		}
 
-		nsxSubnetPath, err := r.GetSubnetPathForPod(ctx, pod)
-		if err != nil {
-			log.Error(err, "failed to get NSX resource path from subnet", "pod.Name", pod.Name, "pod.UID", pod.UID)
-			return common.ResultRequeue, err
-		}
-		log.Info("got NSX subnet for pod", "NSX subnet path", nsxSubnetPath, "pod.Name", pod.Name, "pod.UID", pod.UID)
 		node, err := r.GetNodeByName(pod.Spec.NodeName)
 		if err != nil {
 			// The error at the very beginning of the operator startup is expected because at that time the node may be not cached yet. We can expect the retry to become normal.
@@ -92,6 +90,12 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			return common.ResultRequeue, err
 		}
 		contextID := *node.Id
+		nsxSubnetPath, err := r.GetSubnetPathForPod(ctx, pod, contextID)
+		if err != nil {
+			log.Error(err, "failed to get NSX resource path from subnet", "pod.Name", pod.Name, "pod.UID", pod.UID)
+			return common.ResultRequeue, err
+		}
+		log.Info("got NSX subnet for pod", "NSX subnet path", nsxSubnetPath, "pod.Name", pod.Name, "pod.UID", pod.UID)
 		nsxSubnet, err := r.SubnetService.GetSubnetByPath(nsxSubnetPath)
 		if err != nil {
 			return common.ResultRequeue, err
@@ -106,6 +110,21 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			servicecommon.AnnotationPodMAC:        strings.Trim(*nsxSubnetPortState.RealizedBindings[0].Binding.MacAddress, "\""),
 			servicecommon.AnnotationPodAttachment: *nsxSubnetPortState.Attachment.Id,
 		}
+		networks, err := parsePodNetworks(pod)
+		if err != nil {
+			log.Error(err, "invalid additional networks of pod", "pod.Name", req.NamespacedName, "pod.UID", pod.UID)
+			updateFail(r, &ctx, pod, &err)
+			return common.ResultNormal, nil
+		}
+		if len(networks) > 0 || pod.Annotations[servicecommon.AnnotationPodNetworkStatus] != "" {
+			networkStatus, err := r.getPodNetworkStatus(ctx, pod, nsxSubnetPath, nsxSubnetPortState, networks)
+			if err != nil {
+				log.Error(err, "failed to create SubnetPorts for additional networks of pod", "pod.Name", req.NamespacedName, "pod.UID", pod.UID)
+				updateFail(r, &ctx, pod, &err)
+				return common.ResultRequeue, err
+			}
+			setNetworkStatusAnnotation(pod, podAnnotationChanges, networkStatus)
+		}
 		err = util.UpdateK8sResourceAnnotation(r.Client, &ctx, pod, podAnnotationChanges)
 		if err != nil {
 			log.Error(err, "failed to update pod annotation", "pod.Name", req.NamespacedName, "pod.UID", pod.UID, "podAnnotationChanges", podAnnotationChanges)
@@ -115,6 +134,11 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	} else {
 		if controllerutil.ContainsFinalizer(pod, servicecommon.PodFinalizerName) {
 			metrics.CounterInc(r.SubnetPortService.NSXConfig, metrics.ControllerDeleteTotal, MetricResTypePod)
+			if err := r.deleteSecondaryPorts(ctx, pod); err != nil {
+				log.Error(err, "failed to delete SubnetPorts of pod, would retry exponentially", "pod", req.NamespacedName)
+				deleteFail(r, &ctx, pod, &err)
+				return common.ResultRequeue, err
+			}
 			if err := r.SubnetPortService.DeleteSubnetPort(pod.UID); err != nil {
 				log.Error(err, "deletion failed, would retry exponentially", "pod", req.NamespacedName)
 				deleteFail(r, &ctx, pod, &err)
@@ -135,6 +159,25 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	return ctrl.Result{}, nil
 }
 
+// removeSchedulingGate removes the scheduling gate from the Pod if the network of its Namespace is ready.
+// The Namespace is checked again later if it's not ready, as the Pod may be missed when the Namespace
+// controller removes the scheduling gates.
+func (r *PodReconciler) removeSchedulingGate(ctx context.Context, pod *v1.Pod) (ctrl.Result, error) {
+	namespace := &v1.Namespace{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: pod.Namespace}, namespace); err != nil {
+		log.Error(err, "failed to get namespace of pod", "pod.Name", pod.Name, "pod.Namespace", pod.Namespace)
+		return common.ResultRequeue, err
+	}
+	if !common.IsNamespaceNetworkReady(namespace) {
+		log.V(1).Info("network of namespace is not ready, keeping pod scheduling gate", "pod.Name", pod.Name, "pod.Namespace", pod.Namespace)
+		return common.ResultRequeueAfter10sec, nil
+	}
+	if err := common.RemovePodSchedulingGate(r.Client, ctx, pod); err != nil {
+		return common.ResultRequeue, err
+	}
+	return common.ResultNormal, nil
+}
+
 func (r *PodReconciler) GetNodeByName(nodeName string) (*model.HostTransportNode, error) {
 	nodes := r.NodeServiceReader.GetNodeByName(nodeName)
 	if len(nodes) == 0 {
@@ -166,6 +209,8 @@ func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			controller.Options{
 				MaxConcurrentReconciles: common.NumReconcile(),
 			}).
+		// The SubnetPorts of the additional networks are watched to publish the network status when they are realized.
+		Owns(&v1alpha1.SubnetPort{}).
 		Complete(r)
 }
 
@@ -192,6 +237,7 @@ func (r *PodReconciler) Start(mgr ctrl.Manager) error {
 		return err
 	}
 	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	go r.RefillSubnetPortPools(make(chan bool), SubnetPortPoolInterval)
 	return nil
 }
 
@@ -258,7 +304,7 @@ func deleteSuccess(r *PodReconciler, _ *context.Context, o *v1.Pod) {
 	metrics.CounterInc(r.SubnetPortService.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResTypePod)
 }
 
-func (r *PodReconciler) GetSubnetPathForPod(ctx context.Context, pod *v1.Pod) (string, error) {
+func (r *PodReconciler) GetSubnetPathForPod(ctx context.Context, pod *v1.Pod, contextID string) (string, error) {
 	subnetPath := r.SubnetPortService.GetSubnetPathForSubnetPortFromStore(string(pod.UID))
 	if len(subnetPath) > 0 {
 		log.V(1).Info("NSX subnet port had been created, returning the existing NSX subnet path", "pod.UID", pod.UID, "subnetPath", subnetPath)
@@ -268,8 +314,12 @@ func (r *PodReconciler) GetSubnetPathForPod(ctx context.Context, pod *v1.Pod) (s
 	if err != nil {
 		return "", err
 	}
+	if subnetPath := r.SubnetPortService.ClaimPooledSubnetPort(pod.UID, string(subnetSet.UID), contextID); subnetPath != "" {
+		log.Info("claimed pre-created NSX subnet port for pod", "nsxSubnetPath", subnetPath, "pod.Name", pod.Name, "pod.UID", pod.UID)
+		return subnetPath, nil
+	}
 	log.Info("got default subnetset for pod, allocating the NSX subnet", "subnetSet.Name", subnetSet.Name, "subnetSet.UID", subnetSet.UID, "pod.Name", pod.Name, "pod.UID", pod.UID)
-	subnetPath, err = common.AllocateSubnetFromSubnetSet(subnetSet, r.VPCService, r.SubnetService, r.SubnetPortService)
+	subnetPath, err = common.AllocateSubnetFromSubnetSet(subnetSet, r.VPCService, r.SubnetService, r.SubnetPortService, contextID)
 	if err != nil {
 		return subnetPath, err
 	}
@@ -277,6 +327,37 @@ func (r *PodReconciler) GetSubnetPathForPod(ctx context.Context, pod *v1.Pod) (s
 	return subnetPath, nil
 }
 
+// getPodNetworkStatus creates the SubnetPorts for the additional networks of the Pod, and returns the network-status
+// annotation of the interfaces which are ready, or empty if the Pod has no additional network.
+func (r *PodReconciler) getPodNetworkStatus(ctx context.Context, pod *v1.Pod, nsxSubnetPath string, nsxSubnetPortState *model.SegmentPortState, networks []networkSelection) (string, error) {
+	secondaries, allReady, err := r.reconcileSecondaryPorts(ctx, pod, networks)
+	if err != nil {
+		return "", err
+	}
+	if len(networks) == 0 {
+		return "", nil
+	}
+	if !allReady {
+		log.Info("SubnetPorts of pod are not ready yet", "pod.Name", pod.Name, "pod.UID", pod.UID)
+	}
+	binding := nsxSubnetPortState.RealizedBindings[0].Binding
+	primary := networkStatus{
+		Name:      fmt.Sprintf("%s/%s", pod.Namespace, servicecommon.DefaultPodSubnetSet),
+		Interface: primaryInterface,
+		MAC:       strings.Trim(*binding.MacAddress, "\""),
+		Default:   true,
+	}
+	if binding.IpAddress != nil {
+		primary.IPs = []string{*binding.IpAddress}
+	}
+	if gateway, _, err := r.SubnetPortService.GetGatewayNetmaskForSubnetPort(nil, nsxSubnetPath); err != nil {
+		log.Error(err, "failed to get gateway of pod", "pod.Name", pod.Name, "pod.UID", pod.UID)
+	} else {
+		primary.Gateway = []string{gateway}
+	}
+	return buildNetworkStatusAnnotation(pod, primary, secondaries)
+}
+
 func podIsDeleted(pod *v1.Pod) bool {
 	return !pod.ObjectMeta.DeletionTimestamp.IsZero() || pod.Status.Phase == "Succeeded" || pod.Status.Phase == "Failed"
 }
@@ -0,0 +1,136 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package pod
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetport"
+)
+
+// SubnetPortPoolInterval is the interval to refill the pre-created subnet ports claimed by the Pods.
+const SubnetPortPoolInterval = 10 * time.Second
+
+// poolLabels are the metric labels of a subnet port pool.
+type poolLabels struct {
+	namespace string
+	subnetSet string
+	node      string
+}
+
+// RefillSubnetPortPools keeps subnetport_pool_size pre-created subnet ports for each node and default Pod SubnetSet.
+// cancel is used to break the loop during UT
+func (r *PodReconciler) RefillSubnetPortPools(cancel chan bool, interval time.Duration) {
+	ctx := context.Background()
+	log.Info("subnetport pool refilling started")
+	reported := map[subnetport.SubnetPortPoolKey]poolLabels{}
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		if err := r.refillSubnetPortPools(ctx, r.SubnetPortService.NSXConfig.SubnetPortPoolSize, reported); err != nil {
+			log.Error(err, "failed to refill subnetport pools")
+		}
+	}
+}
+
+// refillSubnetPortPools creates the subnet ports missing from the pools, and deletes the pooled ports of the removed
+// nodes and SubnetSets, or exceeding the pool size. reported records the pools whose depth is exported as metric.
+func (r *PodReconciler) refillSubnetPortPools(ctx context.Context, size int, reported map[subnetport.SubnetPortPoolKey]poolLabels) error {
+	desired := map[subnetport.SubnetPortPoolKey]poolLabels{}
+	subnetSets := map[string]*v1alpha1.SubnetSet{}
+	if size > 0 {
+		nodeList := &v1.NodeList{}
+		if err := r.Client.List(ctx, nodeList); err != nil {
+			return err
+		}
+		subnetSetList := &v1alpha1.SubnetSetList{}
+		if err := r.Client.List(ctx, subnetSetList, client.MatchingLabels{servicecommon.LabelDefaultSubnetSet: servicecommon.LabelDefaultPodSubnetSet}); err != nil {
+			return err
+		}
+		for i := range subnetSetList.Items {
+			subnetSet := &subnetSetList.Items[i]
+			if !subnetSet.DeletionTimestamp.IsZero() {
+				continue
+			}
+			subnetSets[string(subnetSet.UID)] = subnetSet
+			for _, node := range nodeList.Items {
+				nsxNode, err := r.GetNodeByName(node.Name)
+				if err != nil {
+					log.V(1).Info("skipping subnetport pool of node", "node", node.Name, "error", err.Error())
+					continue
+				}
+				key := subnetport.SubnetPortPoolKey{SubnetSetUID: string(subnetSet.UID), ContextID: *nsxNode.Id}
+				desired[key] = poolLabels{namespace: subnetSet.Namespace, subnetSet: subnetSet.Name, node: node.Name}
+			}
+		}
+	}
+
+	hitError := false
+	pools := r.SubnetPortService.ListSubnetPortPools()
+	for key, ports := range pools {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		for _, port := range ports {
+			if err := r.SubnetPortService.DeletePooledSubnetPort(port); err != nil {
+				hitError = true
+			}
+		}
+	}
+	for key, labels := range reported {
+		if _, ok := desired[key]; !ok {
+			metrics.DeleteSubnetPortPoolDepth(r.SubnetPortService.NSXConfig, labels.namespace, labels.subnetSet, labels.node)
+			delete(reported, key)
+		}
+	}
+
+	for key, labels := range desired {
+		ports := pools[key]
+		for len(ports) > size {
+			if err := r.SubnetPortService.DeletePooledSubnetPort(ports[len(ports)-1]); err != nil {
+				hitError = true
+				break
+			}
+			ports = ports[:len(ports)-1]
+		}
+		depth := len(ports)
+		for ; depth < size; depth++ {
+			if err := r.createPooledSubnetPort(subnetSets[key.SubnetSetUID], key.ContextID); err != nil {
+				log.Error(err, "failed to pre-create subnet port", "subnetSet", labels.subnetSet, "namespace", labels.namespace, "node", labels.node)
+				hitError = true
+				break
+			}
+		}
+		metrics.SetSubnetPortPoolDepth(r.SubnetPortService.NSXConfig, labels.namespace, labels.subnetSet, labels.node, depth)
+		reported[key] = labels
+	}
+	if hitError {
+		return errors.New("error occurs when refilling subnetport pools")
+	}
+	return nil
+}
+
+func (r *PodReconciler) createPooledSubnetPort(subnetSet *v1alpha1.SubnetSet, contextID string) error {
+	subnetPath, err := common.AllocateSubnetFromSubnetSet(subnetSet, r.VPCService, r.SubnetService, r.SubnetPortService, contextID)
+	if err != nil {
+		return err
+	}
+	nsxSubnet, err := r.SubnetService.GetSubnetByPath(subnetPath)
+	if err != nil {
+		return err
+	}
+	return r.SubnetPortService.CreatePooledSubnetPort(nsxSubnet, string(subnetSet.UID), contextID)
+}
@@ -0,0 +1,84 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package pod
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetport"
+)
+
+type fakeNodeService struct{}
+
+func (f *fakeNodeService) GetNodeByName(nodeName string) []*model.HostTransportNode {
+	id := "id-" + nodeName
+	return []*model.HostTransportNode{{Id: &id}}
+}
+
+func TestRefillSubnetPortPools(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, v1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		&v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns1", Name: "pod-default", UID: "subnetset-1",
+			Labels: map[string]string{servicecommon.LabelDefaultSubnetSet: servicecommon.LabelDefaultPodSubnetSet},
+		}},
+		&v1alpha1.SubnetSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "user-subnetset", UID: "subnetset-2"}},
+	).Build()
+	subnetPortService := &subnetport.SubnetPortService{
+		Service: servicecommon.Service{NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{}}},
+	}
+	r := &PodReconciler{Client: k8sClient, Scheme: scheme, SubnetPortService: subnetPortService, NodeServiceReader: &fakeNodeService{}}
+
+	pools := map[subnetport.SubnetPortPoolKey][]*model.VpcSubnetPort{
+		// The pool of the removed node.
+		{SubnetSetUID: "subnetset-1", ContextID: "id-node-2"}: {{Id: servicecommon.String("stale")}},
+		{SubnetSetUID: "subnetset-1", ContextID: "id-node-1"}: {{Id: servicecommon.String("port-1")}},
+	}
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(subnetPortService), "ListSubnetPortPools", func(_ *subnetport.SubnetPortService) map[subnetport.SubnetPortPoolKey][]*model.VpcSubnetPort {
+		return pools
+	})
+	var deleted []string
+	patches.ApplyMethod(reflect.TypeOf(subnetPortService), "DeletePooledSubnetPort", func(_ *subnetport.SubnetPortService, port *model.VpcSubnetPort) error {
+		deleted = append(deleted, *port.Id)
+		return nil
+	})
+	var created []string
+	patches.ApplyPrivateMethod(reflect.TypeOf(r), "createPooledSubnetPort", func(_ *PodReconciler, subnetSet *v1alpha1.SubnetSet, contextID string) error {
+		created = append(created, string(subnetSet.UID)+"/"+contextID)
+		return nil
+	})
+	defer patches.Reset()
+
+	reported := map[subnetport.SubnetPortPoolKey]poolLabels{}
+	assert.NoError(t, r.refillSubnetPortPools(context.TODO(), 3, reported))
+	assert.Equal(t, []string{"stale"}, deleted)
+	assert.Equal(t, []string{"subnetset-1/id-node-1", "subnetset-1/id-node-1"}, created)
+	assert.Equal(t, map[subnetport.SubnetPortPoolKey]poolLabels{
+		{SubnetSetUID: "subnetset-1", ContextID: "id-node-1"}: {namespace: "ns1", subnetSet: "pod-default", node: "node-1"},
+	}, reported)
+
+	// All the pooled ports are deleted if the pool is disabled.
+	deleted, created = nil, nil
+	assert.NoError(t, r.refillSubnetPortPools(context.TODO(), 0, reported))
+	assert.ElementsMatch(t, []string{"stale", "port-1"}, deleted)
+	assert.Empty(t, created)
+	assert.Empty(t, reported)
+}
@@ -0,0 +1,57 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+// requestsForAddressGroup enqueues the SecurityPolicies whose rule peers reference the AddressGroup,
+// so that they are realized once the NSX group path of the AddressGroup is available.
+func (r *SecurityPolicyReconciler) requestsForAddressGroup(ctx context.Context, obj client.Object) []reconcile.Request {
+	spList := &v1alpha1.SecurityPolicyList{}
+	if err := r.Client.List(ctx, spList); err != nil {
+		log.Error(err, "failed to list all the security policies")
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range spList.Items {
+		sp := &spList.Items[i]
+		if securitypolicy.ReferencesAddressGroup(sp, obj.GetNamespace(), obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: sp.Namespace, Name: sp.Name}})
+		}
+	}
+	return requests
+}
+
+var PredicateFuncsAddressGroup = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldObj, okOld := e.ObjectOld.(*v1alpha1.AddressGroup)
+		newObj, okNew := e.ObjectNew.(*v1alpha1.AddressGroup)
+		if !okOld || !okNew {
+			return false
+		}
+		return oldObj.Status.NSXResourcePath != newObj.Status.NSXResourcePath ||
+			!reflect.DeepEqual(oldObj.Spec.SharedNamespaceSelector, newObj.Spec.SharedNamespaceSelector)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return true
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
@@ -24,7 +24,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 
@@ -160,6 +163,11 @@ func (r *SecurityPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			return ResultRequeue, err
 		}
 		updateSuccess(r, &ctx, obj)
+		// Requeue at the next schedule window boundary to enable or disable the scheduled rules
+		if next := r.Service.GetNextScheduleTransition(obj); !next.IsZero() {
+			log.V(1).Info("requeue at the next schedule transition", "securitypolicy", req.NamespacedName, "time", next)
+			return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+		}
 	} else {
 		if controllerutil.ContainsFinalizer(obj, servicecommon.SecurityPolicyFinalizerName) {
 			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
@@ -272,17 +280,35 @@ func (r *SecurityPolicyReconciler) setupWithManager(mgr ctrl.Manager) error {
 			&EnqueueRequestForPod{Client: k8sClient(mgr)},
 			builder.WithPredicates(PredicateFuncsPod),
 		).
+		Watches(
+			&v1alpha1.AddressGroup{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForAddressGroup),
+			builder.WithPredicates(PredicateFuncsAddressGroup),
+		).
 		Complete(r)
 }
 
 // Start setup manager and launch GC
-func (r *SecurityPolicyReconciler) Start(mgr ctrl.Manager) error {
+func (r *SecurityPolicyReconciler) Start(mgr ctrl.Manager, enableWebhook bool) error {
 	err := r.setupWithManager(mgr)
 	if err != nil {
 		return err
 	}
+	if enableWebhook {
+		mgr.GetWebhookServer().Register("/validate-nsx-vmware-com-v1alpha1-securitypolicy",
+			&webhook.Admission{
+				Handler: &SecurityPolicyValidator{
+					Client:  mgr.GetClient(),
+					Service: r.Service,
+					decoder: admission.NewDecoder(mgr.GetScheme()),
+				},
+			})
+	}
 
 	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	if r.Service.NSXConfig.EnableRuleStatistics {
+		go r.CollectRuleStatistics(make(chan bool), time.Duration(r.Service.NSXConfig.GetRuleStatisticsInterval())*time.Second)
+	}
 	return nil
 }
 
@@ -329,6 +355,52 @@ func (r *SecurityPolicyReconciler) GarbageCollector(cancel chan bool, timeout ti
 	}
 }
 
+// CollectRuleStatistics periodically reads the NSX rule statistics of SecurityPolicy CRs and publishes them
+// in SecurityPolicy status. The requests sent to NSX are rate limited by SecurityPolicyService.
+// cancel is used to break the loop during UT
+func (r *SecurityPolicyReconciler) CollectRuleStatistics(cancel chan bool, interval time.Duration) {
+	ctx := context.Background()
+	log.Info("rule statistics collector started", "interval", interval)
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		policyList := &v1alpha1.SecurityPolicyList{}
+		if err := r.Client.List(ctx, policyList); err != nil {
+			log.Error(err, "failed to list SecurityPolicy CR")
+			continue
+		}
+		for i := range policyList.Items {
+			policy := &policyList.Items[i]
+			if !policy.ObjectMeta.DeletionTimestamp.IsZero() {
+				continue
+			}
+			if err := r.updateRuleStatistics(ctx, policy); err != nil {
+				log.Error(err, "failed to update rule statistics", "securitypolicy", types.NamespacedName{Namespace: policy.Namespace, Name: policy.Name})
+			}
+		}
+	}
+}
+
+func (r *SecurityPolicyReconciler) updateRuleStatistics(ctx context.Context, secPolicy *v1alpha1.SecurityPolicy) error {
+	ruleStatistics, err := r.Service.GetRuleStatistics(secPolicy)
+	if err != nil {
+		return err
+	}
+	ruleStatistics = securitypolicy.MergeRuleStatistics(secPolicy.Status.RuleStatistics, ruleStatistics, metav1.Now())
+	if reflect.DeepEqual(secPolicy.Status.RuleStatistics, ruleStatistics) {
+		return nil
+	}
+	secPolicy.Status.RuleStatistics = ruleStatistics
+	if err := r.Client.Status().Update(ctx, secPolicy); err != nil {
+		return err
+	}
+	log.V(1).Info("updated SecurityPolicy rule statistics", "Name", secPolicy.Name, "Namespace", secPolicy.Namespace)
+	return nil
+}
+
 // It is triggered by associated controller like pod, namespace, etc.
 func reconcileSecurityPolicy(client client.Client, pods []v1.Pod, q workqueue.RateLimitingInterface) error {
 	podPortNames := getAllPodPortNames(pods)
@@ -369,14 +441,14 @@ func reconcileSecurityPolicy(client client.Client, pods []v1.Pod, q workqueue.Ra
 	return nil
 }
 
-func StartSecurityPolicyController(mgr ctrl.Manager, commonService servicecommon.Service, vpcService servicecommon.VPCServiceProvider) {
+func StartSecurityPolicyController(mgr ctrl.Manager, commonService servicecommon.Service, vpcService servicecommon.VPCServiceProvider, enableWebhook bool) {
 	securityPolicyReconcile := SecurityPolicyReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
 		Recorder: mgr.GetEventRecorderFor("securitypolicy-controller"),
 	}
 	securityPolicyReconcile.Service = securitypolicy.GetSecurityService(commonService, vpcService)
-	if err := securityPolicyReconcile.Start(mgr); err != nil {
+	if err := securityPolicyReconcile.Start(mgr, enableWebhook); err != nil {
 		log.Error(err, "failed to create controller", "controller", "SecurityPolicy")
 		os.Exit(1)
 	}
@@ -321,7 +321,7 @@ func TestSecurityPolicyReconciler_Start(t *testing.T) {
 		Scheme:  nil,
 		Service: service,
 	}
-	err := r.Start(mgr)
+	err := r.Start(mgr, false)
 	assert.NotEqual(t, err, nil)
 }
 
@@ -0,0 +1,47 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+// log is for logging in this package.
+var securitypolicylog = logf.Log.WithName("securitypolicy-webhook")
+
+//+kubebuilder:webhook:path=/validate-nsx-vmware-com-v1alpha1-securitypolicy,mutating=false,failurePolicy=fail,sideEffects=None,groups=nsx.vmware.com,resources=securitypolicies,verbs=create;update,versions=v1alpha1,name=default.securitypolicy.validating.nsx.vmware.com,admissionReviewVersions=v1
+
+// SecurityPolicyValidator rejects the SecurityPolicy which can't be realized in NSX, it runs the same
+// validation as building the NSX SecurityPolicy but doesn't call NSX.
+type SecurityPolicyValidator struct {
+	Client  client.Client
+	Service *securitypolicy.SecurityPolicyService
+	decoder *admission.Decoder
+}
+
+// Handle handles admission requests.
+func (v *SecurityPolicyValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	securityPolicy := &v1alpha1.SecurityPolicy{}
+	if err := v.decoder.Decode(req, securityPolicy); err != nil {
+		securitypolicylog.Error(err, "error while decoding SecurityPolicy", "SecurityPolicy", req.Namespace+"/"+req.Name)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if errs := v.Service.ValidateSecurityPolicy(securityPolicy); len(errs) > 0 {
+		securitypolicylog.Info("invalid SecurityPolicy", "SecurityPolicy", req.Namespace+"/"+req.Name, "errors", errs.ToAggregate().Error())
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
@@ -0,0 +1,141 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+const SimulationPath = "/debug/policy-simulation"
+
+// SimulationServer serves the policy simulation on a debug endpoint, e.g.
+// GET /debug/policy-simulation?srcPod=ns1/client&dstPod=ns2/server&protocol=TCP&port=80
+// The caller must present a bearer token which is allowed to get the non-resource URL
+// /debug/policy-simulation, the token is checked with a TokenReview and a SubjectAccessReview.
+type SimulationServer struct {
+	Addr    string
+	Client  client.Client
+	Service *securitypolicy.SecurityPolicyService
+}
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// authorize returns the HTTP status code to reply with if the request is not allowed, or 0.
+func (s *SimulationServer) authorize(r *http.Request) (int, error) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return http.StatusUnauthorized, errors.New("bearer token is required")
+	}
+	tokenReview := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := s.Client.Create(r.Context(), tokenReview); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !tokenReview.Status.Authenticated {
+		return http.StatusUnauthorized, errors.New("invalid bearer token")
+	}
+	user := tokenReview.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	accessReview := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:   user.Username,
+		UID:    user.UID,
+		Groups: user.Groups,
+		Extra:  extra,
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+			Path: SimulationPath,
+			Verb: "get",
+		},
+	}}
+	if err := s.Client.Create(r.Context(), accessReview); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !accessReview.Status.Allowed {
+		return http.StatusForbidden, errors.New("user " + user.Username + " is not allowed to get " + SimulationPath)
+	}
+	return 0, nil
+}
+
+func parseSimulationEndpoint(r *http.Request, prefix string) securitypolicy.SimulationEndpoint {
+	query := r.URL.Query()
+	return securitypolicy.SimulationEndpoint{
+		Pod:       query.Get(prefix + "Pod"),
+		VM:        query.Get(prefix + "VM"),
+		Namespace: query.Get(prefix + "Namespace"),
+		IP:        query.Get(prefix + "IP"),
+	}
+}
+
+func (s *SimulationServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if code, err := s.authorize(r); code != 0 {
+		log.V(1).Info("rejected policy simulation request", "code", code, "error", err)
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+	flow := securitypolicy.SimulationFlow{
+		Source:      parseSimulationEndpoint(r, "src"),
+		Destination: parseSimulationEndpoint(r, "dst"),
+		Protocol:    r.URL.Query().Get("protocol"),
+	}
+	if port := r.URL.Query().Get("port"); port != "" {
+		var err error
+		if flow.Port, err = strconv.Atoi(port); err != nil {
+			http.Error(w, "invalid port "+port, http.StatusBadRequest)
+			return
+		}
+	}
+	result, err := s.Service.SimulateFlow(r.Context(), flow)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Error(err, "failed to write policy simulation result")
+	}
+}
+
+// Start implements manager.Runnable, the server is stopped when ctx is done.
+func (s *SimulationServer) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(SimulationPath, s)
+	server := &http.Server{Addr: s.Addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "failed to shut down debug server")
+		}
+	}()
+	log.Info("starting debug server", "address", s.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, only the leader serves the simulation
+// since the NSX stores of the other replicas are not kept up to date.
+func (s *SimulationServer) NeedLeaderElection() bool {
+	return true
+}
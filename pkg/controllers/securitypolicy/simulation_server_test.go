@@ -0,0 +1,66 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestSimulationServer_authorize(t *testing.T) {
+	newServer := func() *SimulationServer {
+		fakeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				switch review := obj.(type) {
+				case *authenticationv1.TokenReview:
+					if review.Spec.Token == "valid" || review.Spec.Token == "readonly" {
+						review.Status.Authenticated = true
+						review.Status.User.Username = review.Spec.Token
+					}
+				case *authorizationv1.SubjectAccessReview:
+					review.Status.Allowed = review.Spec.User == "valid" &&
+						review.Spec.NonResourceAttributes.Path == SimulationPath && review.Spec.NonResourceAttributes.Verb == "get"
+				}
+				return nil
+			},
+		}).Build()
+		return &SimulationServer{Client: fakeClient}
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		code   int
+	}{
+		{name: "no token", code: http.StatusUnauthorized},
+		{name: "invalid token", header: "Bearer invalid", code: http.StatusUnauthorized},
+		{name: "forbidden", header: "Bearer readonly", code: http.StatusForbidden},
+		{name: "allowed", header: "Bearer valid", code: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, SimulationPath, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			code, _ := newServer().authorize(req)
+			assert.Equal(t, tt.code, code)
+		})
+	}
+
+	s := newServer()
+	assert.True(t, s.NeedLeaderElection())
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, SimulationPath, nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
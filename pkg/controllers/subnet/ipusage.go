@@ -0,0 +1,70 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnet
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
+)
+
+// updateSubnetIPUsage sets the IP usage and the IPUtilizationHigh condition in the Subnet status,
+// and exports the IP usage to the metrics.
+func (r *SubnetReconciler) updateSubnetIPUsage(obj *v1alpha1.Subnet, nsxSubnet *model.VpcSubnet) {
+	usage, err := r.SubnetService.GetSubnetIPUsage(nsxSubnet)
+	if err != nil {
+		// Keep the existing IP usage, it's refreshed later.
+		log.Error(err, "failed to get IP usage of Subnet", "subnet", obj.Name, "namespace", obj.Namespace)
+		return
+	}
+	obj.Status.IPUsage = usage
+	subnet.UpdateIPUtilizationCondition(&obj.Status.Conditions, usage, r.SubnetService.NSXConfig.GetIPUtilizationThreshold())
+	if usage != nil {
+		metrics.SetSubnetIPUsage(r.SubnetService.NSXConfig, MetricResTypeSubnet, obj.Namespace, obj.Name, usage.TotalIPs, usage.AllocatedIPs, usage.AvailableIPs)
+	}
+}
+
+// RefreshIPUsage updates the IP usage of the Subnets periodically, as the IPs are allocated without
+// the Subnet CR being changed.
+// cancel is used to break the loop during UT
+func (r *SubnetReconciler) RefreshIPUsage(cancel chan bool, interval time.Duration) {
+	ctx := context.Background()
+	log.Info("subnet IP usage refresher started")
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		subnetList := &v1alpha1.SubnetList{}
+		if err := r.Client.List(ctx, subnetList); err != nil {
+			log.Error(err, "failed to list subnet CR")
+			continue
+		}
+		for i := range subnetList.Items {
+			obj := &subnetList.Items[i]
+			if !obj.DeletionTimestamp.IsZero() {
+				continue
+			}
+			nsxSubnet := r.SubnetService.GetSubnetByCR(obj)
+			if nsxSubnet == nil {
+				continue
+			}
+			oldStatus := obj.Status.DeepCopy()
+			r.updateSubnetIPUsage(obj, nsxSubnet)
+			if reflect.DeepEqual(oldStatus, &obj.Status) {
+				continue
+			}
+			if err := r.Client.Status().Update(ctx, obj); err != nil {
+				log.Error(err, "failed to update subnet IP usage", "subnet", obj.Name, "namespace", obj.Namespace)
+			}
+		}
+	}
+}
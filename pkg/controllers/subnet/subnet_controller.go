@@ -11,6 +11,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -19,7 +20,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 
@@ -71,6 +74,9 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			}
 			log.V(1).Info("added finalizer on subnet CR", "subnet", req.NamespacedName)
 		}
+		if obj.Spec.NSXSubnetPath != "" {
+			return r.importSubnet(ctx, obj)
+		}
 		if obj.Spec.AccessMode == "" || obj.Spec.IPv4SubnetSize == 0 {
 			vpcNetworkConfig := r.VPCService.GetVPCNetworkConfigByNamespace(obj.Namespace)
 			if vpcNetworkConfig == nil {
@@ -90,16 +96,36 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		if tags == nil {
 			return ResultRequeue, errors.New("failed to generate subnet tags")
 		}
-		vpcInfoList := r.VPCService.ListVPCInfo(req.Namespace)
-		if len(vpcInfoList) == 0 {
+		vpcInfo, found := r.VPCService.GetVPCInfo(req.Namespace, obj.Spec.VPCName)
+		if !found {
+			log.Info("VPC of Subnet CR not found, would retry after 10 seconds", "subnet", req.NamespacedName, "VPC", obj.Spec.VPCName)
 			return ResultRequeueAfter10sec, nil
 		}
-		if _, err := r.SubnetService.CreateOrUpdateSubnet(obj, vpcInfoList[0], tags); err != nil {
+		var privateCIDRs []string
+		if vpcNetworkConfig := r.VPCService.GetVPCNetworkConfigByNamespace(obj.Namespace); vpcNetworkConfig != nil {
+			privateCIDRs = vpcNetworkConfig.PrivateIPv4CIDRs
+		}
+		if err := r.SubnetService.ValidateSubnetExpansion(obj, privateCIDRs); err != nil {
+			log.Error(err, "invalid Subnet expansion, would not retry", "subnet", req.NamespacedName)
+			expansionFail(r, &ctx, obj, err)
+			return ResultNormal, nil
+		}
+		if _, err := r.SubnetService.CreateOrUpdateSubnet(obj, vpcInfo, tags); err != nil {
 			if errors.As(err, &util.ExceedTagsError{}) {
 				log.Error(err, "exceed tags limit, would not retry", "subnet", req.NamespacedName)
 				updateFail(r, &ctx, obj, err.Error())
 				return ResultNormal, nil
 			}
+			if errors.As(err, &util.RestrictionError{}) {
+				log.Error(err, "invalid Subnet expansion, would not retry", "subnet", req.NamespacedName)
+				expansionFail(r, &ctx, obj, err)
+				return ResultNormal, nil
+			}
+			if errors.As(err, &util.SubnetExpansionError{}) {
+				log.Error(err, "Subnet expansion rejected, would retry after 5 minutes", "subnet", req.NamespacedName)
+				expansionFail(r, &ctx, obj, err)
+				return ResultRequeueAfter5mins, nil
+			}
 			log.Error(err, "operate failed, would retry exponentially", "subnet", req.NamespacedName)
 			updateFail(r, &ctx, obj, "")
 			return ResultRequeue, err
@@ -109,6 +135,7 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			updateFail(r, &ctx, obj, "")
 			return ResultRequeue, err
 		}
+		updateDHCPConfigCondition(r, &ctx, obj)
 		updateSuccess(r, &ctx, obj)
 	} else {
 		if controllerutil.ContainsFinalizer(obj, servicecommon.SubnetFinalizerName) {
@@ -125,6 +152,7 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 				return ResultRequeue, err
 			}
 			log.V(1).Info("removed finalizer", "subnet", req.NamespacedName)
+			metrics.DeleteSubnetIPUsage(r.SubnetService.NSXConfig, MetricResTypeSubnet, obj.Namespace, obj.Name)
 			deleteSuccess(r, &ctx, obj)
 		} else {
 			log.Info("finalizers cannot be recognized", "subnet", req.NamespacedName)
@@ -133,7 +161,90 @@ func (r *SubnetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	return ctrl.Result{}, nil
 }
 
+// importSubnet imports the existing NSX Subnet referenced by the Subnet CR, the NSX Subnet is only read
+// into the store and is never updated or deleted. An NSX Subnet is imported by the oldest Subnet CR only.
+func (r *SubnetReconciler) importSubnet(ctx context.Context, obj *v1alpha1.Subnet) (ctrl.Result, error) {
+	vpcInfo, found := r.VPCService.GetVPCInfo(obj.Namespace, obj.Spec.VPCName)
+	if !found {
+		log.Info("VPC of Subnet CR not found, would retry after 10 seconds", "subnet", obj.Name, "namespace", obj.Namespace, "VPC", obj.Spec.VPCName)
+		return ResultRequeueAfter10sec, nil
+	}
+	others, err := r.listSubnetsImporting(ctx, obj)
+	if err != nil {
+		log.Error(err, "failed to list Subnet CRs, would retry exponentially", "subnet", obj.Name, "namespace", obj.Namespace)
+		updateFail(r, &ctx, obj, "")
+		return ResultRequeue, err
+	}
+	for _, other := range others {
+		if isOlderSubnet(other, obj) {
+			// The Subnet is reconciled again when the older Subnet is deleted.
+			msg := fmt.Sprintf("NSX Subnet %s is already imported by Subnet %s/%s", obj.Spec.NSXSubnetPath, other.Namespace, other.Name)
+			log.Info("NSX Subnet is imported by another Subnet CR, would not retry", "subnet", obj.Name, "namespace", obj.Namespace, "reason", msg)
+			updateFail(r, &ctx, obj, msg)
+			return ResultNormal, nil
+		}
+	}
+	if _, err := r.SubnetService.ImportSubnet(obj.Spec.NSXSubnetPath, vpcInfo); err != nil {
+		if errors.As(err, &util.RestrictionError{}) {
+			log.Error(err, "invalid NSX Subnet to import, would not retry", "subnet", obj.Name, "namespace", obj.Namespace)
+			updateFail(r, &ctx, obj, err.Error())
+			return ResultNormal, nil
+		}
+		log.Error(err, "failed to import NSX Subnet, would retry exponentially", "subnet", obj.Name, "namespace", obj.Namespace)
+		updateFail(r, &ctx, obj, "")
+		return ResultRequeue, err
+	}
+	if err := r.updateSubnetStatus(obj); err != nil {
+		log.Error(err, "update subnet status failed, would retry exponentially", "subnet", obj.Name, "namespace", obj.Namespace)
+		updateFail(r, &ctx, obj, "")
+		return ResultRequeue, err
+	}
+	updateSuccess(r, &ctx, obj)
+	return ResultNormal, nil
+}
+
+// isOlderSubnet returns true if the Subnet a is created before b, the one with the smaller Namespace and name
+// is older if they are created at the same time.
+func isOlderSubnet(a, b *v1alpha1.Subnet) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// listSubnetsImporting returns the other Subnet CRs not being deleted which import the same NSX Subnet as the Subnet CR.
+func (r *SubnetReconciler) listSubnetsImporting(ctx context.Context, obj *v1alpha1.Subnet) ([]*v1alpha1.Subnet, error) {
+	subnetList := &v1alpha1.SubnetList{}
+	if err := r.Client.List(ctx, subnetList); err != nil {
+		return nil, err
+	}
+	var subnets []*v1alpha1.Subnet
+	for i := range subnetList.Items {
+		other := &subnetList.Items[i]
+		if other.UID == obj.UID || !other.DeletionTimestamp.IsZero() || other.Spec.NSXSubnetPath != obj.Spec.NSXSubnetPath {
+			continue
+		}
+		subnets = append(subnets, other)
+	}
+	return subnets, nil
+}
+
 func (r *SubnetReconciler) DeleteSubnet(obj v1alpha1.Subnet) error {
+	if obj.Spec.NSXSubnetPath != "" {
+		log.Info("NSX Subnet is imported, skip deleting it", "uid", string(obj.GetUID()), "path", obj.Spec.NSXSubnetPath)
+		others, err := r.listSubnetsImporting(context.TODO(), &obj)
+		if err != nil {
+			return err
+		}
+		// The NSX Subnet is kept in the store for the Subnet CR which imports it next.
+		if len(others) > 0 {
+			return nil
+		}
+		return r.SubnetService.RemoveImportedSubnet(obj.Spec.NSXSubnetPath)
+	}
 	nsxSubnets := r.SubnetService.SubnetStore.GetByIndex(servicecommon.TagScopeSubnetCRUID, string(obj.GetUID()))
 	if len(nsxSubnets) == 0 {
 		log.Info("no subnet found for subnet CR", "uid", string(obj.GetUID()))
@@ -149,7 +260,7 @@ func (r *SubnetReconciler) DeleteSubnet(obj v1alpha1.Subnet) error {
 }
 
 func (r *SubnetReconciler) updateSubnetStatus(obj *v1alpha1.Subnet) error {
-	nsxSubnet := r.SubnetService.SubnetStore.GetByKey(r.SubnetService.BuildSubnetID(obj))
+	nsxSubnet := r.SubnetService.GetSubnetByCR(obj)
 	if nsxSubnet == nil {
 		return errors.New("failed to get NSX Subnet from store")
 	}
@@ -162,6 +273,8 @@ func (r *SubnetReconciler) updateSubnetStatus(obj *v1alpha1.Subnet) error {
 		obj.Status.IPAddresses = append(obj.Status.IPAddresses, *status.NetworkAddress)
 	}
 	obj.Status.NSXResourcePath = *nsxSubnet.Path
+	subnet.UpdateExpansionCondition(&obj.Status.Conditions, nil)
+	r.updateSubnetIPUsage(obj, nsxSubnet)
 	return nil
 }
 
@@ -243,6 +356,31 @@ func updateFail(r *SubnetReconciler, c *context.Context, o *v1alpha1.Subnet, m s
 	metrics.CounterInc(r.SubnetService.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResTypeSubnet)
 }
 
+// expansionFail sets the ExpansionFailed condition, the existing NSX Subnet is still available with its previous CIDRs.
+func expansionFail(r *SubnetReconciler, c *context.Context, o *v1alpha1.Subnet, err error) {
+	if subnet.UpdateExpansionCondition(&o.Status.Conditions, err) {
+		if err := r.Client.Status().Update(*c, o); err != nil {
+			log.Error(err, "failed to update subnet status", "Name", o.Name, "Namespace", o.Namespace)
+		}
+	}
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, err.Error())
+	metrics.CounterInc(r.SubnetService.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResTypeSubnet)
+}
+
+// updateDHCPConfigCondition sets the DHCPConfigInvalid condition if the DHCP relay or the DNS servers are not
+// applied to the NSX Subnet for being invalid, the Subnet is still available without them.
+func updateDHCPConfigCondition(r *SubnetReconciler, c *context.Context, o *v1alpha1.Subnet) {
+	err := subnet.ValidateDHCPConfig(o.Spec.DHCPConfig)
+	if subnet.UpdateDHCPConfigCondition(&o.Status.Conditions, err) {
+		if err := r.Client.Status().Update(*c, o); err != nil {
+			log.Error(err, "failed to update subnet status", "Name", o.Name, "Namespace", o.Namespace)
+		}
+	}
+	if err != nil {
+		r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, err.Error())
+	}
+}
+
 func deleteFail(r *SubnetReconciler, c *context.Context, o *v1alpha1.Subnet, m string) {
 	r.setSubnetReadyStatusFalse(c, o, metav1.Now(), m)
 	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, m)
@@ -274,6 +412,10 @@ func (r *SubnetReconciler) setupWithManager(mgr ctrl.Manager) error {
 			&EnqueueRequestForNamespace{Client: mgr.GetClient()},
 			builder.WithPredicates(PredicateFuncsNs),
 		).
+		Watches(
+			&v1alpha1.Subnet{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForImportedSubnet),
+		).
 		WithOptions(
 			controller.Options{
 				MaxConcurrentReconciles: common.NumReconcile(),
@@ -281,6 +423,25 @@ func (r *SubnetReconciler) setupWithManager(mgr ctrl.Manager) error {
 		Complete(r)
 }
 
+// requestsForImportedSubnet enqueues the other Subnet CRs importing the same NSX Subnet when the Subnet CR is
+// being deleted, so that the next oldest one imports it.
+func (r *SubnetReconciler) requestsForImportedSubnet(ctx context.Context, obj client.Object) []reconcile.Request {
+	subnetCR, ok := obj.(*v1alpha1.Subnet)
+	if !ok || subnetCR.Spec.NSXSubnetPath == "" || subnetCR.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	others, err := r.listSubnetsImporting(ctx, subnetCR)
+	if err != nil {
+		log.Error(err, "failed to list Subnet CRs")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, other := range others {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: other.Namespace, Name: other.Name}})
+	}
+	return requests
+}
+
 func StartSubnetController(mgr ctrl.Manager, subnetService *subnet.SubnetService, subnetPortService servicecommon.SubnetPortServiceProvider, vpcService servicecommon.VPCServiceProvider) error {
 	subnetReconciler := &SubnetReconciler{
 		Client:            mgr.GetClient(),
@@ -304,6 +465,7 @@ func (r *SubnetReconciler) Start(mgr ctrl.Manager) error {
 		return err
 	}
 	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	go r.RefreshIPUsage(make(chan bool), servicecommon.GCInterval)
 	return nil
 }
 
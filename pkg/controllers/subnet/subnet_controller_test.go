@@ -10,14 +10,21 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/config"
 	mock_client "github.com/vmware-tanzu/nsx-operator/pkg/mock/controller-runtime/client"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 )
 
 func TestSubnetReconciler_GarbageCollector(t *testing.T) {
@@ -113,3 +120,195 @@ func TestSubnetReconciler_GarbageCollector(t *testing.T) {
 	r.GarbageCollector(cancel, time.Second)
 	patch.Reset()
 }
+
+type fakeVPCService struct {
+	common.VPCServiceProvider
+}
+
+func (f *fakeVPCService) GetVPCInfo(_ string, _ string) (common.VPCResourceInfo, bool) {
+	return common.VPCResourceInfo{OrgID: "default", ProjectID: "p1", VPCID: "vpc1"}, true
+}
+
+func TestSubnetReconciler_ImportSubnet(t *testing.T) {
+	path := "/orgs/default/projects/p1/vpcs/vpc1/subnets/imported"
+	obj := &v1alpha1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "imported", UID: "subnet-uid-1"},
+		Spec:       v1alpha1.SubnetSpec{NSXSubnetPath: path},
+	}
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	service := &subnet.SubnetService{
+		Service: common.Service{
+			NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{}},
+		},
+	}
+	r := &SubnetReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.Subnet{}).WithObjects(obj).Build(),
+		Scheme:        scheme,
+		SubnetService: service,
+		VPCService:    &fakeVPCService{},
+		Recorder:      record.NewFakeRecorder(10),
+	}
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "imported"}}
+
+	// The invalid NSX Subnet is not retried.
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(service), "ImportSubnet", func(_ *subnet.SubnetService, _ string, _ common.VPCResourceInfo) (*model.VpcSubnet, error) {
+		return nil, nsxutil.RestrictionError{Desc: "NSX Subnet is not in the VPC"}
+	})
+	defer patches.Reset()
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	updated := &v1alpha1.Subnet{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+
+	nsxSubnet := &model.VpcSubnet{Id: common.String("imported"), Path: common.String(path)}
+	patches.ApplyMethod(reflect.TypeOf(service), "ImportSubnet", func(_ *subnet.SubnetService, _ string, _ common.VPCResourceInfo) (*model.VpcSubnet, error) {
+		return nsxSubnet, nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(service), "GetSubnetByCR", func(_ *subnet.SubnetService, _ *v1alpha1.Subnet) *model.VpcSubnet {
+		return nsxSubnet
+	})
+	patches.ApplyMethod(reflect.TypeOf(service), "GetSubnetStatus", func(_ *subnet.SubnetService, _ *model.VpcSubnet) ([]model.VpcSubnetStatus, error) {
+		return []model.VpcSubnetStatus{{NetworkAddress: common.String("10.0.0.0/28")}}, nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(service), "GetSubnetIPUsage", func(_ *subnet.SubnetService, _ *model.VpcSubnet) (*v1alpha1.IPUsage, error) {
+		return &v1alpha1.IPUsage{TotalIPs: 12, AvailableIPs: 12}, nil
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, path, updated.Status.NSXResourcePath)
+	assert.Equal(t, []string{"10.0.0.0/28"}, updated.Status.IPAddresses)
+
+	// The newer Subnet CR importing the same NSX Subnet is rejected.
+	newer := &v1alpha1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "newer", UID: "subnet-uid-2", CreationTimestamp: metav1.NewTime(time.Now().Add(time.Hour))},
+		Spec:       v1alpha1.SubnetSpec{NSXSubnetPath: path},
+	}
+	assert.NoError(t, r.Client.Create(ctx, newer))
+	newerReq := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "newer"}}
+	result, err = r.Reconcile(ctx, newerReq)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, newerReq.NamespacedName, newer))
+	assert.Equal(t, v1.ConditionFalse, newer.Status.Conditions[0].Status)
+	assert.Contains(t, newer.Status.Conditions[0].Message, "already imported by Subnet ns1/imported")
+
+	// Deleting the Subnet CR doesn't delete the imported NSX Subnet, the NSX Subnet is kept in the store
+	// for the newer Subnet CR which is enqueued to import it.
+	patches.ApplyMethod(reflect.TypeOf(service), "DeleteSubnet", func(_ *subnet.SubnetService, _ model.VpcSubnet) error {
+		t.Error("imported NSX Subnet should not be deleted")
+		return nil
+	})
+	removed := 0
+	patches.ApplyMethod(reflect.TypeOf(service), "RemoveImportedSubnet", func(_ *subnet.SubnetService, _ string) error {
+		removed++
+		return nil
+	})
+	assert.NoError(t, r.Client.Delete(ctx, updated))
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, newerReq, r.requestsForImportedSubnet(ctx, updated)[0])
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Error(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, 0, removed)
+
+	// The NSX Subnet is removed from the store with the last Subnet CR importing it.
+	result, err = r.Reconcile(ctx, newerReq)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, newerReq.NamespacedName, newer))
+	assert.Equal(t, path, newer.Status.NSXResourcePath)
+	assert.NoError(t, r.Client.Delete(ctx, newer))
+	_, err = r.Reconcile(ctx, newerReq)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+}
+
+func TestSubnetReconciler_updateDHCPConfigCondition(t *testing.T) {
+	obj := &v1alpha1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet1"},
+		Spec: v1alpha1.SubnetSpec{
+			DHCPConfig: v1alpha1.DHCPConfig{DNSClientConfig: v1alpha1.DNSClientConfig{DNSServersIPs: []string{"10.1.1.1"}}},
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	r := &SubnetReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.Subnet{}).WithObjects(obj).Build(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+	ctx := context.TODO()
+	updated := &v1alpha1.Subnet{}
+
+	// The DNS servers can't be applied with DHCP disabled.
+	updateDHCPConfigCondition(r, &ctx, obj)
+	assert.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "subnet1"}, updated))
+	assert.Len(t, updated.Status.Conditions, 1)
+	assert.Equal(t, v1alpha1.DHCPConfigInvalid, updated.Status.Conditions[0].Type)
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+	assert.Equal(t, "InvalidDHCPConfig", updated.Status.Conditions[0].Reason)
+
+	// The condition is removed once the DHCP configuration is valid.
+	updated.Spec.DHCPConfig.EnableDHCP = true
+	updateDHCPConfigCondition(r, &ctx, updated)
+	assert.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "subnet1"}, updated))
+	assert.Empty(t, updated.Status.Conditions)
+}
+
+func TestSubnetReconciler_RefreshIPUsage(t *testing.T) {
+	objs := []client.Object{
+		&v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet1", UID: "subnet-uid-1"}},
+		&v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "dhcp", UID: "subnet-uid-2"}},
+		&v1alpha1.Subnet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "unrealized", UID: "subnet-uid-3"}},
+	}
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	service := &subnet.SubnetService{
+		Service: common.Service{
+			NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{}},
+		},
+	}
+	r := &SubnetReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.Subnet{}).WithObjects(objs...).Build(),
+		Scheme:        scheme,
+		SubnetService: service,
+	}
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(service), "GetSubnetByCR", func(_ *subnet.SubnetService, obj *v1alpha1.Subnet) *model.VpcSubnet {
+		if obj.Name == "unrealized" {
+			return nil
+		}
+		return &model.VpcSubnet{Id: common.String(obj.Name)}
+	})
+	defer patches.Reset()
+	patches.ApplyMethod(reflect.TypeOf(service), "GetSubnetIPUsage", func(_ *subnet.SubnetService, nsxSubnet *model.VpcSubnet) (*v1alpha1.IPUsage, error) {
+		if *nsxSubnet.Id == "dhcp" {
+			return nil, nil
+		}
+		return &v1alpha1.IPUsage{TotalIPs: 12, AllocatedIPs: 11, AvailableIPs: 1}, nil
+	})
+
+	cancel := make(chan bool)
+	go func() {
+		time.Sleep(time.Second)
+		cancel <- true
+	}()
+	r.RefreshIPUsage(cancel, 100*time.Millisecond)
+
+	ctx := context.TODO()
+	updated := &v1alpha1.Subnet{}
+	assert.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "subnet1"}, updated))
+	assert.Equal(t, &v1alpha1.IPUsage{TotalIPs: 12, AllocatedIPs: 11, AvailableIPs: 1}, updated.Status.IPUsage)
+	assert.Equal(t, v1alpha1.IPUtilizationHigh, updated.Status.Conditions[0].Type)
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+	for _, name := range []string{"dhcp", "unrealized"} {
+		assert.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: name}, updated))
+		assert.Nil(t, updated.Status.IPUsage)
+		assert.Empty(t, updated.Status.Conditions)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"reflect"
 	"strings"
@@ -39,6 +40,7 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetport"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 )
 
 var (
@@ -53,6 +55,7 @@ type SubnetPortReconciler struct {
 	SubnetPortService *subnetport.SubnetPortService
 	SubnetService     servicecommon.SubnetServiceProvider
 	VPCService        servicecommon.VPCServiceProvider
+	NodeServiceReader servicecommon.NodeServiceReader
 	Recorder          record.EventRecorder
 }
 
@@ -89,9 +92,18 @@ func (r *SubnetPortReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 
 		old_status := subnetPort.Status.DeepCopy()
-		nsxSubnetPath, err := r.GetSubnetPathForSubnetPort(ctx, subnetPort)
+		contextID, err := r.getContextIDForSubnetPort(ctx, subnetPort)
+		if err != nil {
+			log.Error(err, "failed to get node of the pod owning subnetport", "subnetport", req.NamespacedName)
+			return common.ResultRequeue, err
+		}
+		nsxSubnetPath, err := r.GetSubnetPathForSubnetPort(ctx, subnetPort, contextID)
 		if err != nil {
 			log.Error(err, "failed to get NSX resource path from subnet", "subnetport", subnetPort)
+			if errors.As(err, &nsxutil.RestrictionError{}) {
+				updateFail(r, &ctx, subnetPort, &err)
+				return common.ResultNormal, nil
+			}
 			return common.ResultRequeue, err
 		}
 		labels, err := r.getLabelsFromVirtualMachine(ctx, subnetPort)
@@ -103,7 +115,18 @@ func (r *SubnetPortReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		if err != nil {
 			return common.ResultRequeue, err
 		}
-		nsxSubnetPortState, err := r.SubnetPortService.CreateOrUpdateSubnetPort(subnetPort, nsxSubnet, "", labels)
+		nsxSubnetPortState, err := r.SubnetPortService.CreateOrUpdateSubnetPort(subnetPort, nsxSubnet, contextID, labels)
+		if errors.As(err, &nsxutil.RestrictionError{}) {
+			log.Error(err, "invalid address binding of subnetport", "subnetport", req.NamespacedName)
+			updateFail(r, &ctx, subnetPort, &err)
+			return common.ResultNormal, nil
+		}
+		if errors.As(err, &nsxutil.AddressUnavailableError{}) {
+			// The requested address may be released by the other port later.
+			log.Error(err, "requested address is unavailable, would retry later", "subnetport", req.NamespacedName)
+			updateFail(r, &ctx, subnetPort, &err)
+			return common.ResultRequeueAfter5mins, nil
+		}
 		if err != nil {
 			log.Error(err, "failed to create or update NSX subnet port, would retry exponentially", "subnetport", req.NamespacedName)
 			updateFail(r, &ctx, subnetPort, &err)
@@ -204,13 +227,14 @@ func (r *SubnetPortReconciler) vmMapFunc(_ context.Context, vm client.Object) []
 	return requests
 }
 
-func StartSubnetPortController(mgr ctrl.Manager, subnetPortService *subnetport.SubnetPortService, subnetService *subnet.SubnetService, vpcService *vpc.VPCService) {
+func StartSubnetPortController(mgr ctrl.Manager, subnetPortService *subnetport.SubnetPortService, subnetService *subnet.SubnetService, vpcService *vpc.VPCService, nodeService servicecommon.NodeServiceReader) {
 	subnetPortReconciler := SubnetPortReconciler{
 		Client:            mgr.GetClient(),
 		Scheme:            mgr.GetScheme(),
 		SubnetService:     subnetService,
 		SubnetPortService: subnetPortService,
 		VPCService:        vpcService,
+		NodeServiceReader: nodeService,
 		Recorder:          mgr.GetEventRecorderFor("subnetport-controller"),
 	}
 	if err := subnetPortReconciler.Start(mgr); err != nil {
@@ -286,11 +310,15 @@ func (r *SubnetPortReconciler) setSubnetPortReadyStatusTrue(ctx *context.Context
 }
 
 func (r *SubnetPortReconciler) setSubnetPortReadyStatusFalse(ctx *context.Context, subnetPort *v1alpha1.SubnetPort, transitionTime metav1.Time, err *error) {
+	message := "NSX subnet port could not be created/updated"
+	if errors.As(*err, &nsxutil.AddressUnavailableError{}) {
+		message = "NSX subnet port could not be created/updated because the requested address is unavailable"
+	}
 	newConditions := []v1alpha1.Condition{
 		{
 			Type:    v1alpha1.Ready,
 			Status:  v1.ConditionFalse,
-			Message: "NSX subnet port could not be created/updated",
+			Message: message,
 			Reason: fmt.Sprintf(
 				"error occurred while processing the SubnetPort CR. Error: %v",
 				*err,
@@ -365,7 +393,7 @@ func deleteSuccess(r *SubnetPortReconciler, _ *context.Context, o *v1alpha1.Subn
 	metrics.CounterInc(r.SubnetPortService.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResTypeSubnetPort)
 }
 
-func (r *SubnetPortReconciler) GetSubnetPathForSubnetPort(ctx context.Context, subnetPort *v1alpha1.SubnetPort) (string, error) {
+func (r *SubnetPortReconciler) GetSubnetPathForSubnetPort(ctx context.Context, subnetPort *v1alpha1.SubnetPort, contextID string) (string, error) {
 	subnetPath := r.SubnetPortService.GetSubnetPathForSubnetPortFromStore(string(subnetPort.UID))
 	if len(subnetPath) > 0 {
 		log.V(1).Info("NSX subnet port had been created, returning the existing NSX subnet path", "subnetPort.UID", subnetPort.UID, "subnetPath", subnetPath)
@@ -386,6 +414,9 @@ func (r *SubnetPortReconciler) GetSubnetPathForSubnetPort(ctx context.Context, s
 			err := fmt.Errorf("empty NSX resource path from subnet %s", subnet.Name)
 			return subnetPath, err
 		}
+		if err := r.checkVPCOfSubnetPort(subnetPort, subnet.Spec.VPCName); err != nil {
+			return "", err
+		}
 	} else if len(subnetPort.Spec.SubnetSet) > 0 {
 		subnetSet := &v1alpha1.SubnetSet{}
 		namespacedName := types.NamespacedName{
@@ -396,8 +427,14 @@ func (r *SubnetPortReconciler) GetSubnetPathForSubnetPort(ctx context.Context, s
 			log.Error(err, "subnetSet CR not found", "subnet CR", namespacedName)
 			return subnetPath, err
 		}
+		if err := r.checkVPCOfSubnetPort(subnetPort, subnetSet.Spec.VPCName); err != nil {
+			return "", err
+		}
 		log.Info("got subnetset for subnetport CR, allocating the NSX subnet", "subnetSet.Name", subnetSet.Name, "subnetSet.UID", subnetSet.UID, "subnetPort.Name", subnetPort.Name, "subnetPort.UID", subnetPort.UID)
-		subnetPath, err := common.AllocateSubnetFromSubnetSet(subnetSet, r.VPCService, r.SubnetService, r.SubnetPortService)
+		if ip := getRequestedIP(subnetPort); ip != "" {
+			return r.getSubnetPathForIP(subnetSet, ip)
+		}
+		subnetPath, err := common.AllocateSubnetFromSubnetSet(subnetSet, r.VPCService, r.SubnetService, r.SubnetPortService, contextID)
 		log.Info("allocated Subnet for SubnetPort", "subnetPath", subnetPath, "subnetPort.Name", subnetPort.Name, "subnetPort.UID", subnetPort.UID)
 		if err != nil {
 			return subnetPath, err
@@ -408,8 +445,14 @@ func (r *SubnetPortReconciler) GetSubnetPathForSubnetPort(ctx context.Context, s
 		if err != nil {
 			return "", err
 		}
+		if err := r.checkVPCOfSubnetPort(subnetPort, subnetSet.Spec.VPCName); err != nil {
+			return "", err
+		}
 		log.Info("got default subnetset for subnetport CR, allocating the NSX subnet", "subnetSet.Name", subnetSet.Name, "subnetSet.UID", subnetSet.UID, "subnetPort.Name", subnetPort.Name, "subnetPort.UID", subnetPort.UID)
-		subnetPath, err := common.AllocateSubnetFromSubnetSet(subnetSet, r.VPCService, r.SubnetService, r.SubnetPortService)
+		if ip := getRequestedIP(subnetPort); ip != "" {
+			return r.getSubnetPathForIP(subnetSet, ip)
+		}
+		subnetPath, err := common.AllocateSubnetFromSubnetSet(subnetSet, r.VPCService, r.SubnetService, r.SubnetPortService, contextID)
 		log.Info("allocated Subnet for SubnetPort", "subnetPath", subnetPath, "subnetPort.Name", subnetPort.Name, "subnetPort.UID", subnetPort.UID)
 		if err != nil {
 			return subnetPath, err
@@ -419,6 +462,74 @@ func (r *SubnetPortReconciler) GetSubnetPathForSubnetPort(ctx context.Context, s
 	return subnetPath, nil
 }
 
+// checkVPCOfSubnetPort checks the parent Subnet or SubnetSet of the SubnetPort is in the VPC specified by
+// the SubnetPort, parentVPCName is the VPC name specified by the parent.
+func (r *SubnetPortReconciler) checkVPCOfSubnetPort(subnetPort *v1alpha1.SubnetPort, parentVPCName string) error {
+	if subnetPort.Spec.VPCName == "" || subnetPort.Spec.VPCName == parentVPCName {
+		return nil
+	}
+	vpcInfo, found := r.VPCService.GetVPCInfo(subnetPort.Namespace, subnetPort.Spec.VPCName)
+	if !found {
+		return fmt.Errorf("VPC %s of SubnetPort %s not found", subnetPort.Spec.VPCName, subnetPort.Name)
+	}
+	parentVPCInfo, found := r.VPCService.GetVPCInfo(subnetPort.Namespace, parentVPCName)
+	if !found {
+		return fmt.Errorf("VPC %q of the parent of SubnetPort %s not found", parentVPCName, subnetPort.Name)
+	}
+	if vpcInfo.VPCID != parentVPCInfo.VPCID {
+		return nsxutil.RestrictionError{Desc: fmt.Sprintf("the Subnet or SubnetSet of SubnetPort %s is not in VPC %s", subnetPort.Name, subnetPort.Spec.VPCName)}
+	}
+	return nil
+}
+
+// getContextIDForSubnetPort returns the ID of the node transport node if the SubnetPort is an interface of a Pod,
+// or empty for the other SubnetPorts.
+func (r *SubnetPortReconciler) getContextIDForSubnetPort(ctx context.Context, subnetPort *v1alpha1.SubnetPort) (string, error) {
+	owner := metav1.GetControllerOf(subnetPort)
+	if owner == nil || owner.Kind != "Pod" {
+		return "", nil
+	}
+	pod := &v1.Pod{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: subnetPort.Namespace, Name: owner.Name}, pod); err != nil {
+		return "", err
+	}
+	if pod.Spec.NodeName == "" {
+		return "", fmt.Errorf("pod %s/%s is not scheduled on node yet", pod.Namespace, pod.Name)
+	}
+	nodes := r.NodeServiceReader.GetNodeByName(pod.Spec.NodeName)
+	if len(nodes) != 1 {
+		return "", fmt.Errorf("failed to get the unique node ID for node %s, found %d", pod.Spec.NodeName, len(nodes))
+	}
+	return *nodes[0].Id, nil
+}
+
+// getRequestedIP returns the IP address requested by the SubnetPort, or empty if the IP is allocated by NSX.
+func getRequestedIP(subnetPort *v1alpha1.SubnetPort) string {
+	if len(subnetPort.Spec.AddressBindings) == 0 {
+		return ""
+	}
+	return subnetPort.Spec.AddressBindings[0].IPAddress
+}
+
+// getSubnetPathForIP returns the path of the NSX Subnet of the SubnetSet which the requested IP belongs to.
+func (r *SubnetPortReconciler) getSubnetPathForIP(subnetSet *v1alpha1.SubnetSet, ip string) (string, error) {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return "", nsxutil.RestrictionError{Desc: fmt.Sprintf("invalid IP address %s", ip)}
+	}
+	nsxSubnets := r.SubnetService.GetSubnetsByIndex(servicecommon.TagScopeSubnetSetCRUID, string(subnetSet.UID))
+	nsxSubnets = append(nsxSubnets, r.SubnetService.ListImportedSubnets(subnetSet)...)
+	for _, nsxSubnet := range nsxSubnets {
+		for _, cidr := range nsxSubnet.IpAddresses {
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil && ipNet.Contains(parsedIP) {
+				log.Info("selected Subnet of the requested IP for SubnetPort", "subnetSet.Name", subnetSet.Name, "ip", ip, "subnetPath", *nsxSubnet.Path)
+				return *nsxSubnet.Path, nil
+			}
+		}
+	}
+	return "", nsxutil.RestrictionError{Desc: fmt.Sprintf("IP address %s is not in any Subnet of SubnetSet %s", ip, subnetSet.Name)}
+}
+
 func (r *SubnetPortReconciler) updateSubnetStatusOnSubnetPort(subnetPort *v1alpha1.SubnetPort, nsxSubnetPath string) error {
 	gateway, netmask, err := r.SubnetPortService.GetGatewayNetmaskForSubnetPort(subnetPort, nsxSubnetPath)
 	if err != nil {
@@ -20,6 +20,7 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetport"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -314,3 +315,35 @@ func TestSubnetPortReconciler_GarbageCollector(t *testing.T) {
 	}()
 	r.GarbageCollector(cancel, time.Second)
 }
+
+type fakeVPCService struct {
+	common.VPCServiceProvider
+	vpcs map[string]string
+}
+
+func (f *fakeVPCService) GetVPCInfo(_ string, vpcName string) (common.VPCResourceInfo, bool) {
+	vpcID, ok := f.vpcs[vpcName]
+	return common.VPCResourceInfo{VPCID: vpcID}, ok
+}
+
+func TestSubnetPortReconciler_CheckVPCOfSubnetPort(t *testing.T) {
+	r := &SubnetPortReconciler{
+		VPCService: &fakeVPCService{vpcs: map[string]string{"": "vpc-1", "vpc-default": "vpc-1", "vpc-data": "vpc-2"}},
+	}
+	subnetPort := &v1alpha1.SubnetPort{ObjectMeta: metav1.ObjectMeta{Name: "port1", Namespace: "ns1"}}
+
+	// The VPC is not specified by the SubnetPort.
+	assert.NoError(t, r.checkVPCOfSubnetPort(subnetPort, "vpc-data"))
+
+	subnetPort.Spec.VPCName = "vpc-default"
+	assert.NoError(t, r.checkVPCOfSubnetPort(subnetPort, "vpc-default"))
+	// The parent is in the default VPC.
+	assert.NoError(t, r.checkVPCOfSubnetPort(subnetPort, ""))
+	err := r.checkVPCOfSubnetPort(subnetPort, "vpc-data")
+	assert.ErrorAs(t, err, &nsxutil.RestrictionError{})
+
+	subnetPort.Spec.VPCName = "dummy"
+	err = r.checkVPCOfSubnetPort(subnetPort, "")
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &nsxutil.RestrictionError{}))
+}
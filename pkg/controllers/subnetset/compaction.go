@@ -0,0 +1,112 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetset
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+// CompactionGracePeriod is how long a Subnet of SubnetSet keeps empty before it's reclaimed,
+// so that the Subnet is not deleted and created again for the Pods restarted in a short time.
+const CompactionGracePeriod = 10 * time.Minute
+
+// CompactSubnetSets reclaims the Subnets of the SubnetSets which have no port for the grace period.
+// cancel is used to break the loop during UT
+func (r *SubnetSetReconciler) CompactSubnetSets(cancel chan bool, interval time.Duration, gracePeriod time.Duration) {
+	ctx := context.Background()
+	log.Info("subnetset compaction started")
+	// emptySince records the first time a Subnet is found empty, keyed by the Subnet ID.
+	emptySince := map[string]time.Time{}
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+
+		subnetSetList := &v1alpha1.SubnetSetList{}
+		if err := r.Client.List(ctx, subnetSetList); err != nil {
+			log.Error(err, "failed to list SubnetSet CR")
+			continue
+		}
+		subnetIDs := sets.New[string]()
+		for i := range subnetSetList.Items {
+			subnetSet := &subnetSetList.Items[i]
+			if !subnetSet.DeletionTimestamp.IsZero() {
+				continue
+			}
+			for _, subnet := range r.SubnetService.ListSubnetCreatedBySubnetSet(string(subnetSet.UID)) {
+				subnetIDs.Insert(*subnet.Id)
+			}
+			if err := r.compactSubnetSet(subnetSet, emptySince, time.Now(), gracePeriod); err != nil {
+				log.Error(err, "failed to compact SubnetSet", "subnetset", subnetSet.Name, "namespace", subnetSet.Namespace)
+			}
+		}
+		for id := range emptySince {
+			if !subnetIDs.Has(id) {
+				delete(emptySince, id)
+			}
+		}
+	}
+}
+
+// compactSubnetSet deletes the Subnets of the SubnetSet which have been empty for the grace period,
+// and keeps at least spec.minSubnets Subnets. The status of the SubnetSet is updated if any Subnet is deleted.
+func (r *SubnetSetReconciler) compactSubnetSet(subnetSet *v1alpha1.SubnetSet, emptySince map[string]time.Time, now time.Time, gracePeriod time.Duration) error {
+	// Hold the SubnetSet lock, so that no Subnet being deleted is allocated to a new port. The Subnets allocated
+	// within the grace period are skipped, as the ports may not be created on them yet.
+	lock := common.LockSubnetSet(subnetSet.UID)
+	defer lock.Unlock()
+
+	nsxSubnets := r.SubnetService.ListSubnetCreatedBySubnetSet(string(subnetSet.UID))
+	sort.Slice(nsxSubnets, func(i, j int) bool {
+		return *nsxSubnets[i].Path < *nsxSubnets[j].Path
+	})
+	remaining := len(nsxSubnets)
+	deleted := 0
+	hitError := false
+	for _, subnet := range nsxSubnets {
+		if len(r.SubnetPortService.GetPortsOfSubnet(*subnet.Id)) > 0 || lock.IsSubnetRecentlySelected(*subnet.Path, now, gracePeriod) {
+			delete(emptySince, *subnet.Id)
+			continue
+		}
+		since, ok := emptySince[*subnet.Id]
+		if !ok {
+			emptySince[*subnet.Id] = now
+			continue
+		}
+		if now.Sub(since) < gracePeriod || remaining <= subnetSet.Spec.MinSubnets {
+			continue
+		}
+		log.Info("reclaiming empty Subnet of SubnetSet", "subnetset", subnetSet.Name, "namespace", subnetSet.Namespace, "ID", *subnet.Id, "emptySince", since)
+		metrics.CounterInc(r.SubnetService.NSXConfig, metrics.ControllerDeleteTotal, MetricResTypeSubnetSet)
+		if err := r.SubnetService.DeleteSubnet(*subnet); err != nil {
+			metrics.CounterInc(r.SubnetService.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResTypeSubnetSet)
+			hitError = true
+			continue
+		}
+		metrics.CounterInc(r.SubnetService.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResTypeSubnetSet)
+		delete(emptySince, *subnet.Id)
+		remaining--
+		deleted++
+	}
+	if deleted > 0 {
+		if err := r.SubnetService.UpdateSubnetSetStatus(subnetSet); err != nil {
+			return err
+		}
+	}
+	if hitError {
+		return errors.New("error occurs when reclaiming subnet")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetset
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
+)
+
+type fakeSubnetPortService struct {
+	ports map[string]int
+}
+
+func (f *fakeSubnetPortService) GetPortsOfSubnet(nsxSubnetID string) []*model.VpcSubnetPort {
+	return make([]*model.VpcSubnetPort, f.ports[nsxSubnetID])
+}
+
+func TestCompactSubnetSet(t *testing.T) {
+	subnetService := &subnet.SubnetService{
+		Service: servicecommon.Service{NSXConfig: &config.NSXOperatorConfig{NsxConfig: &config.NsxConfig{}}},
+	}
+	portService := &fakeSubnetPortService{ports: map[string]int{"subnet-1": 2}}
+	r := &SubnetSetReconciler{SubnetService: subnetService, SubnetPortService: portService}
+	subnetSet := &v1alpha1.SubnetSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pod-default", UID: "subnetset-uid"},
+		Spec:       v1alpha1.SubnetSetSpec{MinSubnets: 2},
+	}
+
+	nsxSubnets := map[string]*model.VpcSubnet{}
+	for _, id := range []string{"subnet-1", "subnet-2", "subnet-3"} {
+		id := id
+		path := "/orgs/default/projects/p1/vpcs/v1/subnets/" + id
+		nsxSubnets[id] = &model.VpcSubnet{Id: &id, Path: &path}
+	}
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(subnetService), "ListSubnetCreatedBySubnetSet", func(_ *subnet.SubnetService, _ string) []*model.VpcSubnet {
+		var subnets []*model.VpcSubnet
+		for _, s := range nsxSubnets {
+			subnets = append(subnets, s)
+		}
+		return subnets
+	})
+	var deleted []string
+	deleteErr := error(nil)
+	patches.ApplyMethod(reflect.TypeOf(subnetService), "DeleteSubnet", func(_ *subnet.SubnetService, nsxSubnet model.VpcSubnet) error {
+		if deleteErr != nil {
+			return deleteErr
+		}
+		deleted = append(deleted, *nsxSubnet.Id)
+		delete(nsxSubnets, *nsxSubnet.Id)
+		return nil
+	})
+	statusUpdated := 0
+	patches.ApplyMethod(reflect.TypeOf(subnetService), "UpdateSubnetSetStatus", func(_ *subnet.SubnetService, _ *v1alpha1.SubnetSet) error {
+		statusUpdated++
+		return nil
+	})
+	defer patches.Reset()
+
+	emptySince := map[string]time.Time{}
+	now := time.Now()
+	// The empty Subnets are recorded at the first time.
+	assert.NoError(t, r.compactSubnetSet(subnetSet, emptySince, now, time.Minute))
+	assert.Empty(t, deleted)
+	assert.Equal(t, map[string]time.Time{"subnet-2": now, "subnet-3": now}, emptySince)
+
+	// Not deleted in the grace period.
+	assert.NoError(t, r.compactSubnetSet(subnetSet, emptySince, now.Add(30*time.Second), time.Minute))
+	assert.Empty(t, deleted)
+
+	// The deletion failure is returned and retried next time.
+	deleteErr = errors.New("nsx error")
+	assert.Error(t, r.compactSubnetSet(subnetSet, emptySince, now.Add(2*time.Minute), time.Minute))
+	assert.Equal(t, 0, statusUpdated)
+	deleteErr = nil
+
+	// Only one Subnet is deleted to keep minSubnets.
+	assert.NoError(t, r.compactSubnetSet(subnetSet, emptySince, now.Add(2*time.Minute), time.Minute))
+	assert.Equal(t, []string{"subnet-2"}, deleted)
+	assert.Equal(t, 1, statusUpdated)
+	assert.Equal(t, map[string]time.Time{"subnet-3": now}, emptySince)
+
+	// The Subnet with ports is no longer empty.
+	portService.ports["subnet-3"] = 1
+	assert.NoError(t, r.compactSubnetSet(subnetSet, emptySince, now.Add(3*time.Minute), time.Minute))
+	assert.Empty(t, emptySince)
+	assert.Equal(t, []string{"subnet-2"}, deleted)
+
+	// The Subnet allocated to a port within the grace period is not reclaimed before the port is created.
+	subnetSet.Spec.MinSubnets = 1
+	portService.ports["subnet-3"] = 0
+	emptySince["subnet-3"] = now
+	lock := common.LockSubnetSet(subnetSet.UID)
+	lock.MarkSubnetSelected(*nsxSubnets["subnet-3"].Path, now.Add(3*time.Minute))
+	lock.Unlock()
+	assert.NoError(t, r.compactSubnetSet(subnetSet, emptySince, now.Add(3*time.Minute+30*time.Second), time.Minute))
+	assert.Equal(t, []string{"subnet-2"}, deleted)
+	assert.Empty(t, emptySince)
+
+	// The Subnet is reclaimed once the selection expires and the grace period passes again.
+	assert.NoError(t, r.compactSubnetSet(subnetSet, emptySince, now.Add(5*time.Minute), time.Minute))
+	assert.NoError(t, r.compactSubnetSet(subnetSet, emptySince, now.Add(7*time.Minute), time.Minute))
+	assert.Equal(t, []string{"subnet-2", "subnet-3"}, deleted)
+}
@@ -0,0 +1,45 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetset
+
+import (
+	"context"
+	"time"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+)
+
+// RefreshIPUsage updates the Subnets and the IP usage in the SubnetSet status periodically, as the IPs
+// are allocated without the SubnetSet CR being changed.
+// cancel is used to break the loop during UT
+func (r *SubnetSetReconciler) RefreshIPUsage(cancel chan bool, interval time.Duration) {
+	ctx := context.Background()
+	log.Info("subnetset IP usage refresher started")
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		subnetSetList := &v1alpha1.SubnetSetList{}
+		if err := r.Client.List(ctx, subnetSetList); err != nil {
+			log.Error(err, "failed to list SubnetSet CR")
+			continue
+		}
+		for i := range subnetSetList.Items {
+			subnetSet := &subnetSetList.Items[i]
+			if !subnetSet.DeletionTimestamp.IsZero() {
+				continue
+			}
+			if err := r.SubnetService.UpdateSubnetSetStatus(subnetSet); err != nil {
+				log.Error(err, "failed to update SubnetSet IP usage", "subnetset", subnetSet.Name, "namespace", subnetSet.Namespace)
+				continue
+			}
+			if usage := subnetSet.Status.IPUsage; usage != nil {
+				metrics.SetSubnetIPUsage(r.SubnetService.NSXConfig, MetricResTypeSubnetSet, subnetSet.Namespace, subnetSet.Name, usage.TotalIPs, usage.AllocatedIPs, usage.AvailableIPs)
+			}
+		}
+	}
+}
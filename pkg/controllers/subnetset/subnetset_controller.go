@@ -19,10 +19,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 
-	"github.com/vmware-tanzu/nsx-operator/pkg/config"
 	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
 	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
@@ -103,6 +103,7 @@ func (r *SubnetSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 				log.Error(err, "failed to update subnetset tags")
 			}
 		}
+		updateDHCPConfigCondition(r, &ctx, obj)
 		updateSuccess(r, &ctx, obj)
 	} else {
 		if controllerutil.ContainsFinalizer(obj, servicecommon.SubnetSetFinalizerName) {
@@ -118,7 +119,9 @@ func (r *SubnetSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 				deleteFail(r, &ctx, obj, "")
 				return ResultRequeue, err
 			}
+			common.RemoveSubnetSetLock(obj.UID)
 			log.V(1).Info("removed finalizer", "subnetset", req.NamespacedName)
+			metrics.DeleteSubnetIPUsage(r.SubnetService.NSXConfig, MetricResTypeSubnetSet, obj.Namespace, obj.Name)
 			deleteSuccess(r, &ctx, obj)
 		} else {
 			log.Info("finalizers cannot be recognized", "subnetset", req.NamespacedName)
@@ -133,6 +136,20 @@ func updateFail(r *SubnetSetReconciler, c *context.Context, o *v1alpha1.SubnetSe
 	metrics.CounterInc(r.SubnetService.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResTypeSubnetSet)
 }
 
+// updateDHCPConfigCondition sets the DHCPConfigInvalid condition if the DHCP relay or the DNS servers are not
+// applied to the NSX Subnets of the SubnetSet for being invalid.
+func updateDHCPConfigCondition(r *SubnetSetReconciler, c *context.Context, o *v1alpha1.SubnetSet) {
+	err := subnet.ValidateDHCPConfig(o.Spec.DHCPConfig)
+	if subnet.UpdateDHCPConfigCondition(&o.Status.Conditions, err) {
+		if err := r.Client.Status().Update(*c, o); err != nil {
+			log.Error(err, "failed to update subnetset status", "Name", o.Name, "Namespace", o.Namespace)
+		}
+	}
+	if err != nil {
+		r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, err.Error())
+	}
+}
+
 func deleteFail(r *SubnetSetReconciler, c *context.Context, o *v1alpha1.SubnetSet, m string) {
 	r.setSubnetSetReadyStatusFalse(c, o, metav1.Now(), m)
 	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, m)
@@ -262,13 +279,9 @@ func (r *SubnetSetReconciler) GarbageCollector(cancel chan bool, timeout time.Du
 			continue
 		}
 
+		// The empty Subnets of the existing SubnetSets are reclaimed by CompactSubnetSets after the grace period.
 		subnetSetIDs := sets.New[string]()
 		for _, subnetSet := range subnetSetList.Items {
-			if err := r.DeleteSubnetForSubnetSet(subnetSet, true); err != nil {
-				metrics.CounterInc(r.SubnetService.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResTypeSubnetSet)
-			} else {
-				metrics.CounterInc(r.SubnetService.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResTypeSubnetSet)
-			}
 			subnetSetIDs.Insert(string(subnetSet.UID))
 		}
 		for _, subnet := range nsxSubnetList {
@@ -334,18 +347,16 @@ func (r *SubnetSetReconciler) Start(mgr ctrl.Manager, enableWebhook bool) error
 		return err
 	}
 	if enableWebhook {
-		hookServer := webhook.NewServer(webhook.Options{
-			Port:    config.WebhookServerPort,
-			CertDir: config.WebhookCertDir,
-		})
-		if err := mgr.Add(hookServer); err != nil {
-			return err
-		}
-		hookServer.Register("/validate-nsx-vmware-com-v1alpha1-subnetset",
+		mgr.GetWebhookServer().Register("/validate-nsx-vmware-com-v1alpha1-subnetset",
 			&webhook.Admission{
-				Handler: &SubnetSetValidator{Client: mgr.GetClient()},
+				Handler: &SubnetSetValidator{
+					Client:  mgr.GetClient(),
+					decoder: admission.NewDecoder(mgr.GetScheme()),
+				},
 			})
 	}
 	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	go r.CompactSubnetSets(make(chan bool), servicecommon.GCInterval, CompactionGracePeriod)
+	go r.RefreshIPUsage(make(chan bool), servicecommon.GCInterval)
 	return nil
 }
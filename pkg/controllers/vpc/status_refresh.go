@@ -0,0 +1,77 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpc
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// updateVPCRealizedStatus sets the realized state of the NSX VPC and the count of the NSX resources in the VPC
+// in the status. The realized state of the existing status is kept if it can't be read.
+func (r *VPCReconciler) updateVPCRealizedStatus(obj *v1alpha1.VPC, nsxVPC *model.Vpc, status *v1alpha1.VPCStatus) {
+	state, realizedErr, err := r.Service.GetVPCRealizedState(*nsxVPC)
+	if err != nil {
+		log.Error(err, "failed to read realized state of VPC, keep the existing one", "VPC", obj.Name, "Namespace", obj.Namespace)
+		state, realizedErr = obj.Status.RealizedState, obj.Status.RealizedError
+	}
+	status.RealizedState = state
+	status.RealizedError = realizedErr
+
+	if r.SubnetService != nil {
+		status.Inventory.Subnets = len(r.SubnetService.ListSubnetByVPCPath(*nsxVPC.Path))
+	}
+	if r.SubnetPortService != nil {
+		status.Inventory.SubnetPorts = len(r.SubnetPortService.ListSubnetPortByVPCPath(*nsxVPC.Path))
+	}
+	if r.StaticRouteService != nil {
+		status.Inventory.StaticRoutes = len(r.StaticRouteService.ListStaticRouteByVPCPath(*nsxVPC.Path))
+	}
+	if r.VPCPeeringService != nil {
+		status.Inventory.StaticRoutes += len(r.VPCPeeringService.ListStaticRouteByVPCPath(*nsxVPC.Path))
+	}
+}
+
+// RefreshVPCStatus updates the realized state and the inventory of the VPCs periodically, as they are changed
+// without the VPC CR being changed.
+// cancel is used to break the loop during UT
+func (r *VPCReconciler) RefreshVPCStatus(cancel chan bool, interval time.Duration) {
+	ctx := context.Background()
+	log.Info("VPC status refresher started")
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(interval):
+		}
+		vpcList := &v1alpha1.VPCList{}
+		if err := r.Client.List(ctx, vpcList); err != nil {
+			log.Error(err, "failed to list VPC CR")
+			continue
+		}
+		for i := range vpcList.Items {
+			obj := &vpcList.Items[i]
+			if !obj.DeletionTimestamp.IsZero() {
+				continue
+			}
+			nsxVPC := r.Service.GetVPCByCRUID(string(obj.UID))
+			if nsxVPC == nil || nsxVPC.Path == nil {
+				continue
+			}
+			oldStatus := obj.Status.DeepCopy()
+			r.updateVPCRealizedStatus(obj, nsxVPC, &obj.Status)
+			if reflect.DeepEqual(oldStatus, &obj.Status) {
+				continue
+			}
+			if err := r.Client.Status().Update(ctx, obj); err != nil {
+				log.Error(err, "failed to update VPC status", "VPC", obj.Name, "Namespace", obj.Namespace)
+			}
+		}
+	}
+}
@@ -7,11 +7,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -23,7 +23,11 @@ import (
 	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
 	_ "github.com/vmware-tanzu/nsx-operator/pkg/nsx/ratelimiter"
 	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/staticroute"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetport"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpcpeering"
 )
 
 var (
@@ -40,6 +44,12 @@ type VPCReconciler struct {
 	Scheme   *apimachineryruntime.Scheme
 	Service  *vpc.VPCService
 	Recorder record.EventRecorder
+	// The services below are used to count the NSX resources in the VPC for the VPC CR status,
+	// the resources are not counted if the service is not set.
+	SubnetService      *subnet.SubnetService
+	SubnetPortService  *subnetport.SubnetPortService
+	StaticRouteService *staticroute.StaticRouteService
+	VPCPeeringService  *vpcpeering.VPCPeeringService
 }
 
 func (r *VPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -80,7 +90,7 @@ func (r *VPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		snatIP, path, cidr := "", "", ""
 		// currently, auto snat is not exposed, and use default value True
 		// checking autosnat to support future extension in vpc configuration
-		if *createdVpc.ServiceGateway.AutoSnat {
+		if createdVpc.ServiceGateway != nil && createdVpc.ServiceGateway.AutoSnat != nil && *createdVpc.ServiceGateway.AutoSnat {
 			snatIP, err = r.Service.GetDefaultSNATIP(*createdVpc)
 			if err != nil {
 				log.Error(err, "failed to read default SNAT ip from VPC", "VPC", createdVpc.Id)
@@ -91,7 +101,8 @@ func (r *VPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		// if lb vpc enabled, read avi subnet path and cidr
 		// nsx bug, if set LoadBalancerVpcEndpoint.Enabled to false, when read this vpc back,
 		// LoadBalancerVpcEndpoint.Enabled will become a nil pointer.
-		if createdVpc.LoadBalancerVpcEndpoint.Enabled != nil && *createdVpc.LoadBalancerVpcEndpoint.Enabled {
+		// the adopted VPC may be created without the load balancer endpoint.
+		if createdVpc.LoadBalancerVpcEndpoint != nil && createdVpc.LoadBalancerVpcEndpoint.Enabled != nil && *createdVpc.LoadBalancerVpcEndpoint.Enabled {
 			path, cidr, err = r.Service.GetAVISubnetInfo(*createdVpc)
 			if err != nil {
 				log.Error(err, "failed to read lb subnet path and cidr", "VPC", createdVpc.Id)
@@ -99,17 +110,20 @@ func (r *VPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			}
 		}
 
-		updateSuccess(r, &ctx, obj, r.Client, *createdVpc.Path, snatIP, path, cidr, nc.PrivateIPv4CIDRs)
+		status := r.buildVPCStatus(obj, createdVpc, nc)
+		status.DefaultSNATIP = snatIP
+		status.LBSubnetPath = path
+		status.LBSubnetCIDR = cidr
+		updateSuccess(r, &ctx, obj, r.Client, status)
 	} else {
 		if controllerutil.ContainsFinalizer(obj, commonservice.VPCFinalizerName) {
 			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, common.MetricResTypeVPC)
-			vpcs := r.Service.GetVPCsByNamespace(obj.GetNamespace())
+			vpc := r.Service.GetVPCByCRUID(string(obj.UID))
 			// if nsx resource do not exist, continue to remove finalizer, or the crd can not be removed
-			if len(vpcs) == 0 {
+			if vpc == nil {
 				// when nsx vpc not found in vpc store, skip deleting NSX VPC
 				log.Info("can not find VPC in store, skip deleting NSX VPC, remove finalizer from VPC CR")
 			} else {
-				vpc := vpcs[0]
 				if err := r.Service.DeleteVPC(*vpc.Path); err != nil {
 					log.Error(err, "failed to delete VPC CR, would retry exponentially", "VPC", req.NamespacedName)
 					deleteFail(r, &ctx, obj, &err, r.Client)
@@ -136,6 +150,26 @@ func (r *VPCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	return common.ResultNormal, nil
 }
 
+// buildVPCStatus builds the VPC CR status from the NSX VPC, its realized state and the NSX resources
+// of the VPC in the stores.
+func (r *VPCReconciler) buildVPCStatus(obj *v1alpha1.VPC, nsxVPC *model.Vpc, nc *commonservice.VPCNetworkConfigInfo) v1alpha1.VPCStatus {
+	status := v1alpha1.VPCStatus{
+		NSXResourcePath:    *nsxVPC.Path,
+		PrivateIPv4CIDRs:   nc.PrivateIPv4CIDRs,
+		EdgeClusterPath:    vpc.GetEdgeClusterPath(nsxVPC),
+		ExternalIPv4Blocks: nsxVPC.ExternalIpv4Blocks,
+	}
+	if nsxVPC.ShortId != nil {
+		status.ShortID = *nsxVPC.ShortId
+	}
+	if nsxVPC.DefaultGatewayPath != nil {
+		status.GatewayPath = *nsxVPC.DefaultGatewayPath
+	}
+
+	r.updateVPCRealizedStatus(obj, nsxVPC, &status)
+	return status
+}
+
 func (r *VPCReconciler) setupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.VPC{}).
@@ -143,16 +177,6 @@ func (r *VPCReconciler) setupWithManager(mgr ctrl.Manager) error {
 			controller.Options{
 				MaxConcurrentReconciles: common.NumReconcile(),
 			}).
-		Watches(
-			// For created/removed network config, add/remove from vpc network config cache.
-			// For modified network config, currently only support appending ips to public ip blocks,
-			// update network config in cache and update nsx vpc object.
-			&v1alpha1.VPCNetworkConfiguration{},
-			&VPCNetworkConfigurationHandler{
-				Client:     mgr.GetClient(),
-				vpcService: r.Service,
-			},
-			builder.WithPredicates(VPCNetworkConfigurationPredicate)).
 		Complete(r)
 }
 
@@ -164,6 +188,7 @@ func (r *VPCReconciler) Start(mgr ctrl.Manager) error {
 	}
 
 	go r.GarbageCollector(make(chan bool), commonservice.GCInterval)
+	go r.RefreshVPCStatus(make(chan bool), commonservice.GCInterval)
 	return nil
 }
 
@@ -196,7 +221,8 @@ func (r *VPCReconciler) GarbageCollector(cancel chan bool, timeout time.Duration
 		}
 
 		for _, elem := range nsxVPCList {
-			if crdVPCSet.Has(*elem.Id) {
+			// the adopted VPC is not named with the VPC CR UID
+			if crdVPCSet.Has(*elem.Id) || crdVPCSet.Has(vpc.GetVPCCRUID(&elem)) {
 				continue
 			}
 
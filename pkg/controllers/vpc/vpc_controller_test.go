@@ -0,0 +1,171 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/staticroute"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnet"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/subnetport"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpcpeering"
+)
+
+func newFakeVPCReconciler(objs ...client.Object) *VPCReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.VPC{}).WithObjects(objs...).Build()
+	return &VPCReconciler{
+		Client: k8sClient,
+		Scheme: scheme,
+		Service: &vpc.VPCService{
+			Service: commonservice.Service{
+				NSXConfig: &config.NSXOperatorConfig{
+					NsxConfig: &config.NsxConfig{EnforcementPoint: "vmc-enforcementpoint"},
+					CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test"},
+				},
+			},
+		},
+		Recorder:           record.NewFakeRecorder(10),
+		SubnetService:      &subnet.SubnetService{},
+		SubnetPortService:  &subnetport.SubnetPortService{},
+		StaticRouteService: &staticroute.StaticRouteService{},
+		VPCPeeringService:  &vpcpeering.VPCPeeringService{},
+	}
+}
+
+func TestVPCReconciler_ReconcileStatus(t *testing.T) {
+	obj := &v1alpha1.VPC{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vpc1", UID: "vpc-uid-1"}}
+	r := newFakeVPCReconciler(obj)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "vpc1"}}
+
+	vpcPath := "/orgs/default/projects/project-1/vpcs/vpc-1"
+	nsxVPC := &model.Vpc{
+		Id:                 commonservice.String("vpc-1"),
+		Path:               commonservice.String(vpcPath),
+		ShortId:            commonservice.String("vpc1short"),
+		DefaultGatewayPath: commonservice.String("/infra/tier-0s/t0-1"),
+		ExternalIpv4Blocks: []string{"/infra/ip-blocks/external-1"},
+		SiteInfos:          []model.SiteInfo{{EdgeClusterPaths: []string{"/infra/sites/default/enforcement-points/default/edge-clusters/ec-1"}}},
+	}
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "CreateorUpdateVPC", func(_ *vpc.VPCService, _ *v1alpha1.VPC) (*model.Vpc, *commonservice.VPCNetworkConfigInfo, error) {
+		return nsxVPC, &commonservice.VPCNetworkConfigInfo{PrivateIPv4CIDRs: []string{"172.26.0.0/16"}}, nil
+	})
+	defer patches.Reset()
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateAVIRule", func(_ *vpc.VPCService, _ *model.Vpc, _ string) error {
+		return nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "GetVPCRealizedState", func(_ *vpc.VPCService, _ model.Vpc) (string, string, error) {
+		return model.GenericPolicyRealizedResource_STATE_ERROR, "edge cluster is not available", nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(r.SubnetService), "ListSubnetByVPCPath", func(_ *subnet.SubnetService, path string) []*model.VpcSubnet {
+		assert.Equal(t, vpcPath, path)
+		return []*model.VpcSubnet{{}, {}}
+	})
+	patches.ApplyMethod(reflect.TypeOf(r.SubnetPortService), "ListSubnetPortByVPCPath", func(_ *subnetport.SubnetPortService, _ string) []*model.VpcSubnetPort {
+		return []*model.VpcSubnetPort{{}, {}, {}}
+	})
+	patches.ApplyMethod(reflect.TypeOf(r.StaticRouteService), "ListStaticRouteByVPCPath", func(_ *staticroute.StaticRouteService, _ string) []*model.StaticRoutes {
+		return []*model.StaticRoutes{{}}
+	})
+	patches.ApplyMethod(reflect.TypeOf(r.VPCPeeringService), "ListStaticRouteByVPCPath", func(_ *vpcpeering.VPCPeeringService, _ string) []*model.StaticRoutes {
+		return []*model.StaticRoutes{{}}
+	})
+
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	updated := &v1alpha1.VPC{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+	assert.Equal(t, vpcPath, updated.Status.NSXResourcePath)
+	assert.Equal(t, []string{"172.26.0.0/16"}, updated.Status.PrivateIPv4CIDRs)
+	assert.Equal(t, "vpc1short", updated.Status.ShortID)
+	assert.Equal(t, "/infra/tier-0s/t0-1", updated.Status.GatewayPath)
+	assert.Equal(t, "/infra/sites/default/enforcement-points/default/edge-clusters/ec-1", updated.Status.EdgeClusterPath)
+	assert.Equal(t, []string{"/infra/ip-blocks/external-1"}, updated.Status.ExternalIPv4Blocks)
+	assert.Equal(t, model.GenericPolicyRealizedResource_STATE_ERROR, updated.Status.RealizedState)
+	assert.Equal(t, "edge cluster is not available", updated.Status.RealizedError)
+	assert.Equal(t, v1alpha1.VPCInventory{Subnets: 2, SubnetPorts: 3, StaticRoutes: 2}, updated.Status.Inventory)
+
+	// The realized state is kept if it can't be read.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "GetVPCRealizedState", func(_ *vpc.VPCService, _ model.Vpc) (string, string, error) {
+		return "", "", errors.New("connection refused")
+	})
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, model.GenericPolicyRealizedResource_STATE_ERROR, updated.Status.RealizedState)
+	assert.Equal(t, "edge cluster is not available", updated.Status.RealizedError)
+}
+
+func TestVPCReconciler_RefreshVPCStatus(t *testing.T) {
+	vpcPath := "/orgs/default/projects/project-1/vpcs/vpc-1"
+	obj := &v1alpha1.VPC{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vpc1", UID: "vpc-uid-1"},
+		Status: v1alpha1.VPCStatus{
+			NSXResourcePath: vpcPath,
+			RealizedState:   model.GenericPolicyRealizedResource_STATE_UNREALIZED,
+		},
+	}
+	r := newFakeVPCReconciler(obj)
+	ctx := context.TODO()
+
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "GetVPCByCRUID", func(_ *vpc.VPCService, uid string) *model.Vpc {
+		assert.Equal(t, "vpc-uid-1", uid)
+		return &model.Vpc{Id: commonservice.String("vpc-1"), Path: commonservice.String(vpcPath)}
+	})
+	defer patches.Reset()
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "GetVPCRealizedState", func(_ *vpc.VPCService, _ model.Vpc) (string, string, error) {
+		return model.GenericPolicyRealizedResource_STATE_REALIZED, "", nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(r.SubnetService), "ListSubnetByVPCPath", func(_ *subnet.SubnetService, _ string) []*model.VpcSubnet {
+		return []*model.VpcSubnet{{}}
+	})
+	patches.ApplyMethod(reflect.TypeOf(r.SubnetPortService), "ListSubnetPortByVPCPath", func(_ *subnetport.SubnetPortService, _ string) []*model.VpcSubnetPort {
+		return []*model.VpcSubnetPort{{}, {}}
+	})
+	patches.ApplyMethod(reflect.TypeOf(r.StaticRouteService), "ListStaticRouteByVPCPath", func(_ *staticroute.StaticRouteService, _ string) []*model.StaticRoutes {
+		return nil
+	})
+	patches.ApplyMethod(reflect.TypeOf(r.VPCPeeringService), "ListStaticRouteByVPCPath", func(_ *vpcpeering.VPCPeeringService, _ string) []*model.StaticRoutes {
+		return nil
+	})
+
+	cancel := make(chan bool)
+	go func() {
+		time.Sleep(time.Second)
+		cancel <- true
+	}()
+	r.RefreshVPCStatus(cancel, 100*time.Millisecond)
+
+	updated := &v1alpha1.VPC{}
+	assert.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns1", Name: "vpc1"}, updated))
+	assert.Equal(t, vpcPath, updated.Status.NSXResourcePath)
+	assert.Equal(t, model.GenericPolicyRealizedResource_STATE_REALIZED, updated.Status.RealizedState)
+	assert.Equal(t, v1alpha1.VPCInventory{Subnets: 1, SubnetPorts: 2}, updated.Status.Inventory)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -29,11 +30,12 @@ func setVPCReadyStatusFalse(ctx *context.Context, vpc *v1alpha1.VPC, err *error,
 			LastTransitionTime: metav1.Now(),
 		},
 	}
-	updateVPCStatusConditions(ctx, vpc, newConditions, client, "", "", "", "", []string{})
+	updateVPCStatusConditions(ctx, vpc, newConditions, client, v1alpha1.VPCStatus{PrivateIPv4CIDRs: []string{}})
 }
 
-func updateVPCStatusConditions(ctx *context.Context, vpc *v1alpha1.VPC, newConditions []v1alpha1.Condition, client client.Client, path string, snatIP string,
-	subnetPath string, cidr string, privateCidrs []string) {
+// updateVPCStatusConditions merges the new conditions and replaces the other fields of the VPC CR status
+// with the given status, the status is only updated if it's changed.
+func updateVPCStatusConditions(ctx *context.Context, vpc *v1alpha1.VPC, newConditions []v1alpha1.Condition, client client.Client, status v1alpha1.VPCStatus) {
 	conditionsUpdated := false
 	statusUpdated := false
 	for i := range newConditions {
@@ -41,12 +43,10 @@ func updateVPCStatusConditions(ctx *context.Context, vpc *v1alpha1.VPC, newCondi
 			conditionsUpdated = true
 		}
 	}
-	if vpc.Status.NSXResourcePath != path || vpc.Status.DefaultSNATIP != snatIP || vpc.Status.LBSubnetPath != subnetPath || vpc.Status.LBSubnetCIDR != cidr || len(vpc.Status.PrivateIPv4CIDRs) != len(privateCidrs) {
-		vpc.Status.NSXResourcePath = path
-		vpc.Status.DefaultSNATIP = snatIP
-		vpc.Status.LBSubnetPath = subnetPath
-		vpc.Status.LBSubnetCIDR = cidr
-		vpc.Status.PrivateIPv4CIDRs = privateCidrs
+	status.Conditions = vpc.Status.Conditions
+	// empty and nil lists are equal, as the empty lists are omitted when the status is stored
+	if !equality.Semantic.DeepEqual(vpc.Status, status) {
+		vpc.Status = status
 		statusUpdated = true
 	}
 
@@ -69,9 +69,8 @@ func updateFail(r *VPCReconciler, c *context.Context, o *v1alpha1.VPC, e *error,
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
 }
 
-func updateSuccess(r *VPCReconciler, c *context.Context, o *v1alpha1.VPC, client client.Client,
-	path string, snatIP string, subnetPath string, cidr string, privateCidrs []string) {
-	setVPCReadyStatusTrue(c, o, client, path, snatIP, subnetPath, cidr, privateCidrs)
+func updateSuccess(r *VPCReconciler, c *context.Context, o *v1alpha1.VPC, client client.Client, status v1alpha1.VPCStatus) {
+	setVPCReadyStatusTrue(c, o, client, status)
 	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulUpdate, "VPC CR has been successfully updated")
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, common.MetricResTypeVPC)
 }
@@ -81,7 +80,7 @@ func deleteSuccess(r *VPCReconciler, _ *context.Context, o *v1alpha1.VPC) {
 	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, common.MetricResTypeVPC)
 }
 
-func setVPCReadyStatusTrue(ctx *context.Context, vpc *v1alpha1.VPC, client client.Client, path, snatIP, subnetPath, cidr string, privateCidrs []string) {
+func setVPCReadyStatusTrue(ctx *context.Context, vpc *v1alpha1.VPC, client client.Client, status v1alpha1.VPCStatus) {
 	newConditions := []v1alpha1.Condition{
 		{
 			Type:               v1alpha1.Ready,
@@ -91,7 +90,7 @@ func setVPCReadyStatusTrue(ctx *context.Context, vpc *v1alpha1.VPC, client clien
 			LastTransitionTime: metav1.Now(),
 		},
 	}
-	updateVPCStatusConditions(ctx, vpc, newConditions, client, path, snatIP, subnetPath, cidr, privateCidrs)
+	updateVPCStatusConditions(ctx, vpc, newConditions, client, status)
 }
 
 func mergeVPCStatusCondition(ctx *context.Context, vpc *v1alpha1.VPC, newCondition *v1alpha1.Condition) bool {
@@ -167,3 +166,77 @@ func buildNetworkConfigInfo(vpcConfigCR v1alpha1.VPCNetworkConfiguration) (*type
 	}
 	return ninfo, nil
 }
+
+func updateNetworkConfigStatus(ctx context.Context, client client.Client, nc *v1alpha1.VPCNetworkConfiguration, newCondition v1alpha1.Condition,
+	namespaces []string, vpcs []v1alpha1.VPCInfo, fieldErrors []v1alpha1.FieldError) {
+	newStatus := nc.Status.DeepCopy()
+	// empty lists are omitted when the status is stored, use nil for them to compare with the existing status
+	newStatus.Namespaces, newStatus.VPCs, newStatus.FieldErrors = nil, nil, nil
+	if len(namespaces) > 0 {
+		newStatus.Namespaces = namespaces
+	}
+	if len(vpcs) > 0 {
+		newStatus.VPCs = vpcs
+	}
+	if len(fieldErrors) > 0 {
+		newStatus.FieldErrors = fieldErrors
+	}
+	matchedCondition := getExistingConditionOfType(newCondition.Type, newStatus.Conditions)
+	if matchedCondition == nil {
+		newStatus.Conditions = append(newStatus.Conditions, newCondition)
+	} else if matchedCondition.Status != newCondition.Status || matchedCondition.Reason != newCondition.Reason || matchedCondition.Message != newCondition.Message {
+		// LastTransitionTime is only refreshed when the condition is changed, so that reconciling
+		// an unchanged network config doesn't update the status again.
+		*matchedCondition = newCondition
+	}
+	if reflect.DeepEqual(*newStatus, nc.Status) {
+		log.V(2).Info("VPCNetworkConfiguration status is not changed", "Name", nc.Name)
+		return
+	}
+	nc.Status = *newStatus
+	if err := client.Status().Update(ctx, nc); err != nil {
+		log.Error(err, "failed to update VPCNetworkConfiguration status", "Name", nc.Name)
+		return
+	}
+	log.V(1).Info("updated VPCNetworkConfiguration status", "Name", nc.Name, "Status", nc.Status)
+}
+
+func updateNetworkConfigFail(r *VPCNetworkConfigurationReconciler, ctx context.Context, o *v1alpha1.VPCNetworkConfiguration, msg string,
+	namespaces []string, vpcs []v1alpha1.VPCInfo, fieldErrors []v1alpha1.FieldError) {
+	condition := v1alpha1.Condition{
+		Type:               v1alpha1.Ready,
+		Status:             v1.ConditionFalse,
+		Message:            "VPCNetworkConfiguration could not be applied",
+		Reason:             msg,
+		LastTransitionTime: metav1.Now(),
+	}
+	updateNetworkConfigStatus(ctx, r.Client, o, condition, namespaces, vpcs, fieldErrors)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, msg)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, common.MetricResTypeVPCNetworkConfig)
+}
+
+func deleteNetworkConfigFail(r *VPCNetworkConfigurationReconciler, ctx context.Context, o *v1alpha1.VPCNetworkConfiguration, msg string, namespaces []string) {
+	condition := v1alpha1.Condition{
+		Type:               v1alpha1.Ready,
+		Status:             v1.ConditionFalse,
+		Message:            "VPCNetworkConfiguration could not be deleted",
+		Reason:             msg,
+		LastTransitionTime: metav1.Now(),
+	}
+	updateNetworkConfigStatus(ctx, r.Client, o, condition, namespaces, o.Status.VPCs, o.Status.FieldErrors)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, msg)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, common.MetricResTypeVPCNetworkConfig)
+}
+
+func updateNetworkConfigSuccess(r *VPCNetworkConfigurationReconciler, ctx context.Context, o *v1alpha1.VPCNetworkConfiguration,
+	namespaces []string, vpcs []v1alpha1.VPCInfo) {
+	condition := v1alpha1.Condition{
+		Type:               v1alpha1.Ready,
+		Status:             v1.ConditionTrue,
+		Message:            "VPCNetworkConfiguration has been successfully applied",
+		Reason:             "VPCNetworkConfiguration has been applied to all the bound VPCs",
+		LastTransitionTime: metav1.Now(),
+	}
+	updateNetworkConfigStatus(ctx, r.Client, o, condition, namespaces, vpcs, nil)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, common.MetricResTypeVPCNetworkConfig)
+}
@@ -0,0 +1,210 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
+)
+
+// VPCNetworkConfigurationReconciler reconciles a VPCNetworkConfiguration object:
+// - VPC Network Configuration creation/update: update the network config in cache, and apply the
+// changed fields to the VPCs of the bound Namespaces.
+// - VPC Network Configuration deletion: blocked by the finalizer until no Namespace is bound to the
+// network config, then remove the network config from cache.
+type VPCNetworkConfigurationReconciler struct {
+	Client   client.Client
+	Scheme   *apimachineryruntime.Scheme
+	Service  *vpc.VPCService
+	Recorder record.EventRecorder
+}
+
+func (r *VPCNetworkConfigurationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := &v1alpha1.VPCNetworkConfiguration{}
+	log.Info("reconciling VPCNetworkConfiguration CR", "VPCNetworkConfiguration", req.Name)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, common.MetricResTypeVPCNetworkConfig)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.Service.UnregisterVPCNetworkConfig(req.Name)
+			return common.ResultNormal, nil
+		}
+		log.Error(err, "unable to fetch VPCNetworkConfiguration CR", "req", req.NamespacedName)
+		return common.ResultNormal, err
+	}
+
+	namespaces, err := r.listBoundNamespaces(ctx, obj)
+	if err != nil {
+		log.Error(err, "failed to list Namespaces bound to VPCNetworkConfiguration", "VPCNetworkConfiguration", req.Name)
+		return common.ResultRequeueAfter10sec, err
+	}
+
+	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.deleteNetworkConfig(ctx, obj, namespaces)
+	}
+
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, common.MetricResTypeVPCNetworkConfig)
+	if !controllerutil.ContainsFinalizer(obj, commonservice.VPCNetworkConfigFinalizerName) {
+		controllerutil.AddFinalizer(obj, commonservice.VPCNetworkConfigFinalizerName)
+		if err := r.Client.Update(ctx, obj); err != nil {
+			log.Error(err, "add finalizer", "VPCNetworkConfiguration", req.Name)
+			updateNetworkConfigFail(r, ctx, obj, err.Error(), obj.Status.Namespaces, obj.Status.VPCs, obj.Status.FieldErrors)
+			return common.ResultRequeue, err
+		}
+		log.V(1).Info("added finalizer on VPCNetworkConfiguration CR", "VPCNetworkConfiguration", req.Name)
+	}
+
+	info, err := buildNetworkConfigInfo(*obj)
+	if err != nil {
+		// the network config could only be fixed by updating the CR, so no need to requeue
+		updateNetworkConfigFail(r, ctx, obj, err.Error(), namespaces, obj.Status.VPCs, obj.Status.FieldErrors)
+		return common.ResultNormal, nil
+	}
+	log.Info("update network config in store", "NetworkConfigInfo", info)
+	r.Service.RegisterVPCNetworkConfig(obj.Name, *info)
+
+	vpcs, fieldErrors, err := r.applyNetworkConfig(ctx, *info, namespaces)
+	if err != nil {
+		log.Error(err, "failed to apply VPCNetworkConfiguration to VPCs", "VPCNetworkConfiguration", req.Name)
+		updateNetworkConfigFail(r, ctx, obj, err.Error(), namespaces, obj.Status.VPCs, obj.Status.FieldErrors)
+		return common.ResultRequeueAfter10sec, err
+	}
+	if len(fieldErrors) > 0 {
+		message := fmt.Sprintf("failed to apply %d field(s) to VPCs, check status.fieldErrors for details", len(fieldErrors))
+		updateNetworkConfigFail(r, ctx, obj, message, namespaces, vpcs, fieldErrors)
+		return common.ResultRequeueAfter10sec, nil
+	}
+	updateNetworkConfigSuccess(r, ctx, obj, namespaces, vpcs)
+	return common.ResultNormal, nil
+}
+
+func (r *VPCNetworkConfigurationReconciler) deleteNetworkConfig(ctx context.Context, obj *v1alpha1.VPCNetworkConfiguration, namespaces []string) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(obj, commonservice.VPCNetworkConfigFinalizerName) {
+		// only print a message because it's not a normal case
+		log.Info("finalizers cannot be recognized", "VPCNetworkConfiguration", obj.Name)
+		return common.ResultNormal, nil
+	}
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, common.MetricResTypeVPCNetworkConfig)
+	if len(namespaces) > 0 {
+		// the CR is requeued by the Namespace watch once the Namespaces are deleted or re-bound
+		deleteNetworkConfigFail(r, ctx, obj, fmt.Sprintf("VPCNetworkConfiguration is still used by Namespaces %v", namespaces), namespaces)
+		return common.ResultNormal, nil
+	}
+
+	r.Service.UnregisterVPCNetworkConfig(obj.Name)
+	controllerutil.RemoveFinalizer(obj, commonservice.VPCNetworkConfigFinalizerName)
+	if err := r.Client.Update(ctx, obj); err != nil {
+		deleteNetworkConfigFail(r, ctx, obj, err.Error(), namespaces)
+		return common.ResultRequeue, err
+	}
+	log.V(1).Info("removed finalizer", "VPCNetworkConfiguration", obj.Name)
+	r.Recorder.Event(obj, v1.EventTypeNormal, common.ReasonSuccessfulDelete, "VPCNetworkConfiguration CR has been successfully deleted")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, common.MetricResTypeVPCNetworkConfig)
+	return common.ResultNormal, nil
+}
+
+// listBoundNamespaces lists the Namespaces using the network config, either by the annotation
+// or by falling back to the default network config.
+func (r *VPCNetworkConfigurationReconciler) listBoundNamespaces(ctx context.Context, obj *v1alpha1.VPCNetworkConfiguration) ([]string, error) {
+	nsList := &v1.NamespaceList{}
+	if err := r.Client.List(ctx, nsList); err != nil {
+		return nil, err
+	}
+	isDefault := isDefaultNetworkConfigCR(*obj)
+	namespaces := []string{}
+	for _, ns := range nsList.Items {
+		ncName, exist := ns.Annotations[commonservice.AnnotationVPCNetworkConfig]
+		if (exist && ncName == obj.Name) || (!exist && isDefault) {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces, nil
+}
+
+// applyNetworkConfig applies the network config to the VPCs in the Namespaces, and returns the
+// VPCs and the fields which failed to apply.
+func (r *VPCNetworkConfigurationReconciler) applyNetworkConfig(ctx context.Context, info commonservice.VPCNetworkConfigInfo, namespaces []string) ([]v1alpha1.VPCInfo, []v1alpha1.FieldError, error) {
+	vpcs := []v1alpha1.VPCInfo{}
+	fieldErrors := []v1alpha1.FieldError{}
+	for _, ns := range namespaces {
+		vpcList := &v1alpha1.VPCList{}
+		if err := r.Client.List(ctx, vpcList, client.InNamespace(ns)); err != nil {
+			return nil, nil, err
+		}
+		for i := range vpcList.Items {
+			vpcCR := &vpcList.Items[i]
+			if !vpcCR.ObjectMeta.DeletionTimestamp.IsZero() {
+				continue
+			}
+			nsxVPC, errs := r.Service.ApplyVPCNetworkConfig(vpcCR, info)
+			vpcInfo := v1alpha1.VPCInfo{Name: types.NamespacedName{Namespace: vpcCR.Namespace, Name: vpcCR.Name}.String()}
+			if nsxVPC != nil && nsxVPC.Path != nil {
+				vpcInfo.VPCPath = *nsxVPC.Path
+			}
+			vpcs = append(vpcs, vpcInfo)
+			for field, err := range errs {
+				fieldErrors = append(fieldErrors, v1alpha1.FieldError{Field: field, VPCPath: vpcInfo.VPCPath, Message: err.Error()})
+			}
+		}
+	}
+	sort.Slice(fieldErrors, func(i, j int) bool {
+		if fieldErrors[i].VPCPath != fieldErrors[j].VPCPath {
+			return fieldErrors[i].VPCPath < fieldErrors[j].VPCPath
+		}
+		return fieldErrors[i].Field < fieldErrors[j].Field
+	})
+	return vpcs, fieldErrors, nil
+}
+
+// namespaceToNetworkConfig maps a Namespace to the network config it is bound to, so that the
+// network config is reconciled when the Namespaces bound to it change.
+func (r *VPCNetworkConfigurationReconciler) namespaceToNetworkConfig(_ context.Context, obj client.Object) []reconcile.Request {
+	ncName, exist := obj.GetAnnotations()[commonservice.AnnotationVPCNetworkConfig]
+	if !exist {
+		exist, nc := r.Service.GetDefaultNetworkConfig()
+		if !exist {
+			return nil
+		}
+		ncName = nc.Name
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: ncName}}}
+}
+
+func (r *VPCNetworkConfigurationReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.VPCNetworkConfiguration{}).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Watches(
+			&v1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceToNetworkConfig)).
+		Complete(r)
+}
+
+// Start setup manager
+func (r *VPCNetworkConfigurationReconciler) Start(mgr ctrl.Manager) error {
+	return r.setupWithManager(mgr)
+}
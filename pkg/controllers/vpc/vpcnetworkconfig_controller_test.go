@@ -0,0 +1,161 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	commonservice "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
+)
+
+func newFakeNetworkConfigReconciler(objs ...client.Object) *VPCNetworkConfigurationReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.VPCNetworkConfiguration{}).WithObjects(objs...).Build()
+	return &VPCNetworkConfigurationReconciler{
+		Client: k8sClient,
+		Scheme: scheme,
+		Service: &vpc.VPCService{
+			Service: commonservice.Service{
+				NSXConfig: &config.NSXOperatorConfig{
+					NsxConfig: &config.NsxConfig{EnforcementPoint: "vmc-enforcementpoint"},
+					CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test"},
+				},
+			},
+			VPCNetworkConfigMap:   map[string]commonservice.VPCNetworkConfigInfo{},
+			VPCNSNetworkConfigMap: map[string]string{},
+		},
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestVPCNetworkConfigurationReconciler_Reconcile(t *testing.T) {
+	nc := &v1alpha1.VPCNetworkConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "nc1"},
+		Spec: v1alpha1.VPCNetworkConfigurationSpec{
+			NSXTProject:        "/orgs/default/projects/project-1",
+			DefaultGatewayPath: "/infra/tier-0s/t0-1",
+			PrivateIPv4CIDRs:   []string{"172.26.0.0/16"},
+		},
+	}
+	ns1 := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Annotations: map[string]string{commonservice.AnnotationVPCNetworkConfig: "nc1"}}}
+	ns2 := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}}
+	vpcCR := &v1alpha1.VPC{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vpc1", UID: "vpc-uid-1"}}
+	r := newFakeNetworkConfigReconciler(nc, ns1, ns2, vpcCR)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "nc1"}}
+	vpcPath := "/orgs/default/projects/project-1/vpcs/vpc-uid-1"
+
+	// A field fails to apply.
+	applyErrs := map[string]error{vpc.FieldDefaultGatewayPath: errors.New("gateway is in use")}
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "ApplyVPCNetworkConfig", func(_ *vpc.VPCService, obj *v1alpha1.VPC, info commonservice.VPCNetworkConfigInfo) (*model.Vpc, map[string]error) {
+		assert.Equal(t, "vpc1", obj.Name)
+		assert.Equal(t, "/infra/tier-0s/t0-1", info.DefaultGatewayPath)
+		return &model.Vpc{Path: &vpcPath}, applyErrs
+	})
+	defer patches.Reset()
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, common.ResultRequeueAfter10sec, result)
+	_, exist := r.Service.GetVPCNetworkConfig("nc1")
+	assert.True(t, exist)
+	updated := &v1alpha1.VPCNetworkConfiguration{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Contains(t, updated.Finalizers, commonservice.VPCNetworkConfigFinalizerName)
+	assert.Equal(t, []string{"ns1"}, updated.Status.Namespaces)
+	assert.Equal(t, []v1alpha1.VPCInfo{{Name: "ns1/vpc1", VPCPath: vpcPath}}, updated.Status.VPCs)
+	assert.Equal(t, []v1alpha1.FieldError{{Field: vpc.FieldDefaultGatewayPath, VPCPath: vpcPath, Message: "gateway is in use"}}, updated.Status.FieldErrors)
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+
+	// All the fields are applied.
+	applyErrs = nil
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, common.ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Nil(t, updated.Status.FieldErrors)
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+
+	// Reconciling the unchanged network config doesn't update the status.
+	resourceVersion := updated.ResourceVersion
+	_, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, resourceVersion, updated.ResourceVersion)
+
+	// Deletion is blocked while a Namespace is bound.
+	assert.NoError(t, r.Client.Delete(ctx, updated))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, common.ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Contains(t, updated.Finalizers, commonservice.VPCNetworkConfigFinalizerName)
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+	assert.Contains(t, updated.Status.Conditions[0].Reason, "ns1")
+
+	// Deletion completes once no Namespace is bound.
+	assert.NoError(t, r.Client.Delete(ctx, ns1))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, common.ResultNormal, result)
+	assert.True(t, apierrors.IsNotFound(r.Client.Get(ctx, req.NamespacedName, updated)))
+	_, exist = r.Service.GetVPCNetworkConfig("nc1")
+	assert.False(t, exist)
+}
+
+func TestVPCNetworkConfigurationReconciler_ListBoundNamespaces(t *testing.T) {
+	ns1 := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Annotations: map[string]string{commonservice.AnnotationVPCNetworkConfig: "nc1"}}}
+	ns2 := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}}
+	ns3 := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns3", Annotations: map[string]string{commonservice.AnnotationVPCNetworkConfig: "default"}}}
+	r := newFakeNetworkConfigReconciler(ns1, ns2, ns3)
+	ctx := context.TODO()
+
+	namespaces, err := r.listBoundNamespaces(ctx, &v1alpha1.VPCNetworkConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "nc1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ns1"}, namespaces)
+
+	defaultNC := &v1alpha1.VPCNetworkConfiguration{ObjectMeta: metav1.ObjectMeta{
+		Name:        "default",
+		Annotations: map[string]string{commonservice.AnnotationDefaultNetworkConfig: "true"},
+	}}
+	namespaces, err = r.listBoundNamespaces(ctx, defaultNC)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ns2", "ns3"}, namespaces)
+}
+
+func TestVPCNetworkConfigurationReconciler_NamespaceToNetworkConfig(t *testing.T) {
+	r := newFakeNetworkConfigReconciler()
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", Annotations: map[string]string{commonservice.AnnotationVPCNetworkConfig: "nc1"}}}
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "nc1"}}}, r.namespaceToNetworkConfig(context.TODO(), ns))
+
+	// No default network config is registered.
+	ns = &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns2"}}
+	assert.Nil(t, r.namespaceToNetworkConfig(context.TODO(), ns))
+
+	r.Service.RegisterVPCNetworkConfig("default", commonservice.VPCNetworkConfigInfo{Name: "default", IsDefault: true})
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "default"}}}, r.namespaceToNetworkConfig(context.TODO(), ns))
+}
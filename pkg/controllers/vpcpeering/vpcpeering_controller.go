@@ -0,0 +1,465 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpcpeering
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/controllers/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/metrics"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpcpeering"
+)
+
+var (
+	log                     = logger.Log
+	ResultNormal            = common.ResultNormal
+	ResultRequeue           = common.ResultRequeue
+	ResultRequeueAfter10sec = common.ResultRequeueAfter10sec
+	MetricResType           = common.MetricResTypeVPCPeering
+)
+
+// VPCPeeringReconciler reconciles a VPCPeering object
+type VPCPeeringReconciler struct {
+	Client     client.Client
+	Scheme     *apimachineryruntime.Scheme
+	Service    *vpcpeering.VPCPeeringService
+	VPCService servicecommon.VPCServiceProvider
+	Recorder   record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=vpcpeerings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=vpcpeerings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=nsx.vmware.com,resources=vpcpeerings/finalizers,verbs=update
+
+func (r *VPCPeeringReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := &v1alpha1.VPCPeering{}
+	log.Info("reconciling vpcpeering CR", "vpcpeering", req.NamespacedName)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerSyncTotal, MetricResType)
+
+	if err := r.Client.Get(ctx, req.NamespacedName, obj); err != nil {
+		log.Error(err, "unable to fetch vpcpeering CR", "req", req.NamespacedName)
+		return ResultNormal, client.IgnoreNotFound(err)
+	}
+
+	if !obj.ObjectMeta.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(obj, servicecommon.VPCPeeringFinalizerName) {
+			// only print a message because it's not a normal case
+			log.Info("finalizers cannot be recognized", "vpcpeering", req.NamespacedName)
+			return ResultNormal, nil
+		}
+		metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+		if err := r.Service.DeleteVPCPeering(obj.UID); err != nil {
+			log.Error(err, "deletion failed, would retry exponentially", "vpcpeering", req.NamespacedName)
+			deleteFail(r, &ctx, obj, err.Error())
+			return ResultRequeue, err
+		}
+		controllerutil.RemoveFinalizer(obj, servicecommon.VPCPeeringFinalizerName)
+		if err := r.Client.Update(ctx, obj); err != nil {
+			log.Error(err, "deletion failed, would retry exponentially", "vpcpeering", req.NamespacedName)
+			deleteFail(r, &ctx, obj, err.Error())
+			return ResultRequeue, err
+		}
+		log.V(1).Info("removed finalizer", "vpcpeering", req.NamespacedName)
+		deleteSuccess(r, &ctx, obj)
+		return ResultNormal, nil
+	}
+
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateTotal, MetricResType)
+	if !controllerutil.ContainsFinalizer(obj, servicecommon.VPCPeeringFinalizerName) {
+		controllerutil.AddFinalizer(obj, servicecommon.VPCPeeringFinalizerName)
+		if err := r.Client.Update(ctx, obj); err != nil {
+			log.Error(err, "add finalizer", "vpcpeering", req.NamespacedName)
+			updateFail(r, &ctx, obj, err.Error())
+			return ResultRequeue, err
+		}
+		log.V(1).Info("added finalizer on vpcpeering CR", "vpcpeering", req.NamespacedName)
+	}
+
+	local, err := r.getVPCPeeringEnd(ctx, obj.Namespace, obj.Spec.VPCName)
+	if err != nil {
+		log.Info("local VPC is not ready, would retry after 10 seconds", "vpcpeering", req.NamespacedName, "reason", err.Error())
+		updateFail(r, &ctx, obj, err.Error())
+		return ResultRequeueAfter10sec, nil
+	}
+	peer, err := r.getVPCPeeringEnd(ctx, getPeerNamespace(obj), obj.Spec.PeerVPC.Name)
+	if err != nil {
+		log.Info("peer VPC is not ready, would retry after 10 seconds", "vpcpeering", req.NamespacedName, "reason", err.Error())
+		updateFail(r, &ctx, obj, err.Error())
+		return ResultRequeueAfter10sec, nil
+	}
+
+	if peerNamespace := getPeerNamespace(obj); peerNamespace != obj.Namespace {
+		// The VPC of another Namespace is peered only if the Namespace has a VPCPeering peering back.
+		mirror, err := r.getMirrorVPCPeering(ctx, obj, local, peer)
+		if err != nil {
+			log.Error(err, "failed to get the VPCPeering of the peer Namespace", "vpcpeering", req.NamespacedName)
+			updateFail(r, &ctx, obj, err.Error())
+			return ResultRequeue, err
+		}
+		if mirror == nil {
+			// The NSX resources are removed if the peer Namespace withdraws the peering.
+			if err := r.Service.DeleteVPCPeering(obj.UID); err != nil {
+				log.Error(err, "failed to delete the VPC peering not accepted", "vpcpeering", req.NamespacedName)
+				updateFail(r, &ctx, obj, err.Error())
+				return ResultRequeue, err
+			}
+			msg := fmt.Sprintf("VPC peering is not accepted by Namespace %s, a VPCPeering peering back is required", peerNamespace)
+			log.Info("vpcpeering is not accepted, waiting for the VPCPeering of the peer Namespace", "vpcpeering", req.NamespacedName)
+			updateFail(r, &ctx, obj, msg)
+			return ResultNormal, nil
+		}
+		// The NSX resources are kept as they are until the two VPCPeerings agree on the spec.
+		if err := vpcpeering.CheckMirrorVPCPeering(obj, mirror); err != nil {
+			log.Info("vpcpeering conflicts with the VPCPeering of the peer Namespace", "vpcpeering", req.NamespacedName, "reason", err.Error())
+			updateConflict(r, &ctx, obj, err.Error())
+			return ResultNormal, nil
+		}
+		// Both VPCPeerings of the pair connect the same VPCs, only the one in the Namespace sorted first
+		// creates the NSX routes and rules, the other one reports its state.
+		if peerNamespace < obj.Namespace {
+			return r.updateMirroredStatus(ctx, obj, mirror, local, peer)
+		}
+	}
+
+	routes, err := r.Service.CreateOrUpdateVPCPeering(obj, local, peer)
+	if err != nil {
+		log.Error(err, "operate failed, would retry exponentially", "vpcpeering", req.NamespacedName)
+		updateFail(r, &ctx, obj, err.Error())
+		return ResultRequeue, err
+	}
+	obj.Status.VPCPath = vpcpeering.BuildVPCPath(local.VPCInfo)
+	obj.Status.PeerVPCPath = vpcpeering.BuildVPCPath(peer.VPCInfo)
+	obj.Status.Routes = routes
+	for _, route := range routes {
+		if route.RealizationState != model.GenericPolicyRealizedResource_STATE_REALIZED {
+			msg := fmt.Sprintf("route to %s in VPC %s is %s", route.Network, route.VPCPath, route.RealizationState)
+			log.Info("vpcpeering is not realized, would retry after 10 seconds", "vpcpeering", req.NamespacedName, "reason", msg)
+			updateFail(r, &ctx, obj, msg)
+			return ResultRequeueAfter10sec, nil
+		}
+	}
+	updateSuccess(r, &ctx, obj)
+	return ResultNormal, nil
+}
+
+func getPeerNamespace(obj *v1alpha1.VPCPeering) string {
+	if obj.Spec.PeerVPC.Namespace != "" {
+		return obj.Spec.PeerVPC.Namespace
+	}
+	return obj.Namespace
+}
+
+// getMirrorVPCPeering returns the VPCPeering in the peer Namespace which peers the same VPCs back, or nil if the
+// peer Namespace doesn't have one. The VPCPeerings being deleted are ignored.
+func (r *VPCPeeringReconciler) getMirrorVPCPeering(ctx context.Context, obj *v1alpha1.VPCPeering, local vpcpeering.VPCPeeringEnd, peer vpcpeering.VPCPeeringEnd) (*v1alpha1.VPCPeering, error) {
+	peeringList := &v1alpha1.VPCPeeringList{}
+	if err := r.Client.List(ctx, peeringList, client.InNamespace(getPeerNamespace(obj))); err != nil {
+		return nil, err
+	}
+	for i := range peeringList.Items {
+		peering := &peeringList.Items[i]
+		if !peering.DeletionTimestamp.IsZero() || getPeerNamespace(peering) != obj.Namespace {
+			continue
+		}
+		mirrorLocal, found := r.VPCService.GetVPCInfo(peering.Namespace, peering.Spec.VPCName)
+		if !found || mirrorLocal.VPCID != peer.VPCInfo.VPCID {
+			continue
+		}
+		mirrorPeer, found := r.VPCService.GetVPCInfo(obj.Namespace, peering.Spec.PeerVPC.Name)
+		if !found || mirrorPeer.VPCID != local.VPCInfo.VPCID {
+			continue
+		}
+		return peering, nil
+	}
+	return nil, nil
+}
+
+// updateMirroredStatus reports the state of the VPC peering created by the mirror VPCPeering, and removes the
+// NSX resources created by the VPCPeering before.
+func (r *VPCPeeringReconciler) updateMirroredStatus(ctx context.Context, obj *v1alpha1.VPCPeering, mirror *v1alpha1.VPCPeering, local vpcpeering.VPCPeeringEnd, peer vpcpeering.VPCPeeringEnd) (ctrl.Result, error) {
+	if err := r.Service.DeleteVPCPeering(obj.UID); err != nil {
+		log.Error(err, "failed to delete the VPC peering created by the mirror VPCPeering", "vpcpeering", obj.Namespace+"/"+obj.Name)
+		updateFail(r, &ctx, obj, err.Error())
+		return ResultRequeue, err
+	}
+	obj.Status.VPCPath = vpcpeering.BuildVPCPath(local.VPCInfo)
+	obj.Status.PeerVPCPath = vpcpeering.BuildVPCPath(peer.VPCInfo)
+	obj.Status.Routes = mirror.Status.Routes
+	for _, condition := range mirror.Status.Conditions {
+		if condition.Type == v1alpha1.Ready && condition.Status == v1.ConditionTrue {
+			updateSuccess(r, &ctx, obj)
+			return ResultNormal, nil
+		}
+	}
+	// The VPCPeering is enqueued again when the status of the mirror VPCPeering is updated.
+	updateFail(r, &ctx, obj, fmt.Sprintf("VPC peering created by VPCPeering %s/%s is not ready", mirror.Namespace, mirror.Name))
+	return ResultNormal, nil
+}
+
+// getVPCPeeringEnd returns the NSX VPC created for the VPC CR vpcName in the Namespace, or the default
+// VPC of the Namespace if vpcName is empty, together with the private CIDRs of the VPC.
+func (r *VPCPeeringReconciler) getVPCPeeringEnd(ctx context.Context, namespace string, vpcName string) (vpcpeering.VPCPeeringEnd, error) {
+	vpcInfo, found := r.VPCService.GetVPCInfo(namespace, vpcName)
+	if !found {
+		return vpcpeering.VPCPeeringEnd{}, fmt.Errorf("VPC %s not found in Namespace %s", vpcName, namespace)
+	}
+	// The NSX VPC ID is the UID of the VPC CR, which may be in the shared VPC Namespace.
+	vpcList := &v1alpha1.VPCList{}
+	if err := r.Client.List(ctx, vpcList); err != nil {
+		return vpcpeering.VPCPeeringEnd{}, err
+	}
+	for _, vpc := range vpcList.Items {
+		if string(vpc.UID) == vpcInfo.VPCID {
+			return vpcpeering.VPCPeeringEnd{VPCInfo: vpcInfo, CIDRs: vpc.Status.PrivateIPv4CIDRs}, nil
+		}
+	}
+	return vpcpeering.VPCPeeringEnd{}, fmt.Errorf("VPC CR of NSX VPC %s not found", vpcInfo.VPCID)
+}
+
+func (r *VPCPeeringReconciler) setReadyStatusTrue(ctx *context.Context, obj *v1alpha1.VPCPeering, transitionTime metav1.Time) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionTrue,
+			Message:            "NSX routes of VPC peering have been successfully realized",
+			Reason:             "VPCPeeringReady",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateStatusConditions(ctx, obj, newConditions)
+}
+
+func (r *VPCPeeringReconciler) setReadyStatusFalse(ctx *context.Context, obj *v1alpha1.VPCPeering, transitionTime metav1.Time, msg string) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionFalse,
+			Message:            "NSX routes of VPC peering could not be created/updated/deleted",
+			Reason:             "VPCPeeringNotReady",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	if msg != "" {
+		newConditions[0].Message = msg
+	}
+	r.updateStatusConditions(ctx, obj, newConditions)
+}
+
+func (r *VPCPeeringReconciler) setConflictStatus(ctx *context.Context, obj *v1alpha1.VPCPeering, transitionTime metav1.Time, msg string) {
+	newConditions := []v1alpha1.Condition{
+		{
+			Type:               v1alpha1.Ready,
+			Status:             v1.ConditionFalse,
+			Message:            msg,
+			Reason:             "VPCPeeringConflict",
+			LastTransitionTime: transitionTime,
+		},
+	}
+	r.updateStatusConditions(ctx, obj, newConditions)
+}
+
+func (r *VPCPeeringReconciler) updateStatusConditions(ctx *context.Context, obj *v1alpha1.VPCPeering, newConditions []v1alpha1.Condition) {
+	conditionsUpdated := false
+	for i := range newConditions {
+		if mergeStatusCondition(obj, &newConditions[i]) {
+			conditionsUpdated = true
+		}
+	}
+	if conditionsUpdated {
+		if err := r.Client.Status().Update(*ctx, obj); err != nil {
+			log.Error(err, "failed to update vpcpeering status", "Name", obj.Name, "Namespace", obj.Namespace)
+		} else {
+			log.V(1).Info("updated vpcpeering", "Name", obj.Name, "Namespace", obj.Namespace, "New Conditions", newConditions)
+		}
+	}
+}
+
+func mergeStatusCondition(obj *v1alpha1.VPCPeering, newCondition *v1alpha1.Condition) bool {
+	for i := range obj.Status.Conditions {
+		matchedCondition := &obj.Status.Conditions[i]
+		if matchedCondition.Type != newCondition.Type {
+			continue
+		}
+		if reflect.DeepEqual(matchedCondition, newCondition) {
+			return false
+		}
+		matchedCondition.Reason = newCondition.Reason
+		matchedCondition.Message = newCondition.Message
+		matchedCondition.Status = newCondition.Status
+		return true
+	}
+	obj.Status.Conditions = append(obj.Status.Conditions, *newCondition)
+	return true
+}
+
+func updateFail(r *VPCPeeringReconciler, c *context.Context, o *v1alpha1.VPCPeering, m string) {
+	r.setReadyStatusFalse(c, o, metav1.Now(), m)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, m)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+}
+
+func updateConflict(r *VPCPeeringReconciler, c *context.Context, o *v1alpha1.VPCPeering, m string) {
+	r.setConflictStatus(c, o, metav1.Now(), m)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailUpdate, m)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateFailTotal, MetricResType)
+}
+
+func deleteFail(r *VPCPeeringReconciler, c *context.Context, o *v1alpha1.VPCPeering, m string) {
+	r.setReadyStatusFalse(c, o, metav1.Now(), m)
+	r.Recorder.Event(o, v1.EventTypeWarning, common.ReasonFailDelete, m)
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+}
+
+func updateSuccess(r *VPCPeeringReconciler, c *context.Context, o *v1alpha1.VPCPeering) {
+	r.setReadyStatusTrue(c, o, metav1.Now())
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulUpdate, "VPCPeering CR has been successfully updated")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerUpdateSuccessTotal, MetricResType)
+}
+
+func deleteSuccess(r *VPCPeeringReconciler, _ *context.Context, o *v1alpha1.VPCPeering) {
+	r.Recorder.Event(o, v1.EventTypeNormal, common.ReasonSuccessfulDelete, "VPCPeering CR has been successfully deleted")
+	metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+}
+
+// vpcToVPCPeerings enqueues the VPCPeerings connecting the VPCs in the Namespace of the VPC CR, so that
+// the routes are updated when the private CIDRs of the VPC change.
+func (r *VPCPeeringReconciler) vpcToVPCPeerings(ctx context.Context, obj client.Object) []reconcile.Request {
+	peeringList := &v1alpha1.VPCPeeringList{}
+	if err := r.Client.List(ctx, peeringList); err != nil {
+		log.Error(err, "failed to list vpcpeering CR")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, peering := range peeringList.Items {
+		if peering.Namespace == obj.GetNamespace() || getPeerNamespace(&peering) == obj.GetNamespace() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: peering.Namespace, Name: peering.Name}})
+		}
+	}
+	return requests
+}
+
+// vpcPeeringToMirrors enqueues the VPCPeerings peering with the Namespace of the VPCPeering, so that a VPC peering
+// is created once the peer Namespace accepts it and removed once the peer Namespace withdraws it.
+func (r *VPCPeeringReconciler) vpcPeeringToMirrors(ctx context.Context, obj client.Object) []reconcile.Request {
+	peering, ok := obj.(*v1alpha1.VPCPeering)
+	if !ok || getPeerNamespace(peering) == peering.Namespace {
+		return nil
+	}
+	peeringList := &v1alpha1.VPCPeeringList{}
+	if err := r.Client.List(ctx, peeringList, client.InNamespace(getPeerNamespace(peering))); err != nil {
+		log.Error(err, "failed to list vpcpeering CR")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, mirror := range peeringList.Items {
+		if getPeerNamespace(&mirror) == peering.Namespace {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: mirror.Namespace, Name: mirror.Name}})
+		}
+	}
+	return requests
+}
+
+func (r *VPCPeeringReconciler) setupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.VPCPeering{}).
+		WithEventFilter(predicate.Funcs{
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				// Suppress Delete events to avoid filtering them out in the Reconcile function
+				return false
+			},
+		}).
+		WithOptions(
+			controller.Options{
+				MaxConcurrentReconciles: common.NumReconcile(),
+			}).
+		Watches(
+			&v1alpha1.VPC{},
+			handler.EnqueueRequestsFromMapFunc(r.vpcToVPCPeerings)).
+		Watches(
+			&v1alpha1.VPCPeering{},
+			handler.EnqueueRequestsFromMapFunc(r.vpcPeeringToMirrors)).
+		Complete(r)
+}
+
+// Start setup manager and launch GC
+func (r *VPCPeeringReconciler) Start(mgr ctrl.Manager) error {
+	if err := r.setupWithManager(mgr); err != nil {
+		return err
+	}
+	go r.GarbageCollector(make(chan bool), servicecommon.GCInterval)
+	return nil
+}
+
+// GarbageCollector removes the NSX routes and rules of the VPCPeering CRs which have been removed.
+// cancel is used to break the loop during UT
+func (r *VPCPeeringReconciler) GarbageCollector(cancel chan bool, timeout time.Duration) {
+	ctx := context.Background()
+	log.Info("vpcpeering garbage collector started")
+	for {
+		select {
+		case <-cancel:
+			return
+		case <-time.After(timeout):
+		}
+		nsxPeeringSet := r.Service.ListVPCPeeringID()
+		if len(nsxPeeringSet) == 0 {
+			continue
+		}
+		peeringList := &v1alpha1.VPCPeeringList{}
+		if err := r.Client.List(ctx, peeringList); err != nil {
+			log.Error(err, "failed to list vpcpeering CR")
+			continue
+		}
+		crPeeringSet := sets.New[string]()
+		for _, peering := range peeringList.Items {
+			crPeeringSet.Insert(string(peering.UID))
+		}
+		for uid := range nsxPeeringSet.Difference(crPeeringSet) {
+			log.Info("GC collected vpcpeering CR", "UID", uid)
+			metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteTotal, MetricResType)
+			if err := r.Service.DeleteVPCPeering(types.UID(uid)); err != nil {
+				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteFailTotal, MetricResType)
+			} else {
+				metrics.CounterInc(r.Service.NSXConfig, metrics.ControllerDeleteSuccessTotal, MetricResType)
+			}
+		}
+	}
+}
+
+func StartVPCPeeringController(mgr ctrl.Manager, vpcPeeringService *vpcpeering.VPCPeeringService, vpcService servicecommon.VPCServiceProvider) {
+	reconciler := &VPCPeeringReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Service:    vpcPeeringService,
+		VPCService: vpcService,
+		Recorder:   mgr.GetEventRecorderFor("vpcpeering-controller"),
+	}
+	if err := reconciler.Start(mgr); err != nil {
+		log.Error(err, "failed to create controller", "controller", "VPCPeering")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,254 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpcpeering
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpcpeering"
+)
+
+type fakeVPCService struct {
+	common.VPCServiceProvider
+}
+
+func (f *fakeVPCService) GetVPCInfo(ns string, _ string) (common.VPCResourceInfo, bool) {
+	switch ns {
+	case "ns1":
+		return common.VPCResourceInfo{OrgID: "default", ProjectID: "p1", VPCID: "vpc-uid-1"}, true
+	case "ns2":
+		return common.VPCResourceInfo{OrgID: "default", ProjectID: "p1", VPCID: "vpc-uid-2"}, true
+	}
+	return common.VPCResourceInfo{}, false
+}
+
+func newFakeReconciler(objs ...client.Object) *VPCPeeringReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = v1alpha1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&v1alpha1.VPCPeering{}).WithObjects(objs...).Build()
+	return &VPCPeeringReconciler{
+		Client: fakeClient,
+		Scheme: scheme,
+		Service: &vpcpeering.VPCPeeringService{
+			Service: common.Service{
+				NSXConfig: &config.NSXOperatorConfig{
+					NsxConfig: &config.NsxConfig{EnforcementPoint: "vmc-enforcementpoint"},
+					CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test"},
+				},
+			},
+		},
+		VPCService: &fakeVPCService{},
+		Recorder:   record.NewFakeRecorder(10),
+	}
+}
+
+func TestVPCPeeringReconciler_Reconcile(t *testing.T) {
+	obj := &v1alpha1.VPCPeering{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "peering1", UID: "peering-uid-1"},
+		Spec: v1alpha1.VPCPeeringSpec{
+			PeerVPC:  v1alpha1.PeerVPCReference{Namespace: "ns2"},
+			NextHops: []v1alpha1.NextHop{{IPAddress: "172.10.0.1"}},
+		},
+	}
+	vpc1 := &v1alpha1.VPC{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vpc1", UID: "vpc-uid-1"},
+		Status:     v1alpha1.VPCStatus{PrivateIPv4CIDRs: []string{"10.1.0.0/16"}},
+	}
+	r := newFakeReconciler(obj, vpc1)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "peering1"}}
+
+	// Not found
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns1", Name: "dummy"}})
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+
+	// The peer VPC is not ready.
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultRequeueAfter10sec, result)
+	updated := &v1alpha1.VPCPeering{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Contains(t, updated.Finalizers, common.VPCPeeringFinalizerName)
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+
+	// The routes are not realized yet.
+	assert.NoError(t, r.Client.Create(ctx, &v1alpha1.VPC{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "vpc2", UID: "vpc-uid-2"},
+		Status:     v1alpha1.VPCStatus{PrivateIPv4CIDRs: []string{"10.2.0.0/16"}},
+	}))
+	assert.NoError(t, r.Client.Create(ctx, &v1alpha1.VPCPeering{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "peering2"},
+		Spec: v1alpha1.VPCPeeringSpec{
+			PeerVPC:  v1alpha1.PeerVPCReference{Namespace: "ns1"},
+			NextHops: []v1alpha1.NextHop{{IPAddress: "172.10.0.1"}},
+		},
+	}))
+	state := model.GenericPolicyRealizedResource_STATE_UNREALIZED
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateVPCPeering", func(_ *vpcpeering.VPCPeeringService, _ *v1alpha1.VPCPeering, local vpcpeering.VPCPeeringEnd, peer vpcpeering.VPCPeeringEnd) ([]v1alpha1.VPCPeeringRoute, error) {
+		assert.Equal(t, []string{"10.1.0.0/16"}, local.CIDRs)
+		assert.Equal(t, []string{"10.2.0.0/16"}, peer.CIDRs)
+		return []v1alpha1.VPCPeeringRoute{{
+			VPCPath:          vpcpeering.BuildVPCPath(local.VPCInfo),
+			Network:          "10.2.0.0/16",
+			RealizationState: state,
+		}}, nil
+	})
+	defer patches.Reset()
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultRequeueAfter10sec, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+	assert.Equal(t, state, updated.Status.Routes[0].RealizationState)
+
+	// The routes are realized.
+	state = model.GenericPolicyRealizedResource_STATE_REALIZED
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+	assert.Equal(t, "/orgs/default/projects/p1/vpcs/vpc-uid-1", updated.Status.VPCPath)
+	assert.Equal(t, "/orgs/default/projects/p1/vpcs/vpc-uid-2", updated.Status.PeerVPCPath)
+
+	// The VPCPeerings of the Namespace are enqueued for the VPC changes.
+	requests := r.vpcToVPCPeerings(ctx, vpc1)
+	assert.Equal(t, req.NamespacedName, requests[0].NamespacedName)
+
+	// Deletion fails.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteVPCPeering", func(_ *vpcpeering.VPCPeeringService, _ types.UID) error {
+		return errors.New("delete failed")
+	})
+	assert.NoError(t, r.Client.Delete(ctx, updated))
+	_, err = r.Reconcile(ctx, req)
+	assert.Error(t, err)
+
+	// Deletion succeeds.
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteVPCPeering", func(_ *vpcpeering.VPCPeeringService, _ types.UID) error {
+		return nil
+	})
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.Error(t, r.Client.Get(ctx, req.NamespacedName, updated))
+}
+
+func TestVPCPeeringReconciler_ReconcileNotAccepted(t *testing.T) {
+	obj := &v1alpha1.VPCPeering{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "peering2", UID: "peering-uid-2", Finalizers: []string{common.VPCPeeringFinalizerName}},
+		Spec: v1alpha1.VPCPeeringSpec{
+			PeerVPC:  v1alpha1.PeerVPCReference{Namespace: "ns1"},
+			NextHops: []v1alpha1.NextHop{{IPAddress: "172.10.0.1"}},
+		},
+	}
+	// The VPCPeering of the peer Namespace peers with another Namespace.
+	other := &v1alpha1.VPCPeering{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "peering1", UID: "peering-uid-1"},
+		Spec: v1alpha1.VPCPeeringSpec{
+			PeerVPC:  v1alpha1.PeerVPCReference{Namespace: "ns3"},
+			NextHops: []v1alpha1.NextHop{{IPAddress: "172.10.0.1"}},
+		},
+	}
+	vpc1 := &v1alpha1.VPC{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "vpc1", UID: "vpc-uid-1"}}
+	vpc2 := &v1alpha1.VPC{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "vpc2", UID: "vpc-uid-2"}}
+	r := newFakeReconciler(obj, other, vpc1, vpc2)
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns2", Name: "peering2"}}
+
+	created := false
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "CreateOrUpdateVPCPeering", func(_ *vpcpeering.VPCPeeringService, _ *v1alpha1.VPCPeering, _ vpcpeering.VPCPeeringEnd, _ vpcpeering.VPCPeeringEnd) ([]v1alpha1.VPCPeeringRoute, error) {
+		created = true
+		return nil, nil
+	})
+	defer patches.Reset()
+	deleted := sets.New[string]()
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteVPCPeering", func(_ *vpcpeering.VPCPeeringService, uid types.UID) error {
+		deleted.Insert(string(uid))
+		return nil
+	})
+
+	// The VPC of the peer Namespace is not touched without a VPCPeering peering back.
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.False(t, created)
+	assert.True(t, deleted.Has("peering-uid-2"))
+	updated := &v1alpha1.VPCPeering{}
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+	assert.Contains(t, updated.Status.Conditions[0].Message, "not accepted by Namespace ns1")
+
+	// The peer Namespace accepts the peering, the VPCPeering in ns1 creates the NSX resources and the one
+	// in ns2 reports its state.
+	other.Spec.PeerVPC.Namespace = "ns2"
+	other.Status.Conditions = []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}}
+	other.Status.Routes = []v1alpha1.VPCPeeringRoute{{Network: "10.2.0.0/16"}}
+	assert.NoError(t, r.Client.Update(ctx, other))
+	assert.NoError(t, r.Client.Status().Update(ctx, other))
+	assert.Equal(t, req.NamespacedName, r.vpcPeeringToMirrors(ctx, other)[0].NamespacedName)
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.False(t, created)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionTrue, updated.Status.Conditions[0].Status)
+	assert.Equal(t, "/orgs/default/projects/p1/vpcs/vpc-uid-1", updated.Status.PeerVPCPath)
+	assert.Equal(t, other.Status.Routes, updated.Status.Routes)
+
+	// The VPCPeering in ns1 uses different next hops for the routes in ns2, the conflict is reported.
+	other.Spec.PeerNextHops = []v1alpha1.NextHop{{IPAddress: "172.10.0.2"}}
+	assert.NoError(t, r.Client.Update(ctx, other))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ResultNormal, result)
+	assert.False(t, created)
+	assert.NoError(t, r.Client.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, v1.ConditionFalse, updated.Status.Conditions[0].Status)
+	assert.Equal(t, "VPCPeeringConflict", updated.Status.Conditions[0].Reason)
+	assert.Contains(t, updated.Status.Conditions[0].Message, "VPCPeering ns1/peering1")
+}
+
+func TestVPCPeeringReconciler_GarbageCollector(t *testing.T) {
+	obj := &v1alpha1.VPCPeering{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "peering1", UID: "peering-uid-1"}}
+	r := newFakeReconciler(obj)
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(r.Service), "ListVPCPeeringID", func(_ *vpcpeering.VPCPeeringService) sets.Set[string] {
+		return sets.New[string]("peering-uid-1", "peering-uid-2")
+	})
+	defer patches.Reset()
+	deleted := sets.New[string]()
+	patches.ApplyMethod(reflect.TypeOf(r.Service), "DeleteVPCPeering", func(_ *vpcpeering.VPCPeeringService, uid types.UID) error {
+		deleted.Insert(string(uid))
+		return nil
+	})
+	cancel := make(chan bool)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		cancel <- true
+	}()
+	r.GarbageCollector(cancel, 100*time.Millisecond)
+	assert.Equal(t, []string{"peering-uid-2"}, deleted.UnsortedList())
+}
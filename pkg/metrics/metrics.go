@@ -21,6 +21,10 @@ const (
 	ControllerDeleteTotalKey        = "controller_delete_total"
 	ControllerDeleteSuccessTotalKey = "controller_delete_success_total"
 	ControllerDeleteFailTotalKey    = "controller_delete_fail_total"
+	SubnetIPTotalKey                = "subnet_ip_total"
+	SubnetIPAllocatedKey            = "subnet_ip_allocated"
+	SubnetIPAvailableKey            = "subnet_ip_available"
+	SubnetPortPoolDepthKey          = "subnetport_pool_depth"
 	ScrapeTimeout                   = 30
 )
 
@@ -98,6 +102,42 @@ var (
 		},
 		[]string{"res_type"},
 	)
+	SubnetIPTotal = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      SubnetIPTotalKey,
+			Help:      "Total number of IPs which can be allocated in Subnet or SubnetSet",
+		},
+		[]string{"res_type", "namespace", "name"},
+	)
+	SubnetIPAllocated = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      SubnetIPAllocatedKey,
+			Help:      "Number of IPs allocated in Subnet or SubnetSet",
+		},
+		[]string{"res_type", "namespace", "name"},
+	)
+	SubnetIPAvailable = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      SubnetIPAvailableKey,
+			Help:      "Number of IPs available for allocation in Subnet or SubnetSet",
+		},
+		[]string{"res_type", "namespace", "name"},
+	)
+	SubnetPortPoolDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: MetricNamespace,
+			Subsystem: MetricSubsystem,
+			Name:      SubnetPortPoolDepthKey,
+			Help:      "Number of pre-created subnet ports available in the pool of the node and SubnetSet",
+		},
+		[]string{"namespace", "subnetset", "node"},
+	)
 )
 
 var registerMetrics sync.Once
@@ -121,6 +161,10 @@ func InitializePrometheusMetrics() {
 		ControllerDeleteTotal,
 		ControllerDeleteSuccessTotal,
 		ControllerDeleteFailTotal,
+		SubnetIPTotal,
+		SubnetIPAllocated,
+		SubnetIPAvailable,
+		SubnetPortPoolDepth,
 	)
 }
 
@@ -136,3 +180,35 @@ func CounterInc(cf *config.NSXOperatorConfig, counter *prometheus.CounterVec, re
 		counter.WithLabelValues(res_type).Inc()
 	}
 }
+
+// SetSubnetIPUsage sets the IP usage gauges of the Subnet or SubnetSet.
+func SetSubnetIPUsage(cf *config.NSXOperatorConfig, resType, namespace, name string, total, allocated, available int64) {
+	if AreMetricsExposed(cf) {
+		SubnetIPTotal.WithLabelValues(resType, namespace, name).Set(float64(total))
+		SubnetIPAllocated.WithLabelValues(resType, namespace, name).Set(float64(allocated))
+		SubnetIPAvailable.WithLabelValues(resType, namespace, name).Set(float64(available))
+	}
+}
+
+// DeleteSubnetIPUsage deletes the IP usage gauges of the deleted Subnet or SubnetSet.
+func DeleteSubnetIPUsage(cf *config.NSXOperatorConfig, resType, namespace, name string) {
+	if AreMetricsExposed(cf) {
+		SubnetIPTotal.DeleteLabelValues(resType, namespace, name)
+		SubnetIPAllocated.DeleteLabelValues(resType, namespace, name)
+		SubnetIPAvailable.DeleteLabelValues(resType, namespace, name)
+	}
+}
+
+// SetSubnetPortPoolDepth sets the number of the pre-created subnet ports of the node and SubnetSet.
+func SetSubnetPortPoolDepth(cf *config.NSXOperatorConfig, namespace, subnetSet, node string, depth int) {
+	if AreMetricsExposed(cf) {
+		SubnetPortPoolDepth.WithLabelValues(namespace, subnetSet, node).Set(float64(depth))
+	}
+}
+
+// DeleteSubnetPortPoolDepth deletes the pool depth gauge of the removed node or SubnetSet.
+func DeleteSubnetPortPoolDepth(cf *config.NSXOperatorConfig, namespace, subnetSet, node string) {
+	if AreMetricsExposed(cf) {
+		SubnetPortPoolDepth.DeleteLabelValues(namespace, subnetSet, node)
+	}
+}
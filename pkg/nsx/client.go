@@ -17,11 +17,14 @@ import (
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-mp/nsx/trust_management/principal_identities"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/domains"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/domains/security_policies"
+	infra_ip_pools "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/ip_pools"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/sites/enforcement_points"
 	projects "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects"
 	infra "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/infra"
+	project_ip_pools "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/infra/ip_pools"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/infra/realized_state"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs"
+	vpc_group_members "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/groups/members"
 	nat "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/nat"
 	vpc_sp "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/security_policies"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/subnets"
@@ -58,6 +61,10 @@ type Client struct {
 	RuleClient     security_policies.RulesClient
 	InfraClient    nsx_policy.InfraClient
 
+	// for SecurityPolicy rule statistics
+	SecurityPolicyStatisticsClient    security_policies.StatisticsClient
+	VPCSecurityPolicyStatisticsClient vpc_sp.StatisticsClient
+
 	ClusterControlPlanesClient enforcement_points.ClusterControlPlanesClient
 	HostTransPortNodesClient   enforcement_points.HostTransportNodesClient
 	SubnetStatusClient         subnets.StatusClient
@@ -71,19 +78,25 @@ type Client struct {
 	VPCSecurityClient vpcs.SecurityPoliciesClient
 	VPCRuleClient     vpc_sp.RulesClient
 
-	OrgRootClient       nsx_policy.OrgRootClient
-	ProjectInfraClient  projects.InfraClient
-	VPCClient           projects.VpcsClient
-	IPBlockClient       infra.IpBlocksClient
-	StaticRouteClient   vpcs.StaticRoutesClient
-	NATRuleClient       nat.NatRulesClient
-	VpcGroupClient      vpcs.GroupsClient
-	PortClient          subnets.PortsClient
-	PortStateClient     ports.StateClient
-	IPPoolClient        subnets.IpPoolsClient
-	IPAllocationClient  ip_pools.IpAllocationsClient
-	SubnetsClient       vpcs.SubnetsClient
-	RealizedStateClient realized_state.RealizedEntitiesClient
+	OrgRootClient             nsx_policy.OrgRootClient
+	ProjectInfraClient        projects.InfraClient
+	VPCClient                 projects.VpcsClient
+	IPBlockClient             infra.IpBlocksClient
+	StaticRouteClient         vpcs.StaticRoutesClient
+	NATRuleClient             nat.NatRulesClient
+	VpcGroupClient            vpcs.GroupsClient
+	VpcGroupIPMembersClient   vpc_group_members.IpAddressesClient
+	PortClient                subnets.PortsClient
+	PortStateClient           ports.StateClient
+	IPPoolClient              subnets.IpPoolsClient
+	IPAllocationClient        ip_pools.IpAllocationsClient
+	IPAddressAllocationClient vpcs.IpAddressAllocationsClient
+	SubnetsClient             vpcs.SubnetsClient
+	RealizedStateClient       realized_state.RealizedEntitiesClient
+
+	// ProjectIPPoolAllocationClient and InfraIPPoolAllocationClient allocate IPs from the private and public IPPools.
+	ProjectIPPoolAllocationClient project_ip_pools.IpAllocationsClient
+	InfraIPPoolAllocationClient   infra_ip_pools.IpAllocationsClient
 
 	NSXChecker    NSXHealthChecker
 	NSXVerChecker NSXVersionChecker
@@ -140,6 +153,7 @@ func GetClient(cf *config.NSXOperatorConfig) *Client {
 	securityClient := domains.NewSecurityPoliciesClient(restConnector(cluster))
 	ruleClient := security_policies.NewRulesClient(restConnector(cluster))
 	infraClient := nsx_policy.NewInfraClient(restConnector(cluster))
+	securityPolicyStatisticsClient := security_policies.NewStatisticsClient(restConnector(cluster))
 
 	clusterControlPlanesClient := enforcement_points.NewClusterControlPlanesClient(restConnector(cluster))
 	hostTransportNodesClient := enforcement_points.NewHostTransportNodesClient(restConnector(cluster))
@@ -156,16 +170,21 @@ func GetClient(cf *config.NSXOperatorConfig) *Client {
 	staticRouteClient := vpcs.NewStaticRoutesClient(restConnector(cluster))
 	natRulesClient := nat.NewNatRulesClient(restConnector(cluster))
 	vpcGroupClient := vpcs.NewGroupsClient(restConnector(cluster))
+	vpcGroupIPMembersClient := vpc_group_members.NewIpAddressesClient(restConnector(cluster))
 	portClient := subnets.NewPortsClient(restConnector(cluster))
 	portStateClient := ports.NewStateClient(restConnector(cluster))
 	ipPoolClient := subnets.NewIpPoolsClient(restConnector(cluster))
 	ipAllocationClient := ip_pools.NewIpAllocationsClient(restConnector(cluster))
+	ipAddressAllocationClient := vpcs.NewIpAddressAllocationsClient(restConnector(cluster))
+	projectIPPoolAllocationClient := project_ip_pools.NewIpAllocationsClient(restConnector(cluster))
+	infraIPPoolAllocationClient := infra_ip_pools.NewIpAllocationsClient(restConnector(cluster))
 	subnetsClient := vpcs.NewSubnetsClient(restConnector(cluster))
 	subnetStatusClient := subnets.NewStatusClient(restConnector(cluster))
 	realizedStateClient := realized_state.NewRealizedEntitiesClient(restConnector(cluster))
 
 	vpcSecurityClient := vpcs.NewSecurityPoliciesClient(restConnector(cluster))
 	vpcRuleClient := vpc_sp.NewRulesClient(restConnector(cluster))
+	vpcSecurityPolicyStatisticsClient := vpc_sp.NewStatisticsClient(restConnector(cluster))
 
 	nsxChecker := &NSXHealthChecker{
 		cluster: cluster,
@@ -192,25 +211,33 @@ func GetClient(cf *config.NSXOperatorConfig) *Client {
 		PrincipalIdentitiesClient:  principalIdentitiesClient,
 		WithCertificateClient:      withCertificateClient,
 
-		OrgRootClient:      orgRootClient,
-		ProjectInfraClient: projectInfraClient,
-		VPCClient:          vpcClient,
-		IPBlockClient:      ipBlockClient,
-		StaticRouteClient:  staticRouteClient,
-		NATRuleClient:      natRulesClient,
-		VpcGroupClient:     vpcGroupClient,
-		PortClient:         portClient,
-		PortStateClient:    portStateClient,
-		SubnetStatusClient: subnetStatusClient,
-		VPCSecurityClient:  vpcSecurityClient,
-		VPCRuleClient:      vpcRuleClient,
-
-		NSXChecker:          *nsxChecker,
-		NSXVerChecker:       *nsxVersionChecker,
-		IPPoolClient:        ipPoolClient,
-		IPAllocationClient:  ipAllocationClient,
-		SubnetsClient:       subnetsClient,
-		RealizedStateClient: realizedStateClient,
+		OrgRootClient:           orgRootClient,
+		ProjectInfraClient:      projectInfraClient,
+		VPCClient:               vpcClient,
+		IPBlockClient:           ipBlockClient,
+		StaticRouteClient:       staticRouteClient,
+		NATRuleClient:           natRulesClient,
+		VpcGroupClient:          vpcGroupClient,
+		VpcGroupIPMembersClient: vpcGroupIPMembersClient,
+		PortClient:              portClient,
+		PortStateClient:         portStateClient,
+		SubnetStatusClient:      subnetStatusClient,
+		VPCSecurityClient:       vpcSecurityClient,
+		VPCRuleClient:           vpcRuleClient,
+
+		SecurityPolicyStatisticsClient:    securityPolicyStatisticsClient,
+		VPCSecurityPolicyStatisticsClient: vpcSecurityPolicyStatisticsClient,
+
+		NSXChecker:                *nsxChecker,
+		NSXVerChecker:             *nsxVersionChecker,
+		IPPoolClient:              ipPoolClient,
+		IPAllocationClient:        ipAllocationClient,
+		IPAddressAllocationClient: ipAddressAllocationClient,
+		SubnetsClient:             subnetsClient,
+		RealizedStateClient:       realizedStateClient,
+
+		ProjectIPPoolAllocationClient: projectIPPoolAllocationClient,
+		InfraIPPoolAllocationClient:   infraIPPoolAllocationClient,
 	}
 	// NSX version check will be restarted during SecurityPolicy reconcile
 	// So, it's unnecessary to exit even if failed in the first time
@@ -4,6 +4,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 )
 
 // VPCServiceProvider provides to methods other controllers and services.
@@ -17,12 +19,15 @@ type VPCServiceProvider interface {
 	GetVPCNetworkConfigByNamespace(ns string) *VPCNetworkConfigInfo
 	GetDefaultNetworkConfig() (bool, *VPCNetworkConfigInfo)
 	ListVPCInfo(ns string) []VPCResourceInfo
+	GetVPCInfo(ns string, vpcName string) (VPCResourceInfo, bool)
+	GetExternalIPv4BlockCIDRs(blocks []string) ([]string, error)
 }
 
 type SubnetServiceProvider interface {
 	GetSubnetByKey(key string) (*model.VpcSubnet, error)
 	GetSubnetByPath(path string) (*model.VpcSubnet, error)
 	GetSubnetsByIndex(key, value string) []*model.VpcSubnet
+	ListImportedSubnets(subnetSet *v1alpha1.SubnetSet) []*model.VpcSubnet
 	CreateOrUpdateSubnet(obj client.Object, vpcInfo VPCResourceInfo, tags []model.Tag) (string, error)
 	GenerateSubnetNSTags(obj client.Object, nsUID string) []model.Tag
 }
@@ -40,3 +40,13 @@ func (m *MockVPCServiceProvider) ListVPCInfo(ns string) []VPCResourceInfo {
 	m.Called()
 	return []VPCResourceInfo{}
 }
+
+func (m *MockVPCServiceProvider) GetVPCInfo(ns string, vpcName string) (VPCResourceInfo, bool) {
+	m.Called()
+	return VPCResourceInfo{}, false
+}
+
+func (m *MockVPCServiceProvider) GetExternalIPv4BlockCIDRs(blocks []string) ([]string, error) {
+	m.Called()
+	return nil, nil
+}
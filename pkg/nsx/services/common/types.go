@@ -40,6 +40,8 @@ const (
 	TagScopeNetworkPolicyUID           string = "nsx-op/network_policy_uid"
 	TagScopeStaticRouteCRName          string = "nsx-op/static_route_name"
 	TagScopeStaticRouteCRUID           string = "nsx-op/static_route_uid"
+	TagScopeAddressGroupCRName         string = "nsx-op/address_group_name"
+	TagScopeAddressGroupCRUID          string = "nsx-op/address_group_uid"
 	TagScopeRuleID                     string = "nsx-op/rule_id"
 	TagScopeGoupID                     string = "nsx-op/group_id"
 	TagScopeGroupType                  string = "nsx-op/group_type"
@@ -50,12 +52,21 @@ const (
 	TagScopeProjectGroupShared         string = "nsx-op/is_nsx_project_shared"
 	TagScopeVPCCRName                  string = "nsx-op/vpc_name"
 	TagScopeVPCCRUID                   string = "nsx-op/vpc_uid"
+	TagScopeVPCAdopted                 string = "nsx-op/vpc_adopted"
 	TagScopeSubnetPortCRName           string = "nsx-op/subnetport_name"
 	TagScopeSubnetPortCRUID            string = "nsx-op/subnetport_uid"
 	TagScopeIPPoolCRName               string = "nsx-op/ippool_name"
 	TagScopeIPPoolCRUID                string = "nsx-op/ippool_uid"
 	TagScopeIPPoolCRType               string = "nsx-op/ippool_type"
 	TagScopeIPSubnetName               string = "nsx-op/ipsubnet_name"
+	TagScopeIPAddressAllocationCRName  string = "nsx-op/ipaddressallocation_name"
+	TagScopeIPAddressAllocationCRUID   string = "nsx-op/ipaddressallocation_uid"
+	TagScopeVPCPeeringCRName           string = "nsx-op/vpcpeering_name"
+	TagScopeVPCPeeringCRUID            string = "nsx-op/vpcpeering_uid"
+	TagScopeNATRuleCRName              string = "nsx-op/natrule_name"
+	TagScopeNATRuleCRUID               string = "nsx-op/natrule_uid"
+	TagScopeEgressIPCRName             string = "nsx-op/egressip_name"
+	TagScopeEgressIPCRUID              string = "nsx-op/egressip_uid"
 	TagScopeVMNamespaceUID             string = "nsx-op/vm_namespace_uid"
 	TagScopeVMNamespace                string = "nsx-op/vm_namespace"
 	LabelDefaultSubnetSet              string = "nsxoperator.vmware.com/default-subnetset-for"
@@ -76,12 +87,19 @@ const (
 	TagValueGroupAvi                   string = "avi"
 	AnnotationVPCNetworkConfig         string = "nsx.vmware.com/vpc_network_config"
 	AnnotationVPCName                  string = "nsx.vmware.com/vpc_name"
+	AnnotationNSXVPCPath               string = "nsx.vmware.com/nsx_vpc_path"
+	AnnotationNetworkReady             string = "nsx.vmware.com/network_ready"
 	AnnotationDefaultNetworkConfig     string = "nsx.vmware.com/default"
 	AnnotationAttachmentRef            string = "nsx.vmware.com/attachment_ref"
 	AnnotationPodMAC                   string = "nsx.vmware.com/mac"
 	AnnotationPodAttachment            string = "nsx.vmware.com/attachment"
+	AnnotationRuleStatistics           string = "nsx.vmware.com/rule_statistics"
+	AnnotationPodNetworks              string = "k8s.v1.cni.cncf.io/networks"
+	AnnotationPodNetworkStatus         string = "k8s.v1.cni.cncf.io/network-status"
 	TagScopePodName                    string = "nsx-op/pod_name"
 	TagScopePodUID                     string = "nsx-op/pod_uid"
+	TagScopeSubnetPortPoolSubnetSetUID string = "nsx-op/pool_subnetset_uid"
+	TagScopeSubnetPortPoolNode         string = "nsx-op/pool_node"
 	ValueMajorVersion                  string = "1"
 	ValueMinorVersion                  string = "0"
 	ValuePatchVersion                  string = "0"
@@ -91,19 +109,29 @@ const (
 	RealizeMaxRetries   = 3
 	IPPoolFinalizerName = "ippool.nsx.vmware.com/finalizer"
 	DefaultSNATID       = "DEFAULT"
+	UserNATID           = "USER"
 	AVISubnetLBID       = "_AVI_SUBNET--LB"
 	IPPoolTypePublic    = "Public"
 	IPPoolTypePrivate   = "Private"
 
-	SecurityPolicyFinalizerName    = "securitypolicy.nsx.vmware.com/finalizer"
-	NetworkPolicyFinalizerName     = "networkpolicy.nsx.vmware.com/finalizer"
-	StaticRouteFinalizerName       = "staticroute.nsx.vmware.com/finalizer"
-	NSXServiceAccountFinalizerName = "nsxserviceaccount.nsx.vmware.com/finalizer"
-	SubnetFinalizerName            = "subnet.nsx.vmware.com/finalizer"
-	SubnetSetFinalizerName         = "subnetset.nsx.vmware.com/finalizer"
-	SubnetPortFinalizerName        = "subnetport.nsx.vmware.com/finalizer"
-	VPCFinalizerName               = "vpc.nsx.vmware.com/finalizer"
-	PodFinalizerName               = "pod.nsx.vmware.com/finalizer"
+	SecurityPolicyFinalizerName      = "securitypolicy.nsx.vmware.com/finalizer"
+	NetworkPolicyFinalizerName       = "networkpolicy.nsx.vmware.com/finalizer"
+	AddressGroupFinalizerName        = "addressgroup.nsx.vmware.com/finalizer"
+	StaticRouteFinalizerName         = "staticroute.nsx.vmware.com/finalizer"
+	NSXServiceAccountFinalizerName   = "nsxserviceaccount.nsx.vmware.com/finalizer"
+	SubnetFinalizerName              = "subnet.nsx.vmware.com/finalizer"
+	SubnetSetFinalizerName           = "subnetset.nsx.vmware.com/finalizer"
+	SubnetPortFinalizerName          = "subnetport.nsx.vmware.com/finalizer"
+	VPCFinalizerName                 = "vpc.nsx.vmware.com/finalizer"
+	PodFinalizerName                 = "pod.nsx.vmware.com/finalizer"
+	IPAddressAllocationFinalizerName = "ipaddressallocation.nsx.vmware.com/finalizer"
+	VPCPeeringFinalizerName          = "vpcpeering.nsx.vmware.com/finalizer"
+	NATRuleFinalizerName             = "natrule.nsx.vmware.com/finalizer"
+	EgressIPFinalizerName            = "egressip.nsx.vmware.com/finalizer"
+	VPCNetworkConfigFinalizerName    = "vpcnetworkconfiguration.nsx.vmware.com/finalizer"
+
+	// PodSchedulingGateNetworkReady is removed from the Pods by nsx-operator when the network of the Namespace is ready.
+	PodSchedulingGateNetworkReady = "nsx.vmware.com/network-ready"
 
 	IndexKeySubnetID            = "IndexKeySubnetID"
 	IndexKeyPathPath            = "Path"
@@ -118,6 +146,7 @@ const (
 	RuleSuffixEgressReject  = "egress-reject"
 	SecurityPolicyPrefix    = "sp"
 	NetworkPolicyPrefix     = "np"
+	AddressGroupPrefix      = "ag"
 	TargetGroupSuffix       = "scope"
 	SrcGroupSuffix          = "src"
 	DstGroupSuffix          = "dst"
@@ -137,6 +166,7 @@ var (
 	ResourceTypeDomain                 = "Domain"
 	ResourceTypeSecurityPolicy         = "SecurityPolicy"
 	ResourceTypeNetworkPolicy          = "NetworkPolicy"
+	ResourceTypeAddressGroup           = "AddressGroup"
 	ResourceTypeGroup                  = "Group"
 	ResourceTypeRule                   = "Rule"
 	ResourceTypeIPBlock                = "IpAddressBlock"
@@ -158,11 +188,14 @@ var (
 	// ResourceTypeClusterControlPlane is used by NSXServiceAccountController
 	ResourceTypeClusterControlPlane = "clustercontrolplane"
 	// ResourceTypePrincipalIdentity is used by NSXServiceAccountController, and it is MP resource type.
-	ResourceTypePrincipalIdentity = "principalidentity"
-	ResourceTypeSubnet            = "VpcSubnet"
-	ResourceTypeIPPool            = "IpAddressPool"
-	ResourceTypeIPPoolBlockSubnet = "IpAddressPoolBlockSubnet"
-	ResourceTypeNode              = "HostTransportNode"
+	ResourceTypePrincipalIdentity   = "principalidentity"
+	ResourceTypeSubnet              = "VpcSubnet"
+	ResourceTypeIPPool              = "IpAddressPool"
+	ResourceTypeIPPoolBlockSubnet   = "IpAddressPoolBlockSubnet"
+	ResourceTypeIPAddressAllocation = "VpcIpAddressAllocation"
+	ResourceTypeIPPoolAllocation    = "IpAddressAllocation"
+	ResourceTypeNATRule             = "PolicyVpcNatRule"
+	ResourceTypeNode                = "HostTransportNode"
 )
 
 type Service struct {
@@ -0,0 +1,78 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package egressip
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+var String = common.String
+
+const (
+	EGRESSIPPREFIX = "egressip"
+	// natRuleSequenceNumber is reserved for the SNAT rules of EgressIP, the sequence numbers of
+	// the NATRule CRs start from 1.
+	natRuleSequenceNumber int64 = 0
+)
+
+func buildID(obj *v1alpha1.EgressIP) string {
+	return util.GenerateID(string(obj.UID), EGRESSIPPREFIX, "", "")
+}
+
+func buildName(obj *v1alpha1.EgressIP) string {
+	return util.GenerateTruncName(common.MaxNameLength, obj.Name, EGRESSIPPREFIX, "", "", "")
+}
+
+func buildVPCPath(vpcInfo common.VPCResourceInfo) string {
+	return fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s", vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID)
+}
+
+func (service *EgressIPService) buildGroup(obj *v1alpha1.EgressIP, vpcInfo common.VPCResourceInfo) (*model.Group, error) {
+	id := buildID(obj)
+	group := &model.Group{
+		Id:          String(id),
+		Path:        String(fmt.Sprintf("%s/groups/%s", buildVPCPath(vpcInfo), id)),
+		DisplayName: String(buildName(obj)),
+		Tags:        util.BuildBasicTags(service.NSXConfig.Cluster, obj, ""),
+	}
+	if err := service.groupBuilder.BuildPodSelectorGroupExpression(obj.Namespace, obj.Spec.PodSelector, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// buildNATRule builds the SNAT rule translating the source IPs of the group members to the egress IP.
+// The rule is in the USER NAT section, which has a higher priority than the default SNAT rule of the VPC.
+// The VPC NAT rules can't reference an NSX group, so the rule matches the sorted IPs realized as the
+// members of the group.
+func (service *EgressIPService) buildNATRule(obj *v1alpha1.EgressIP, vpcInfo common.VPCResourceInfo, egressIP string, sourceIPs []string) *model.PolicyVpcNatRule {
+	id := buildID(obj)
+	return &model.PolicyVpcNatRule{
+		Id:                String(id),
+		Path:              String(fmt.Sprintf("%s/nat/%s/nat-rules/%s", buildVPCPath(vpcInfo), common.UserNATID, id)),
+		DisplayName:       String(buildName(obj)),
+		Action:            String(model.PolicyVpcNatRule_ACTION_SNAT),
+		SourceNetwork:     String(strings.Join(sourceIPs, ",")),
+		TranslatedNetwork: String(egressIP),
+		SequenceNumber:    common.Int64(natRuleSequenceNumber),
+		Enabled:           common.Bool(true),
+		Tags:              util.BuildBasicTags(service.NSXConfig.Cluster, obj, ""),
+	}
+}
+
+func (service *EgressIPService) buildIPAddressAllocation(obj *v1alpha1.EgressIP) *model.VpcIpAddressAllocation {
+	return &model.VpcIpAddressAllocation{
+		Id:                       String(buildID(obj)),
+		DisplayName:              String(buildName(obj)),
+		Tags:                     util.BuildBasicTags(service.NSXConfig.Cluster, obj, ""),
+		IpAddressBlockVisibility: String(model.VpcIpAddressAllocation_IP_ADDRESS_BLOCK_VISIBILITY_EXTERNAL),
+	}
+}
@@ -0,0 +1,33 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package egressip
+
+import (
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// compareNATRule returns true if the SNAT rules translate the same Pod IPs to the same egress IP.
+func compareNATRule(existing *model.PolicyVpcNatRule, expected *model.PolicyVpcNatRule) bool {
+	return stringValue(existing.Path) == stringValue(expected.Path) &&
+		stringValue(existing.SourceNetwork) == stringValue(expected.SourceNetwork) &&
+		stringValue(existing.TranslatedNetwork) == stringValue(expected.TranslatedNetwork)
+}
+
+// compareGroup returns true if the groups are in the same VPC and select the same Pods.
+func compareGroup(existing *model.Group, expected *model.Group) bool {
+	if stringValue(existing.Path) != stringValue(expected.Path) {
+		return false
+	}
+	return !common.CompareResource((*securitypolicy.Group)(existing), (*securitypolicy.Group)(expected))
+}
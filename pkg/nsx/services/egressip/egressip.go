@@ -0,0 +1,346 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package egressip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+var (
+	log             = logger.Log
+	MarkedForDelete = true
+)
+
+type EgressIPService struct {
+	common.Service
+	NATRuleStore             *NATRuleStore
+	IPAddressAllocationStore *IPAddressAllocationStore
+	GroupStore               *GroupStore
+	VPCService               common.VPCServiceProvider
+	// groupBuilder builds the group expressions of the Pod selector as SecurityPolicy does.
+	groupBuilder *securitypolicy.SecurityPolicyService
+}
+
+// InitializeEgressIP sync NSX resources
+func InitializeEgressIP(service common.Service, vpcService common.VPCServiceProvider) (*EgressIPService, error) {
+	wg := sync.WaitGroup{}
+	wgDone := make(chan bool)
+	fatalErrors := make(chan error)
+
+	wg.Add(3)
+	egressIPService := &EgressIPService{
+		Service:    service,
+		VPCService: vpcService,
+		NATRuleStore: &NATRuleStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeEgressIPCRUID: indexFunc}),
+			BindingType: model.PolicyVpcNatRuleBindingType(),
+		}},
+		IPAddressAllocationStore: &IPAddressAllocationStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeEgressIPCRUID: indexFunc}),
+			BindingType: model.VpcIpAddressAllocationBindingType(),
+		}},
+		GroupStore: &GroupStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeEgressIPCRUID: indexFunc}),
+			BindingType: model.GroupBindingType(),
+		}},
+		groupBuilder: &securitypolicy.SecurityPolicyService{Service: service},
+	}
+	tags := []model.Tag{
+		{Scope: String(common.TagScopeEgressIPCRUID)},
+	}
+	go egressIPService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeNATRule, tags, egressIPService.NATRuleStore)
+	go egressIPService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeIPAddressAllocation, tags, egressIPService.IPAddressAllocationStore)
+	go egressIPService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeGroup, tags, egressIPService.GroupStore)
+
+	go func() {
+		wg.Wait()
+		close(wgDone)
+	}()
+	select {
+	case <-wgDone:
+		break
+	case err := <-fatalErrors:
+		close(fatalErrors)
+		return egressIPService, err
+	}
+	return egressIPService, nil
+}
+
+// CreateOrUpdateEgressIP allocates the egress IP from the VPC external IP blocks, creates the group of the
+// selected Pods and the SNAT rule translating the IPs of the group members to the egress IP, then fills them
+// in the status of the EgressIP CR. podIPs are the IPs of the selected Pods known by Kubernetes, the SNAT rule
+// is removed if none is given, and an error is returned until NSX has realized all of them as group members.
+func (service *EgressIPService) CreateOrUpdateEgressIP(obj *v1alpha1.EgressIP, podIPs []string) error {
+	vpcInfo, found := service.VPCService.GetVPCInfo(obj.Namespace, obj.Spec.VPCName)
+	if !found {
+		return fmt.Errorf("no VPC found for namespace %s", obj.Namespace)
+	}
+
+	egressIP, err := service.allocateEgressIP(obj, vpcInfo)
+	if err != nil {
+		return err
+	}
+	obj.Status.EgressIP = egressIP
+
+	group, err := service.buildGroup(obj, vpcInfo)
+	if err != nil {
+		return nsxutil.RestrictionError{Desc: err.Error()}
+	}
+	if err := service.createOrUpdateGroup(group, vpcInfo); err != nil {
+		return err
+	}
+	obj.Status.GroupPath = *group.Path
+
+	if len(podIPs) == 0 {
+		// An empty source network matches all the traffic, remove the SNAT rule instead.
+		return service.ReleaseNATRule(obj)
+	}
+	memberIPs, err := service.listGroupIPMembers(group, vpcInfo)
+	if err != nil {
+		return err
+	}
+	if missing := sets.New[string](podIPs...).Difference(memberIPs); missing.Len() > 0 {
+		return fmt.Errorf("Pod IPs %v are not realized in NSX group %s yet", sets.List(missing), *group.Path)
+	}
+	rule := service.buildNATRule(obj, vpcInfo, egressIP, sets.List(memberIPs))
+	if err := service.createOrUpdateNATRule(rule, vpcInfo); err != nil {
+		return err
+	}
+	obj.Status.NATRulePath = *rule.Path
+	return nil
+}
+
+// ReleaseNATRule deletes the SNAT rule of the EgressIP CR and clears it in the status, the egress IP
+// and the group are kept.
+func (service *EgressIPService) ReleaseNATRule(obj *v1alpha1.EgressIP) error {
+	if err := service.deleteNATRules(obj.UID); err != nil {
+		return err
+	}
+	obj.Status.NATRulePath = ""
+	return nil
+}
+
+// listGroupIPMembers returns the IPv4 addresses of the members realized in the NSX group.
+func (service *EgressIPService) listGroupIPMembers(group *model.Group, vpcInfo common.VPCResourceInfo) (sets.Set[string], error) {
+	memberIPs := sets.New[string]()
+	var cursor *string
+	for {
+		result, err := service.NSXClient.VpcGroupIPMembersClient.List(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *group.Id, cursor, nil, nil, nil, nil, nil, nil)
+		if err != nil {
+			log.Error(err, "failed to list NSX group IP members", "path", *group.Path)
+			return nil, err
+		}
+		for _, memberIP := range result.Results {
+			if ip := net.ParseIP(memberIP); ip != nil && ip.To4() != nil {
+				memberIPs.Insert(memberIP)
+			}
+		}
+		if result.Cursor == nil || *result.Cursor == "" || len(result.Results) == 0 {
+			return memberIPs, nil
+		}
+		cursor = result.Cursor
+	}
+}
+
+func (service *EgressIPService) createOrUpdateGroup(group *model.Group, vpcInfo common.VPCResourceInfo) error {
+	existing := service.GroupStore.GetByKey(*group.Id)
+	if existing != nil && compareGroup(existing, group) {
+		log.V(1).Info("group is not changed", "path", *group.Path)
+		return nil
+	}
+	if existing != nil && *existing.Path != *group.Path {
+		// The EgressIP is moved to another VPC.
+		if err := service.deleteGroup(existing); err != nil {
+			return err
+		}
+	}
+	if err := service.NSXClient.VpcGroupClient.Patch(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *group.Id, *group); err != nil {
+		log.Error(err, "failed to patch NSX group", "path", *group.Path)
+		return err
+	}
+	if err := service.GroupStore.Apply(group); err != nil {
+		return err
+	}
+	log.Info("created or updated group", "path", *group.Path)
+	return nil
+}
+
+func (service *EgressIPService) createOrUpdateNATRule(rule *model.PolicyVpcNatRule, vpcInfo common.VPCResourceInfo) error {
+	existing := service.NATRuleStore.GetByKey(*rule.Id)
+	if existing != nil && compareNATRule(existing, rule) {
+		log.V(1).Info("NAT rule is not changed", "path", *rule.Path)
+		return nil
+	}
+	if existing != nil && *existing.Path != *rule.Path {
+		if err := service.deleteNATRule(existing); err != nil {
+			return err
+		}
+	}
+	client := service.NSXClient.NATRuleClient
+	if err := client.Patch(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, common.UserNATID, *rule.Id, *rule); err != nil {
+		log.Error(err, "failed to patch NSX NAT rule", "path", *rule.Path)
+		return err
+	}
+	nsxRule, err := client.Get(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, common.UserNATID, *rule.Id)
+	if err != nil {
+		return err
+	}
+	if err := service.NATRuleStore.Apply(&nsxRule); err != nil {
+		return err
+	}
+	log.Info("created or updated NAT rule", "path", *rule.Path)
+	return nil
+}
+
+// allocateEgressIP returns the IP allocated from the external IP blocks of the VPC for the EgressIP CR.
+func (service *EgressIPService) allocateEgressIP(obj *v1alpha1.EgressIP, vpcInfo common.VPCResourceInfo) (string, error) {
+	for _, allocation := range service.IPAddressAllocationStore.GetByIndex(common.TagScopeEgressIPCRUID, string(obj.UID)) {
+		allocationVPC, err := common.ParseVPCResourcePath(*allocation.Path)
+		if err == nil && allocationVPC.VPCID == vpcInfo.VPCID && allocation.AllocationIp != nil {
+			return *allocation.AllocationIp, nil
+		}
+		if err := service.deleteIPAddressAllocation(allocation); err != nil {
+			return "", err
+		}
+	}
+	nsxAllocation := service.buildIPAddressAllocation(obj)
+	client := service.NSXClient.IPAddressAllocationClient
+	if err := client.Patch(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *nsxAllocation.Id, *nsxAllocation); err != nil {
+		log.Error(err, "failed to allocate egress IP", "id", *nsxAllocation.Id)
+		return "", err
+	}
+	// Get the allocation from NSX after patch operation as NSX renders the allocated IP.
+	allocation, err := client.Get(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *nsxAllocation.Id)
+	if err != nil {
+		return "", err
+	}
+	if err := service.IPAddressAllocationStore.Apply(&allocation); err != nil {
+		return "", err
+	}
+	if allocation.AllocationIp == nil || *allocation.AllocationIp == "" {
+		return "", fmt.Errorf("no egress IP allocated for NSX IP address allocation %s", *allocation.Id)
+	}
+	log.Info("allocated egress IP", "id", *allocation.Id, "ip", *allocation.AllocationIp)
+	return *allocation.AllocationIp, nil
+}
+
+func (service *EgressIPService) deleteIPAddressAllocation(allocation *model.VpcIpAddressAllocation) error {
+	vpcInfo, err := common.ParseVPCResourcePath(*allocation.Path)
+	if err != nil {
+		return err
+	}
+	if err := service.NSXClient.IPAddressAllocationClient.Delete(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *allocation.Id); err != nil {
+		log.Error(err, "failed to release egress IP", "id", *allocation.Id)
+		return err
+	}
+	allocationCopy := *allocation
+	allocationCopy.MarkedForDelete = &MarkedForDelete
+	if err := service.IPAddressAllocationStore.Apply(&allocationCopy); err != nil {
+		return err
+	}
+	log.Info("released egress IP", "id", *allocation.Id, "ip", allocation.AllocationIp)
+	return nil
+}
+
+func (service *EgressIPService) deleteGroup(group *model.Group) error {
+	vpcInfo, err := common.ParseVPCResourcePath(*group.Path)
+	if err != nil {
+		return err
+	}
+	if err := service.NSXClient.VpcGroupClient.Delete(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *group.Id); err != nil {
+		log.Error(err, "failed to delete NSX group", "path", *group.Path)
+		return err
+	}
+	groupCopy := *group
+	groupCopy.MarkedForDelete = &MarkedForDelete
+	if err := service.GroupStore.Apply(&groupCopy); err != nil {
+		return err
+	}
+	log.Info("deleted group", "path", *group.Path)
+	return nil
+}
+
+func (service *EgressIPService) deleteNATRule(rule *model.PolicyVpcNatRule) error {
+	vpcInfo, err := common.ParseVPCResourcePath(*rule.Path)
+	if err != nil {
+		return err
+	}
+	if err := service.NSXClient.NATRuleClient.Delete(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, common.UserNATID, *rule.Id); err != nil {
+		log.Error(err, "failed to delete NSX NAT rule", "path", *rule.Path)
+		return err
+	}
+	ruleCopy := *rule
+	ruleCopy.MarkedForDelete = &MarkedForDelete
+	if err := service.NATRuleStore.Apply(&ruleCopy); err != nil {
+		return err
+	}
+	log.Info("deleted NAT rule", "path", *rule.Path)
+	return nil
+}
+
+func (service *EgressIPService) deleteNATRules(uid types.UID) error {
+	for _, rule := range service.NATRuleStore.GetByIndex(common.TagScopeEgressIPCRUID, string(uid)) {
+		if err := service.deleteNATRule(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteEgressIP deletes the SNAT rule and the group of the EgressIP CR, then releases the egress IP.
+// The SNAT rule is deleted first as it references the egress IP.
+func (service *EgressIPService) DeleteEgressIP(uid types.UID) error {
+	if err := service.deleteNATRules(uid); err != nil {
+		return err
+	}
+	for _, group := range service.GroupStore.GetByIndex(common.TagScopeEgressIPCRUID, string(uid)) {
+		if err := service.deleteGroup(group); err != nil {
+			return err
+		}
+	}
+	for _, allocation := range service.IPAddressAllocationStore.GetByIndex(common.TagScopeEgressIPCRUID, string(uid)) {
+		if err := service.deleteIPAddressAllocation(allocation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListEgressIPID returns the UIDs of the EgressIP CRs which have NSX resources.
+func (service *EgressIPService) ListEgressIPID() sets.Set[string] {
+	uidSet := service.NATRuleStore.ListIndexFuncValues(common.TagScopeEgressIPCRUID)
+	uidSet = uidSet.Union(service.IPAddressAllocationStore.ListIndexFuncValues(common.TagScopeEgressIPCRUID))
+	return uidSet.Union(service.GroupStore.ListIndexFuncValues(common.TagScopeEgressIPCRUID))
+}
+
+func (service *EgressIPService) Cleanup(ctx context.Context) error {
+	uids := service.ListEgressIPID()
+	log.Info("cleaning up egressip", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			if err := service.DeleteEgressIP(types.UID(uid)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
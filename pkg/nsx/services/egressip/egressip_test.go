@@ -0,0 +1,208 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package egressip
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs"
+	vpc_group_members "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/groups/members"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/nat"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/securitypolicy"
+)
+
+type fakeVPCService struct {
+	common.VPCServiceProvider
+}
+
+func (f *fakeVPCService) GetVPCInfo(_ string, vpcName string) (common.VPCResourceInfo, bool) {
+	if vpcName == "" {
+		vpcName = "vpc1"
+	}
+	return common.VPCResourceInfo{OrgID: "default", ProjectID: "p1", VPCID: vpcName}, true
+}
+
+type fakeNatRulesClient struct {
+	nat.NatRulesClient
+	rules map[string]model.PolicyVpcNatRule
+}
+
+func (f *fakeNatRulesClient) Patch(orgId string, projectId string, vpcId string, natId string, natRuleId string, rule model.PolicyVpcNatRule) error {
+	f.rules[fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/nat/%s/nat-rules/%s", orgId, projectId, vpcId, natId, natRuleId)] = rule
+	return nil
+}
+
+func (f *fakeNatRulesClient) Get(orgId string, projectId string, vpcId string, natId string, natRuleId string) (model.PolicyVpcNatRule, error) {
+	return f.rules[fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/nat/%s/nat-rules/%s", orgId, projectId, vpcId, natId, natRuleId)], nil
+}
+
+func (f *fakeNatRulesClient) Delete(orgId string, projectId string, vpcId string, natId string, natRuleId string) error {
+	delete(f.rules, fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/nat/%s/nat-rules/%s", orgId, projectId, vpcId, natId, natRuleId))
+	return nil
+}
+
+// fakeIPAddressAllocationsClient renders the IPs from 192.168.0.0/24 for the allocations.
+type fakeIPAddressAllocationsClient struct {
+	vpcs.IpAddressAllocationsClient
+	allocations map[string]model.VpcIpAddressAllocation
+	next        int
+}
+
+func (f *fakeIPAddressAllocationsClient) Patch(orgId string, projectId string, vpcId string, ipAddressAllocationId string, allocation model.VpcIpAddressAllocation) error {
+	f.next++
+	allocation.AllocationIp = String(fmt.Sprintf("192.168.0.%d", f.next))
+	allocation.Path = String(fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/ip-address-allocations/%s", orgId, projectId, vpcId, ipAddressAllocationId))
+	f.allocations[*allocation.Path] = allocation
+	return nil
+}
+
+func (f *fakeIPAddressAllocationsClient) Get(orgId string, projectId string, vpcId string, ipAddressAllocationId string) (model.VpcIpAddressAllocation, error) {
+	return f.allocations[fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/ip-address-allocations/%s", orgId, projectId, vpcId, ipAddressAllocationId)], nil
+}
+
+func (f *fakeIPAddressAllocationsClient) Delete(orgId string, projectId string, vpcId string, ipAddressAllocationId string) error {
+	delete(f.allocations, fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/ip-address-allocations/%s", orgId, projectId, vpcId, ipAddressAllocationId))
+	return nil
+}
+
+// fakeGroupIPMembersClient returns the IPs of members for all the groups.
+type fakeGroupIPMembersClient struct {
+	vpc_group_members.IpAddressesClient
+	members []string
+}
+
+func (f *fakeGroupIPMembersClient) List(_ string, _ string, _ string, _ string, _ *string, _ *string, _ *bool, _ *string, _ *int64, _ *bool, _ *string) (model.PolicyGroupIPMembersListResult, error) {
+	return model.PolicyGroupIPMembersListResult{Results: f.members}, nil
+}
+
+type fakeGroupsClient struct {
+	vpcs.GroupsClient
+	groups  map[string]model.Group
+	patched int
+}
+
+func (f *fakeGroupsClient) Patch(orgId string, projectId string, vpcId string, groupId string, group model.Group) error {
+	f.patched++
+	f.groups[fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/groups/%s", orgId, projectId, vpcId, groupId)] = group
+	return nil
+}
+
+func (f *fakeGroupsClient) Delete(orgId string, projectId string, vpcId string, groupId string) error {
+	delete(f.groups, fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/groups/%s", orgId, projectId, vpcId, groupId))
+	return nil
+}
+
+func createService() (*EgressIPService, *fakeNatRulesClient, *fakeIPAddressAllocationsClient, *fakeGroupsClient, *fakeGroupIPMembersClient) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ns1", UID: "ns-uid-1"},
+	}).Build()
+	rulesClient := &fakeNatRulesClient{rules: map[string]model.PolicyVpcNatRule{}}
+	allocationsClient := &fakeIPAddressAllocationsClient{allocations: map[string]model.VpcIpAddressAllocation{}}
+	groupsClient := &fakeGroupsClient{groups: map[string]model.Group{}}
+	membersClient := &fakeGroupIPMembersClient{}
+	commonService := common.Service{
+		Client:    k8sClient,
+		NSXClient: &nsx.Client{NATRuleClient: rulesClient, IPAddressAllocationClient: allocationsClient, VpcGroupClient: groupsClient, VpcGroupIPMembersClient: membersClient},
+		NSXConfig: &config.NSXOperatorConfig{CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test", EnableVPCNetwork: true}},
+	}
+	service := &EgressIPService{
+		Service:    commonService,
+		VPCService: &fakeVPCService{},
+		NATRuleStore: &NATRuleStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeEgressIPCRUID: indexFunc}),
+			BindingType: model.PolicyVpcNatRuleBindingType(),
+		}},
+		IPAddressAllocationStore: &IPAddressAllocationStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeEgressIPCRUID: indexFunc}),
+			BindingType: model.VpcIpAddressAllocationBindingType(),
+		}},
+		GroupStore: &GroupStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeEgressIPCRUID: indexFunc}),
+			BindingType: model.GroupBindingType(),
+		}},
+		groupBuilder: &securitypolicy.SecurityPolicyService{Service: commonService},
+	}
+	return service, rulesClient, allocationsClient, groupsClient, membersClient
+}
+
+func TestCreateOrUpdateEgressIP(t *testing.T) {
+	service, rulesClient, allocationsClient, groupsClient, membersClient := createService()
+	obj := &v1alpha1.EgressIP{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web", UID: "egressip-uid-1"},
+		Spec: v1alpha1.EgressIPSpec{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	}
+	rulePath := "/orgs/default/projects/p1/vpcs/vpc1/nat/USER/nat-rules/egressip_egressip-uid-1"
+
+	// No Pod is selected, the egress IP and the group are created without the SNAT rule.
+	assert.NoError(t, service.CreateOrUpdateEgressIP(obj, nil))
+	assert.Equal(t, "192.168.0.1", obj.Status.EgressIP)
+	assert.Equal(t, "/orgs/default/projects/p1/vpcs/vpc1/groups/egressip_egressip-uid-1", obj.Status.GroupPath)
+	assert.Empty(t, obj.Status.NATRulePath)
+	assert.Len(t, groupsClient.groups, 1)
+	assert.NotEmpty(t, groupsClient.groups["/orgs/default/projects/p1/vpcs/vpc1/groups/egressip_egressip-uid-1"].Expression)
+	assert.Empty(t, rulesClient.rules)
+
+	// The SNAT rule isn't created until the Pods are realized as the group members.
+	membersClient.members = []string{"10.0.0.3"}
+	err := service.CreateOrUpdateEgressIP(obj, []string{"10.0.0.5", "10.0.0.3"})
+	assert.ErrorContains(t, err, "10.0.0.5")
+	assert.Empty(t, rulesClient.rules)
+
+	// The SNAT rule translates the IPs of the group members to the egress IP.
+	membersClient.members = []string{"10.0.0.5", "fd00::5", "10.0.0.3"}
+	assert.NoError(t, service.CreateOrUpdateEgressIP(obj, []string{"10.0.0.5", "10.0.0.3"}))
+	assert.Equal(t, rulePath, obj.Status.NATRulePath)
+	rule := rulesClient.rules[rulePath]
+	assert.Equal(t, "SNAT", *rule.Action)
+	assert.Equal(t, "10.0.0.3,10.0.0.5", *rule.SourceNetwork)
+	assert.Equal(t, "192.168.0.1", *rule.TranslatedNetwork)
+	assert.Equal(t, int64(0), *rule.SequenceNumber)
+	// The unchanged group is not patched again.
+	assert.Equal(t, 1, groupsClient.patched)
+
+	// The SNAT rule is removed once no Pod is selected.
+	assert.NoError(t, service.CreateOrUpdateEgressIP(obj, []string{}))
+	assert.Empty(t, obj.Status.NATRulePath)
+	assert.Empty(t, rulesClient.rules)
+
+	// The EgressIP is moved to another VPC.
+	obj.Spec.VPCName = "vpc2"
+	assert.NoError(t, service.CreateOrUpdateEgressIP(obj, []string{"10.0.0.3"}))
+	assert.Equal(t, "192.168.0.2", obj.Status.EgressIP)
+	assert.Equal(t, "/orgs/default/projects/p1/vpcs/vpc2/groups/egressip_egressip-uid-1", obj.Status.GroupPath)
+	assert.Len(t, groupsClient.groups, 1)
+	assert.Len(t, allocationsClient.allocations, 1)
+	assert.Len(t, rulesClient.rules, 1)
+
+	// The SNAT rule of the rejected EgressIP is released.
+	assert.NoError(t, service.ReleaseNATRule(obj))
+	assert.Empty(t, obj.Status.NATRulePath)
+	assert.Empty(t, rulesClient.rules)
+
+	// All the NSX resources are removed.
+	assert.NoError(t, service.Cleanup(context.TODO()))
+	assert.Empty(t, rulesClient.rules)
+	assert.Empty(t, groupsClient.groups)
+	assert.Empty(t, allocationsClient.allocations)
+	assert.Empty(t, service.ListEgressIPID())
+}
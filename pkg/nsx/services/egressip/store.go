@@ -0,0 +1,162 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package egressip
+
+import (
+	"errors"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// keyFunc is used to get the key of a resource, usually, which is the ID of the resource
+func keyFunc(obj interface{}) (string, error) {
+	switch v := obj.(type) {
+	case *model.PolicyVpcNatRule:
+		return *v.Id, nil
+	case *model.VpcIpAddressAllocation:
+		return *v.Id, nil
+	case *model.Group:
+		return *v.Id, nil
+	default:
+		return "", errors.New("keyFunc doesn't support unknown type")
+	}
+}
+
+func filterTag(tags []model.Tag, tagScope string) []string {
+	var res []string
+	for _, tag := range tags {
+		if *tag.Scope == tagScope {
+			res = append(res, *tag.Tag)
+		}
+	}
+	return res
+}
+
+// indexFunc is used to filter out NSX resources which are tagged with EgressIP CR UID.
+func indexFunc(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.PolicyVpcNatRule:
+		return filterTag(o.Tags, common.TagScopeEgressIPCRUID), nil
+	case *model.VpcIpAddressAllocation:
+		return filterTag(o.Tags, common.TagScopeEgressIPCRUID), nil
+	case *model.Group:
+		return filterTag(o.Tags, common.TagScopeEgressIPCRUID), nil
+	default:
+		return nil, errors.New("indexFunc doesn't support unknown type")
+	}
+}
+
+// NATRuleStore is a store for the SNAT rules of EgressIP.
+type NATRuleStore struct {
+	common.ResourceStore
+}
+
+func (natRuleStore *NATRuleStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	rule := i.(*model.PolicyVpcNatRule)
+	if rule.MarkedForDelete != nil && *rule.MarkedForDelete {
+		if err := natRuleStore.Delete(rule); err != nil {
+			return err
+		}
+		log.V(1).Info("NAT rule deleted from store", "rule", rule)
+	} else {
+		if err := natRuleStore.Add(rule); err != nil {
+			return err
+		}
+		log.V(1).Info("NAT rule added to store", "rule", rule)
+	}
+	return nil
+}
+
+func (natRuleStore *NATRuleStore) GetByIndex(key string, value string) []*model.PolicyVpcNatRule {
+	rules := make([]*model.PolicyVpcNatRule, 0)
+	for _, obj := range natRuleStore.ResourceStore.GetByIndex(key, value) {
+		rules = append(rules, obj.(*model.PolicyVpcNatRule))
+	}
+	return rules
+}
+
+func (natRuleStore *NATRuleStore) GetByKey(key string) *model.PolicyVpcNatRule {
+	obj := natRuleStore.ResourceStore.GetByKey(key)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*model.PolicyVpcNatRule)
+}
+
+// IPAddressAllocationStore is a store for the egress IPs allocated from the VPC external IP blocks.
+type IPAddressAllocationStore struct {
+	common.ResourceStore
+}
+
+func (allocationStore *IPAddressAllocationStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	allocation := i.(*model.VpcIpAddressAllocation)
+	if allocation.MarkedForDelete != nil && *allocation.MarkedForDelete {
+		if err := allocationStore.Delete(allocation); err != nil {
+			return err
+		}
+		log.V(1).Info("IP address allocation deleted from store", "allocation", allocation)
+	} else {
+		if err := allocationStore.Add(allocation); err != nil {
+			return err
+		}
+		log.V(1).Info("IP address allocation added to store", "allocation", allocation)
+	}
+	return nil
+}
+
+func (allocationStore *IPAddressAllocationStore) GetByIndex(key string, value string) []*model.VpcIpAddressAllocation {
+	allocations := make([]*model.VpcIpAddressAllocation, 0)
+	for _, obj := range allocationStore.ResourceStore.GetByIndex(key, value) {
+		allocations = append(allocations, obj.(*model.VpcIpAddressAllocation))
+	}
+	return allocations
+}
+
+// GroupStore is a store for the groups of the Pods selected by EgressIP.
+type GroupStore struct {
+	common.ResourceStore
+}
+
+func (groupStore *GroupStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	group := i.(*model.Group)
+	if group.MarkedForDelete != nil && *group.MarkedForDelete {
+		if err := groupStore.Delete(group); err != nil {
+			return err
+		}
+		log.V(1).Info("group deleted from store", "group", group)
+	} else {
+		if err := groupStore.Add(group); err != nil {
+			return err
+		}
+		log.V(1).Info("group added to store", "group", group)
+	}
+	return nil
+}
+
+func (groupStore *GroupStore) GetByIndex(key string, value string) []*model.Group {
+	groups := make([]*model.Group, 0)
+	for _, obj := range groupStore.ResourceStore.GetByIndex(key, value) {
+		groups = append(groups, obj.(*model.Group))
+	}
+	return groups
+}
+
+func (groupStore *GroupStore) GetByKey(key string) *model.Group {
+	obj := groupStore.ResourceStore.GetByKey(key)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*model.Group)
+}
@@ -0,0 +1,89 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package ipaddressallocation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha2"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/ippool"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+var String = common.String
+
+const (
+	IPADDRESSALLOCATIONPREFIX = "ipa"
+)
+
+func getCluster(service *IPAddressAllocationService) string {
+	return service.NSXConfig.Cluster
+}
+
+func (service *IPAddressAllocationService) buildIPAddressAllocationID(obj *v1alpha1.IPAddressAllocation, index int) string {
+	return util.GenerateID(string(obj.UID), IPADDRESSALLOCATIONPREFIX, "", strconv.Itoa(index))
+}
+
+func (service *IPAddressAllocationService) buildIPAddressAllocationName(obj *v1alpha1.IPAddressAllocation, index int) string {
+	return util.GenerateTruncName(common.MaxNameLength, obj.Name, IPADDRESSALLOCATIONPREFIX, strconv.Itoa(index), "", getCluster(service))
+}
+
+// getIPAddressBlockVisibility converts the visibility of IPAddressAllocation CR to the one of NSX.
+func getIPAddressBlockVisibility(visibility v1alpha1.IPAddressVisibility) string {
+	if visibility == v1alpha1.IPAddressVisibilityExternal {
+		return model.VpcIpAddressAllocation_IP_ADDRESS_BLOCK_VISIBILITY_EXTERNAL
+	}
+	return model.VpcIpAddressAllocation_IP_ADDRESS_BLOCK_VISIBILITY_PRIVATE
+}
+
+func (service *IPAddressAllocationService) buildIPAddressAllocation(obj *v1alpha1.IPAddressAllocation, index int, visibility string) *model.VpcIpAddressAllocation {
+	return &model.VpcIpAddressAllocation{
+		Id:                       String(service.buildIPAddressAllocationID(obj, index)),
+		DisplayName:              String(service.buildIPAddressAllocationName(obj, index)),
+		Tags:                     util.BuildBasicTags(getCluster(service), obj, ""),
+		IpAddressBlockVisibility: String(visibility),
+	}
+}
+
+// buildIPPoolAllocation builds the IP allocation in the NSX IP pool without the IP, so that NSX allocates it.
+func (service *IPAddressAllocationService) buildIPPoolAllocation(obj *v1alpha1.IPAddressAllocation, index int) *model.IpAddressAllocation {
+	return &model.IpAddressAllocation{
+		Id:          String(service.buildIPAddressAllocationID(obj, index)),
+		DisplayName: String(service.buildIPAddressAllocationName(obj, index)),
+		Tags:        util.BuildBasicTags(getCluster(service), obj, ""),
+	}
+}
+
+// buildIPPoolID returns the ID of the NSX IP pool created for the IPPool CR.
+func buildIPPoolID(ipPool *v1alpha2.IPPool) string {
+	return util.GenerateID(string(ipPool.UID), ippool.IPPOOLPREFIX, "", "")
+}
+
+// buildIPPoolPath returns the policy path of the NSX IP pool of the IPPool CR, the public IPPool is realized in
+// the NSX infra and the private IPPool is realized in the NSX project.
+func buildIPPoolPath(ipPool *v1alpha2.IPPool, vpcInfo common.VPCResourceInfo) string {
+	if ipPool.Spec.Type == common.IPPoolTypePublic {
+		return fmt.Sprintf("/infra/ip-pools/%s", buildIPPoolID(ipPool))
+	}
+	return fmt.Sprintf("/orgs/%s/projects/%s/infra/ip-pools/%s", vpcInfo.OrgID, vpcInfo.ProjectID, buildIPPoolID(ipPool))
+}
+
+// parseIPPoolPath returns the org, the project and the ID of the NSX IP pool from its policy path, the org and the
+// project are empty for the IP pool in the NSX infra.
+func parseIPPoolPath(path string) (string, string, string, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) == 3 && parts[0] == "infra" && parts[1] == "ip-pools":
+		return "", "", parts[2], nil
+	case len(parts) == 7 && parts[0] == "orgs" && parts[2] == "projects" && parts[4] == "infra" && parts[5] == "ip-pools":
+		return parts[1], parts[3], parts[6], nil
+	}
+	return "", "", "", fmt.Errorf("invalid NSX IP pool path %s", path)
+}
@@ -0,0 +1,318 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package ipaddressallocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha2"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+var (
+	log             = logger.Log
+	MarkedForDelete = true
+)
+
+type IPAddressAllocationService struct {
+	common.Service
+	IPAddressAllocationStore *IPAddressAllocationStore
+	IPPoolAllocationStore    *IPPoolAllocationStore
+	VPCService               common.VPCServiceProvider
+}
+
+// InitializeIPAddressAllocation sync NSX resources
+func InitializeIPAddressAllocation(service common.Service, vpcService common.VPCServiceProvider) (*IPAddressAllocationService, error) {
+	wg := sync.WaitGroup{}
+	wgDone := make(chan bool)
+	fatalErrors := make(chan error)
+
+	wg.Add(2)
+	allocationService := &IPAddressAllocationService{
+		Service:    service,
+		VPCService: vpcService,
+		IPAddressAllocationStore: &IPAddressAllocationStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeIPAddressAllocationCRUID: indexFunc}),
+			BindingType: model.VpcIpAddressAllocationBindingType(),
+		}},
+		IPPoolAllocationStore: &IPPoolAllocationStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeIPAddressAllocationCRUID: indexFunc}),
+			BindingType: model.IpAddressAllocationBindingType(),
+		}},
+	}
+	tags := []model.Tag{
+		{Scope: String(common.TagScopeIPAddressAllocationCRUID)},
+	}
+	go allocationService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeIPAddressAllocation, tags, allocationService.IPAddressAllocationStore)
+	go allocationService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeIPPoolAllocation, tags, allocationService.IPPoolAllocationStore)
+
+	go func() {
+		wg.Wait()
+		close(wgDone)
+	}()
+	select {
+	case <-wgDone:
+		break
+	case err := <-fatalErrors:
+		close(fatalErrors)
+		return allocationService, err
+	}
+	return allocationService, nil
+}
+
+// CreateOrUpdateIPAddressAllocation allocates the IPs of the IPAddressAllocation CR, one NSX IP allocation for each
+// IP, and returns the allocated IPs. If ipPool is not nil, NSX allocates the IPs from the NSX IP pool of the IPPool,
+// otherwise NSX allocates the IPs from the VPC IP blocks of the visibility.
+func (service *IPAddressAllocationService) CreateOrUpdateIPAddressAllocation(obj *v1alpha1.IPAddressAllocation, ipPool *v1alpha2.IPPool) ([]string, error) {
+	vpcInfo := service.VPCService.ListVPCInfo(obj.Namespace)
+	if len(vpcInfo) == 0 {
+		return nil, fmt.Errorf("no VPC found for namespace %s", obj.Namespace)
+	}
+	size := obj.Spec.AllocationSize
+	if size == 0 {
+		size = 1
+	}
+	if ipPool != nil {
+		// The IPs allocated from the VPC IP blocks are released once the IPPool is set.
+		for _, allocation := range service.IPAddressAllocationStore.GetByIndex(common.TagScopeIPAddressAllocationCRUID, string(obj.UID)) {
+			if err := service.deleteIPAddressAllocation(allocation); err != nil {
+				return nil, err
+			}
+		}
+		return service.createOrUpdateIPPoolAllocation(obj, ipPool, size, vpcInfo[0])
+	}
+	for _, allocation := range service.IPPoolAllocationStore.GetByIndex(common.TagScopeIPAddressAllocationCRUID, string(obj.UID)) {
+		if err := service.deleteIPPoolAllocation(allocation); err != nil {
+			return nil, err
+		}
+	}
+
+	visibility := getIPAddressBlockVisibility(obj.Spec.IPAddressBlockVisibility)
+	existingAllocations := map[string]*model.VpcIpAddressAllocation{}
+	for _, allocation := range service.IPAddressAllocationStore.GetByIndex(common.TagScopeIPAddressAllocationCRUID, string(obj.UID)) {
+		existingAllocations[*allocation.Id] = allocation
+	}
+	var allocationIPs []string
+	for index := 0; index < size; index++ {
+		id := service.buildIPAddressAllocationID(obj, index)
+		existing, ok := existingAllocations[id]
+		delete(existingAllocations, id)
+		if ok && existing.AllocationIp != nil && existing.IpAddressBlockVisibility != nil && *existing.IpAddressBlockVisibility == visibility {
+			allocationIPs = append(allocationIPs, *existing.AllocationIp)
+			continue
+		}
+		if ok {
+			// The visibility is changed, the IP needs to be re-allocated.
+			if err := service.deleteIPAddressAllocation(existing); err != nil {
+				return nil, err
+			}
+		}
+		allocationIP, err := service.allocateIP(obj, index, visibility, vpcInfo[0])
+		if err != nil {
+			return nil, err
+		}
+		allocationIPs = append(allocationIPs, allocationIP)
+	}
+	// Release the IPs exceeding the allocation size.
+	for _, stale := range existingAllocations {
+		if err := service.deleteIPAddressAllocation(stale); err != nil {
+			return nil, err
+		}
+	}
+	return allocationIPs, nil
+}
+
+func (service *IPAddressAllocationService) allocateIP(obj *v1alpha1.IPAddressAllocation, index int, visibility string, vpcInfo common.VPCResourceInfo) (string, error) {
+	nsxAllocation := service.buildIPAddressAllocation(obj, index, visibility)
+	client := service.NSXClient.IPAddressAllocationClient
+	if err := client.Patch(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *nsxAllocation.Id, *nsxAllocation); err != nil {
+		log.Error(err, "failed to create NSX IP address allocation", "id", *nsxAllocation.Id)
+		return "", err
+	}
+	// Get the allocation from NSX after patch operation as NSX renders the allocated IP.
+	allocation, err := client.Get(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *nsxAllocation.Id)
+	if err != nil {
+		return "", err
+	}
+	if err := service.IPAddressAllocationStore.Apply(&allocation); err != nil {
+		return "", err
+	}
+	if allocation.AllocationIp == nil || *allocation.AllocationIp == "" {
+		return "", fmt.Errorf("no IP allocated for NSX IP address allocation %s", *allocation.Id)
+	}
+	log.Info("allocated IP", "id", *allocation.Id, "ip", *allocation.AllocationIp)
+	return *allocation.AllocationIp, nil
+}
+
+// createOrUpdateIPPoolAllocation allocates the IPs from the NSX IP pool of the IPPool. The IPs are allocated by NSX,
+// so that an IP is never allocated twice across the allocations of the IP pool.
+func (service *IPAddressAllocationService) createOrUpdateIPPoolAllocation(obj *v1alpha1.IPAddressAllocation, ipPool *v1alpha2.IPPool, size int, vpcInfo common.VPCResourceInfo) ([]string, error) {
+	realized := false
+	for _, subnet := range ipPool.Status.Subnets {
+		if subnet.CIDR != "" {
+			realized = true
+			break
+		}
+	}
+	if !realized {
+		return nil, fmt.Errorf("no realized CIDR in IPPool %s", ipPool.Name)
+	}
+	poolPath := buildIPPoolPath(ipPool, vpcInfo)
+	existingAllocations := map[string]*model.IpAddressAllocation{}
+	for _, allocation := range service.IPPoolAllocationStore.GetByIndex(common.TagScopeIPAddressAllocationCRUID, string(obj.UID)) {
+		existingAllocations[*allocation.Id] = allocation
+	}
+	var allocationIPs []string
+	for index := 0; index < size; index++ {
+		id := service.buildIPAddressAllocationID(obj, index)
+		existing, ok := existingAllocations[id]
+		delete(existingAllocations, id)
+		if ok && existing.AllocationIp != nil && existing.ParentPath != nil && *existing.ParentPath == poolPath {
+			allocationIPs = append(allocationIPs, *existing.AllocationIp)
+			continue
+		}
+		if ok {
+			// The IPPool is changed, the IP needs to be re-allocated.
+			if err := service.deleteIPPoolAllocation(existing); err != nil {
+				return nil, err
+			}
+		}
+		allocationIP, err := service.allocatePoolIP(obj, index, ipPool, vpcInfo)
+		if err != nil {
+			return nil, err
+		}
+		allocationIPs = append(allocationIPs, allocationIP)
+	}
+	// Release the IPs exceeding the allocation size.
+	for _, stale := range existingAllocations {
+		if err := service.deleteIPPoolAllocation(stale); err != nil {
+			return nil, err
+		}
+	}
+	return allocationIPs, nil
+}
+
+func (service *IPAddressAllocationService) allocatePoolIP(obj *v1alpha1.IPAddressAllocation, index int, ipPool *v1alpha2.IPPool, vpcInfo common.VPCResourceInfo) (string, error) {
+	nsxAllocation := service.buildIPPoolAllocation(obj, index)
+	poolID := buildIPPoolID(ipPool)
+	var allocation model.IpAddressAllocation
+	var err error
+	// The public IPPool is realized in the NSX infra, the private IPPool is realized in the NSX project.
+	if ipPool.Spec.Type == common.IPPoolTypePublic {
+		client := service.NSXClient.InfraIPPoolAllocationClient
+		if err = client.Patch(poolID, *nsxAllocation.Id, *nsxAllocation); err == nil {
+			// Get the allocation from NSX after patch operation as NSX renders the allocated IP.
+			allocation, err = client.Get(poolID, *nsxAllocation.Id)
+		}
+	} else {
+		client := service.NSXClient.ProjectIPPoolAllocationClient
+		if err = client.Patch(vpcInfo.OrgID, vpcInfo.ProjectID, poolID, *nsxAllocation.Id, *nsxAllocation); err == nil {
+			allocation, err = client.Get(vpcInfo.OrgID, vpcInfo.ProjectID, poolID, *nsxAllocation.Id)
+		}
+	}
+	if err != nil {
+		log.Error(err, "failed to allocate IP from NSX IP pool", "id", *nsxAllocation.Id, "pool", poolID)
+		return "", err
+	}
+	if err := service.IPPoolAllocationStore.Apply(&allocation); err != nil {
+		return "", err
+	}
+	if allocation.AllocationIp == nil || *allocation.AllocationIp == "" {
+		return "", fmt.Errorf("no IP allocated for NSX IP pool allocation %s", *allocation.Id)
+	}
+	log.Info("allocated IP from IP pool", "id", *allocation.Id, "pool", poolID, "ip", *allocation.AllocationIp)
+	return *allocation.AllocationIp, nil
+}
+
+func (service *IPAddressAllocationService) deleteIPAddressAllocation(allocation *model.VpcIpAddressAllocation) error {
+	vpcInfo, err := common.ParseVPCResourcePath(*allocation.Path)
+	if err != nil {
+		return err
+	}
+	if err := service.NSXClient.IPAddressAllocationClient.Delete(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *allocation.Id); err != nil {
+		log.Error(err, "failed to delete NSX IP address allocation", "id", *allocation.Id)
+		return err
+	}
+	allocationCopy := *allocation
+	allocationCopy.MarkedForDelete = &MarkedForDelete
+	if err := service.IPAddressAllocationStore.Apply(&allocationCopy); err != nil {
+		return err
+	}
+	log.Info("released IP", "id", *allocation.Id, "ip", allocation.AllocationIp)
+	return nil
+}
+
+func (service *IPAddressAllocationService) deleteIPPoolAllocation(allocation *model.IpAddressAllocation) error {
+	if allocation.ParentPath == nil {
+		return fmt.Errorf("no IP pool path in NSX IP pool allocation %s", *allocation.Id)
+	}
+	org, project, poolID, err := parseIPPoolPath(*allocation.ParentPath)
+	if err != nil {
+		return err
+	}
+	if project == "" {
+		err = service.NSXClient.InfraIPPoolAllocationClient.Delete(poolID, *allocation.Id)
+	} else {
+		err = service.NSXClient.ProjectIPPoolAllocationClient.Delete(org, project, poolID, *allocation.Id)
+	}
+	if err != nil {
+		log.Error(err, "failed to delete NSX IP pool allocation", "id", *allocation.Id, "pool", poolID)
+		return err
+	}
+	allocationCopy := *allocation
+	allocationCopy.MarkedForDelete = &MarkedForDelete
+	if err := service.IPPoolAllocationStore.Apply(&allocationCopy); err != nil {
+		return err
+	}
+	log.Info("released IP from IP pool", "id", *allocation.Id, "pool", poolID, "ip", allocation.AllocationIp)
+	return nil
+}
+
+// DeleteIPAddressAllocation releases all the IPs of the IPAddressAllocation CR.
+func (service *IPAddressAllocationService) DeleteIPAddressAllocation(uid types.UID) error {
+	for _, allocation := range service.IPAddressAllocationStore.GetByIndex(common.TagScopeIPAddressAllocationCRUID, string(uid)) {
+		if err := service.deleteIPAddressAllocation(allocation); err != nil {
+			return err
+		}
+	}
+	for _, allocation := range service.IPPoolAllocationStore.GetByIndex(common.TagScopeIPAddressAllocationCRUID, string(uid)) {
+		if err := service.deleteIPPoolAllocation(allocation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListIPAddressAllocationID returns the UIDs of the IPAddressAllocation CRs which have NSX IP address allocations.
+func (service *IPAddressAllocationService) ListIPAddressAllocationID() sets.Set[string] {
+	return service.IPAddressAllocationStore.ListIndexFuncValues(common.TagScopeIPAddressAllocationCRUID).Union(
+		service.IPPoolAllocationStore.ListIndexFuncValues(common.TagScopeIPAddressAllocationCRUID))
+}
+
+func (service *IPAddressAllocationService) Cleanup(ctx context.Context) error {
+	uids := service.ListIPAddressAllocationID()
+	log.Info("cleaning up ipaddressallocation", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			if err := service.DeleteIPAddressAllocation(types.UID(uid)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package ipaddressallocation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	infra_ip_pools "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/infra/ip_pools"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	project_ip_pools "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/infra/ip_pools"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha2"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+type fakeVPCService struct {
+	common.VPCServiceProvider
+}
+
+func (f *fakeVPCService) ListVPCInfo(_ string) []common.VPCResourceInfo {
+	return []common.VPCResourceInfo{{OrgID: "default", ProjectID: "p1", VPCID: "v1"}}
+}
+
+// fakeIPAddressAllocationsClient renders the IPs from 10.0.0.0/24 for the allocations without the IP.
+type fakeIPAddressAllocationsClient struct {
+	vpcs.IpAddressAllocationsClient
+	allocations map[string]model.VpcIpAddressAllocation
+	next        int
+}
+
+func (f *fakeIPAddressAllocationsClient) Patch(orgId string, projectId string, vpcId string, ipAddressAllocationId string, allocation model.VpcIpAddressAllocation) error {
+	if allocation.AllocationIp == nil {
+		f.next++
+		allocation.AllocationIp = String(fmt.Sprintf("10.0.0.%d", f.next))
+	}
+	allocation.Path = String(fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/ip-address-allocations/%s", orgId, projectId, vpcId, ipAddressAllocationId))
+	f.allocations[ipAddressAllocationId] = allocation
+	return nil
+}
+
+func (f *fakeIPAddressAllocationsClient) Get(_ string, _ string, _ string, ipAddressAllocationId string) (model.VpcIpAddressAllocation, error) {
+	return f.allocations[ipAddressAllocationId], nil
+}
+
+func (f *fakeIPAddressAllocationsClient) Delete(_ string, _ string, _ string, ipAddressAllocationId string) error {
+	delete(f.allocations, ipAddressAllocationId)
+	return nil
+}
+
+// fakeIPPoolAllocations renders the IPs from 172.16.0.0/24 for the allocations in the IP pools, the allocations
+// are keyed by the IP pool path and the allocation ID.
+type fakeIPPoolAllocations struct {
+	allocations map[string]model.IpAddressAllocation
+	next        int
+}
+
+func (f *fakeIPPoolAllocations) patch(poolPath string, id string, allocation model.IpAddressAllocation) {
+	f.next++
+	allocation.AllocationIp = String(fmt.Sprintf("172.16.0.%d", f.next))
+	allocation.ParentPath = String(poolPath)
+	f.allocations[poolPath+"/"+id] = allocation
+}
+
+type fakeProjectIPPoolAllocationsClient struct {
+	project_ip_pools.IpAllocationsClient
+	*fakeIPPoolAllocations
+}
+
+func projectPoolPath(orgId, projectId, poolId string) string {
+	return fmt.Sprintf("/orgs/%s/projects/%s/infra/ip-pools/%s", orgId, projectId, poolId)
+}
+
+func (f *fakeProjectIPPoolAllocationsClient) Patch(orgId string, projectId string, poolId string, id string, allocation model.IpAddressAllocation) error {
+	f.patch(projectPoolPath(orgId, projectId, poolId), id, allocation)
+	return nil
+}
+
+func (f *fakeProjectIPPoolAllocationsClient) Get(orgId string, projectId string, poolId string, id string) (model.IpAddressAllocation, error) {
+	return f.allocations[projectPoolPath(orgId, projectId, poolId)+"/"+id], nil
+}
+
+func (f *fakeProjectIPPoolAllocationsClient) Delete(orgId string, projectId string, poolId string, id string) error {
+	delete(f.allocations, projectPoolPath(orgId, projectId, poolId)+"/"+id)
+	return nil
+}
+
+type fakeInfraIPPoolAllocationsClient struct {
+	infra_ip_pools.IpAllocationsClient
+	*fakeIPPoolAllocations
+}
+
+func (f *fakeInfraIPPoolAllocationsClient) Patch(poolId string, id string, allocation model.IpAddressAllocation) error {
+	f.patch("/infra/ip-pools/"+poolId, id, allocation)
+	return nil
+}
+
+func (f *fakeInfraIPPoolAllocationsClient) Get(poolId string, id string) (model.IpAddressAllocation, error) {
+	return f.allocations["/infra/ip-pools/"+poolId+"/"+id], nil
+}
+
+func (f *fakeInfraIPPoolAllocationsClient) Delete(poolId string, id string) error {
+	delete(f.allocations, "/infra/ip-pools/"+poolId+"/"+id)
+	return nil
+}
+
+func createService() (*IPAddressAllocationService, *fakeIPAddressAllocationsClient, *fakeIPPoolAllocations) {
+	fakeClient := &fakeIPAddressAllocationsClient{allocations: map[string]model.VpcIpAddressAllocation{}}
+	fakePools := &fakeIPPoolAllocations{allocations: map[string]model.IpAddressAllocation{}}
+	service := &IPAddressAllocationService{
+		Service: common.Service{
+			NSXClient: &nsx.Client{
+				IPAddressAllocationClient:     fakeClient,
+				ProjectIPPoolAllocationClient: &fakeProjectIPPoolAllocationsClient{fakeIPPoolAllocations: fakePools},
+				InfraIPPoolAllocationClient:   &fakeInfraIPPoolAllocationsClient{fakeIPPoolAllocations: fakePools},
+			},
+			NSXConfig: &config.NSXOperatorConfig{CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test"}},
+		},
+		VPCService: &fakeVPCService{},
+		IPAddressAllocationStore: &IPAddressAllocationStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeIPAddressAllocationCRUID: indexFunc}),
+			BindingType: model.VpcIpAddressAllocationBindingType(),
+		}},
+		IPPoolAllocationStore: &IPPoolAllocationStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeIPAddressAllocationCRUID: indexFunc}),
+			BindingType: model.IpAddressAllocationBindingType(),
+		}},
+	}
+	return service, fakeClient, fakePools
+}
+
+func TestParseIPPoolPath(t *testing.T) {
+	org, project, poolID, err := parseIPPoolPath("/infra/ip-pools/pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "", "pool1"}, []string{org, project, poolID})
+
+	org, project, poolID, err = parseIPPoolPath("/orgs/default/projects/p1/infra/ip-pools/pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "p1", "pool1"}, []string{org, project, poolID})
+
+	_, _, _, err = parseIPPoolPath("/orgs/default/projects/p1/vpcs/v1")
+	assert.Error(t, err)
+}
+
+func TestCreateOrUpdateIPAddressAllocation(t *testing.T) {
+	service, fakeClient, fakePools := createService()
+	obj := &v1alpha1.IPAddressAllocation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "ipa1", UID: "ipa-uid-1"},
+		Spec:       v1alpha1.IPAddressAllocationSpec{AllocationSize: 2},
+	}
+
+	// NSX allocates the IPs from the VPC private IP blocks.
+	ips, err := service.CreateOrUpdateIPAddressAllocation(obj, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, ips)
+	assert.Len(t, fakeClient.allocations, 2)
+	for _, allocation := range fakeClient.allocations {
+		assert.Equal(t, model.VpcIpAddressAllocation_IP_ADDRESS_BLOCK_VISIBILITY_PRIVATE, *allocation.IpAddressBlockVisibility)
+	}
+
+	// The existing allocations are kept.
+	ips, err = service.CreateOrUpdateIPAddressAllocation(obj, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, ips)
+
+	// The IPs exceeding the allocation size are released.
+	obj.Spec.AllocationSize = 1
+	ips, err = service.CreateOrUpdateIPAddressAllocation(obj, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1"}, ips)
+	assert.Len(t, fakeClient.allocations, 1)
+	assert.Len(t, service.IPAddressAllocationStore.GetByIndex(common.TagScopeIPAddressAllocationCRUID, "ipa-uid-1"), 1)
+
+	// The IPs are re-allocated by NSX from the IP pool of the public IPPool.
+	ipPool := &v1alpha2.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pool1", UID: "pool-uid-1"},
+		Spec:       v1alpha2.IPPoolSpec{Type: common.IPPoolTypePublic},
+		Status:     v1alpha2.IPPoolStatus{Subnets: []v1alpha2.SubnetResult{{CIDR: "172.16.0.0/24", Name: "subnet1"}}},
+	}
+	ips, err = service.CreateOrUpdateIPAddressAllocation(obj, ipPool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"172.16.0.1"}, ips)
+	assert.Empty(t, fakeClient.allocations)
+	assert.Contains(t, fakePools.allocations, "/infra/ip-pools/"+buildIPPoolID(ipPool)+"/"+service.buildIPAddressAllocationID(obj, 0))
+
+	// The IP allocated from the IPPool is not allocated twice, and the existing allocation is kept.
+	obj2 := &v1alpha1.IPAddressAllocation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "ipa2", UID: "ipa-uid-2"},
+		Spec:       v1alpha1.IPAddressAllocationSpec{IPPool: "pool1", AllocationSize: 1},
+	}
+	ips, err = service.CreateOrUpdateIPAddressAllocation(obj2, ipPool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"172.16.0.2"}, ips)
+	ips, err = service.CreateOrUpdateIPAddressAllocation(obj2, ipPool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"172.16.0.2"}, ips)
+	assert.Equal(t, sets.New[string]("ipa-uid-1", "ipa-uid-2"), service.ListIPAddressAllocationID())
+
+	// The IP is re-allocated from the IP pool of the private IPPool in the project.
+	privatePool := &v1alpha2.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pool2", UID: "pool-uid-2"},
+		Spec:       v1alpha2.IPPoolSpec{Type: common.IPPoolTypePrivate},
+		Status:     v1alpha2.IPPoolStatus{Subnets: []v1alpha2.SubnetResult{{CIDR: "172.16.0.0/24", Name: "subnet1"}}},
+	}
+	ips, err = service.CreateOrUpdateIPAddressAllocation(obj2, privatePool)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"172.16.0.3"}, ips)
+	assert.Len(t, fakePools.allocations, 2)
+	assert.Contains(t, fakePools.allocations, "/orgs/default/projects/p1/infra/ip-pools/"+buildIPPoolID(privatePool)+"/"+service.buildIPAddressAllocationID(obj2, 0))
+
+	// The IPPool without realized CIDRs.
+	_, err = service.CreateOrUpdateIPAddressAllocation(obj2, &v1alpha2.IPPool{ObjectMeta: metav1.ObjectMeta{Name: "pool3"}})
+	assert.Error(t, err)
+
+	assert.NoError(t, service.DeleteIPAddressAllocation(obj.UID))
+	assert.Equal(t, sets.New[string]("ipa-uid-2"), service.ListIPAddressAllocationID())
+	assert.NoError(t, service.Cleanup(context.TODO()))
+	assert.Empty(t, fakeClient.allocations)
+	assert.Empty(t, fakePools.allocations)
+	assert.Empty(t, service.ListIPAddressAllocationID())
+}
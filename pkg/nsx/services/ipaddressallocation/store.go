@@ -0,0 +1,118 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package ipaddressallocation
+
+import (
+	"errors"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// keyFunc is used to get the key of a resource, usually, which is the ID of the resource
+func keyFunc(obj interface{}) (string, error) {
+	switch v := obj.(type) {
+	case *model.VpcIpAddressAllocation:
+		return *v.Id, nil
+	case *model.IpAddressAllocation:
+		return *v.Id, nil
+	default:
+		return "", errors.New("keyFunc doesn't support unknown type")
+	}
+}
+
+func filterTag(tags []model.Tag, tagScope string) []string {
+	var res []string
+	for _, tag := range tags {
+		if *tag.Scope == tagScope {
+			res = append(res, *tag.Tag)
+		}
+	}
+	return res
+}
+
+// indexFunc is used to filter out NSX IP address allocations which are tagged with CR UID.
+func indexFunc(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.VpcIpAddressAllocation:
+		return filterTag(o.Tags, common.TagScopeIPAddressAllocationCRUID), nil
+	case *model.IpAddressAllocation:
+		return filterTag(o.Tags, common.TagScopeIPAddressAllocationCRUID), nil
+	default:
+		return nil, errors.New("indexFunc doesn't support unknown type")
+	}
+}
+
+// IPAddressAllocationStore is a store for VPC IP address allocation.
+type IPAddressAllocationStore struct {
+	common.ResourceStore
+}
+
+func (allocationStore *IPAddressAllocationStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	allocation := i.(*model.VpcIpAddressAllocation)
+	if allocation.MarkedForDelete != nil && *allocation.MarkedForDelete {
+		if err := allocationStore.Delete(allocation); err != nil {
+			return err
+		}
+		log.V(1).Info("IP address allocation deleted from store", "allocation", allocation)
+	} else {
+		if err := allocationStore.Add(allocation); err != nil {
+			return err
+		}
+		log.V(1).Info("IP address allocation added to store", "allocation", allocation)
+	}
+	return nil
+}
+
+func (allocationStore *IPAddressAllocationStore) GetByIndex(key string, value string) []*model.VpcIpAddressAllocation {
+	allocations := make([]*model.VpcIpAddressAllocation, 0)
+	for _, obj := range allocationStore.ResourceStore.GetByIndex(key, value) {
+		allocations = append(allocations, obj.(*model.VpcIpAddressAllocation))
+	}
+	return allocations
+}
+
+func (allocationStore *IPAddressAllocationStore) GetByKey(key string) *model.VpcIpAddressAllocation {
+	obj := allocationStore.ResourceStore.GetByKey(key)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*model.VpcIpAddressAllocation)
+}
+
+// IPPoolAllocationStore is a store for the IP allocations in the NSX IP pools of the IPPool CRs.
+type IPPoolAllocationStore struct {
+	common.ResourceStore
+}
+
+func (allocationStore *IPPoolAllocationStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	allocation := i.(*model.IpAddressAllocation)
+	if allocation.MarkedForDelete != nil && *allocation.MarkedForDelete {
+		if err := allocationStore.Delete(allocation); err != nil {
+			return err
+		}
+		log.V(1).Info("IP pool allocation deleted from store", "allocation", allocation)
+	} else {
+		if err := allocationStore.Add(allocation); err != nil {
+			return err
+		}
+		log.V(1).Info("IP pool allocation added to store", "allocation", allocation)
+	}
+	return nil
+}
+
+func (allocationStore *IPPoolAllocationStore) GetByIndex(key string, value string) []*model.IpAddressAllocation {
+	allocations := make([]*model.IpAddressAllocation, 0)
+	for _, obj := range allocationStore.ResourceStore.GetByIndex(key, value) {
+		allocations = append(allocations, obj.(*model.IpAddressAllocation))
+	}
+	return allocations
+}
@@ -74,11 +74,11 @@ func (service *IPPoolService) buildIPSubnetTags(IPPool *v1alpha2.IPPool, subnetR
 
 func (service *IPPoolService) buildIPSubnetIntentPath(IPPool *v1alpha2.IPPool, subnetRequest *v1alpha2.SubnetRequest) string {
 	if IPPool.Spec.Type == common.IPPoolTypePrivate {
-		VPCInfo := service.VPCService.ListVPCInfo(IPPool.Namespace)
-		if len(VPCInfo) == 0 {
+		VPCInfo, found := service.VPCService.GetVPCInfo(IPPool.Namespace, IPPool.Spec.VPCName)
+		if !found {
 			return ""
 		}
-		return strings.Join([]string{fmt.Sprintf("/orgs/%s/projects/%s/infra/ip-pools", VPCInfo[0].OrgID, VPCInfo[0].ProjectID),
+		return strings.Join([]string{fmt.Sprintf("/orgs/%s/projects/%s/infra/ip-pools", VPCInfo.OrgID, VPCInfo.ProjectID),
 			service.buildIPPoolID(IPPool),
 			"ip-subnets", service.buildIPSubnetID(IPPool, subnetRequest)}, "/")
 	} else {
@@ -89,16 +89,16 @@ func (service *IPPoolService) buildIPSubnetIntentPath(IPPool *v1alpha2.IPPool, s
 
 func (service *IPPoolService) buildIPSubnet(IPPool *v1alpha2.IPPool, subnetRequest v1alpha2.SubnetRequest) *model.IpAddressPoolBlockSubnet {
 	IpBlockPath := String("")
-	VPCInfo := service.VPCService.ListVPCInfo(IPPool.Namespace)
-	if len(VPCInfo) == 0 {
-		log.Error(nil, "failed to find VPCInfo for IPPool CR", "IPPool", IPPool.Name, "namespace", IPPool.Namespace)
+	VPCInfo, found := service.VPCService.GetVPCInfo(IPPool.Namespace, IPPool.Spec.VPCName)
+	if !found {
+		log.Error(nil, "failed to find VPCInfo for IPPool CR", "IPPool", IPPool.Name, "namespace", IPPool.Namespace, "VPC", IPPool.Spec.VPCName)
 		return nil
 	}
 	var IpBlockPathList []string
 	if IPPool.Spec.Type == common.IPPoolTypePrivate {
-		IpBlockPathList = VPCInfo[0].PrivateIpv4Blocks
+		IpBlockPathList = VPCInfo.PrivateIpv4Blocks
 	} else {
-		IpBlockPathList = VPCInfo[0].ExternalIPv4Blocks
+		IpBlockPathList = VPCInfo.ExternalIPv4Blocks
 	}
 	for _, ipBlockPath := range IpBlockPathList {
 		if util.Contains(service.ExhaustedIPBlock, ipBlockPath) {
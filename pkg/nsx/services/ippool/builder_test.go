@@ -81,6 +81,9 @@ func TestIPPoolService_BuildIPPool(t *testing.T) {
 	) []common.VPCResourceInfo {
 		return vpcinfolist
 	})
+	patch.ApplyMethod(reflect.TypeOf(ipPoolService.VPCService), "GetVPCInfo", func(_ *vpc.VPCService, ns string, vpcName string) (common.VPCResourceInfo, bool) {
+		return vpcinfolist[0], true
+	})
 	defer patch.Reset()
 
 	type args struct {
@@ -226,13 +226,13 @@ func (service *IPPoolService) acquireCidr(obj *v1alpha2.IPPool, subnetRequest *v
 	if intentPath == "" {
 		return "", fmt.Errorf("failed to build intent path for ip pool %s, subnetRequest %s", obj.Name, subnetRequest.Name)
 	}
-	VPCInfo := service.VPCService.ListVPCInfo(obj.Namespace)
+	VPCInfo, found := service.VPCService.GetVPCInfo(obj.Namespace, obj.Spec.VPCName)
 	var err error
-	if len(VPCInfo) == 0 {
+	if !found {
 		err = util.NoEffectiveOption{Desc: "no effective org and project for ippool"}
 		return "", err
 	}
-	m, err := service.NSXClient.RealizedEntitiesClient.List(VPCInfo[0].OrgID, VPCInfo[0].ProjectID, intentPath, nil)
+	m, err := service.NSXClient.RealizedEntitiesClient.List(VPCInfo.OrgID, VPCInfo.ProjectID, intentPath, nil)
 	if err != nil {
 		return "", err
 	}
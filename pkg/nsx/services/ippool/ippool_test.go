@@ -52,6 +52,9 @@ func TestIPPoolService_acquireCidr(t *testing.T) {
 		id := "vpc-1"
 		return []common.VPCResourceInfo{{OrgID: "default", ProjectID: "project-1", VPCID: "vpc-1", ID: id}}
 	})
+	patches.ApplyMethod(reflect.TypeOf(ipPoolService.VPCService), "GetVPCInfo", func(_ *vpc.VPCService, ns string, vpcName string) (common.VPCResourceInfo, bool) {
+		return common.VPCResourceInfo{OrgID: "default", ProjectID: "project-1", VPCID: "vpc-1", ID: "vpc-1"}, true
+	})
 
 	defer patches.Reset()
 
@@ -120,6 +123,9 @@ func TestIPPoolService_AcquireRealizedSubnetIP(t *testing.T) {
 		id := "vpc-1"
 		return []common.VPCResourceInfo{{OrgID: "default", ProjectID: "project-1", VPCID: "vpc-1", ID: id}}
 	})
+	patches.ApplyMethod(reflect.TypeOf(ipPoolService.VPCService), "GetVPCInfo", func(_ *vpc.VPCService, ns string, vpcName string) (common.VPCResourceInfo, bool) {
+		return common.VPCResourceInfo{OrgID: "default", ProjectID: "project-1", VPCID: "vpc-1", ID: "vpc-1"}, true
+	})
 	defer patches.Reset()
 	ipPool2 := &v1alpha2.IPPool{
 		Spec: v1alpha2.IPPoolSpec{
@@ -166,6 +172,9 @@ func TestIPPoolService_CRUDResource(t *testing.T) {
 		id := "vpc-1"
 		return []common.VPCResourceInfo{{OrgID: "default", ProjectID: "project-1", VPCID: "vpc-1", ID: id}}
 	})
+	patches.ApplyMethod(reflect.TypeOf(service.VPCService), "GetVPCInfo", func(_ *vpc.VPCService, ns string, vpcName string) (common.VPCResourceInfo, bool) {
+		return common.VPCResourceInfo{OrgID: "default", ProjectID: "project-1", VPCID: "vpc-1", ID: "vpc-1"}, true
+	})
 	defer patches.Reset()
 	iap := &model.IpAddressPool{Id: String("1"), DisplayName: String("1"),
 		Tags: []model.Tag{{Scope: String(common.TagScopeIPPoolCRUID),
@@ -223,6 +232,9 @@ func TestIPPoolService_CreateOrUpdateIPPool(t *testing.T) {
 		ns string) []common.VPCResourceInfo {
 		return vpcinfolist
 	})
+	patch.ApplyMethod(reflect.TypeOf(service.VPCService), "GetVPCInfo", func(_ *vpc.VPCService, ns string, vpcName string) (common.VPCResourceInfo, bool) {
+		return vpcinfolist[0], true
+	})
 	defer patch.Reset()
 
 	p := &model.IpAddressPool{Id: String("1"), DisplayName: String("1"),
@@ -257,6 +269,9 @@ func TestIPPoolService_CreateOrUpdateIPPool(t *testing.T) {
 		ns string) []common.VPCResourceInfo {
 		return vpcinfo
 	})
+	patch.ApplyMethod(reflect.TypeOf(service.VPCService), "GetVPCInfo", func(_ *vpc.VPCService, ns string, vpcName string) (common.VPCResourceInfo, bool) {
+		return vpcinfo[0], true
+	})
 	defer patch.Reset()
 	t.Run("1", func(t *testing.T) {
 		got, got1, err := service.CreateOrUpdateIPPool(ipPool2)
@@ -0,0 +1,134 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package natrule
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+var String = common.String
+
+const NATRULEPREFIX = "natrule"
+
+func validateNetwork(network string) error {
+	if network == "" || net.ParseIP(network) != nil {
+		return nil
+	}
+	if _, _, err := net.ParseCIDR(network); err != nil {
+		return nsxutil.RestrictionError{Desc: fmt.Sprintf("invalid IP or CIDR %s", network)}
+	}
+	return nil
+}
+
+func validateNATRule(obj *v1alpha1.NATRule) error {
+	switch obj.Spec.Action {
+	case v1alpha1.NATActionSNAT:
+	case v1alpha1.NATActionDNAT:
+		if obj.Spec.TranslatedNetwork == "" {
+			return nsxutil.RestrictionError{Desc: "translatedNetwork is required for DNAT"}
+		}
+	case v1alpha1.NATActionReflexive:
+		if obj.Spec.SourceNetwork == "" {
+			return nsxutil.RestrictionError{Desc: "sourceNetwork is required for REFLEXIVE"}
+		}
+		if obj.Spec.DestinationNetwork != "" {
+			return nsxutil.RestrictionError{Desc: "destinationNetwork is not allowed for REFLEXIVE"}
+		}
+	default:
+		return nsxutil.RestrictionError{Desc: fmt.Sprintf("unsupported action %s", obj.Spec.Action)}
+	}
+	for _, network := range []string{obj.Spec.SourceNetwork, obj.Spec.DestinationNetwork, obj.Spec.TranslatedNetwork} {
+		if err := validateNetwork(network); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// needAllocatedIP returns true if the external IP of the NAT rule is not specified, which is the
+// translated network of SNAT/REFLEXIVE or the destination network of DNAT.
+func needAllocatedIP(obj *v1alpha1.NATRule) bool {
+	return getExternalNetwork(obj) == ""
+}
+
+// getExternalNetwork returns the external IP or CIDR specified in the NATRule CR, which is the translated network
+// of SNAT/REFLEXIVE or the destination network of DNAT.
+func getExternalNetwork(obj *v1alpha1.NATRule) string {
+	if obj.Spec.Action == v1alpha1.NATActionDNAT {
+		return obj.Spec.DestinationNetwork
+	}
+	return obj.Spec.TranslatedNetwork
+}
+
+// networkInCIDRs returns true if the IP or the CIDR is within any of the CIDRs.
+func networkInCIDRs(network string, cidrs []string) bool {
+	ip, ones := net.ParseIP(network), -1
+	if ip == nil {
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			return false
+		}
+		ip = ipNet.IP
+		ones, _ = ipNet.Mask.Size()
+	}
+	for _, cidr := range cidrs {
+		_, blockNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		blockOnes, _ := blockNet.Mask.Size()
+		if blockNet.Contains(ip) && (ones < 0 || ones >= blockOnes) {
+			return true
+		}
+	}
+	return false
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return String(value)
+}
+
+func (service *NATRuleService) buildNATRule(obj *v1alpha1.NATRule, vpcInfo common.VPCResourceInfo, allocatedIP string) *model.PolicyVpcNatRule {
+	id := util.GenerateID(string(obj.UID), NATRULEPREFIX, "", "")
+	rule := &model.PolicyVpcNatRule{
+		Id:                 String(id),
+		Path:               String(fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/nat/%s/nat-rules/%s", vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, common.UserNATID, id)),
+		DisplayName:        String(util.GenerateTruncName(common.MaxNameLength, obj.Name, NATRULEPREFIX, "", "", "")),
+		Action:             String(string(obj.Spec.Action)),
+		SourceNetwork:      optionalString(obj.Spec.SourceNetwork),
+		DestinationNetwork: optionalString(obj.Spec.DestinationNetwork),
+		TranslatedNetwork:  optionalString(obj.Spec.TranslatedNetwork),
+		SequenceNumber:     common.Int64(obj.Spec.SequenceNumber),
+		Enabled:            common.Bool(true),
+		Tags:               util.BuildBasicTags(service.NSXConfig.Cluster, obj, ""),
+	}
+	if allocatedIP != "" {
+		if obj.Spec.Action == v1alpha1.NATActionDNAT {
+			rule.DestinationNetwork = String(allocatedIP)
+		} else {
+			rule.TranslatedNetwork = String(allocatedIP)
+		}
+	}
+	return rule
+}
+
+func (service *NATRuleService) buildIPAddressAllocation(obj *v1alpha1.NATRule) *model.VpcIpAddressAllocation {
+	return &model.VpcIpAddressAllocation{
+		Id:                       String(util.GenerateID(string(obj.UID), NATRULEPREFIX, "", "")),
+		DisplayName:              String(util.GenerateTruncName(common.MaxNameLength, obj.Name, NATRULEPREFIX, "", "", "")),
+		Tags:                     util.BuildBasicTags(service.NSXConfig.Cluster, obj, ""),
+		IpAddressBlockVisibility: String(model.VpcIpAddressAllocation_IP_ADDRESS_BLOCK_VISIBILITY_EXTERNAL),
+	}
+}
@@ -0,0 +1,30 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package natrule
+
+import (
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+)
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// compareNATRule returns true if the NAT rules translate the same traffic in the same way.
+func compareNATRule(existing *model.PolicyVpcNatRule, expected *model.PolicyVpcNatRule) bool {
+	if stringValue(existing.Path) != stringValue(expected.Path) ||
+		stringValue(existing.Action) != stringValue(expected.Action) ||
+		stringValue(existing.SourceNetwork) != stringValue(expected.SourceNetwork) ||
+		stringValue(existing.DestinationNetwork) != stringValue(expected.DestinationNetwork) ||
+		stringValue(existing.TranslatedNetwork) != stringValue(expected.TranslatedNetwork) {
+		return false
+	}
+	if existing.SequenceNumber == nil || *existing.SequenceNumber != *expected.SequenceNumber {
+		return false
+	}
+	return true
+}
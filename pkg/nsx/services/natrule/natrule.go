@@ -0,0 +1,265 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package natrule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+var (
+	log             = logger.Log
+	MarkedForDelete = true
+)
+
+type NATRuleService struct {
+	common.Service
+	NATRuleStore             *NATRuleStore
+	IPAddressAllocationStore *IPAddressAllocationStore
+	VPCService               common.VPCServiceProvider
+}
+
+// InitializeNATRule sync NSX resources
+func InitializeNATRule(service common.Service, vpcService common.VPCServiceProvider) (*NATRuleService, error) {
+	wg := sync.WaitGroup{}
+	wgDone := make(chan bool)
+	fatalErrors := make(chan error)
+
+	wg.Add(2)
+	natRuleService := &NATRuleService{
+		Service:    service,
+		VPCService: vpcService,
+		NATRuleStore: &NATRuleStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeNATRuleCRUID: indexFunc}),
+			BindingType: model.PolicyVpcNatRuleBindingType(),
+		}},
+		IPAddressAllocationStore: &IPAddressAllocationStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeNATRuleCRUID: indexFunc}),
+			BindingType: model.VpcIpAddressAllocationBindingType(),
+		}},
+	}
+	tags := []model.Tag{
+		{Scope: String(common.TagScopeNATRuleCRUID)},
+	}
+	go natRuleService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeNATRule, tags, natRuleService.NATRuleStore)
+	go natRuleService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeIPAddressAllocation, tags, natRuleService.IPAddressAllocationStore)
+
+	go func() {
+		wg.Wait()
+		close(wgDone)
+	}()
+	select {
+	case <-wgDone:
+		break
+	case err := <-fatalErrors:
+		close(fatalErrors)
+		return natRuleService, err
+	}
+	return natRuleService, nil
+}
+
+// CreateOrUpdateNATRule creates or updates the NSX NAT rule of the NATRule CR in the user NAT section of
+// the VPC, and returns the NAT rule with the IP allocated from the VPC external IP blocks if the external
+// IP isn't specified.
+func (service *NATRuleService) CreateOrUpdateNATRule(obj *v1alpha1.NATRule) (*model.PolicyVpcNatRule, string, error) {
+	if err := validateNATRule(obj); err != nil {
+		return nil, "", err
+	}
+	vpcInfo, found := service.VPCService.GetVPCInfo(obj.Namespace, obj.Spec.VPCName)
+	if !found {
+		return nil, "", fmt.Errorf("no VPC found for namespace %s", obj.Namespace)
+	}
+
+	if !needAllocatedIP(obj) {
+		if err := service.validateExternalNetwork(obj, vpcInfo); err != nil {
+			return nil, "", err
+		}
+	}
+
+	allocatedIP := ""
+	if needAllocatedIP(obj) {
+		ip, err := service.allocateExternalIP(obj, vpcInfo)
+		if err != nil {
+			return nil, "", err
+		}
+		allocatedIP = ip
+	}
+	expected := service.buildNATRule(obj, vpcInfo, allocatedIP)
+	existing := service.NATRuleStore.GetByKey(*expected.Id)
+	if existing != nil && compareNATRule(existing, expected) {
+		log.V(1).Info("NAT rule is not changed", "path", *expected.Path)
+	} else {
+		if existing != nil && *existing.Path != *expected.Path {
+			// The NATRule is moved to another VPC.
+			if err := service.deleteNATRule(existing); err != nil {
+				return nil, "", err
+			}
+		}
+		client := service.NSXClient.NATRuleClient
+		if err := client.Patch(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, common.UserNATID, *expected.Id, *expected); err != nil {
+			log.Error(err, "failed to patch NSX NAT rule", "path", *expected.Path)
+			return nil, "", err
+		}
+		rule, err := client.Get(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, common.UserNATID, *expected.Id)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := service.NATRuleStore.Apply(&rule); err != nil {
+			return nil, "", err
+		}
+		existing = &rule
+		log.Info("created or updated NAT rule", "path", *expected.Path)
+	}
+	if allocatedIP == "" {
+		// The external IP is specified, release the IP allocated before.
+		if err := service.releaseExternalIP(obj.UID); err != nil {
+			return nil, "", err
+		}
+	}
+	return existing, allocatedIP, nil
+}
+
+// validateExternalNetwork checks the external IP or CIDR specified in the NATRule CR is within the external IP
+// blocks of the VPC.
+func (service *NATRuleService) validateExternalNetwork(obj *v1alpha1.NATRule, vpcInfo common.VPCResourceInfo) error {
+	cidrs, err := service.VPCService.GetExternalIPv4BlockCIDRs(vpcInfo.ExternalIPv4Blocks)
+	if err != nil {
+		return err
+	}
+	network := getExternalNetwork(obj)
+	if !networkInCIDRs(network, cidrs) {
+		return nsxutil.RestrictionError{Desc: fmt.Sprintf("%s is not within the external IP blocks %v of the VPC", network, cidrs)}
+	}
+	return nil
+}
+
+// allocateExternalIP returns the IP allocated from the external IP blocks of the VPC for the NATRule CR.
+func (service *NATRuleService) allocateExternalIP(obj *v1alpha1.NATRule, vpcInfo common.VPCResourceInfo) (string, error) {
+	for _, allocation := range service.IPAddressAllocationStore.GetByIndex(common.TagScopeNATRuleCRUID, string(obj.UID)) {
+		allocationVPC, err := common.ParseVPCResourcePath(*allocation.Path)
+		if err == nil && allocationVPC.VPCID == vpcInfo.VPCID && allocation.AllocationIp != nil {
+			return *allocation.AllocationIp, nil
+		}
+		if err := service.deleteIPAddressAllocation(allocation); err != nil {
+			return "", err
+		}
+	}
+	nsxAllocation := service.buildIPAddressAllocation(obj)
+	client := service.NSXClient.IPAddressAllocationClient
+	if err := client.Patch(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *nsxAllocation.Id, *nsxAllocation); err != nil {
+		log.Error(err, "failed to allocate external IP", "id", *nsxAllocation.Id)
+		return "", err
+	}
+	// Get the allocation from NSX after patch operation as NSX renders the allocated IP.
+	allocation, err := client.Get(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *nsxAllocation.Id)
+	if err != nil {
+		return "", err
+	}
+	if err := service.IPAddressAllocationStore.Apply(&allocation); err != nil {
+		return "", err
+	}
+	if allocation.AllocationIp == nil || *allocation.AllocationIp == "" {
+		return "", fmt.Errorf("no external IP allocated for NSX IP address allocation %s", *allocation.Id)
+	}
+	log.Info("allocated external IP", "id", *allocation.Id, "ip", *allocation.AllocationIp)
+	return *allocation.AllocationIp, nil
+}
+
+func (service *NATRuleService) releaseExternalIP(uid types.UID) error {
+	for _, allocation := range service.IPAddressAllocationStore.GetByIndex(common.TagScopeNATRuleCRUID, string(uid)) {
+		if err := service.deleteIPAddressAllocation(allocation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *NATRuleService) deleteIPAddressAllocation(allocation *model.VpcIpAddressAllocation) error {
+	vpcInfo, err := common.ParseVPCResourcePath(*allocation.Path)
+	if err != nil {
+		return err
+	}
+	if err := service.NSXClient.IPAddressAllocationClient.Delete(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *allocation.Id); err != nil {
+		log.Error(err, "failed to release external IP", "id", *allocation.Id)
+		return err
+	}
+	allocationCopy := *allocation
+	allocationCopy.MarkedForDelete = &MarkedForDelete
+	if err := service.IPAddressAllocationStore.Apply(&allocationCopy); err != nil {
+		return err
+	}
+	log.Info("released external IP", "id", *allocation.Id, "ip", allocation.AllocationIp)
+	return nil
+}
+
+func (service *NATRuleService) deleteNATRule(rule *model.PolicyVpcNatRule) error {
+	vpcInfo, err := common.ParseVPCResourcePath(*rule.Path)
+	if err != nil {
+		return err
+	}
+	if err := service.NSXClient.NATRuleClient.Delete(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, common.UserNATID, *rule.Id); err != nil {
+		log.Error(err, "failed to delete NSX NAT rule", "path", *rule.Path)
+		return err
+	}
+	ruleCopy := *rule
+	ruleCopy.MarkedForDelete = &MarkedForDelete
+	if err := service.NATRuleStore.Apply(&ruleCopy); err != nil {
+		return err
+	}
+	log.Info("deleted NAT rule", "path", *rule.Path)
+	return nil
+}
+
+// DeleteNATRule deletes the NSX NAT rule of the NATRule CR and releases the allocated external IP.
+func (service *NATRuleService) DeleteNATRule(uid types.UID) error {
+	for _, rule := range service.NATRuleStore.GetByIndex(common.TagScopeNATRuleCRUID, string(uid)) {
+		if err := service.deleteNATRule(rule); err != nil {
+			return err
+		}
+	}
+	return service.releaseExternalIP(uid)
+}
+
+func (service *NATRuleService) ListNATRule() []*model.PolicyVpcNatRule {
+	rules := make([]*model.PolicyVpcNatRule, 0)
+	for _, obj := range service.NATRuleStore.List() {
+		rules = append(rules, obj.(*model.PolicyVpcNatRule))
+	}
+	return rules
+}
+
+// ListNATRuleID returns the UIDs of the NATRule CRs which have NSX resources.
+func (service *NATRuleService) ListNATRuleID() sets.Set[string] {
+	ruleSet := service.NATRuleStore.ListIndexFuncValues(common.TagScopeNATRuleCRUID)
+	allocationSet := service.IPAddressAllocationStore.ListIndexFuncValues(common.TagScopeNATRuleCRUID)
+	return ruleSet.Union(allocationSet)
+}
+
+func (service *NATRuleService) Cleanup(ctx context.Context) error {
+	uids := service.ListNATRuleID()
+	log.Info("cleaning up natrule", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			if err := service.DeleteNATRule(types.UID(uid)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
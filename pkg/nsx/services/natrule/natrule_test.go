@@ -0,0 +1,209 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package natrule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/nat"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+type fakeVPCService struct {
+	common.VPCServiceProvider
+}
+
+func (f *fakeVPCService) GetVPCInfo(_ string, vpcName string) (common.VPCResourceInfo, bool) {
+	if vpcName == "" {
+		vpcName = "vpc1"
+	}
+	return common.VPCResourceInfo{OrgID: "default", ProjectID: "p1", VPCID: vpcName, ExternalIPv4Blocks: []string{"/infra/ip-blocks/external"}}, true
+}
+
+func (f *fakeVPCService) GetExternalIPv4BlockCIDRs(blocks []string) ([]string, error) {
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	return []string{"192.168.0.0/16"}, nil
+}
+
+type fakeNatRulesClient struct {
+	nat.NatRulesClient
+	rules   map[string]model.PolicyVpcNatRule
+	patched int
+}
+
+func (f *fakeNatRulesClient) Patch(orgId string, projectId string, vpcId string, natId string, natRuleId string, rule model.PolicyVpcNatRule) error {
+	f.patched++
+	f.rules[fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/nat/%s/nat-rules/%s", orgId, projectId, vpcId, natId, natRuleId)] = rule
+	return nil
+}
+
+func (f *fakeNatRulesClient) Get(orgId string, projectId string, vpcId string, natId string, natRuleId string) (model.PolicyVpcNatRule, error) {
+	return f.rules[fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/nat/%s/nat-rules/%s", orgId, projectId, vpcId, natId, natRuleId)], nil
+}
+
+func (f *fakeNatRulesClient) Delete(orgId string, projectId string, vpcId string, natId string, natRuleId string) error {
+	delete(f.rules, fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/nat/%s/nat-rules/%s", orgId, projectId, vpcId, natId, natRuleId))
+	return nil
+}
+
+// fakeIPAddressAllocationsClient renders the IPs from 192.168.0.0/24 for the allocations.
+type fakeIPAddressAllocationsClient struct {
+	vpcs.IpAddressAllocationsClient
+	allocations map[string]model.VpcIpAddressAllocation
+	next        int
+}
+
+func (f *fakeIPAddressAllocationsClient) Patch(orgId string, projectId string, vpcId string, ipAddressAllocationId string, allocation model.VpcIpAddressAllocation) error {
+	f.next++
+	allocation.AllocationIp = String(fmt.Sprintf("192.168.0.%d", f.next))
+	allocation.Path = String(fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/ip-address-allocations/%s", orgId, projectId, vpcId, ipAddressAllocationId))
+	f.allocations[*allocation.Path] = allocation
+	return nil
+}
+
+func (f *fakeIPAddressAllocationsClient) Get(orgId string, projectId string, vpcId string, ipAddressAllocationId string) (model.VpcIpAddressAllocation, error) {
+	return f.allocations[fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/ip-address-allocations/%s", orgId, projectId, vpcId, ipAddressAllocationId)], nil
+}
+
+func (f *fakeIPAddressAllocationsClient) Delete(orgId string, projectId string, vpcId string, ipAddressAllocationId string) error {
+	delete(f.allocations, fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s/ip-address-allocations/%s", orgId, projectId, vpcId, ipAddressAllocationId))
+	return nil
+}
+
+func createService() (*NATRuleService, *fakeNatRulesClient, *fakeIPAddressAllocationsClient) {
+	rulesClient := &fakeNatRulesClient{rules: map[string]model.PolicyVpcNatRule{}}
+	allocationsClient := &fakeIPAddressAllocationsClient{allocations: map[string]model.VpcIpAddressAllocation{}}
+	service := &NATRuleService{
+		Service: common.Service{
+			NSXClient: &nsx.Client{NATRuleClient: rulesClient, IPAddressAllocationClient: allocationsClient},
+			NSXConfig: &config.NSXOperatorConfig{CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test"}},
+		},
+		VPCService: &fakeVPCService{},
+		NATRuleStore: &NATRuleStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeNATRuleCRUID: indexFunc}),
+			BindingType: model.PolicyVpcNatRuleBindingType(),
+		}},
+		IPAddressAllocationStore: &IPAddressAllocationStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeNATRuleCRUID: indexFunc}),
+			BindingType: model.VpcIpAddressAllocationBindingType(),
+		}},
+	}
+	return service, rulesClient, allocationsClient
+}
+
+func TestValidateNATRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     v1alpha1.NATRuleSpec
+		hasError bool
+	}{
+		{name: "SNAT", spec: v1alpha1.NATRuleSpec{Action: v1alpha1.NATActionSNAT, SourceNetwork: "10.0.0.0/24"}},
+		{name: "DNAT", spec: v1alpha1.NATRuleSpec{Action: v1alpha1.NATActionDNAT, TranslatedNetwork: "10.0.0.5"}},
+		{name: "DNAT without translated network", spec: v1alpha1.NATRuleSpec{Action: v1alpha1.NATActionDNAT}, hasError: true},
+		{name: "REFLEXIVE", spec: v1alpha1.NATRuleSpec{Action: v1alpha1.NATActionReflexive, SourceNetwork: "10.0.0.5"}},
+		{name: "REFLEXIVE without source network", spec: v1alpha1.NATRuleSpec{Action: v1alpha1.NATActionReflexive}, hasError: true},
+		{name: "REFLEXIVE with destination network", spec: v1alpha1.NATRuleSpec{Action: v1alpha1.NATActionReflexive, SourceNetwork: "10.0.0.5", DestinationNetwork: "10.0.1.5"}, hasError: true},
+		{name: "invalid network", spec: v1alpha1.NATRuleSpec{Action: v1alpha1.NATActionSNAT, SourceNetwork: "10.0.0.0/33"}, hasError: true},
+		{name: "unknown action", spec: v1alpha1.NATRuleSpec{Action: "NOSNAT"}, hasError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNATRule(&v1alpha1.NATRule{Spec: tt.spec})
+			if tt.hasError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNetworkInCIDRs(t *testing.T) {
+	cidrs := []string{"192.168.0.0/16", "172.16.0.0/24"}
+	assert.True(t, networkInCIDRs("192.168.1.10", cidrs))
+	assert.True(t, networkInCIDRs("192.168.1.0/24", cidrs))
+	assert.True(t, networkInCIDRs("172.16.0.0/24", cidrs))
+	assert.False(t, networkInCIDRs("172.16.0.0/16", cidrs))
+	assert.False(t, networkInCIDRs("10.0.0.1", cidrs))
+	assert.False(t, networkInCIDRs("10.0.0.1", nil))
+}
+
+func TestCreateOrUpdateNATRule(t *testing.T) {
+	service, rulesClient, allocationsClient := createService()
+	obj := &v1alpha1.NATRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "snat1", UID: "snat-uid-1"},
+		Spec:       v1alpha1.NATRuleSpec{Action: v1alpha1.NATActionSNAT, SourceNetwork: "10.0.0.0/24"},
+	}
+
+	// The translated IP is allocated from the VPC external IP blocks.
+	rule, allocatedIP, err := service.CreateOrUpdateNATRule(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.0.1", allocatedIP)
+	assert.Equal(t, "/orgs/default/projects/p1/vpcs/vpc1/nat/USER/nat-rules/natrule_snat-uid-1", *rule.Path)
+	assert.Equal(t, "192.168.0.1", *rule.TranslatedNetwork)
+	assert.Len(t, allocationsClient.allocations, 1)
+
+	// The unchanged NAT rule is not patched again and the allocated IP is kept.
+	_, allocatedIP, err = service.CreateOrUpdateNATRule(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.0.1", allocatedIP)
+	assert.Equal(t, 1, rulesClient.patched)
+
+	// The translated network must be within the VPC external IP blocks.
+	obj.Spec.TranslatedNetwork = "10.10.0.10"
+	_, _, err = service.CreateOrUpdateNATRule(obj)
+	assert.ErrorAs(t, err, &nsxutil.RestrictionError{})
+	assert.Equal(t, 1, rulesClient.patched)
+
+	// The allocated IP is released once the translated network is specified.
+	obj.Spec.TranslatedNetwork = "192.168.1.10"
+	rule, allocatedIP, err = service.CreateOrUpdateNATRule(obj)
+	assert.NoError(t, err)
+	assert.Empty(t, allocatedIP)
+	assert.Equal(t, "192.168.1.10", *rule.TranslatedNetwork)
+	assert.Empty(t, allocationsClient.allocations)
+
+	// The NAT rule is moved to another VPC.
+	obj.Spec.VPCName = "vpc2"
+	rule, _, err = service.CreateOrUpdateNATRule(obj)
+	assert.NoError(t, err)
+	assert.Equal(t, "/orgs/default/projects/p1/vpcs/vpc2/nat/USER/nat-rules/natrule_snat-uid-1", *rule.Path)
+	assert.Len(t, rulesClient.rules, 1)
+
+	// DNAT gets the allocated IP as the destination network.
+	dnat := &v1alpha1.NATRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "dnat1", UID: "dnat-uid-1"},
+		Spec:       v1alpha1.NATRuleSpec{Action: v1alpha1.NATActionDNAT, TranslatedNetwork: "10.0.0.5"},
+	}
+	rule, allocatedIP, err = service.CreateOrUpdateNATRule(dnat)
+	assert.NoError(t, err)
+	assert.Equal(t, allocatedIP, *rule.DestinationNetwork)
+	assert.Equal(t, "10.0.0.5", *rule.TranslatedNetwork)
+	assert.Len(t, service.ListNATRule(), 2)
+
+	// The destination network of DNAT must be within the VPC external IP blocks.
+	dnat.Spec.DestinationNetwork = "10.10.0.0/24"
+	_, _, err = service.CreateOrUpdateNATRule(dnat)
+	assert.ErrorAs(t, err, &nsxutil.RestrictionError{})
+
+	// All the NAT rules and the allocated IPs are removed.
+	assert.NoError(t, service.Cleanup(context.TODO()))
+	assert.Empty(t, rulesClient.rules)
+	assert.Empty(t, allocationsClient.allocations)
+	assert.Empty(t, service.ListNATRuleID())
+}
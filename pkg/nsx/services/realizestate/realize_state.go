@@ -58,3 +58,22 @@ func (service *RealizeStateService) CheckRealizeState(backoff wait.Backoff, inte
 		return fmt.Errorf("%s not realized", entityType)
 	})
 }
+
+// GetRealizedResource returns the realized resource of entityType for the intent path, it returns nil
+// if the intent is not realized yet.
+func (service *RealizeStateService) GetRealizedResource(intentPath, entityType string) (*model.GenericPolicyRealizedResource, error) {
+	vpcInfo, err := common.ParseVPCResourcePath(intentPath)
+	if err != nil {
+		return nil, err
+	}
+	results, err := service.NSXClient.RealizedEntitiesClient.List(vpcInfo.OrgID, vpcInfo.ProjectID, intentPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results.Results {
+		if results.Results[i].EntityType != nil && *results.Results[i].EntityType == entityType {
+			return &results.Results[i], nil
+		}
+	}
+	return nil, nil
+}
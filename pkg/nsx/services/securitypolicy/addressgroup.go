@@ -0,0 +1,396 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// convertAddressGroupToInternalSecurityPolicy converts the AddressGroup to a SecurityPolicy which only has the
+// metadata of the AddressGroup, so that the SecurityPolicy builder functions can be used to build the NSX group.
+func convertAddressGroupToInternalSecurityPolicy(obj *v1alpha1.AddressGroup) *v1alpha1.SecurityPolicy {
+	return &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: obj.Namespace,
+			Name:      obj.Name,
+			UID:       obj.UID,
+		},
+	}
+}
+
+func (service *SecurityPolicyService) buildAddressGroupID(obj *v1alpha1.AddressGroup) string {
+	return util.GenerateID(string(obj.UID), common.AddressGroupPrefix, "", "")
+}
+
+func (service *SecurityPolicyService) buildAddressGroupName(obj *v1alpha1.AddressGroup) string {
+	return util.GenerateTruncName(common.MaxNameLength, obj.Name, "", "", obj.Namespace, "")
+}
+
+// buildAddressGroupPath returns the path of the NSX group of AddressGroup. In VPC network, the group is put
+// under the project level so that it can be shared with the VPCs.
+func (service *SecurityPolicyService) buildAddressGroupPath(obj *v1alpha1.AddressGroup) (string, error) {
+	groupID := service.buildAddressGroupID(obj)
+	if isVpcEnabled(service) {
+		vpcInfo, err := service.getVpcInfo(obj.Namespace)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("/orgs/%s/projects/%s/infra/domains/%s/groups/%s", vpcInfo.OrgID, vpcInfo.ProjectID, getVpcProjectDomain(), groupID), nil
+	}
+	return fmt.Sprintf("/infra/domains/%s/groups/%s", getDomain(service), groupID), nil
+}
+
+func (service *SecurityPolicyService) buildAddressGroup(obj *v1alpha1.AddressGroup) (*model.Group, *model.Share, error) {
+	internalSecurityPolicy := convertAddressGroupToInternalSecurityPolicy(obj)
+	groupShared := isVpcEnabled(service)
+	groupID := service.buildAddressGroupID(obj)
+	groupName := service.buildAddressGroupName(obj)
+	groupPath, err := service.buildAddressGroupPath(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	tags := service.buildBasicTags(internalSecurityPolicy, common.ResourceTypeAddressGroup)
+	if groupShared {
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeProjectGroupShared), Tag: String("true")})
+	}
+	group := model.Group{
+		Id:          &groupID,
+		DisplayName: &groupName,
+		Path:        &groupPath,
+		Tags:        tags,
+	}
+
+	groupCriteriaCount, groupTotalExprCount := 0, 0
+	for i, member := range obj.Spec.Members {
+		peer := v1alpha1.SecurityPolicyPeer{
+			VMSelector:        member.VMSelector,
+			PodSelector:       member.PodSelector,
+			NamespaceSelector: member.NamespaceSelector,
+			IPBlocks:          member.IPBlocks,
+		}
+		criteriaCount, totalExprCount, err := service.updatePeerExpressions(internalSecurityPolicy, &peer, &group, i, groupShared)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid member %d: %w", i, err)
+		}
+		groupCriteriaCount += criteriaCount
+		groupTotalExprCount += totalExprCount
+	}
+	if groupCriteriaCount > MaxCriteria {
+		return nil, nil, fmt.Errorf("total counts of address group criteria %d exceed NSX limit of %d", groupCriteriaCount, MaxCriteria)
+	} else if groupTotalExprCount > MaxTotalCriteriaExpressions {
+		return nil, nil, fmt.Errorf("total expression counts in address group criteria %d exceed NSX limit of %d", groupTotalExprCount, MaxTotalCriteriaExpressions)
+	}
+
+	if len(obj.Spec.NSXGroupPaths) > 0 {
+		paths := data.NewListValue()
+		for _, path := range obj.Spec.NSXGroupPaths {
+			paths.Add(data.NewStringValue(path))
+		}
+		service.appendOperatorIfNeeded(&group.Expression, "OR")
+		group.Expression = append(group.Expression, data.NewStructValue(
+			"",
+			map[string]data.DataValue{
+				"resource_type": data.NewStringValue("PathExpression"),
+				"paths":         paths,
+			},
+		))
+	}
+
+	if !groupShared {
+		return &group, nil, nil
+	}
+	sharedNamespaces, err := service.getAddressGroupSharedNamespaces(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedWith, err := service.buildSharedWith(&sharedNamespaces)
+	if err != nil {
+		log.Error(err, "failed to build SharedWith path", "addressGroup", groupName)
+		return nil, nil, err
+	}
+	share, err := service.buildProjectShare(internalSecurityPolicy, &group, []string{groupPath}, *sharedWith, common.ResourceTypeAddressGroup)
+	if err != nil {
+		log.Error(err, "failed to build nsx project share", "addressGroup", groupName)
+		return nil, nil, err
+	}
+	return &group, share, nil
+}
+
+// getAddressGroupSharedNamespaces returns the Namespace of the AddressGroup and the Namespaces with VPC
+// selected by the sharedNamespaceSelector.
+func (service *SecurityPolicyService) getAddressGroupSharedNamespaces(obj *v1alpha1.AddressGroup) ([]string, error) {
+	namespaces := sets.New[string](obj.Namespace)
+	if obj.Spec.SharedNamespaceSelector != nil {
+		nsList, err := service.ResolveNamespace(obj.Spec.SharedNamespaceSelector)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nsList.Items {
+			if len(service.vpcService.ListVPCInfo(ns.Name)) == 0 {
+				log.V(1).Info("skip sharing address group with the Namespace without VPC", "namespace", ns.Name)
+				continue
+			}
+			namespaces.Insert(ns.Name)
+		}
+	}
+	sharedNamespaces := namespaces.UnsortedList()
+	sort.Strings(sharedNamespaces)
+	return sharedNamespaces, nil
+}
+
+// CreateOrUpdateAddressGroup realizes the AddressGroup as an NSX group, and returns the path of the group.
+func (service *SecurityPolicyService) CreateOrUpdateAddressGroup(obj *v1alpha1.AddressGroup) (string, error) {
+	if !nsxutil.IsLicensed(nsxutil.FeatureDFW) {
+		log.Info("no DFW license, skip creating AddressGroup.")
+		return "", nsxutil.RestrictionError{Desc: "no DFW license"}
+	}
+	nsxGroup, nsxShare, err := service.buildAddressGroup(obj)
+	if err != nil {
+		log.Error(err, "failed to build AddressGroup")
+		return "", err
+	}
+
+	groupStore := service.groupStore
+	if isVpcEnabled(service) {
+		groupStore = service.projectGroupStore
+	}
+	existingGroups := groupStore.GetByIndex(common.TagScopeAddressGroupCRUID, string(obj.UID))
+	changed, stale := common.CompareResources(GroupsPtrToComparable(existingGroups), GroupsToComparable([]model.Group{*nsxGroup}))
+	changedGroups, staleGroups := ComparableToGroups(changed), ComparableToGroups(stale)
+	var changedShares, staleShares []model.Share
+	if nsxShare != nil {
+		existingShares := service.shareStore.GetByIndex(common.TagScopeAddressGroupCRUID, string(obj.UID))
+		changed, stale = common.CompareResources(SharesPtrToComparable(existingShares), SharesToComparable([]model.Share{*nsxShare}))
+		changedShares, staleShares = ComparableToShares(changed), ComparableToShares(stale)
+	}
+	if len(changedGroups) == 0 && len(staleGroups) == 0 && len(changedShares) == 0 && len(staleShares) == 0 {
+		log.Info("address group and share are not changed, skip updating them", "nsxGroup.Id", nsxGroup.Id)
+		return *nsxGroup.Path, nil
+	}
+
+	for i := len(staleGroups) - 1; i >= 0; i-- {
+		staleGroups[i].MarkedForDelete = &MarkedForDelete
+	}
+	for i := len(staleShares) - 1; i >= 0; i-- {
+		staleShares[i].MarkedForDelete = &MarkedForDelete
+	}
+	finalGroups := append(staleGroups, changedGroups...)
+	finalShares := append(staleShares, changedShares...)
+	if err := service.patchAddressGroup(obj.Namespace, finalGroups, finalShares); err != nil {
+		log.Error(err, "failed to create or update AddressGroup")
+		return "", err
+	}
+	log.Info("successfully created or updated nsx group for AddressGroup", "nsxGroup", nsxGroup)
+	return *nsxGroup.Path, nil
+}
+
+// DeleteAddressGroup deletes the NSX group and share of the AddressGroup with the UID.
+func (service *SecurityPolicyService) DeleteAddressGroup(uid types.UID) error {
+	groupStore := service.groupStore
+	if isVpcEnabled(service) {
+		groupStore = service.projectGroupStore
+	}
+	var nsxGroups []model.Group
+	var nsxShares []model.Share
+	for _, group := range groupStore.GetByIndex(common.TagScopeAddressGroupCRUID, string(uid)) {
+		nsxGroups = append(nsxGroups, *group)
+	}
+	for _, share := range service.shareStore.GetByIndex(common.TagScopeAddressGroupCRUID, string(uid)) {
+		nsxShares = append(nsxShares, *share)
+	}
+	for i := len(nsxGroups) - 1; i >= 0; i-- { // Don't use range, it would copy the element
+		nsxGroups[i].MarkedForDelete = &MarkedForDelete
+	}
+	for i := len(nsxShares) - 1; i >= 0; i-- {
+		nsxShares[i].MarkedForDelete = &MarkedForDelete
+	}
+	if len(nsxGroups) == 0 && len(nsxShares) == 0 {
+		log.Info("NSX group is not found in store, skip deleting it", "addressGroupUID", uid)
+		return nil
+	}
+	if err := service.patchAddressGroup("", nsxGroups, nsxShares); err != nil {
+		log.Error(err, "failed to delete AddressGroup")
+		return err
+	}
+	log.Info("successfully deleted nsx group for AddressGroup", "addressGroupUID", uid)
+	return nil
+}
+
+// patchAddressGroup patches the groups and shares of AddressGroup in NSX, then applies them to the stores.
+// The org and project are parsed from the group path, so namespace is not used when deleting the groups.
+func (service *SecurityPolicyService) patchAddressGroup(namespace string, nsxGroups []model.Group, nsxShares []model.Share) error {
+	if !isVpcEnabled(service) {
+		groupsChildren, err := service.wrapGroups(nsxGroups)
+		if err != nil {
+			return err
+		}
+		infraChildren, err := service.wrapDomainResource(groupsChildren, getDomain(service))
+		if err != nil {
+			return err
+		}
+		infra, err := service.wrapInfra(infraChildren)
+		if err != nil {
+			return err
+		}
+		if err = service.NSXClient.InfraClient.Patch(*infra, &EnforceRevisionCheckParam); err != nil {
+			return err
+		}
+		return service.groupStore.Apply(&nsxGroups)
+	}
+
+	var orgID, projectID string
+	if len(nsxGroups) > 0 && nsxGroups[0].Path != nil {
+		// Get orgId, projectId from group path "/orgs/<orgId>/projects/<projectId>/infra/domains/default/groups/<groupId>"
+		paths := strings.Split(*nsxGroups[0].Path, "/")
+		if len(paths) > 4 {
+			orgID, projectID = paths[2], paths[4]
+		}
+	}
+	if orgID == "" && namespace != "" {
+		vpcInfo, err := service.getVpcInfo(namespace)
+		if err != nil {
+			return err
+		}
+		orgID, projectID = vpcInfo.OrgID, vpcInfo.ProjectID
+	}
+	if orgID == "" {
+		return errors.New("failed to get the project of AddressGroup")
+	}
+	projectInfra, err := service.wrapHierarchyProjectResources(nsxShares, nsxGroups)
+	if err != nil {
+		return err
+	}
+	orgRoot, err := service.wrapHierarchyProjectInfra(projectInfra, orgID, projectID)
+	if err != nil {
+		return err
+	}
+	if err = service.NSXClient.OrgRootClient.Patch(*orgRoot, &EnforceRevisionCheckParam); err != nil {
+		return err
+	}
+	if len(nsxGroups) != 0 {
+		if err = service.projectGroupStore.Apply(&nsxGroups); err != nil {
+			return err
+		}
+	}
+	if len(nsxShares) != 0 {
+		if err = service.shareStore.Apply(&nsxShares); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListAddressGroupID lists the UIDs of the AddressGroups which have NSX groups or shares.
+func (service *SecurityPolicyService) ListAddressGroupID() sets.Set[string] {
+	groupSet := service.groupStore.ListIndexFuncValues(common.TagScopeAddressGroupCRUID)
+	projectGroupSet := service.projectGroupStore.ListIndexFuncValues(common.TagScopeAddressGroupCRUID)
+	shareSet := service.shareStore.ListIndexFuncValues(common.TagScopeAddressGroupCRUID)
+	return groupSet.Union(projectGroupSet).Union(shareSet)
+}
+
+// getAddressGroupPaths returns the NSX group paths of the AddressGroups referenced by the peers.
+func (service *SecurityPolicyService) getAddressGroupPaths(obj *v1alpha1.SecurityPolicy, peers []v1alpha1.SecurityPolicyPeer) ([]string, error) {
+	var paths []string
+	for _, peer := range peers {
+		if peer.AddressGroupRef == nil {
+			continue
+		}
+		namespace := peer.AddressGroupRef.Namespace
+		if namespace == "" {
+			namespace = obj.Namespace
+		}
+		addressGroup := &v1alpha1.AddressGroup{}
+		if err := service.Client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: peer.AddressGroupRef.Name}, addressGroup); err != nil {
+			return nil, fmt.Errorf("failed to get AddressGroup %s/%s: %w", namespace, peer.AddressGroupRef.Name, err)
+		}
+		if namespace != obj.Namespace {
+			shared, err := service.isAddressGroupSharedWith(addressGroup, obj.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			if !shared {
+				return nil, nsxutil.RestrictionError{Desc: fmt.Sprintf("AddressGroup %s/%s is not shared with Namespace %s", namespace, addressGroup.Name, obj.Namespace)}
+			}
+		}
+		if addressGroup.Status.NSXResourcePath == "" {
+			return nil, fmt.Errorf("AddressGroup %s/%s is not realized", namespace, addressGroup.Name)
+		}
+		paths = append(paths, addressGroup.Status.NSXResourcePath)
+	}
+	return paths, nil
+}
+
+func (service *SecurityPolicyService) isAddressGroupSharedWith(obj *v1alpha1.AddressGroup, namespace string) (bool, error) {
+	if obj.Spec.SharedNamespaceSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(obj.Spec.SharedNamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	ns := &corev1.Namespace{}
+	if err := service.Client.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
+}
+
+// ReferencesAddressGroup returns true if any rule peer of the SecurityPolicy references the AddressGroup.
+func ReferencesAddressGroup(sp *v1alpha1.SecurityPolicy, namespace, name string) bool {
+	for _, rule := range sp.Spec.Rules {
+		for _, peers := range [][]v1alpha1.SecurityPolicyPeer{rule.Sources, rule.Destinations} {
+			for _, peer := range peers {
+				if peer.AddressGroupRef == nil || peer.AddressGroupRef.Name != name {
+					continue
+				}
+				refNamespace := peer.AddressGroupRef.Namespace
+				if refNamespace == "" {
+					refNamespace = sp.Namespace
+				}
+				if refNamespace == namespace {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasAddressGroupRef returns true if any peer references an AddressGroup.
+func hasAddressGroupRef(peers []v1alpha1.SecurityPolicyPeer) bool {
+	for _, peer := range peers {
+		if peer.AddressGroupRef != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSelectorPeers returns the peers which don't reference an AddressGroup.
+func filterSelectorPeers(peers []v1alpha1.SecurityPolicyPeer) []v1alpha1.SecurityPolicyPeer {
+	var selectorPeers []v1alpha1.SecurityPolicyPeer
+	for _, peer := range peers {
+		if peer.AddressGroupRef == nil {
+			selectorPeers = append(selectorPeers, peer)
+		}
+	}
+	return selectorPeers
+}
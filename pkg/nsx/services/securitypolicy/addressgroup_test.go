@@ -0,0 +1,202 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func newAddressGroupService(objs ...runtime.Object) *SecurityPolicyService {
+	newScheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(newScheme)
+	v1alpha1.AddToScheme(newScheme)
+	objs = append(objs,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "infra", UID: "infra-uid"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "web", UID: "web-uid", Labels: map[string]string{"team": "web"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "db", UID: "db-uid"}},
+	)
+	return &SecurityPolicyService{
+		Service: common.Service{
+			Client: fake.NewClientBuilder().WithScheme(newScheme).WithRuntimeObjects(objs...).Build(),
+			NSXConfig: &config.NSXOperatorConfig{
+				CoeConfig: &config.CoeConfig{Cluster: "k8scl-one"},
+			},
+		},
+	}
+}
+
+func TestBuildAddressGroup(t *testing.T) {
+	s := newAddressGroupService()
+	addressGroup := &v1alpha1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "proxies", UID: "ag-uid"},
+		Spec: v1alpha1.AddressGroupSpec{
+			Members: []v1alpha1.AddressGroupMember{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "proxy"}}},
+				{IPBlocks: []v1alpha1.IPBlock{{CIDR: "192.168.10.0/24"}}},
+			},
+			NSXGroupPaths: []string{"/infra/domains/default/groups/proxies"},
+		},
+	}
+
+	group, share, err := s.buildAddressGroup(addressGroup)
+	require.NoError(t, err)
+	assert.Nil(t, share)
+	assert.Equal(t, "ag_ag-uid", *group.Id)
+	assert.Equal(t, "/infra/domains/k8scl-one/groups/ag_ag-uid", *group.Path)
+	assert.Equal(t, "proxies-infra", *group.DisplayName)
+	tags := map[string]string{}
+	for _, tag := range group.Tags {
+		tags[*tag.Scope] = *tag.Tag
+	}
+	assert.Equal(t, "proxies", tags[common.TagScopeAddressGroupCRName])
+	assert.Equal(t, "ag-uid", tags[common.TagScopeAddressGroupCRUID])
+	// pod criteria, IP addresses and the path expression joined by OR operators
+	require.Len(t, group.Expression, 5)
+	resourceType, _ := group.Expression[4].String("resource_type")
+	assert.Equal(t, "PathExpression", resourceType)
+
+	addressGroup.Spec.Members = []v1alpha1.AddressGroupMember{
+		{NamespaceSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"web"}},
+			},
+		}},
+	}
+	_, _, err = s.buildAddressGroup(addressGroup)
+	assert.Error(t, err)
+}
+
+func TestBuildRulePeerGroupPaths(t *testing.T) {
+	sharedAddressGroup := &v1alpha1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "proxies"},
+		Spec: v1alpha1.AddressGroupSpec{
+			SharedNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+		},
+		Status: v1alpha1.AddressGroupStatus{NSXResourcePath: "/infra/domains/k8scl-one/groups/ag_proxies"},
+	}
+	localAddressGroup := &v1alpha1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "local"},
+	}
+	s := newAddressGroupService(sharedAddressGroup, localAddressGroup)
+
+	proxiesRef := v1alpha1.SecurityPolicyPeer{AddressGroupRef: &v1alpha1.AddressGroupReference{Name: "proxies", Namespace: "infra"}}
+	tests := []struct {
+		name      string
+		namespace string
+		peers     []v1alpha1.SecurityPolicyPeer
+		groupPath string
+		expected  []string
+		errCheck  func(error) bool
+	}{
+		{
+			name:      "no reference",
+			namespace: "web",
+			peers:     []v1alpha1.SecurityPolicyPeer{{IPBlocks: []v1alpha1.IPBlock{{CIDR: cidr}}}},
+			groupPath: "/infra/domains/k8scl-one/groups/peer",
+			expected:  []string{"/infra/domains/k8scl-one/groups/peer"},
+		},
+		{
+			name:      "shared reference only",
+			namespace: "web",
+			peers:     []v1alpha1.SecurityPolicyPeer{proxiesRef},
+			expected:  []string{"/infra/domains/k8scl-one/groups/ag_proxies"},
+		},
+		{
+			name:      "reference with selector peer",
+			namespace: "web",
+			peers:     []v1alpha1.SecurityPolicyPeer{{IPBlocks: []v1alpha1.IPBlock{{CIDR: cidr}}}, proxiesRef},
+			groupPath: "/infra/domains/k8scl-one/groups/peer",
+			expected:  []string{"/infra/domains/k8scl-one/groups/peer", "/infra/domains/k8scl-one/groups/ag_proxies"},
+		},
+		{
+			name:      "not shared",
+			namespace: "db",
+			peers:     []v1alpha1.SecurityPolicyPeer{proxiesRef},
+			errCheck: func(err error) bool {
+				return errors.As(err, &nsxutil.RestrictionError{})
+			},
+		},
+		{
+			name:      "not realized",
+			namespace: "web",
+			peers:     []v1alpha1.SecurityPolicyPeer{{AddressGroupRef: &v1alpha1.AddressGroupReference{Name: "local"}}},
+			errCheck: func(err error) bool {
+				return err != nil && !errors.As(err, &nsxutil.RestrictionError{})
+			},
+		},
+		{
+			name:      "not found",
+			namespace: "web",
+			peers:     []v1alpha1.SecurityPolicyPeer{{AddressGroupRef: &v1alpha1.AddressGroupReference{Name: "missing"}}},
+			errCheck: func(err error) bool {
+				return err != nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: tt.namespace, Name: "sp"}}
+			paths, err := s.buildRulePeerGroupPaths(sp, tt.peers, tt.groupPath)
+			if tt.errCheck != nil {
+				assert.True(t, tt.errCheck(err), "unexpected error %v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, paths)
+		})
+	}
+}
+
+func TestBuildRuleAndGroupsNamedPortWithAddressGroup(t *testing.T) {
+	sharedAddressGroup := &v1alpha1.AddressGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "infra", Name: "proxies"},
+		Spec: v1alpha1.AddressGroupSpec{
+			SharedNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "web"}},
+		},
+		Status: v1alpha1.AddressGroupStatus{NSXResourcePath: "/infra/domains/k8scl-one/groups/ag_proxies"},
+	}
+	s := newAddressGroupService(sharedAddressGroup)
+	ipSetGroupPath := "/infra/domains/k8scl-one/groups/sp_uid_ipset"
+	ruleID := "sp_uid_0"
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "expandRule",
+		func(s *SecurityPolicyService, obj *v1alpha1.SecurityPolicy, rule *v1alpha1.SecurityPolicyRule, ruleIdx int, createdFor string) ([]*model.Group, []*model.Rule, error) {
+			return nil, []*model.Rule{{Id: &ruleID, DestinationGroups: []string{ipSetGroupPath}}}, nil
+		})
+	patches.ApplyPrivateMethod(reflect.TypeOf(s), "buildRuleAppliedToGroup",
+		func(s *SecurityPolicyService, obj *v1alpha1.SecurityPolicy, rule *v1alpha1.SecurityPolicyRule, ruleIdx int, nsxRuleSrcGroupPath string, nsxRuleDstGroupPath string, createdFor string) (*model.Group, string, error) {
+			return nil, "ANY", nil
+		})
+	defer patches.Reset()
+
+	direction := v1alpha1.RuleDirectionOut
+	sp := &v1alpha1.SecurityPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "sp", UID: "uid"}}
+	rule := &v1alpha1.SecurityPolicyRule{
+		Direction:    &direction,
+		Destinations: []v1alpha1.SecurityPolicyPeer{{AddressGroupRef: &v1alpha1.AddressGroupReference{Name: "proxies", Namespace: "infra"}}},
+		Ports:        []v1alpha1.SecurityPolicyPort{{Protocol: "TCP", Port: intstr.FromString("http")}},
+	}
+	rules, _, _, err := s.buildRuleAndGroups(sp, rule, 0, common.ResourceTypeSecurityPolicy)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	// Only the ip set group of the named port is the destination, the AddressGroup isn't appended.
+	assert.Equal(t, []string{ipSetGroupPath}, rules[0].DestinationGroups)
+}
@@ -36,6 +36,7 @@ const (
 var (
 	String = common.String
 	Int64  = common.Int64
+	Bool   = common.Bool
 )
 
 func (service *SecurityPolicyService) buildecurityPolicyName(obj *v1alpha1.SecurityPolicy, createdFor string) string {
@@ -190,6 +191,28 @@ func (service *SecurityPolicyService) buildPolicyGroup(obj *v1alpha1.SecurityPol
 	return &policyAppliedGroup, policyAppliedGroupPath, nil
 }
 
+// BuildPodSelectorGroupExpression adds the expressions selecting the Pods of the Namespace by podSelector to
+// the group, in the same way as the appliedTo target of SecurityPolicy. All the Pods of the Namespace are
+// selected if podSelector is nil.
+func (service *SecurityPolicyService) BuildPodSelectorGroupExpression(namespace string, podSelector *v1.LabelSelector, group *model.Group) error {
+	if podSelector == nil {
+		podSelector = &v1.LabelSelector{}
+	}
+	obj := &v1alpha1.SecurityPolicy{ObjectMeta: v1.ObjectMeta{Namespace: namespace}}
+	target := &v1alpha1.SecurityPolicyTarget{PodSelector: podSelector}
+	criteriaCount, totalExprCount, err := service.updateTargetExpressions(obj, target, group, -1)
+	if err != nil {
+		return err
+	}
+	if criteriaCount > MaxCriteria {
+		return fmt.Errorf("total counts of group criteria %d exceed NSX limit of %d", criteriaCount, MaxCriteria)
+	}
+	if totalExprCount > MaxTotalCriteriaExpressions {
+		return fmt.Errorf("total expression counts in group criteria %d exceed NSX limit of %d", totalExprCount, MaxTotalCriteriaExpressions)
+	}
+	return nil
+}
+
 func (service *SecurityPolicyService) buildTargetTags(obj *v1alpha1.SecurityPolicy, targets *[]v1alpha1.SecurityPolicyTarget,
 	rule *v1alpha1.SecurityPolicyRule, ruleIdx int, createdFor string,
 ) []model.Tag {
@@ -245,6 +268,9 @@ func (service *SecurityPolicyService) buildBasicTags(obj *v1alpha1.SecurityPolic
 	if createdFor == common.ResourceTypeNetworkPolicy {
 		scopeOwnerName = common.TagScopeNetworkPolicyName
 		scopeOwnerUID = common.TagScopeNetworkPolicyUID
+	} else if createdFor == common.ResourceTypeAddressGroup {
+		scopeOwnerName = common.TagScopeAddressGroupCRName
+		scopeOwnerUID = common.TagScopeAddressGroupCRUID
 	}
 
 	tags := util.BuildBasicTags(getCluster(service), obj, service.getNamespaceUID(obj.ObjectMeta.Namespace))
@@ -440,6 +466,9 @@ func (service *SecurityPolicyService) buildRuleAndGroups(obj *v1alpha1.SecurityP
 	}
 
 	for _, nsxRule := range nsxRules {
+		// An OUT rule with a named port already has the ip set group of the resolved Pods as its destination,
+		// the named port can't be resolved on the AddressGroup members, so don't add them to the destination.
+		namedPortDst := ruleDirection == "OUT" && len(nsxRule.DestinationGroups) > 0
 		if ruleDirection == "IN" {
 			nsxRuleSrcGroup, nsxRuleSrcGroupPath, nsxRuleDstGroupPath, nsxProjectShare, err = service.buildRuleInGroup(
 				obj, rule, nsxRule, ruleIdx, createdFor)
@@ -468,8 +497,18 @@ func (service *SecurityPolicyService) buildRuleAndGroups(obj *v1alpha1.SecurityP
 			}
 		}
 
-		nsxRule.SourceGroups = []string{nsxRuleSrcGroupPath}
-		nsxRule.DestinationGroups = []string{nsxRuleDstGroupPath}
+		nsxRule.SourceGroups, err = service.buildRulePeerGroupPaths(obj, rule.Sources, nsxRuleSrcGroupPath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if namedPortDst {
+			nsxRule.DestinationGroups = []string{nsxRuleDstGroupPath}
+		} else {
+			nsxRule.DestinationGroups, err = service.buildRulePeerGroupPaths(obj, rule.Destinations, nsxRuleDstGroupPath)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
 
 		nsxRuleAppliedGroup, nsxRuleAppliedGroupPath, err = service.buildRuleAppliedToGroup(
 			obj, rule, ruleIdx, nsxRuleSrcGroupPath, nsxRuleDstGroupPath, createdFor)
@@ -482,6 +521,23 @@ func (service *SecurityPolicyService) buildRuleAndGroups(obj *v1alpha1.SecurityP
 	return nsxRules, ruleGroups, projectShares, nil
 }
 
+// buildRulePeerGroupPaths appends the NSX group paths of the AddressGroups referenced by the peers
+// to the rule peer group path. The rule peer group path is empty if all the peers reference AddressGroups.
+func (service *SecurityPolicyService) buildRulePeerGroupPaths(obj *v1alpha1.SecurityPolicy, peers []v1alpha1.SecurityPolicyPeer, groupPath string) ([]string, error) {
+	if groupPath == "ANY" || !hasAddressGroupRef(peers) {
+		return []string{groupPath}, nil
+	}
+	addressGroupPaths, err := service.getAddressGroupPaths(obj, peers)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	if groupPath != "" {
+		paths = append(paths, groupPath)
+	}
+	return append(paths, addressGroupPaths...), nil
+}
+
 func (service *SecurityPolicyService) buildRuleServiceEntries(port v1alpha1.SecurityPolicyPort, portAddress nsxutil.PortAddress) *data.StructValue {
 	var portRange string
 	sourcePorts := data.NewListValue()
@@ -777,6 +833,11 @@ func (service *SecurityPolicyService) buildRulePeerGroup(obj *v1alpha1.SecurityP
 		rulePeers = rule.Destinations
 		ruleDirection = "destination"
 	}
+	// The peers referencing AddressGroups use the NSX groups of the AddressGroups directly.
+	rulePeers = filterSelectorPeers(rulePeers)
+	if len(rulePeers) == 0 {
+		return nil, "", nil, nil
+	}
 
 	groupShared := false
 	for _, peer := range rulePeers {
@@ -884,6 +945,10 @@ func (service *SecurityPolicyService) buildRuleBasicInfo(obj *v1alpha1.SecurityP
 	if err != nil {
 		log.Error(err, "failed to build rule's display name", "object.UID", obj.UID, "rule", rule, "createdFor", createdFor)
 	}
+	ruleDisabled, err := isRuleDisabled(obj, rule)
+	if err != nil {
+		return nil, err
+	}
 
 	nsxRule := model.Rule{
 		Id:             String(fmt.Sprintf("%s_%d_%d", service.buildRuleID(obj, rule, ruleIdx, createdFor), portIdx, portAddressIdx)),
@@ -892,6 +957,7 @@ func (service *SecurityPolicyService) buildRuleBasicInfo(obj *v1alpha1.SecurityP
 		SequenceNumber: Int64(int64(ruleIdx)),
 		Action:         &ruleAction,
 		Services:       []string{"ANY"},
+		Disabled:       Bool(ruleDisabled),
 		Tags:           service.buildBasicTags(obj, createdFor),
 	}
 	log.V(1).Info("built rule basic info", "nsxRule", nsxRule)
@@ -1657,6 +1723,9 @@ func (service *SecurityPolicyService) buildShareTags(obj *v1alpha1.SecurityPolic
 	if createdFor == common.ResourceTypeSecurityPolicy {
 		scopeOwnerName = common.TagValueScopeSecurityPolicyName
 		scopeOwnerUID = common.TagValueScopeSecurityPolicyUID
+	} else if createdFor == common.ResourceTypeAddressGroup {
+		scopeOwnerName = common.TagScopeAddressGroupCRName
+		scopeOwnerUID = common.TagScopeAddressGroupCRUID
 	} else {
 		scopeOwnerName = common.TagScopeNetworkPolicyName
 		scopeOwnerUID = common.TagScopeNetworkPolicyUID
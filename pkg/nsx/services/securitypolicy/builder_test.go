@@ -69,6 +69,7 @@ func TestBuildSecurityPolicy(t *testing.T) {
 						Services:          []string{"ANY"},
 						SourceGroups:      []string{"/infra/domains/k8scl-one/groups/sp_uidA_0_src"},
 						Action:            &nsxActionAllow,
+						Disabled:          Bool(false),
 						Tags:              basicTags,
 					},
 					{
@@ -81,6 +82,7 @@ func TestBuildSecurityPolicy(t *testing.T) {
 						Services:          []string{"ANY"},
 						SourceGroups:      []string{"/infra/domains/k8scl-one/groups/sp_uidA_1_src"},
 						Action:            &nsxActionAllow,
+						Disabled:          Bool(false),
 						ServiceEntries:    []*data.StructValue{serviceEntry},
 						Tags:              basicTags,
 					},
@@ -107,6 +109,7 @@ func TestBuildSecurityPolicy(t *testing.T) {
 						Services:          []string{"ANY"},
 						SourceGroups:      []string{"ANY"},
 						Action:            &nsxActionDrop,
+						Disabled:          Bool(false),
 						Tags:              basicTags,
 					},
 					{
@@ -119,6 +122,7 @@ func TestBuildSecurityPolicy(t *testing.T) {
 						Services:          []string{"ANY"},
 						SourceGroups:      []string{"ANY"},
 						Action:            &nsxActionDrop,
+						Disabled:          Bool(false),
 						Tags:              basicTags,
 					},
 
@@ -132,6 +136,7 @@ func TestBuildSecurityPolicy(t *testing.T) {
 						Services:          []string{"ANY"},
 						SourceGroups:      []string{"ANY"},
 						Action:            &nsxActionDrop,
+						Disabled:          Bool(false),
 						Tags:              basicTags,
 					},
 				},
@@ -187,6 +192,31 @@ func TestBuildPolicyGroup(t *testing.T) {
 	}
 }
 
+func TestBuildPodSelectorGroupExpression(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	// All the Pods of the Namespace are selected without podSelector.
+	group := &model.Group{}
+	assert.NoError(t, service.BuildPodSelectorGroupExpression("ns1", nil, group))
+	assert.Equal(t, 1, len(group.Expression))
+	expressions, _ := group.Expression[0].Field("expressions")
+	assert.Equal(t, 3, len(expressions.(*data.ListValue).List()))
+
+	group = &model.Group{}
+	podSelector := &v1.LabelSelector{
+		MatchLabels:      map[string]string{"app": "web"},
+		MatchExpressions: []v1.LabelSelectorRequirement{{Key: "tier", Operator: v1.LabelSelectorOpIn, Values: []string{"a", "b"}}},
+	}
+	assert.NoError(t, service.BuildPodSelectorGroupExpression("ns1", podSelector, group))
+	// Operator In produces one criteria per value.
+	assert.Equal(t, 3, len(group.Expression))
+}
+
 func TestBuildTargetTags(t *testing.T) {
 	ruleTagID0 := service.buildRuleID(&spWithPodSelector, &spWithPodSelector.Spec.Rules[0], 0, common.ResourceTypeSecurityPolicy)
 	tests := []struct {
@@ -57,6 +57,7 @@ func (rule *Rule) Value() data.DataValue {
 		ServiceEntries:    rule.ServiceEntries,
 		DestinationGroups: rule.DestinationGroups,
 		SourceGroups:      rule.SourceGroups,
+		Disabled:          rule.Disabled,
 	}
 	dataValue, _ := ComparableToRule(r).GetDataValue__()
 	return dataValue
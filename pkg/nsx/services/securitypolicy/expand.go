@@ -320,6 +320,9 @@ func (service *SecurityPolicyService) getPodSelectors(obj *v1alpha1.SecurityPoli
 	} else if ruleDirection == "OUT" {
 		if len(rule.Destinations) > 0 {
 			for _, target := range rule.Destinations {
+				if target.AddressGroupRef != nil {
+					continue
+				}
 				var namespaceSelectors []client.ListOptions // ResolveNamespace may return multiple namespaces
 				var labelSelector client.ListOptions
 				var namespaceSelector client.ListOptions
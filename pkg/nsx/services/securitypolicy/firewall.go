@@ -17,6 +17,7 @@ import (
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/ratelimiter"
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
 	"github.com/vmware-tanzu/nsx-operator/pkg/util"
@@ -41,6 +42,8 @@ type SecurityPolicyService struct {
 	projectGroupStore   *GroupStore
 	shareStore          *ShareStore
 	vpcService          common.VPCServiceProvider
+	// ruleStatisticsLimiter limits the rate of rule statistics requests sent to NSX
+	ruleStatisticsLimiter ratelimiter.RateLimiter
 }
 
 type ProjectShare struct {
@@ -95,9 +98,10 @@ func InitializeSecurityPolicy(service common.Service, vpcService common.VPCServi
 	}}
 	securityPolicyService.groupStore = &GroupStore{ResourceStore: common.ResourceStore{
 		Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
-			indexScope:                      indexBySecurityPolicyUID,
-			common.TagScopeNetworkPolicyUID: indexByNetworkPolicyUID,
-			common.TagScopeRuleID:           indexGroupFunc,
+			indexScope:                       indexBySecurityPolicyUID,
+			common.TagScopeNetworkPolicyUID:  indexByNetworkPolicyUID,
+			common.TagScopeRuleID:            indexGroupFunc,
+			common.TagScopeAddressGroupCRUID: indexByAddressGroupUID,
 		}),
 		BindingType: model.GroupBindingType(),
 	}}
@@ -111,19 +115,24 @@ func InitializeSecurityPolicy(service common.Service, vpcService common.VPCServi
 
 	securityPolicyService.projectGroupStore = &GroupStore{ResourceStore: common.ResourceStore{
 		Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
-			indexScope:                      indexBySecurityPolicyUID,
-			common.TagScopeNetworkPolicyUID: indexByNetworkPolicyUID,
+			indexScope:                       indexBySecurityPolicyUID,
+			common.TagScopeNetworkPolicyUID:  indexByNetworkPolicyUID,
+			common.TagScopeAddressGroupCRUID: indexByAddressGroupUID,
 		}),
 		BindingType: model.GroupBindingType(),
 	}}
 	securityPolicyService.shareStore = &ShareStore{ResourceStore: common.ResourceStore{
 		Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
-			indexScope:                      indexBySecurityPolicyUID,
-			common.TagScopeNetworkPolicyUID: indexByNetworkPolicyUID,
+			indexScope:                       indexBySecurityPolicyUID,
+			common.TagScopeNetworkPolicyUID:  indexByNetworkPolicyUID,
+			common.TagScopeAddressGroupCRUID: indexByAddressGroupUID,
 		}),
 		BindingType: model.ShareBindingType(),
 	}}
 	securityPolicyService.vpcService = vpcService
+	if service.NSXConfig.EnableRuleStatistics {
+		securityPolicyService.ruleStatisticsLimiter = ratelimiter.NewFixRateLimiter(service.NSXConfig.GetRuleStatisticsRateLimit())
+	}
 
 	projectGroupShareTag := []model.Tag{
 		{
@@ -842,6 +851,22 @@ func (service *SecurityPolicyService) Cleanup(ctx context.Context) error {
 			}
 		}
 	}
+
+	// Delete all the groups created for AddressGroup in store, they are deleted after the
+	// security policies referencing them.
+	uids = service.ListAddressGroupID()
+	log.Info("cleaning up groups created for AddressGroup", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			err := service.DeleteAddressGroup(types.UID(uid))
+			if err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,160 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"time"
+	// Embed the time zone database since the operator image may not have it.
+	_ "time/tzdata"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+var weekdays = map[v1alpha1.Weekday]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// timeNow is replaced in UT.
+var timeNow = time.Now
+
+type scheduleWindow struct {
+	// days is nil if the window starts every day.
+	days sets.Set[time.Weekday]
+	// start and end are the minutes from midnight.
+	start int
+	end   int
+}
+
+type schedule struct {
+	location *time.Location
+	windows  []scheduleWindow
+}
+
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, it must be in the format of HH:MM", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func parseSchedule(s *v1alpha1.SecurityPolicySchedule) (*schedule, error) {
+	location, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", s.TimeZone, err)
+	}
+	if len(s.Windows) == 0 {
+		return nil, fmt.Errorf("schedule must have at least one window")
+	}
+	parsed := &schedule{location: location}
+	for _, w := range s.Windows {
+		window := scheduleWindow{}
+		if window.start, err = parseClock(w.Start); err != nil {
+			return nil, err
+		}
+		if window.end, err = parseClock(w.End); err != nil {
+			return nil, err
+		}
+		for _, day := range w.Days {
+			weekday, ok := weekdays[day]
+			if !ok {
+				return nil, fmt.Errorf("invalid day %q", day)
+			}
+			if window.days == nil {
+				window.days = sets.New[time.Weekday]()
+			}
+			window.days.Insert(weekday)
+		}
+		parsed.windows = append(parsed.windows, window)
+	}
+	return parsed, nil
+}
+
+// evaluate returns whether now is inside any window of the schedule and the next time
+// a window starts or ends after now.
+func (s *schedule) evaluate(now time.Time) (bool, time.Time) {
+	active := false
+	var next time.Time
+	updateNext := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	local := now.In(s.location)
+	// A window started yesterday may still be active, and every window starts in the next 7 days.
+	for offset := -1; offset <= 7; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, s.location)
+		for _, w := range s.windows {
+			if w.days != nil {
+				if !w.days.Has(day.Weekday()) {
+					continue
+				}
+			}
+			start := time.Date(day.Year(), day.Month(), day.Day(), w.start/60, w.start%60, 0, 0, s.location)
+			endDay := day
+			if w.end <= w.start {
+				endDay = day.AddDate(0, 0, 1)
+			}
+			end := time.Date(endDay.Year(), endDay.Month(), endDay.Day(), w.end/60, w.end%60, 0, 0, s.location)
+			if !now.Before(start) && now.Before(end) {
+				active = true
+			}
+			updateNext(start)
+			updateNext(end)
+		}
+	}
+	return active, next
+}
+
+func getRuleSchedule(obj *v1alpha1.SecurityPolicy, rule *v1alpha1.SecurityPolicyRule) *v1alpha1.SecurityPolicySchedule {
+	if rule.Schedule != nil {
+		return rule.Schedule
+	}
+	return obj.Spec.Schedule
+}
+
+// isRuleDisabled returns true if the rule has a schedule and now is outside its windows.
+func isRuleDisabled(obj *v1alpha1.SecurityPolicy, rule *v1alpha1.SecurityPolicyRule) (bool, error) {
+	ruleSchedule := getRuleSchedule(obj, rule)
+	if ruleSchedule == nil {
+		return false, nil
+	}
+	s, err := parseSchedule(ruleSchedule)
+	if err != nil {
+		return false, nsxutil.RestrictionError{Desc: err.Error()}
+	}
+	active, _ := s.evaluate(timeNow())
+	return !active, nil
+}
+
+// GetNextScheduleTransition returns the earliest time a rule of the SecurityPolicy will be enabled or
+// disabled by its schedule. A zero time is returned if none of the rules has a schedule.
+func (service *SecurityPolicyService) GetNextScheduleTransition(obj *v1alpha1.SecurityPolicy) time.Time {
+	var next time.Time
+	now := timeNow()
+	for i := range obj.Spec.Rules {
+		ruleSchedule := getRuleSchedule(obj, &obj.Spec.Rules[i])
+		if ruleSchedule == nil {
+			continue
+		}
+		s, err := parseSchedule(ruleSchedule)
+		if err != nil {
+			continue
+		}
+		if _, t := s.evaluate(now); !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
@@ -0,0 +1,123 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestScheduleEvaluate(t *testing.T) {
+	// 2024-03-02 is a Saturday.
+	maintenance := &v1alpha1.SecurityPolicySchedule{
+		TimeZone: "America/Los_Angeles",
+		Windows:  []v1alpha1.ScheduleWindow{{Days: []v1alpha1.Weekday{"Sat"}, Start: "02:00", End: "06:00"}},
+	}
+	overnight := &v1alpha1.SecurityPolicySchedule{
+		Windows: []v1alpha1.ScheduleWindow{{Start: "22:00", End: "02:00"}},
+	}
+	la, _ := time.LoadLocation("America/Los_Angeles")
+
+	tests := []struct {
+		name       string
+		schedule   *v1alpha1.SecurityPolicySchedule
+		now        time.Time
+		wantActive bool
+		wantNext   time.Time
+	}{
+		{
+			name:       "before-window",
+			schedule:   maintenance,
+			now:        time.Date(2024, 3, 2, 1, 0, 0, 0, la),
+			wantActive: false,
+			wantNext:   time.Date(2024, 3, 2, 2, 0, 0, 0, la),
+		},
+		{
+			name:       "window-start",
+			schedule:   maintenance,
+			now:        time.Date(2024, 3, 2, 2, 0, 0, 0, la),
+			wantActive: true,
+			wantNext:   time.Date(2024, 3, 2, 6, 0, 0, 0, la),
+		},
+		{
+			name:       "after-window",
+			schedule:   maintenance,
+			now:        time.Date(2024, 3, 2, 6, 0, 0, 0, la),
+			wantActive: false,
+			wantNext:   time.Date(2024, 3, 9, 2, 0, 0, 0, la),
+		},
+		{
+			name:       "overnight-started-yesterday",
+			schedule:   overnight,
+			now:        time.Date(2024, 3, 2, 1, 0, 0, 0, time.UTC),
+			wantActive: true,
+			wantNext:   time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "overnight-inactive",
+			schedule:   overnight,
+			now:        time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC),
+			wantActive: false,
+			wantNext:   time.Date(2024, 3, 2, 22, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseSchedule(tt.schedule)
+			assert.Nil(t, err)
+			active, next := s.evaluate(tt.now)
+			assert.Equal(t, tt.wantActive, active)
+			assert.True(t, tt.wantNext.Equal(next), "expected %v, got %v", tt.wantNext, next)
+		})
+	}
+
+	_, err := parseSchedule(&v1alpha1.SecurityPolicySchedule{TimeZone: "Mars/Base", Windows: overnight.Windows})
+	assert.NotNil(t, err)
+	_, err = parseSchedule(&v1alpha1.SecurityPolicySchedule{Windows: []v1alpha1.ScheduleWindow{{Start: "25:00", End: "02:00"}}})
+	assert.NotNil(t, err)
+}
+
+func TestRuleSchedule(t *testing.T) {
+	defer func() { timeNow = time.Now }()
+	timeNow = func() time.Time { return time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC) }
+
+	sp := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "spA", UID: "uidA"},
+		Spec: v1alpha1.SecurityPolicySpec{
+			Schedule: &v1alpha1.SecurityPolicySchedule{
+				Windows: []v1alpha1.ScheduleWindow{{Start: "08:00", End: "18:00"}},
+			},
+			Rules: []v1alpha1.SecurityPolicyRule{
+				{Name: "policy-schedule"},
+				{
+					Name: "rule-schedule",
+					Schedule: &v1alpha1.SecurityPolicySchedule{
+						Windows: []v1alpha1.ScheduleWindow{{Start: "13:00", End: "14:00"}},
+					},
+				},
+			},
+		},
+	}
+	disabled, err := isRuleDisabled(sp, &sp.Spec.Rules[0])
+	assert.Nil(t, err)
+	assert.False(t, disabled)
+	disabled, err = isRuleDisabled(sp, &sp.Spec.Rules[1])
+	assert.Nil(t, err)
+	assert.True(t, disabled)
+
+	next := service.GetNextScheduleTransition(sp)
+	assert.True(t, time.Date(2024, 3, 2, 13, 0, 0, 0, time.UTC).Equal(next))
+
+	sp.Spec.Schedule = nil
+	sp.Spec.Rules = sp.Spec.Rules[:1]
+	disabled, err = isRuleDisabled(sp, &sp.Spec.Rules[0])
+	assert.Nil(t, err)
+	assert.False(t, disabled)
+	assert.True(t, service.GetNextScheduleTransition(sp).IsZero())
+}
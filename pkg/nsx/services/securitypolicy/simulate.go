@@ -0,0 +1,557 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	vmv1alpha1 "github.com/vmware-tanzu/vm-operator/api/v1alpha1"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+// SimulationEndpoint is the source or destination of a simulated flow.
+// Only one of Pod, VM and Namespace is expected to be set.
+type SimulationEndpoint struct {
+	// Pod is the Pod in the format of <namespace>/<name>.
+	Pod string `json:"pod,omitempty"`
+	// VM is the VirtualMachine in the format of <namespace>/<name>.
+	VM string `json:"vm,omitempty"`
+	// Namespace stands for a workload without labels in the Namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// IP is the IP address of the endpoint. If none of the above is set, the endpoint is
+	// treated as an address outside of the cluster.
+	IP string `json:"ip,omitempty"`
+}
+
+// SimulationFlow is the flow to simulate.
+type SimulationFlow struct {
+	Source      SimulationEndpoint `json:"source"`
+	Destination SimulationEndpoint `json:"destination"`
+	// Protocol is the L4 protocol of the flow, TCP by default.
+	Protocol string `json:"protocol,omitempty"`
+	// Port is the destination port of the flow.
+	Port int `json:"port"`
+}
+
+// SimulationRule is a realized NSX rule matching the simulated flow.
+type SimulationRule struct {
+	PolicyID   string `json:"policyID"`
+	PolicyName string `json:"policyName"`
+	RuleID     string `json:"ruleID"`
+	RuleName   string `json:"ruleName"`
+	// Direction is In for the rules enforced on the destination, Out for the rules enforced on the source.
+	Direction string `json:"direction"`
+	Action    string `json:"action"`
+	// Unresolved is true if the rule may match the flow, but its groups can't be evaluated from the stores,
+	// e.g. the groups referencing NSX paths not managed by nsx-operator.
+	Unresolved bool `json:"unresolved,omitempty"`
+}
+
+// SimulationResult is the verdict of a simulated flow.
+type SimulationResult struct {
+	// Verdict is Allow, Drop or Reject. It's Unknown if an unresolved rule is enforced before the rules
+	// deciding the verdict.
+	Verdict string `json:"verdict"`
+	// EgressRule is the first rule matching the flow on the source, nil if the flow is allowed by default.
+	EgressRule *SimulationRule `json:"egressRule,omitempty"`
+	// IngressRule is the first rule matching the flow on the destination, nil if the flow is allowed by default.
+	IngressRule *SimulationRule `json:"ingressRule,omitempty"`
+	// MatchedRules are all the rules matching the flow in the order of enforcement.
+	MatchedRules []SimulationRule `json:"matchedRules"`
+}
+
+// simulationEndpoint holds the attributes of an endpoint used to evaluate the NSX group membership.
+type simulationEndpoint struct {
+	ips []net.IP
+	// portTags are the tags on the NSX port of the workload, nil if the endpoint is not a workload.
+	portTags map[string]string
+	// segmentTags are the tags on the NSX segment of the workload Namespace.
+	segmentTags map[string]string
+}
+
+// VerdictUnknown is the verdict of the flow when an unresolved rule may decide it.
+const VerdictUnknown = "Unknown"
+
+// maxGroupPathDepth limits the groups resolved from the paths of PathExpression, to stop at circular references.
+const maxGroupPathDepth = 8
+
+// matchResult is the three-valued result of evaluating the NSX group membership, the membership is unknown
+// if any expression can't be evaluated from the stores.
+type matchResult int
+
+const (
+	matchFalse matchResult = iota
+	matchTrue
+	matchUnknown
+)
+
+func (a matchResult) and(b matchResult) matchResult {
+	if a == matchFalse || b == matchFalse {
+		return matchFalse
+	}
+	if a == matchUnknown || b == matchUnknown {
+		return matchUnknown
+	}
+	return matchTrue
+}
+
+func (a matchResult) or(b matchResult) matchResult {
+	if a == matchTrue || b == matchTrue {
+		return matchTrue
+	}
+	if a == matchUnknown || b == matchUnknown {
+		return matchUnknown
+	}
+	return matchFalse
+}
+
+func matchBool(matched bool) matchResult {
+	if matched {
+		return matchTrue
+	}
+	return matchFalse
+}
+
+var nsxActionToRuleAction = map[string]string{
+	"ALLOW":  string(v1alpha1.RuleActionAllow),
+	"DROP":   string(v1alpha1.RuleActionDrop),
+	"REJECT": string(v1alpha1.RuleActionReject),
+}
+
+// SimulateFlow computes which realized SecurityPolicy and NetworkPolicy rules match the flow and the verdict
+// of the flow. It only reads the NSX resources cached in the stores and the Kubernetes objects, NSX is not
+// called. The NSX default rule is assumed to allow the flow.
+func (service *SecurityPolicyService) SimulateFlow(ctx context.Context, flow SimulationFlow) (*SimulationResult, error) {
+	src, err := service.resolveSimulationEndpoint(ctx, flow.Source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source: %w", err)
+	}
+	dst, err := service.resolveSimulationEndpoint(ctx, flow.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination: %w", err)
+	}
+	protocol := strings.ToUpper(flow.Protocol)
+	if protocol == "" {
+		protocol = string(corev1.ProtocolTCP)
+	}
+
+	result := &SimulationResult{Verdict: string(v1alpha1.RuleActionAllow), MatchedRules: []SimulationRule{}}
+	for _, policy := range service.listSortedSecurityPolicies() {
+		for _, rule := range service.listSortedRules(policy) {
+			if rule.Disabled != nil && *rule.Disabled {
+				continue
+			}
+			// The rule is enforced on the source for egress, and on the destination for ingress.
+			enforcedOn := dst
+			if rule.Direction != nil && *rule.Direction == "OUT" {
+				enforcedOn = src
+			}
+			scope := rule.Scope
+			if isAnyPath(scope) {
+				scope = policy.Scope
+			}
+			matchedResult := service.matchGroups(scope, enforcedOn, 0).and(service.matchGroups(rule.SourceGroups, src, 0)).
+				and(service.matchGroups(rule.DestinationGroups, dst, 0)).and(matchBool(matchServiceEntries(rule.ServiceEntries, protocol, flow.Port)))
+			if matchedResult == matchFalse {
+				continue
+			}
+			matched := SimulationRule{
+				PolicyID:   *policy.Id,
+				PolicyName: stringValue(policy.DisplayName),
+				RuleID:     *rule.Id,
+				RuleName:   stringValue(rule.DisplayName),
+				Direction:  "In",
+				Action:     nsxActionToRuleAction[stringValue(rule.Action)],
+				Unresolved: matchedResult == matchUnknown,
+			}
+			if enforcedOn == src {
+				matched.Direction = "Out"
+			}
+			result.MatchedRules = append(result.MatchedRules, matched)
+			if enforcedOn == src && result.EgressRule == nil {
+				result.EgressRule = &result.MatchedRules[len(result.MatchedRules)-1]
+			} else if enforcedOn == dst && result.IngressRule == nil {
+				result.IngressRule = &result.MatchedRules[len(result.MatchedRules)-1]
+			}
+		}
+	}
+	// Take copies since the pointers to the slice elements are invalidated by append.
+	if result.EgressRule != nil {
+		egressRule := *result.EgressRule
+		result.EgressRule = &egressRule
+	}
+	if result.IngressRule != nil {
+		ingressRule := *result.IngressRule
+		result.IngressRule = &ingressRule
+	}
+	// The first rule of each direction decides, an unresolved one makes the verdict unknown.
+	if result.EgressRule != nil && result.EgressRule.Unresolved {
+		result.Verdict = VerdictUnknown
+	} else if result.EgressRule != nil && result.EgressRule.Action != string(v1alpha1.RuleActionAllow) {
+		result.Verdict = result.EgressRule.Action
+	} else if result.IngressRule != nil && result.IngressRule.Unresolved {
+		result.Verdict = VerdictUnknown
+	} else if result.IngressRule != nil {
+		result.Verdict = result.IngressRule.Action
+	}
+	return result, nil
+}
+
+func (service *SecurityPolicyService) listSortedSecurityPolicies() []*model.SecurityPolicy {
+	var policies []*model.SecurityPolicy
+	for _, obj := range service.securityPolicyStore.List() {
+		policies = append(policies, obj.(*model.SecurityPolicy))
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if sequenceValue(policies[i].SequenceNumber) != sequenceValue(policies[j].SequenceNumber) {
+			return sequenceValue(policies[i].SequenceNumber) < sequenceValue(policies[j].SequenceNumber)
+		}
+		return *policies[i].Id < *policies[j].Id
+	})
+	return policies
+}
+
+// listSortedRules returns the rules of the policy, the rule ID is prefixed with the policy ID.
+func (service *SecurityPolicyService) listSortedRules(policy *model.SecurityPolicy) []*model.Rule {
+	var rules []*model.Rule
+	for _, obj := range service.ruleStore.List() {
+		rule := obj.(*model.Rule)
+		if strings.HasPrefix(*rule.Id, *policy.Id+"_") {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if sequenceValue(rules[i].SequenceNumber) != sequenceValue(rules[j].SequenceNumber) {
+			return sequenceValue(rules[i].SequenceNumber) < sequenceValue(rules[j].SequenceNumber)
+		}
+		return *rules[i].Id < *rules[j].Id
+	})
+	return rules
+}
+
+func (service *SecurityPolicyService) resolveSimulationEndpoint(ctx context.Context, endpoint SimulationEndpoint) (*simulationEndpoint, error) {
+	resolved := &simulationEndpoint{}
+	var namespace string
+	var labels map[string]string
+	isVM := false
+	switch {
+	case endpoint.Pod != "":
+		name, err := parseNamespacedName(endpoint.Pod)
+		if err != nil {
+			return nil, err
+		}
+		pod := &corev1.Pod{}
+		if err := service.Client.Get(ctx, name, pod); err != nil {
+			return nil, err
+		}
+		namespace, labels = pod.Namespace, pod.Labels
+		for _, podIP := range pod.Status.PodIPs {
+			resolved.ips = append(resolved.ips, net.ParseIP(podIP.IP))
+		}
+		resolved.portTags = map[string]string{getScopePodTag(service): string(pod.UID)}
+	case endpoint.VM != "":
+		name, err := parseNamespacedName(endpoint.VM)
+		if err != nil {
+			return nil, err
+		}
+		vm := &vmv1alpha1.VirtualMachine{}
+		if err := service.Client.Get(ctx, name, vm); err != nil {
+			return nil, err
+		}
+		namespace, labels = vm.Namespace, vm.Labels
+		if vm.Status.VmIp != "" {
+			resolved.ips = append(resolved.ips, net.ParseIP(vm.Status.VmIp))
+		}
+		isVM = true
+		resolved.portTags = map[string]string{getScopeVMInterfaceTag(service): string(vm.UID)}
+	case endpoint.Namespace != "":
+		namespace = endpoint.Namespace
+		resolved.portTags = map[string]string{getScopePodTag(service): "simulated"}
+	}
+
+	if endpoint.IP != "" {
+		ip := net.ParseIP(endpoint.IP)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP %q", endpoint.IP)
+		}
+		resolved.ips = []net.IP{ip}
+	}
+	if resolved.portTags == nil {
+		if len(resolved.ips) == 0 {
+			return nil, fmt.Errorf("one of pod, vm, namespace and ip must be set")
+		}
+		return resolved, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := service.Client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return nil, err
+	}
+	resolved.portTags[getScopeCluserTag(service)] = getCluster(service)
+	resolved.portTags[getScopeNamespaceUIDTag(service, isVM)] = string(ns.UID)
+	for k, v := range *util.NormalizeLabels(&labels) {
+		resolved.portTags[k] = v
+	}
+	resolved.segmentTags = map[string]string{getScopeCluserTag(service): getCluster(service)}
+	for k, v := range *util.NormalizeLabels(&ns.Labels) {
+		resolved.segmentTags[k] = v
+	}
+	return resolved, nil
+}
+
+// matchGroups returns whether the endpoint is a member of any group in the paths. The membership is unknown
+// if a group isn't found in the stores.
+func (service *SecurityPolicyService) matchGroups(paths []string, endpoint *simulationEndpoint, depth int) matchResult {
+	if isAnyPath(paths) {
+		return matchTrue
+	}
+	result := matchFalse
+	for _, path := range paths {
+		id := path[strings.LastIndex(path, "/")+1:]
+		group := service.groupStore.GetByKey(id)
+		if group == nil && service.projectGroupStore != nil {
+			group = service.projectGroupStore.GetByKey(id)
+		}
+		if group == nil || stringValue(group.Path) != "" && stringValue(group.Path) != path {
+			log.V(1).Info("group is not found in store", "path", path)
+			result = result.or(matchUnknown)
+			continue
+		}
+		expressions := make([]data.DataValue, 0, len(group.Expression))
+		for _, expression := range group.Expression {
+			expressions = append(expressions, expression)
+		}
+		result = result.or(service.evaluateExpressions(expressions, endpoint, depth))
+		if result == matchTrue {
+			return result
+		}
+	}
+	return result
+}
+
+// evaluateExpressions evaluates a list of NSX group expressions joined by conjunction operators,
+// AND takes precedence over OR. The paths of PathExpression are resolved by the groups in the stores,
+// the expressions which can't be evaluated are unknown.
+func (service *SecurityPolicyService) evaluateExpressions(expressions []data.DataValue, endpoint *simulationEndpoint, depth int) matchResult {
+	result, term, hasTerm := matchFalse, matchTrue, false
+	for _, value := range expressions {
+		expression, ok := value.(*data.StructValue)
+		if !ok {
+			continue
+		}
+		switch structString(expression, "resource_type") {
+		case "ConjunctionOperator":
+			if structString(expression, "conjunction_operator") == "OR" {
+				if hasTerm {
+					result = result.or(term)
+				}
+				term, hasTerm = matchTrue, false
+			}
+			continue
+		case "Condition":
+			term = term.and(matchBool(evaluateCondition(expression, endpoint)))
+		case "NestedExpression":
+			nested, err := expression.List("expressions")
+			if err != nil {
+				term = term.and(matchUnknown)
+			} else {
+				term = term.and(service.evaluateExpressions(nested.List(), endpoint, depth))
+			}
+		case "IPAddressExpression":
+			addresses, err := expression.List("ip_addresses")
+			term = term.and(matchBool(err == nil && matchIPAddresses(addresses.List(), endpoint.ips)))
+		case "PathExpression":
+			term = term.and(service.evaluatePathExpression(expression, endpoint, depth))
+		default:
+			term = term.and(matchUnknown)
+		}
+		hasTerm = true
+	}
+	if hasTerm {
+		result = result.or(term)
+	}
+	return result
+}
+
+// evaluatePathExpression returns whether the endpoint is a member of any group in the paths. Only the paths
+// of the groups are resolved, the membership of the other paths such as segments is unknown.
+func (service *SecurityPolicyService) evaluatePathExpression(expression *data.StructValue, endpoint *simulationEndpoint, depth int) matchResult {
+	paths, err := expression.List("paths")
+	if err != nil || depth >= maxGroupPathDepth {
+		return matchUnknown
+	}
+	var groupPaths []string
+	result := matchFalse
+	for _, value := range paths.List() {
+		path, ok := value.(*data.StringValue)
+		if !ok || !strings.Contains(path.Value(), "/groups/") {
+			result = matchUnknown
+			continue
+		}
+		groupPaths = append(groupPaths, path.Value())
+	}
+	if len(groupPaths) == 0 {
+		return result
+	}
+	return result.or(service.matchGroups(groupPaths, endpoint, depth+1))
+}
+
+func evaluateCondition(expression *data.StructValue, endpoint *simulationEndpoint) bool {
+	tags := endpoint.portTags
+	if structString(expression, "member_type") == "Segment" {
+		tags = endpoint.segmentTags
+	}
+	if tags == nil {
+		return false
+	}
+	scope, value, _ := strings.Cut(structString(expression, "value"), "|")
+	tag, exists := tags[scope]
+	if structString(expression, "scope_operator") == "NOTEQUALS" {
+		return !exists
+	}
+	switch structString(expression, "operator") {
+	case "EQUALS":
+		if value == "" {
+			return exists
+		}
+		return exists && tag == value
+	case "NOTIN":
+		if !exists {
+			return true
+		}
+		for _, v := range strings.Split(value, ",") {
+			if v == tag {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// matchIPAddresses returns true if any of the IPs is in the NSX IP addresses, which may be
+// an IP, a CIDR or an IP range.
+func matchIPAddresses(addresses []data.DataValue, ips []net.IP) bool {
+	for _, value := range addresses {
+		address, ok := value.(*data.StringValue)
+		if !ok {
+			continue
+		}
+		for _, ip := range ips {
+			if matchIPAddress(address.Value(), ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchIPAddress(address string, ip net.IP) bool {
+	if _, ipNet, err := net.ParseCIDR(address); err == nil {
+		return ipNet.Contains(ip)
+	}
+	if start, end, found := strings.Cut(address, "-"); found {
+		startIP, endIP := net.ParseIP(start), net.ParseIP(end)
+		if startIP == nil || endIP == nil || ip.To4() == nil != (startIP.To4() == nil) {
+			return false
+		}
+		return compareIP(ip, startIP) >= 0 && compareIP(ip, endIP) <= 0
+	}
+	addressIP := net.ParseIP(address)
+	return addressIP != nil && addressIP.Equal(ip)
+}
+
+func compareIP(a, b net.IP) int {
+	if a.To4() != nil {
+		a, b = a.To4(), b.To4()
+	} else {
+		a, b = a.To16(), b.To16()
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return int(a[i]) - int(b[i])
+		}
+	}
+	return 0
+}
+
+// matchServiceEntries returns true if the rule has no service entries, or any L4 service entry matches
+// the protocol and the destination port.
+func matchServiceEntries(serviceEntries []*data.StructValue, protocol string, port int) bool {
+	if len(serviceEntries) == 0 {
+		return true
+	}
+	for _, entry := range serviceEntries {
+		entryProtocol := structString(entry, "l4_protocol")
+		if entryProtocol != "" && !strings.EqualFold(entryProtocol, protocol) {
+			continue
+		}
+		ports, err := entry.List("destination_ports")
+		if err != nil || ports.IsEmpty() {
+			return true
+		}
+		for _, value := range ports.List() {
+			portRange, ok := value.(*data.StringValue)
+			if ok && matchPortRange(portRange.Value(), port) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchPortRange(portRange string, port int) bool {
+	start, end, found := strings.Cut(portRange, "-")
+	if !found {
+		end = start
+	}
+	startPort, err1 := strconv.Atoi(start)
+	endPort, err2 := strconv.Atoi(end)
+	return err1 == nil && err2 == nil && port >= startPort && port <= endPort
+}
+
+func structString(structValue *data.StructValue, field string) string {
+	value, err := structValue.String(field)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+func isAnyPath(paths []string) bool {
+	return len(paths) == 0 || (len(paths) == 1 && paths[0] == "ANY")
+}
+
+func parseNamespacedName(name string) (types.NamespacedName, error) {
+	namespace, n, found := strings.Cut(name, "/")
+	if !found || namespace == "" || n == "" {
+		return types.NamespacedName{}, fmt.Errorf("%q is not in the format of <namespace>/<name>", name)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: n}, nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func sequenceValue(seq *int64) int64 {
+	if seq == nil {
+		return 0
+	}
+	return *seq
+}
@@ -0,0 +1,278 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func newSimulationService(t *testing.T, policies ...*v1alpha1.SecurityPolicy) *SecurityPolicyService {
+	objs := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1", UID: "ns1-uid", Labels: map[string]string{"env": "prod"}}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "web", UID: "web-uid", Labels: map[string]string{"role": "web"}},
+			Status:     corev1.PodStatus{PodIPs: []corev1.PodIP{{IP: "10.0.0.2"}}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "db", UID: "db-uid", Labels: map[string]string{"role": "db"}},
+			Status:     corev1.PodStatus{PodIPs: []corev1.PodIP{{IP: "10.0.0.3"}}},
+		},
+	}
+	s := &SecurityPolicyService{
+		Service: common.Service{
+			Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(objs...).Build(),
+			NSXConfig: &config.NSXOperatorConfig{
+				CoeConfig: &config.CoeConfig{Cluster: "k8scl-one"},
+			},
+		},
+	}
+	s.securityPolicyStore = &SecurityPolicyStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagValueScopeSecurityPolicyUID: indexBySecurityPolicyUID}),
+		BindingType: model.SecurityPolicyBindingType(),
+	}}
+	s.groupStore = &GroupStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagValueScopeSecurityPolicyUID: indexBySecurityPolicyUID}),
+		BindingType: model.GroupBindingType(),
+	}}
+	s.ruleStore = &RuleStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagValueScopeSecurityPolicyUID: indexBySecurityPolicyUID}),
+		BindingType: model.RuleBindingType(),
+	}}
+	for _, policy := range policies {
+		nsxPolicy, nsxGroups, _, err := s.buildSecurityPolicy(policy, common.ResourceTypeSecurityPolicy)
+		require.NoError(t, err)
+		require.NoError(t, s.ruleStore.Apply(nsxPolicy))
+		require.NoError(t, s.groupStore.Apply(nsxGroups))
+		require.NoError(t, s.securityPolicyStore.Apply(nsxPolicy))
+	}
+	return s
+}
+
+func TestSimulateFlow(t *testing.T) {
+	port := intstr.FromInt(5432)
+	allow, drop := v1alpha1.RuleActionAllow, v1alpha1.RuleActionDrop
+	policy := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "db-policy", UID: "sp-uid"},
+		Spec: v1alpha1.SecurityPolicySpec{
+			AppliedTo: []v1alpha1.SecurityPolicyTarget{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "db"}}},
+			},
+			Rules: []v1alpha1.SecurityPolicyRule{
+				{
+					Name:      "allow-web",
+					Action:    &allow,
+					Direction: &directionIn,
+					Sources: []v1alpha1.SecurityPolicyPeer{
+						{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"role": "web"}}},
+					},
+					Ports: []v1alpha1.SecurityPolicyPort{{Protocol: "TCP", Port: port}},
+				},
+				{
+					Name:      "drop-all",
+					Action:    &drop,
+					Direction: &directionIn,
+				},
+			},
+		},
+	}
+	s := newSimulationService(t, policy)
+
+	tests := []struct {
+		name        string
+		flow        SimulationFlow
+		verdict     string
+		ingressRule string
+		matched     int
+	}{
+		{
+			name: "allowed by rule",
+			flow: SimulationFlow{
+				Source:      SimulationEndpoint{Pod: "ns1/web"},
+				Destination: SimulationEndpoint{Pod: "ns1/db"},
+				Port:        5432,
+			},
+			verdict:     "Allow",
+			ingressRule: "allow-web",
+			matched:     2,
+		},
+		{
+			name: "port not allowed",
+			flow: SimulationFlow{
+				Source:      SimulationEndpoint{Pod: "ns1/web"},
+				Destination: SimulationEndpoint{Pod: "ns1/db"},
+				Port:        22,
+			},
+			verdict:     "Drop",
+			ingressRule: "drop-all",
+			matched:     1,
+		},
+		{
+			name: "source not allowed",
+			flow: SimulationFlow{
+				Source:      SimulationEndpoint{IP: "192.168.1.1"},
+				Destination: SimulationEndpoint{Pod: "ns1/db"},
+				Protocol:    "tcp",
+				Port:        5432,
+			},
+			verdict:     "Drop",
+			ingressRule: "drop-all",
+			matched:     1,
+		},
+		{
+			name: "destination not applied",
+			flow: SimulationFlow{
+				Source:      SimulationEndpoint{Pod: "ns1/db"},
+				Destination: SimulationEndpoint{Pod: "ns1/web"},
+				Port:        80,
+			},
+			verdict: "Allow",
+			matched: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := s.SimulateFlow(context.TODO(), tt.flow)
+			require.NoError(t, err)
+			assert.Equal(t, tt.verdict, result.Verdict)
+			assert.Len(t, result.MatchedRules, tt.matched)
+			assert.Nil(t, result.EgressRule)
+			if tt.ingressRule == "" {
+				assert.Nil(t, result.IngressRule)
+			} else {
+				require.NotNil(t, result.IngressRule)
+				assert.Contains(t, result.IngressRule.RuleName, tt.ingressRule)
+				assert.Equal(t, "In", result.IngressRule.Direction)
+			}
+		})
+	}
+
+	// The verdict is unknown if the source group of the first matching rule can't be resolved.
+	for _, obj := range s.ruleStore.List() {
+		rule := obj.(*model.Rule)
+		if !strings.Contains(*rule.DisplayName, "allow-web") {
+			continue
+		}
+		id := rule.SourceGroups[0][strings.LastIndex(rule.SourceGroups[0], "/")+1:]
+		group := *s.groupStore.GetByKey(id)
+		group.MarkedForDelete = Bool(true)
+		require.NoError(t, s.groupStore.Apply(&[]model.Group{group}))
+	}
+	result, err := s.SimulateFlow(context.TODO(), SimulationFlow{
+		Source:      SimulationEndpoint{Pod: "ns1/web"},
+		Destination: SimulationEndpoint{Pod: "ns1/db"},
+		Port:        5432,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, VerdictUnknown, result.Verdict)
+	require.NotNil(t, result.IngressRule)
+	assert.True(t, result.IngressRule.Unresolved)
+	assert.Len(t, result.MatchedRules, 2)
+
+	_, err = s.SimulateFlow(context.TODO(), SimulationFlow{Source: SimulationEndpoint{Pod: "ns1"}, Destination: SimulationEndpoint{IP: "10.0.0.3"}})
+	assert.Error(t, err)
+	_, err = s.SimulateFlow(context.TODO(), SimulationFlow{Source: SimulationEndpoint{}, Destination: SimulationEndpoint{IP: "10.0.0.3"}})
+	assert.Error(t, err)
+}
+
+func TestEvaluateExpressions(t *testing.T) {
+	condition := func(value, operator string) *data.StructValue {
+		return data.NewStructValue("", map[string]data.DataValue{
+			"resource_type":  data.NewStringValue("Condition"),
+			"member_type":    data.NewStringValue("SegmentPort"),
+			"key":            data.NewStringValue("Tag"),
+			"value":          data.NewStringValue(value),
+			"operator":       data.NewStringValue(operator),
+			"scope_operator": data.NewStringValue("EQUALS"),
+		})
+	}
+	conjunction := func(operator string) *data.StructValue {
+		return data.NewStructValue("", map[string]data.DataValue{
+			"resource_type":        data.NewStringValue("ConjunctionOperator"),
+			"conjunction_operator": data.NewStringValue(operator),
+		})
+	}
+	ipAddresses := data.NewStructValue("", map[string]data.DataValue{
+		"resource_type": data.NewStringValue("IPAddressExpression"),
+		"ip_addresses":  data.NewListValue(),
+	})
+	addresses, _ := ipAddresses.List("ip_addresses")
+	addresses.Add(data.NewStringValue("10.0.0.0/24"))
+	addresses.Add(data.NewStringValue("10.0.1.10-10.0.1.20"))
+
+	pathExpression := func(paths ...string) *data.StructValue {
+		list := data.NewListValue()
+		for _, path := range paths {
+			list.Add(data.NewStringValue(path))
+		}
+		return data.NewStructValue("", map[string]data.DataValue{
+			"resource_type": data.NewStringValue("PathExpression"),
+			"paths":         list,
+		})
+	}
+	s := newSimulationService(t)
+	webGroupPath := "/infra/domains/default/groups/web"
+	require.NoError(t, s.groupStore.Apply(&[]model.Group{{
+		Id:         String("web"),
+		Path:       String(webGroupPath),
+		Expression: []*data.StructValue{condition("role|web", "EQUALS")},
+	}}))
+
+	endpoint := &simulationEndpoint{
+		ips:      []net.IP{net.ParseIP("10.0.1.15")},
+		portTags: map[string]string{"role": "web", "env": "prod"},
+	}
+	tests := []struct {
+		name        string
+		expressions []data.DataValue
+		expected    matchResult
+	}{
+		{"empty", []data.DataValue{}, matchFalse},
+		{"equals", []data.DataValue{condition("role|web", "EQUALS")}, matchTrue},
+		{"exists", []data.DataValue{condition("role|", "EQUALS")}, matchTrue},
+		{"not in", []data.DataValue{condition("role|db,web", "NOTIN")}, matchFalse},
+		{"and", []data.DataValue{condition("role|web", "EQUALS"), conjunction("AND"), condition("env|dev", "EQUALS")}, matchFalse},
+		{"or", []data.DataValue{condition("role|db", "EQUALS"), conjunction("OR"), condition("env|prod", "EQUALS")}, matchTrue},
+		{
+			"and binds tighter than or",
+			[]data.DataValue{
+				condition("role|db", "EQUALS"), conjunction("OR"),
+				condition("role|web", "EQUALS"), conjunction("AND"), condition("env|dev", "EQUALS"),
+			},
+			matchFalse,
+		},
+		{"ip range", []data.DataValue{ipAddresses}, matchTrue},
+		{"group path", []data.DataValue{pathExpression(webGroupPath)}, matchTrue},
+		{"group path not in store", []data.DataValue{pathExpression("/infra/domains/default/groups/unknown")}, matchUnknown},
+		{"segment path", []data.DataValue{pathExpression("/infra/segments/seg1")}, matchUnknown},
+		{"unknown or match", []data.DataValue{pathExpression("/infra/segments/seg1"), conjunction("OR"), condition("role|web", "EQUALS")}, matchTrue},
+		{"unknown and mismatch", []data.DataValue{pathExpression("/infra/segments/seg1"), conjunction("AND"), condition("role|db", "EQUALS")}, matchFalse},
+		{"unsupported expression", []data.DataValue{data.NewStructValue("", map[string]data.DataValue{
+			"resource_type": data.NewStringValue("MACAddressExpression"),
+		})}, matchUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, s.evaluateExpressions(tt.expressions, endpoint, 0))
+		})
+	}
+}
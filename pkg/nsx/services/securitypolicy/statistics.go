@@ -0,0 +1,149 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// GetRuleStatistics reads the NSX statistics of the rules realized for a SecurityPolicy or NetworkPolicy,
+// and aggregates them per SecurityPolicyRule name. A rule containing named port may be expanded to
+// multiple NSX rules, the statistics of these NSX rules are summed up.
+func (service *SecurityPolicyService) GetRuleStatistics(obj interface{}) ([]v1alpha1.SecurityPolicyRuleStatistics, error) {
+	switch o := obj.(type) {
+	case *networkingv1.NetworkPolicy:
+		internalSecurityPolicies, err := service.convertNetworkPolicyToInternalSecurityPolicies(o)
+		if err != nil {
+			return nil, err
+		}
+		var ruleStatistics []v1alpha1.SecurityPolicyRuleStatistics
+		for _, internalSecurityPolicy := range internalSecurityPolicies {
+			statistics, err := service.getRuleStatistics(internalSecurityPolicy, common.ResourceTypeNetworkPolicy)
+			if err != nil {
+				return nil, err
+			}
+			ruleStatistics = append(ruleStatistics, statistics...)
+		}
+		return ruleStatistics, nil
+	case *v1alpha1.SecurityPolicy:
+		return service.getRuleStatistics(o, common.ResourceTypeSecurityPolicy)
+	}
+	return nil, fmt.Errorf("unsupported object type %T for rule statistics", obj)
+}
+
+func (service *SecurityPolicyService) getRuleStatistics(obj *v1alpha1.SecurityPolicy, createdFor string) ([]v1alpha1.SecurityPolicyRuleStatistics, error) {
+	nsxSecurityPolicy := service.securityPolicyStore.GetByKey(service.buildecurityPolicyID(obj, createdFor))
+	if nsxSecurityPolicy == nil {
+		log.V(1).Info("NSX security policy is not found in store, skip collecting rule statistics", "securityPolicy", obj.Name, "namespace", obj.Namespace)
+		return nil, nil
+	}
+
+	results, err := service.listSecurityPolicyStatistics(nsxSecurityPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleStatistics := make([]v1alpha1.SecurityPolicyRuleStatistics, len(obj.Spec.Rules))
+	for ruleIdx := range obj.Spec.Rules {
+		ruleStatistics[ruleIdx].Name = service.buildRuleStatisticsName(obj, ruleIdx)
+	}
+	// NSX rule ID is in the format of <prefix>_<uid>_<ruleIdx>_<hash>_<portIdx>_<portAddressIdx>.
+	prefix := common.SecurityPolicyPrefix
+	if createdFor == common.ResourceTypeNetworkPolicy {
+		prefix = common.NetworkPolicyPrefix
+	}
+	ruleIDPrefix := fmt.Sprintf("%s_%s_", prefix, obj.UID)
+	for _, result := range results {
+		if result.Statistics == nil {
+			continue
+		}
+		for _, stats := range result.Statistics.Results {
+			if stats.Rule == nil {
+				continue
+			}
+			ruleID := (*stats.Rule)[strings.LastIndex(*stats.Rule, "/")+1:]
+			if !strings.HasPrefix(ruleID, ruleIDPrefix) {
+				continue
+			}
+			ruleIdx, err := strconv.Atoi(strings.Split(strings.TrimPrefix(ruleID, ruleIDPrefix), "_")[0])
+			if err != nil || ruleIdx >= len(ruleStatistics) {
+				log.V(1).Info("NSX rule doesn't match any rule of the security policy", "ruleID", ruleID, "securityPolicy", obj.Name)
+				continue
+			}
+			ruleStatistics[ruleIdx].HitCount += int64Value(stats.HitCount)
+			ruleStatistics[ruleIdx].PacketCount += int64Value(stats.PacketCount)
+			ruleStatistics[ruleIdx].ByteCount += int64Value(stats.ByteCount)
+			ruleStatistics[ruleIdx].SessionCount += int64Value(stats.SessionCount)
+		}
+	}
+	return ruleStatistics, nil
+}
+
+func (service *SecurityPolicyService) listSecurityPolicyStatistics(nsxSecurityPolicy *model.SecurityPolicy) ([]model.SecurityPolicyStatisticsForEnforcementPoint, error) {
+	if service.ruleStatisticsLimiter != nil {
+		service.ruleStatisticsLimiter.Wait()
+	}
+	var statistics model.SecurityPolicyStatisticsListResult
+	var err error
+	if isVpcEnabled(service) {
+		if nsxSecurityPolicy.Path == nil {
+			return nil, errors.New("nsxSecurityPolicy path is empty")
+		}
+		orgId, projectId, vpcId, _ := nsxutil.ParseVPCPath(*nsxSecurityPolicy.Path)
+		statistics, err = service.NSXClient.VPCSecurityPolicyStatisticsClient.List(orgId, projectId, vpcId, *nsxSecurityPolicy.Id, nil, nil)
+	} else {
+		statistics, err = service.NSXClient.SecurityPolicyStatisticsClient.List(getDomain(service), *nsxSecurityPolicy.Id, nil, nil)
+	}
+	if err != nil {
+		log.Error(err, "failed to list SecurityPolicy statistics", "nsxSecurityPolicyId", *nsxSecurityPolicy.Id)
+		return nil, err
+	}
+	return statistics.Results, nil
+}
+
+func (service *SecurityPolicyService) buildRuleStatisticsName(obj *v1alpha1.SecurityPolicy, ruleIdx int) string {
+	if len(obj.Spec.Rules[ruleIdx].Name) > 0 {
+		return obj.Spec.Rules[ruleIdx].Name
+	}
+	return fmt.Sprintf("%s-%d", obj.Name, ruleIdx)
+}
+
+func int64Value(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// MergeRuleStatistics fills the LastHitTime of the latest rule statistics. The LastHitTime is set to now
+// if the hit count of the rule increases since the last collection, otherwise the existing one is kept.
+func MergeRuleStatistics(existing, latest []v1alpha1.SecurityPolicyRuleStatistics, now metav1.Time) []v1alpha1.SecurityPolicyRuleStatistics {
+	existingByName := make(map[string]v1alpha1.SecurityPolicyRuleStatistics, len(existing))
+	for _, stats := range existing {
+		existingByName[stats.Name] = stats
+	}
+	merged := make([]v1alpha1.SecurityPolicyRuleStatistics, 0, len(latest))
+	for _, stats := range latest {
+		old, found := existingByName[stats.Name]
+		switch {
+		case found && stats.HitCount == old.HitCount:
+			stats.LastHitTime = old.LastHitTime
+		case stats.HitCount > 0:
+			stats.LastHitTime = now.DeepCopy()
+		}
+		merged = append(merged, stats)
+	}
+	return merged
+}
@@ -0,0 +1,105 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+type fakeStatisticsClient struct {
+	result model.SecurityPolicyStatisticsListResult
+}
+
+func (f *fakeStatisticsClient) List(domainIdParam string, securityPolicyIdParam string, containerClusterPathParam *string, enforcementPointPathParam *string) (model.SecurityPolicyStatisticsListResult, error) {
+	return f.result, nil
+}
+
+func ruleStatistics(rule string, hit int64) model.RuleStatistics {
+	return model.RuleStatistics{Rule: &rule, HitCount: &hit, PacketCount: &hit, ByteCount: &hit, SessionCount: &hit}
+}
+
+func TestGetRuleStatistics(t *testing.T) {
+	fakeClient := &fakeStatisticsClient{
+		result: model.SecurityPolicyStatisticsListResult{
+			Results: []model.SecurityPolicyStatisticsForEnforcementPoint{
+				{
+					Statistics: &model.SecurityPolicyStatistics{
+						Results: []model.RuleStatistics{
+							ruleStatistics("/infra/domains/k8scl-one/security-policies/sp_uidA/rules/sp_uidA_0_aaaa_0_0", 2),
+							ruleStatistics("/infra/domains/k8scl-one/security-policies/sp_uidA/rules/sp_uidA_0_aaaa_1_0", 3),
+							ruleStatistics("/infra/domains/k8scl-one/security-policies/sp_uidA/rules/sp_uidA_1_bbbb_0_0", 4),
+							ruleStatistics("/infra/domains/k8scl-one/security-policies/sp_uidA/rules/sp_uidB_0_cccc_0_0", 5),
+						},
+					},
+				},
+			},
+		},
+	}
+	s := &SecurityPolicyService{
+		Service: common.Service{
+			NSXClient: &nsx.Client{SecurityPolicyStatisticsClient: fakeClient},
+			NSXConfig: &config.NSXOperatorConfig{
+				CoeConfig: &config.CoeConfig{Cluster: "k8scl-one"},
+			},
+		},
+	}
+	s.securityPolicyStore = &SecurityPolicyStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagValueScopeSecurityPolicyUID: indexBySecurityPolicyUID}),
+		BindingType: model.SecurityPolicyBindingType(),
+	}}
+	sp := &v1alpha1.SecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "spA", UID: "uidA"},
+		Spec: v1alpha1.SecurityPolicySpec{
+			Rules: []v1alpha1.SecurityPolicyRule{{Name: "rule-a"}, {}},
+		},
+	}
+
+	// NSX SecurityPolicy is not realized yet.
+	stats, err := s.GetRuleStatistics(sp)
+	assert.Nil(t, err)
+	assert.Nil(t, stats)
+
+	nsxID := "sp_uidA"
+	assert.Nil(t, s.securityPolicyStore.Add(&model.SecurityPolicy{Id: &nsxID}))
+	stats, err = s.GetRuleStatistics(sp)
+	assert.Nil(t, err)
+	expected := []v1alpha1.SecurityPolicyRuleStatistics{
+		{Name: "rule-a", HitCount: 5, PacketCount: 5, ByteCount: 5, SessionCount: 5},
+		{Name: "spA-1", HitCount: 4, PacketCount: 4, ByteCount: 4, SessionCount: 4},
+	}
+	assert.Equal(t, expected, stats)
+
+	_, err = s.GetRuleStatistics("invalid")
+	assert.NotNil(t, err)
+}
+
+func TestMergeRuleStatistics(t *testing.T) {
+	lastHit := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	existing := []v1alpha1.SecurityPolicyRuleStatistics{
+		{Name: "rule-a", HitCount: 5, LastHitTime: &lastHit},
+		{Name: "rule-b", HitCount: 1, LastHitTime: &lastHit},
+	}
+	latest := []v1alpha1.SecurityPolicyRuleStatistics{
+		{Name: "rule-a", HitCount: 5},
+		{Name: "rule-b", HitCount: 3},
+		{Name: "rule-c", HitCount: 0},
+	}
+	merged := MergeRuleStatistics(existing, latest, now)
+	assert.Equal(t, 3, len(merged))
+	assert.Equal(t, &lastHit, merged[0].LastHitTime)
+	assert.Equal(t, &now, merged[1].LastHitTime)
+	assert.Nil(t, merged[2].LastHitTime)
+}
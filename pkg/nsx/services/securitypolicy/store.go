@@ -66,6 +66,17 @@ func indexByNetworkPolicyUID(obj interface{}) ([]string, error) {
 	}
 }
 
+func indexByAddressGroupUID(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.Group:
+		return filterTag(o.Tags, common.TagScopeAddressGroupCRUID), nil
+	case *model.Share:
+		return filterTag(o.Tags, common.TagScopeAddressGroupCRUID), nil
+	default:
+		return nil, errors.New("indexByAddressGroupUID doesn't support unknown type")
+	}
+}
+
 func indexGroupFunc(obj interface{}) ([]string, error) {
 	res := make([]string, 0, 5)
 	switch o := obj.(type) {
@@ -196,6 +207,15 @@ func (groupStore *GroupStore) Apply(i interface{}) error {
 	return nil
 }
 
+func (groupStore *GroupStore) GetByKey(key string) *model.Group {
+	var group *model.Group
+	obj := groupStore.ResourceStore.GetByKey(key)
+	if obj != nil {
+		group = obj.(*model.Group)
+	}
+	return group
+}
+
 func (groupStore *GroupStore) GetByIndex(key string, value string) []*model.Group {
 	groups := make([]*model.Group, 0)
 	objs := groupStore.ResourceStore.GetByIndex(key, value)
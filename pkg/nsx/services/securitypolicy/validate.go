@@ -0,0 +1,188 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// ValidateSecurityPolicy runs the checks done when building the NSX SecurityPolicy and groups
+// against the spec of the SecurityPolicy CR without calling NSX. The returned errors point to the
+// invalid field, e.g. spec.rules[2].sources[0].podSelector.
+func (service *SecurityPolicyService) ValidateSecurityPolicy(obj *v1alpha1.SecurityPolicy) field.ErrorList {
+	var allErrs field.ErrorList
+	// The builder sorts the targets and peers when building tags, so validate a copy
+	// to keep the field indexes in the errors the same as the ones in the spec.
+	sp := obj.DeepCopy()
+	specPath := field.NewPath("spec")
+
+	tagsPolicy := sp.DeepCopy()
+	tags := service.buildTargetTags(tagsPolicy, &tagsPolicy.Spec.AppliedTo, nil, -1, common.ResourceTypeSecurityPolicy)
+	if len(tags) > common.TagsCountMax {
+		allErrs = append(allErrs, field.TooMany(specPath, len(tags), common.TagsCountMax))
+	}
+
+	allErrs = append(allErrs, service.validateTargets(sp, sp.Spec.AppliedTo, specPath.Child("appliedTo"), "policy target")...)
+	if sp.Spec.Schedule != nil {
+		allErrs = append(allErrs, validateSchedule(sp.Spec.Schedule, specPath.Child("schedule"))...)
+	}
+	for ruleIdx := range sp.Spec.Rules {
+		rule := &sp.Spec.Rules[ruleIdx]
+		rulePath := specPath.Child("rules").Index(ruleIdx)
+
+		if rule.Direction == nil {
+			allErrs = append(allErrs, field.Required(rulePath.Child("direction"), "rule direction must be set"))
+		} else if ruleDirection, err := getRuleDirection(rule); err != nil {
+			allErrs = append(allErrs, field.NotSupported(rulePath.Child("direction"), *rule.Direction,
+				[]string{string(v1alpha1.RuleDirectionIn), string(v1alpha1.RuleDirectionIngress), string(v1alpha1.RuleDirectionOut), string(v1alpha1.RuleDirectionEgress)}))
+		} else if ruleDirection == "IN" {
+			allErrs = append(allErrs, service.validatePeers(sp, rule.Sources, rulePath.Child("sources"), "source")...)
+		} else {
+			allErrs = append(allErrs, service.validatePeers(sp, rule.Destinations, rulePath.Child("destinations"), "destination")...)
+			// The named port of an OUT rule is resolved on the destination Pods, it can't be resolved on the AddressGroup members.
+			if service.hasNamedPort(rule) {
+				for peerIdx, peer := range rule.Destinations {
+					if peer.AddressGroupRef != nil {
+						allErrs = append(allErrs, field.Forbidden(rulePath.Child("destinations").Index(peerIdx).Child("addressGroupRef"),
+							"addressGroupRef can't be used with named ports in an egress rule"))
+					}
+				}
+			}
+		}
+
+		if len(rule.AppliedTo) > 0 {
+			allErrs = append(allErrs, service.validateTargets(sp, rule.AppliedTo, rulePath.Child("appliedTo"), "rule applied")...)
+		} else if len(sp.Spec.AppliedTo) == 0 {
+			allErrs = append(allErrs, field.Required(rulePath.Child("appliedTo"), "appliedTo needs to be set in either spec or rules"))
+		}
+
+		if rule.Schedule != nil {
+			allErrs = append(allErrs, validateSchedule(rule.Schedule, rulePath.Child("schedule"))...)
+		}
+
+		for portIdx, port := range rule.Ports {
+			// endPort can only be defined if port is also defined. Both ports must be numeric.
+			if port.Port.Type == intstr.String && port.EndPort != 0 {
+				allErrs = append(allErrs, field.Invalid(rulePath.Child("ports").Index(portIdx).Child("endPort"), port.EndPort,
+					"endPort can only be defined if port is also numeric"))
+			}
+		}
+	}
+	return allErrs
+}
+
+func (service *SecurityPolicyService) validateTargets(obj *v1alpha1.SecurityPolicy, targets []v1alpha1.SecurityPolicyTarget, fldPath *field.Path, groupType string) field.ErrorList {
+	var allErrs field.ErrorList
+	group := model.Group{}
+	groupCriteriaCount, groupTotalExprCount := 0, 0
+	for i := range targets {
+		criteriaCount, totalExprCount, err := service.updateTargetExpressions(obj, &targets[i], &group, i)
+		if err != nil {
+			selectorPath := fldPath.Index(i).Child("podSelector")
+			if targets[i].PodSelector == nil {
+				selectorPath = fldPath.Index(i).Child("vmSelector")
+			}
+			allErrs = append(allErrs, field.Forbidden(selectorPath, err.Error()))
+			continue
+		}
+		groupCriteriaCount += criteriaCount
+		groupTotalExprCount += totalExprCount
+	}
+	return append(allErrs, validateGroupCriteria(fldPath, groupType, groupCriteriaCount, groupTotalExprCount)...)
+}
+
+func (service *SecurityPolicyService) validatePeers(obj *v1alpha1.SecurityPolicy, peers []v1alpha1.SecurityPolicyPeer, fldPath *field.Path, groupType string) field.ErrorList {
+	var allErrs field.ErrorList
+	groupShared := false
+	for _, peer := range peers {
+		if peer.NamespaceSelector != nil {
+			groupShared = true
+			break
+		}
+	}
+
+	group := model.Group{}
+	groupCriteriaCount, groupTotalExprCount := 0, 0
+	for i := range peers {
+		peer := &peers[i]
+		if peer.AddressGroupRef != nil {
+			if peer.PodSelector != nil || peer.VMSelector != nil || peer.NamespaceSelector != nil || len(peer.IPBlocks) > 0 {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("addressGroupRef"),
+					"addressGroupRef can't be set with other fields in one peer"))
+			}
+			continue
+		}
+		if peer.NamespaceSelector != nil && (peer.PodSelector != nil || peer.VMSelector != nil) {
+			if err := service.validateNsSelectorOpNotIn(peer.NamespaceSelector.MatchExpressions); err != nil {
+				allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child("namespaceSelector"), err.Error()))
+				continue
+			}
+		}
+		criteriaCount, totalExprCount, err := service.updatePeerExpressions(obj, peer, &group, i, groupShared)
+		if err != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Index(i).Child(peerSelectorField(peer)), err.Error()))
+			continue
+		}
+		groupCriteriaCount += criteriaCount
+		groupTotalExprCount += totalExprCount
+	}
+	return append(allErrs, validateGroupCriteria(fldPath, groupType, groupCriteriaCount, groupTotalExprCount)...)
+}
+
+func validateGroupCriteria(fldPath *field.Path, groupType string, criteriaCount, totalExprCount int) field.ErrorList {
+	var allErrs field.ErrorList
+	if criteriaCount > MaxCriteria {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("total counts of %s group criteria %d exceed NSX limit of %d",
+			groupType, criteriaCount, MaxCriteria)))
+	} else if totalExprCount > MaxTotalCriteriaExpressions {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("total expression counts in %s group criteria %d exceed NSX limit of %d",
+			groupType, totalExprCount, MaxTotalCriteriaExpressions)))
+	}
+	return allErrs
+}
+
+func validateSchedule(schedule *v1alpha1.SecurityPolicySchedule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeZone"), schedule.TimeZone, err.Error()))
+	}
+	if len(schedule.Windows) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("windows"), "schedule must have at least one window"))
+	}
+	for i, window := range schedule.Windows {
+		windowPath := fldPath.Child("windows").Index(i)
+		if _, err := parseClock(window.Start); err != nil {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("start"), window.Start, err.Error()))
+		}
+		if _, err := parseClock(window.End); err != nil {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("end"), window.End, err.Error()))
+		}
+		for j, day := range window.Days {
+			if _, ok := weekdays[day]; !ok {
+				allErrs = append(allErrs, field.NotSupported(windowPath.Child("days").Index(j), day,
+					[]string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}))
+			}
+		}
+	}
+	return allErrs
+}
+
+// peerSelectorField returns the name of the selector field which the validation error of the peer is reported on.
+func peerSelectorField(peer *v1alpha1.SecurityPolicyPeer) string {
+	if peer.PodSelector != nil {
+		return "podSelector"
+	}
+	if peer.VMSelector != nil {
+		return "vmSelector"
+	}
+	return "namespaceSelector"
+}
@@ -0,0 +1,152 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package securitypolicy
+
+import (
+	"reflect"
+	"testing"
+
+	gomonkey "github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+func TestValidateSecurityPolicy(t *testing.T) {
+	var s *SecurityPolicyService
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(s), "getNamespaceUID",
+		func(s *SecurityPolicyService, ns string) types.UID {
+			return types.UID(tagValueNSUID)
+		})
+	defer patches.Reset()
+
+	invalidDirection := v1alpha1.RuleDirection("Both")
+	podSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	opInSelector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "k1", Operator: metav1.LabelSelectorOpIn, Values: []string{"a"}},
+			{Key: "k2", Operator: metav1.LabelSelectorOpIn, Values: []string{"b"}},
+		},
+	}
+	notInSelector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "k1", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"a"}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		spec       v1alpha1.SecurityPolicySpec
+		wantFields []string
+	}{
+		{
+			name: "valid",
+			spec: v1alpha1.SecurityPolicySpec{
+				AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: podSelector}},
+				Rules: []v1alpha1.SecurityPolicyRule{
+					{Action: &allowAction, Direction: &directionIn, Sources: []v1alpha1.SecurityPolicyPeer{{PodSelector: podSelector}}},
+				},
+			},
+		},
+		{
+			name: "invalid-selectors",
+			spec: v1alpha1.SecurityPolicySpec{
+				AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: podSelector, VMSelector: podSelector}},
+				Rules: []v1alpha1.SecurityPolicyRule{
+					{Action: &allowAction, Direction: &directionIn},
+					{Action: &allowAction, Direction: &invalidDirection},
+					{
+						Action:    &allowAction,
+						Direction: &directionIn,
+						Sources: []v1alpha1.SecurityPolicyPeer{
+							{IPBlocks: []v1alpha1.IPBlock{{CIDR: cidr}}},
+							{PodSelector: opInSelector},
+							{PodSelector: podSelector, NamespaceSelector: notInSelector},
+						},
+						Ports: []v1alpha1.SecurityPolicyPort{
+							{Port: intstr.FromString("http"), EndPort: 8080},
+						},
+						Schedule: &v1alpha1.SecurityPolicySchedule{
+							TimeZone: "Mars/Base",
+							Windows:  []v1alpha1.ScheduleWindow{{Days: []v1alpha1.Weekday{"Someday"}, Start: "02:00", End: "6am"}},
+						},
+					},
+				},
+			},
+			wantFields: []string{
+				"spec.appliedTo[0].podSelector",
+				"spec.rules[1].direction",
+				"spec.rules[2].sources[1].podSelector",
+				"spec.rules[2].sources[2].namespaceSelector",
+				"spec.rules[2].schedule.timeZone",
+				"spec.rules[2].schedule.windows[0].end",
+				"spec.rules[2].schedule.windows[0].days[0]",
+				"spec.rules[2].ports[0].endPort",
+			},
+		},
+		{
+			name: "missing-applied-to",
+			spec: v1alpha1.SecurityPolicySpec{
+				Rules: []v1alpha1.SecurityPolicyRule{
+					{Action: &allowAction, Direction: &directionOut},
+				},
+			},
+			wantFields: []string{"spec.rules[0].appliedTo"},
+		},
+		{
+			name: "address-group-ref",
+			spec: v1alpha1.SecurityPolicySpec{
+				AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: podSelector}},
+				Rules: []v1alpha1.SecurityPolicyRule{
+					{
+						Action:    &allowAction,
+						Direction: &directionOut,
+						Destinations: []v1alpha1.SecurityPolicyPeer{
+							{AddressGroupRef: &v1alpha1.AddressGroupReference{Name: "proxies"}},
+							{AddressGroupRef: &v1alpha1.AddressGroupReference{Name: "proxies"}, PodSelector: podSelector},
+						},
+					},
+				},
+			},
+			wantFields: []string{"spec.rules[0].destinations[1].addressGroupRef"},
+		},
+		{
+			name: "address-group-ref-named-port",
+			spec: v1alpha1.SecurityPolicySpec{
+				AppliedTo: []v1alpha1.SecurityPolicyTarget{{PodSelector: podSelector}},
+				Rules: []v1alpha1.SecurityPolicyRule{
+					{
+						Action:    &allowAction,
+						Direction: &directionOut,
+						Destinations: []v1alpha1.SecurityPolicyPeer{
+							{PodSelector: podSelector},
+							{AddressGroupRef: &v1alpha1.AddressGroupReference{Name: "proxies"}},
+						},
+						Ports: []v1alpha1.SecurityPolicyPort{{Protocol: "TCP", Port: intstr.FromString("http")}},
+					},
+				},
+			},
+			wantFields: []string{"spec.rules[0].destinations[1].addressGroupRef"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := &v1alpha1.SecurityPolicy{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "spA", UID: "uidA"},
+				Spec:       tt.spec,
+			}
+			specCopy := sp.Spec.DeepCopy()
+			errs := service.ValidateSecurityPolicy(sp)
+			var fields []string
+			for _, err := range errs {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+			assert.Equal(t, specCopy, &sp.Spec)
+		})
+	}
+}
@@ -331,3 +331,32 @@ func (service *SecurityPolicyService) wrapHierarchyProjectResources(shares []mod
 	}
 	return projectInfraChildren, nil
 }
+
+// wrapHierarchyProjectInfra wrap the project infra children including project shares and groups into one
+// hierarchy resource tree for OrgRootClient to patch, it's used for the resources not bound to a VPC.
+func (service *SecurityPolicyService) wrapHierarchyProjectInfra(projectInfraChildren []*data.StructValue, orgID, projectID string) (*model.OrgRoot, error) {
+	childProject := model.ChildResourceReference{
+		Id:           &projectID,
+		ResourceType: common.ResourceTypeChildResourceReference,
+		TargetType:   String(common.ResourceTypeProject),
+		Children:     projectInfraChildren,
+	}
+	projectValue, errors := NewConverter().ConvertToVapi(childProject, model.ChildResourceReferenceBindingType())
+	if len(errors) > 0 {
+		return nil, errors[0]
+	}
+	childOrg := model.ChildResourceReference{
+		Id:           &orgID,
+		ResourceType: common.ResourceTypeChildResourceReference,
+		TargetType:   String(common.ResourceTypeOrg),
+		Children:     []*data.StructValue{projectValue.(*data.StructValue)},
+	}
+	orgValue, errors := NewConverter().ConvertToVapi(childOrg, model.ChildResourceReferenceBindingType())
+	if len(errors) > 0 {
+		return nil, errors[0]
+	}
+	return &model.OrgRoot{
+		Children:     []*data.StructValue{orgValue.(*data.StructValue)},
+		ResourceType: String(common.ResourceTypeOrgRoot),
+	}, nil
+}
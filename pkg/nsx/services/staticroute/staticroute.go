@@ -45,7 +45,11 @@ func InitializeStaticRoute(commonService common.Service, vpcService common.VPCSe
 	staticRouteService.NSXConfig = commonService.NSXConfig
 	staticRouteService.VPCService = vpcService
 
-	go staticRouteService.InitializeResourceStore(&wg, fatalErrors, resourceTypeStaticRoute, nil, staticRouteService.StaticRouteStore)
+	// Only the static routes of StaticRoute CRs are cached, the ones injected by VPCPeering are not.
+	tags := []model.Tag{
+		{Scope: String(common.TagScopeStaticRouteCRUID)},
+	}
+	go staticRouteService.InitializeResourceStore(&wg, fatalErrors, resourceTypeStaticRoute, tags, staticRouteService.StaticRouteStore)
 
 	go func() {
 		wg.Wait()
@@ -148,6 +152,17 @@ func (service *StaticRouteService) ListStaticRoute() []*model.StaticRoutes {
 	return staticRouteSet
 }
 
+// ListStaticRouteByVPCPath returns the NSX StaticRoutes in the store which belong to the VPC.
+func (service *StaticRouteService) ListStaticRouteByVPCPath(vpcPath string) []*model.StaticRoutes {
+	staticRouteSet := []*model.StaticRoutes{}
+	for _, staticRoute := range service.ListStaticRoute() {
+		if staticRoute.Path != nil && strings.HasPrefix(*staticRoute.Path, vpcPath+"/") {
+			staticRouteSet = append(staticRouteSet, staticRoute)
+		}
+	}
+	return staticRouteSet
+}
+
 func (service *StaticRouteService) Cleanup(ctx context.Context) error {
 	staticRouteSet := service.ListStaticRoute()
 	log.Info("cleanup staticroute", "count", len(staticRouteSet))
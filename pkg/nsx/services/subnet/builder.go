@@ -2,6 +2,8 @@ package subnet
 
 import (
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
@@ -52,17 +54,20 @@ func (service *SubnetService) buildSubnet(obj client.Object, tags []model.Tag) (
 		nsxSubnet = &model.VpcSubnet{
 			Id:          String(service.BuildSubnetID(o)),
 			AccessMode:  String(util.Capitalize(string(o.Spec.AccessMode))),
-			DhcpConfig:  service.buildDHCPConfig(o.Spec.DHCPConfig.EnableDHCP, int64(o.Spec.IPv4SubnetSize-4)),
+			DhcpConfig:  service.buildDHCPConfig(obj, o.Spec.DHCPConfig, int64(o.Spec.IPv4SubnetSize-4)),
 			DisplayName: String(service.buildSubnetName(o)),
 		}
 		staticIpAllocation = o.Spec.AdvancedConfig.StaticIPAllocation.Enable
 		nsxSubnet.IpAddresses = o.Spec.IPAddresses
+		if len(o.Spec.IPAddresses) == 0 && o.Spec.IPv4SubnetSize > 0 {
+			nsxSubnet.Ipv4SubnetSize = Int64(int64(o.Spec.IPv4SubnetSize))
+		}
 	case *v1alpha1.SubnetSet:
 		index := uuid.NewString()
 		nsxSubnet = &model.VpcSubnet{
 			Id:          String(service.buildSubnetSetID(o, index)),
 			AccessMode:  String(util.Capitalize(string(o.Spec.AccessMode))),
-			DhcpConfig:  service.buildDHCPConfig(o.Spec.DHCPConfig.EnableDHCP, int64(o.Spec.IPv4SubnetSize-4)),
+			DhcpConfig:  service.buildDHCPConfig(obj, o.Spec.DHCPConfig, int64(o.Spec.IPv4SubnetSize-4)),
 			DisplayName: String(service.buildSubnetSetName(o, index)),
 		}
 		staticIpAllocation = o.Spec.AdvancedConfig.StaticIPAllocation.Enable
@@ -83,11 +88,11 @@ func (service *SubnetService) buildSubnet(obj client.Object, tags []model.Tag) (
 	return nsxSubnet, nil
 }
 
-func (service *SubnetService) buildDHCPConfig(enableDHCP bool, poolSize int64) *model.VpcSubnetDhcpConfig {
+func (service *SubnetService) buildDHCPConfig(obj client.Object, dhcpConfig v1alpha1.DHCPConfig, poolSize int64) *model.VpcSubnetDhcpConfig {
 	// Subnet DHCP is used by AVI, not needed for now. We need to explicitly mark enableDhcp = false,
 	// otherwise Subnet will use DhcpConfig inherited from VPC.
-	dhcpConfig := &model.VpcSubnetDhcpConfig{
-		EnableDhcp: Bool(enableDHCP),
+	nsxDHCPConfig := &model.VpcSubnetDhcpConfig{
+		EnableDhcp: Bool(dhcpConfig.EnableDHCP),
 		StaticPoolConfig: &model.StaticPoolConfig{
 			// Number of IPs to be reserved in static ip pool.
 			// By default, if dhcp is enabled then static ipv4 pool size will be zero and all available IPs will be
@@ -95,7 +100,49 @@ func (service *SubnetService) buildDHCPConfig(enableDHCP bool, poolSize int64) *
 			Ipv4PoolSize: Int64(poolSize),
 		},
 	}
-	return dhcpConfig
+	// The DHCP configuration of NSX VPC Subnet only supports the DNS servers, the other DHCP options and the
+	// lease time can't be configured.
+	// The existing CRs may have set the DHCP relay or the DNS servers before they were applied, the invalid
+	// configuration is left out instead of failing the CR, and reported by the DHCPConfigInvalid condition.
+	if err := ValidateDHCPConfig(dhcpConfig); err != nil {
+		log.Info("ignored DHCP relay and DNS servers", "reason", err.Error(), "namespace", obj.GetNamespace(), "name", obj.GetName())
+		return nsxDHCPConfig
+	}
+	if dhcpConfig.DHCPRelayConfigPath != "" {
+		nsxDHCPConfig.DhcpRelayConfigPath = String(dhcpConfig.DHCPRelayConfigPath)
+	}
+	if len(dhcpConfig.DNSClientConfig.DNSServersIPs) > 0 {
+		nsxDHCPConfig.DnsClientConfig = &model.DnsClientConfig{DnsServerIps: dhcpConfig.DNSClientConfig.DNSServersIPs}
+	}
+	return nsxDHCPConfig
+}
+
+// ValidateDHCPConfig checks the DHCP configuration of Subnet or SubnetSet. The DHCP relay and the DNS servers
+// take effect only if DHCP is enabled.
+func ValidateDHCPConfig(dhcpConfig v1alpha1.DHCPConfig) error {
+	if !dhcpConfig.EnableDHCP {
+		if dhcpConfig.DHCPRelayConfigPath != "" {
+			return util2.RestrictionError{Desc: "DHCP relay cannot be configured when DHCP is disabled"}
+		}
+		if len(dhcpConfig.DNSClientConfig.DNSServersIPs) > 0 {
+			return util2.RestrictionError{Desc: "DNS servers cannot be configured when DHCP is disabled"}
+		}
+	}
+	if dhcpConfig.DHCPRelayConfigPath != "" && !strings.HasPrefix(dhcpConfig.DHCPRelayConfigPath, "/") {
+		return util2.RestrictionError{Desc: fmt.Sprintf("invalid DHCP relay config path %s", dhcpConfig.DHCPRelayConfigPath)}
+	}
+	for _, ip := range dhcpConfig.DNSClientConfig.DNSServersIPs {
+		if net.ParseIP(ip) == nil {
+			return util2.RestrictionError{Desc: fmt.Sprintf("invalid DNS server IP %s", ip)}
+		}
+	}
+	return nil
+}
+
+// UpdateDHCPConfigCondition sets the DHCPConfigInvalid condition by the error of validating the DHCP
+// configuration, and removes it if err is nil. It returns true if the conditions are changed.
+func UpdateDHCPConfigCondition(conditions *[]v1alpha1.Condition, err error) bool {
+	return updateErrorCondition(conditions, v1alpha1.DHCPConfigInvalid, "InvalidDHCPConfig", err)
 }
 
 func (service *SubnetService) buildBasicTags(obj client.Object) []model.Tag {
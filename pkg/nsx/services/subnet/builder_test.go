@@ -0,0 +1,105 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func TestBuildDHCPConfig(t *testing.T) {
+	service := &SubnetService{}
+	dhcpConfig := service.buildDHCPConfig(&v1alpha1.Subnet{}, v1alpha1.DHCPConfig{}, 60)
+	assert.Equal(t, &model.VpcSubnetDhcpConfig{
+		EnableDhcp:       common.Bool(false),
+		StaticPoolConfig: &model.StaticPoolConfig{Ipv4PoolSize: common.Int64(60)},
+	}, dhcpConfig)
+
+	dhcpConfig = service.buildDHCPConfig(&v1alpha1.Subnet{}, v1alpha1.DHCPConfig{
+		EnableDHCP:          true,
+		DHCPRelayConfigPath: "/infra/dhcp-relay-configs/relay1",
+		DNSClientConfig:     v1alpha1.DNSClientConfig{DNSServersIPs: []string{"10.1.1.1", "10.1.1.2"}},
+	}, 60)
+	assert.Equal(t, &model.VpcSubnetDhcpConfig{
+		EnableDhcp:          common.Bool(true),
+		DhcpRelayConfigPath: common.String("/infra/dhcp-relay-configs/relay1"),
+		DnsClientConfig:     &model.DnsClientConfig{DnsServerIps: []string{"10.1.1.1", "10.1.1.2"}},
+		StaticPoolConfig:    &model.StaticPoolConfig{Ipv4PoolSize: common.Int64(60)},
+	}, dhcpConfig)
+
+	// The DHCP relay and the DNS servers set with DHCP disabled are ignored.
+	dhcpConfig = service.buildDHCPConfig(&v1alpha1.Subnet{}, v1alpha1.DHCPConfig{
+		DHCPRelayConfigPath: "/infra/dhcp-relay-configs/relay1",
+		DNSClientConfig:     v1alpha1.DNSClientConfig{DNSServersIPs: []string{"10.1.1.1"}},
+	}, 60)
+	assert.Equal(t, &model.VpcSubnetDhcpConfig{
+		EnableDhcp:       common.Bool(false),
+		StaticPoolConfig: &model.StaticPoolConfig{Ipv4PoolSize: common.Int64(60)},
+	}, dhcpConfig)
+}
+
+func TestValidateDHCPConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		dhcpConfig v1alpha1.DHCPConfig
+		hasError   bool
+	}{
+		{name: "DHCP disabled", dhcpConfig: v1alpha1.DHCPConfig{}},
+		{name: "DHCP enabled", dhcpConfig: v1alpha1.DHCPConfig{EnableDHCP: true, DNSClientConfig: v1alpha1.DNSClientConfig{DNSServersIPs: []string{"10.1.1.1", "2001:db8::1"}}}},
+		{name: "DHCP relay", dhcpConfig: v1alpha1.DHCPConfig{EnableDHCP: true, DHCPRelayConfigPath: "/infra/dhcp-relay-configs/relay1"}},
+		{name: "DHCP relay with DHCP disabled", dhcpConfig: v1alpha1.DHCPConfig{DHCPRelayConfigPath: "/infra/dhcp-relay-configs/relay1"}, hasError: true},
+		{name: "DNS servers with DHCP disabled", dhcpConfig: v1alpha1.DHCPConfig{DNSClientConfig: v1alpha1.DNSClientConfig{DNSServersIPs: []string{"10.1.1.1"}}}, hasError: true},
+		{name: "invalid DHCP relay path", dhcpConfig: v1alpha1.DHCPConfig{EnableDHCP: true, DHCPRelayConfigPath: "relay1"}, hasError: true},
+		{name: "invalid DNS server", dhcpConfig: v1alpha1.DHCPConfig{EnableDHCP: true, DNSClientConfig: v1alpha1.DNSClientConfig{DNSServersIPs: []string{"10.1.1"}}}, hasError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDHCPConfig(tt.dhcpConfig)
+			if tt.hasError {
+				assert.ErrorAs(t, err, &nsxutil.RestrictionError{})
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSubnetToComparableDHCPConfig(t *testing.T) {
+	service := &SubnetService{}
+	existing := &model.VpcSubnet{
+		Id:         common.String("subnet1"),
+		DhcpConfig: service.buildDHCPConfig(&v1alpha1.Subnet{}, v1alpha1.DHCPConfig{EnableDHCP: true}, 60),
+	}
+	// The empty DNS servers rendered by NSX are ignored.
+	existing.DhcpConfig.DnsClientConfig = &model.DnsClientConfig{DnsServerIps: []string{}}
+	desired := &model.VpcSubnet{
+		Id:         common.String("subnet1"),
+		DhcpConfig: service.buildDHCPConfig(&v1alpha1.Subnet{}, v1alpha1.DHCPConfig{EnableDHCP: true}, 60),
+	}
+	assert.False(t, common.CompareResource(SubnetToComparable(existing), SubnetToComparable(desired)))
+
+	// The NSX Subnet created before the DHCP relay and DNS servers were applied has the static pool size
+	// rendered by NSX and no DHCP config set by nsx-operator.
+	existing.DhcpConfig = &model.VpcSubnetDhcpConfig{
+		EnableDhcp:          common.Bool(false),
+		DhcpRelayConfigPath: common.String(""),
+		StaticPoolConfig:    &model.StaticPoolConfig{Ipv4PoolSize: common.Int64(0)},
+	}
+	desired.DhcpConfig = service.buildDHCPConfig(&v1alpha1.Subnet{}, v1alpha1.DHCPConfig{}, 60)
+	assert.False(t, common.CompareResource(SubnetToComparable(existing), SubnetToComparable(desired)))
+	existing.DhcpConfig = nil
+	assert.False(t, common.CompareResource(SubnetToComparable(existing), SubnetToComparable(desired)))
+
+	desired.DhcpConfig = service.buildDHCPConfig(&v1alpha1.Subnet{}, v1alpha1.DHCPConfig{EnableDHCP: true, DNSClientConfig: v1alpha1.DNSClientConfig{DNSServersIPs: []string{"10.1.1.1"}}}, 60)
+	assert.True(t, common.CompareResource(SubnetToComparable(existing), SubnetToComparable(desired)))
+
+	desired.DhcpConfig = service.buildDHCPConfig(&v1alpha1.Subnet{}, v1alpha1.DHCPConfig{EnableDHCP: true, DHCPRelayConfigPath: "/infra/dhcp-relay-configs/relay1"}, 60)
+	assert.True(t, common.CompareResource(SubnetToComparable(existing), SubnetToComparable(desired)))
+}
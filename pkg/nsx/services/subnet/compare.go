@@ -18,16 +18,39 @@ func (subnet *Subnet) Key() string {
 }
 
 func (subnet *Subnet) Value() data.DataValue {
-	// IPv4SubnetSize/AccessMode/IPAddresses/DHCPConfig are immutable field,
-	// Only changes of tags are considered as changed.
+	// AccessMode/EnableDHCP are immutable fields, IPv4SubnetSize/IPAddresses can only grow,
+	// so only changes of tags, size, CIDRs, DHCP relay and DNS servers are considered as changed.
 	// TODO AccessMode may also need to be compared in future.
 	s := &Subnet{
-		Tags: subnet.Tags,
+		Tags:           subnet.Tags,
+		IpAddresses:    subnet.IpAddresses,
+		Ipv4SubnetSize: subnet.Ipv4SubnetSize,
+		DhcpConfig:     comparableDHCPConfig(subnet.DhcpConfig),
 	}
 	dataValue, _ := (*model.VpcSubnet)(s).GetDataValue__()
 	return dataValue
 }
 
+// comparableDHCPConfig returns the DHCP relay and the DNS servers set by nsx-operator, the other fields
+// are rendered or defaulted by NSX. It returns nil if neither is set, so that the NSX Subnets created before
+// they were applied are not updated.
+func comparableDHCPConfig(dhcpConfig *model.VpcSubnetDhcpConfig) *model.VpcSubnetDhcpConfig {
+	if dhcpConfig == nil {
+		return nil
+	}
+	comparable := &model.VpcSubnetDhcpConfig{}
+	if dhcpConfig.DhcpRelayConfigPath != nil && *dhcpConfig.DhcpRelayConfigPath != "" {
+		comparable.DhcpRelayConfigPath = dhcpConfig.DhcpRelayConfigPath
+	}
+	if dhcpConfig.DnsClientConfig != nil && len(dhcpConfig.DnsClientConfig.DnsServerIps) > 0 {
+		comparable.DnsClientConfig = &model.DnsClientConfig{DnsServerIps: dhcpConfig.DnsClientConfig.DnsServerIps}
+	}
+	if comparable.DhcpRelayConfigPath == nil && comparable.DnsClientConfig == nil {
+		return nil
+	}
+	return comparable
+}
+
 func SubnetToComparable(subnet *model.VpcSubnet) Comparable {
 	return (*Subnet)(subnet)
 }
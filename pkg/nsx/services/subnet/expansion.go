@@ -0,0 +1,161 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnet
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// getSubnetExpansion returns the CIDRs added to the Subnet CR and the new size of the NSX Subnet, or zero if
+// the size is not increased. The size is only considered if the CIDRs are not specified, and a smaller size
+// is ignored as the size of the existing NSX Subnet may be rendered by NSX.
+func getSubnetExpansion(subnet *v1alpha1.Subnet, existingSubnet *model.VpcSubnet) ([]string, int64, error) {
+	if len(subnet.Spec.IPAddresses) > 0 {
+		desired := sets.New[string](subnet.Spec.IPAddresses...)
+		for _, cidr := range existingSubnet.IpAddresses {
+			if !desired.Has(cidr) {
+				return nil, 0, nsxutil.RestrictionError{Desc: fmt.Sprintf("CIDR %s cannot be removed from Subnet", cidr)}
+			}
+		}
+		existing := sets.New[string](existingSubnet.IpAddresses...)
+		var added []string
+		for _, cidr := range subnet.Spec.IPAddresses {
+			if !existing.Has(cidr) {
+				added = append(added, cidr)
+			}
+		}
+		return added, 0, nil
+	}
+	if existingSubnet.Ipv4SubnetSize != nil && int64(subnet.Spec.IPv4SubnetSize) > *existingSubnet.Ipv4SubnetSize {
+		return nil, int64(subnet.Spec.IPv4SubnetSize), nil
+	}
+	return nil, 0, nil
+}
+
+// buildExpandedSubnet sets the CIDRs and the size of the NSX Subnet to update from the existing one and the
+// expansion of the Subnet CR. It returns true if the NSX Subnet is expanded.
+func buildExpandedSubnet(subnet *v1alpha1.Subnet, existingSubnet *model.VpcSubnet, nsxSubnet *model.VpcSubnet) (bool, error) {
+	added, size, err := getSubnetExpansion(subnet, existingSubnet)
+	if err != nil {
+		return false, err
+	}
+	nsxSubnet.IpAddresses = append(append([]string{}, existingSubnet.IpAddresses...), added...)
+	nsxSubnet.Ipv4SubnetSize = existingSubnet.Ipv4SubnetSize
+	if size > 0 {
+		nsxSubnet.Ipv4SubnetSize = Int64(size)
+	}
+	return len(added) > 0 || size > 0, nil
+}
+
+// ValidateSubnetExpansion checks the CIDRs added to the existing Subnet. The CIDRs of the Private Subnet should be
+// within the private CIDRs of the VPC, and should not overlap with the CIDRs of the other Subnets in the VPC.
+func (service *SubnetService) ValidateSubnetExpansion(subnet *v1alpha1.Subnet, privateCIDRs []string) error {
+	existingSubnet := service.SubnetStore.GetByKey(service.BuildSubnetID(subnet))
+	if existingSubnet == nil {
+		return nil
+	}
+	added, _, err := getSubnetExpansion(subnet, existingSubnet)
+	if err != nil {
+		return err
+	}
+	var vpcCIDRs []*net.IPNet
+	for _, obj := range service.SubnetStore.List() {
+		nsxSubnet := obj.(*model.VpcSubnet)
+		if nsxSubnet.ParentPath == nil || existingSubnet.ParentPath == nil || *nsxSubnet.ParentPath != *existingSubnet.ParentPath {
+			continue
+		}
+		for _, cidr := range nsxSubnet.IpAddresses {
+			if _, ipNet, err := net.ParseCIDR(cidr); err == nil {
+				vpcCIDRs = append(vpcCIDRs, ipNet)
+			}
+		}
+	}
+	for _, cidr := range added {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nsxutil.RestrictionError{Desc: fmt.Sprintf("invalid CIDR %s", cidr)}
+		}
+		if string(subnet.Spec.AccessMode) == v1alpha1.AccessModePrivate && !containedInCIDRs(ipNet, privateCIDRs) {
+			return nsxutil.RestrictionError{Desc: fmt.Sprintf("CIDR %s is not within the private CIDRs %v of VPC", cidr, privateCIDRs)}
+		}
+		for _, vpcCIDR := range vpcCIDRs {
+			if vpcCIDR.Contains(ipNet.IP) || ipNet.Contains(vpcCIDR.IP) {
+				return nsxutil.RestrictionError{Desc: fmt.Sprintf("CIDR %s overlaps with CIDR %s of VPC Subnet", cidr, vpcCIDR.String())}
+			}
+		}
+	}
+	return nil
+}
+
+func containedInCIDRs(ipNet *net.IPNet, cidrs []string) bool {
+	ones, _ := ipNet.Mask.Size()
+	for _, cidr := range cidrs {
+		_, block, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		blockOnes, _ := block.Mask.Size()
+		if block.Contains(ipNet.IP) && blockOnes <= ones {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateExpansionCondition sets the ExpansionFailed condition by the error of expanding the Subnet, and removes
+// it if err is nil. It returns true if the conditions are changed.
+func UpdateExpansionCondition(conditions *[]v1alpha1.Condition, err error) bool {
+	reason := "ExpansionRejected"
+	if _, ok := err.(nsxutil.RestrictionError); ok {
+		reason = "InvalidExpansion"
+	}
+	return updateErrorCondition(conditions, v1alpha1.ExpansionFailed, reason, err)
+}
+
+// updateErrorCondition sets the condition of conditionType to True with the error message, and removes it
+// if err is nil. It returns true if the conditions are changed.
+func updateErrorCondition(conditions *[]v1alpha1.Condition, conditionType v1alpha1.ConditionType, reason string, err error) bool {
+	index := -1
+	for i := range *conditions {
+		if (*conditions)[i].Type == conditionType {
+			index = i
+			break
+		}
+	}
+	if err == nil {
+		if index < 0 {
+			return false
+		}
+		*conditions = append((*conditions)[:index], (*conditions)[index+1:]...)
+		return true
+	}
+
+	newCondition := v1alpha1.Condition{
+		Type:    conditionType,
+		Status:  v1.ConditionTrue,
+		Reason:  reason,
+		Message: err.Error(),
+	}
+	if index < 0 {
+		newCondition.LastTransitionTime = metav1.Now()
+		*conditions = append(*conditions, newCondition)
+		return true
+	}
+	existing := &(*conditions)[index]
+	if existing.Reason == newCondition.Reason && existing.Message == newCondition.Message {
+		return false
+	}
+	existing.Reason = newCondition.Reason
+	existing.Message = newCondition.Message
+	return true
+}
@@ -0,0 +1,172 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func newExpansionService(t *testing.T, nsxSubnets ...*model.VpcSubnet) *SubnetService {
+	service := &SubnetService{
+		Service: common.Service{NSXConfig: &config.NSXOperatorConfig{CoeConfig: &config.CoeConfig{Cluster: "k8scl-one"}}},
+		SubnetStore: &SubnetStore{ResourceStore: common.ResourceStore{
+			Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
+				common.TagScopeSubnetCRUID:    subnetIndexFunc,
+				common.TagScopeSubnetSetCRUID: subnetSetIndexFunc,
+			}),
+			BindingType: model.VpcSubnetBindingType(),
+		}},
+	}
+	for _, nsxSubnet := range nsxSubnets {
+		assert.NoError(t, service.SubnetStore.Apply(nsxSubnet))
+	}
+	return service
+}
+
+func newExpansionSubnet(size int, cidrs ...string) *v1alpha1.Subnet {
+	return &v1alpha1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnet1", UID: "subnet-uid"},
+		Spec:       v1alpha1.SubnetSpec{AccessMode: v1alpha1.AccessMode(v1alpha1.AccessModePrivate), IPv4SubnetSize: size, IPAddresses: cidrs},
+	}
+}
+
+func newExistingNSXSubnet(id string, size int64, cidrs ...string) *model.VpcSubnet {
+	return &model.VpcSubnet{
+		Id:             common.String(id),
+		Path:           common.String("/orgs/default/projects/p1/vpcs/v1/subnets/" + id),
+		ParentPath:     common.String("/orgs/default/projects/p1/vpcs/v1"),
+		IpAddresses:    cidrs,
+		Ipv4SubnetSize: common.Int64(size),
+	}
+}
+
+func TestBuildExpandedSubnet(t *testing.T) {
+	existing := newExistingNSXSubnet("subnet-1", 64, "10.0.0.0/26")
+	tests := []struct {
+		name          string
+		subnet        *v1alpha1.Subnet
+		expanded      bool
+		expectedCIDRs []string
+		expectedSize  int64
+		hasError      bool
+	}{
+		{name: "unchanged", subnet: newExpansionSubnet(64), expectedCIDRs: []string{"10.0.0.0/26"}, expectedSize: 64},
+		{name: "smaller size", subnet: newExpansionSubnet(32), expectedCIDRs: []string{"10.0.0.0/26"}, expectedSize: 64},
+		{name: "larger size", subnet: newExpansionSubnet(128), expanded: true, expectedCIDRs: []string{"10.0.0.0/26"}, expectedSize: 128},
+		{name: "add CIDR", subnet: newExpansionSubnet(64, "10.0.0.0/26", "10.0.1.0/26"), expanded: true, expectedCIDRs: []string{"10.0.0.0/26", "10.0.1.0/26"}, expectedSize: 64},
+		{name: "remove CIDR", subnet: newExpansionSubnet(64, "10.0.1.0/26"), hasError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nsxSubnet := &model.VpcSubnet{}
+			expanded, err := buildExpandedSubnet(tt.subnet, existing, nsxSubnet)
+			if tt.hasError {
+				assert.ErrorAs(t, err, &nsxutil.RestrictionError{})
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expanded, expanded)
+			assert.Equal(t, tt.expectedCIDRs, nsxSubnet.IpAddresses)
+			assert.Equal(t, tt.expectedSize, *nsxSubnet.Ipv4SubnetSize)
+		})
+	}
+	assert.Equal(t, []string{"10.0.0.0/26"}, existing.IpAddresses)
+}
+
+func TestValidateSubnetExpansion(t *testing.T) {
+	subnet := newExpansionSubnet(64)
+	service := newExpansionService(t,
+		newExistingNSXSubnet((&SubnetService{}).BuildSubnetID(subnet), 64, "10.0.0.0/26"),
+		newExistingNSXSubnet("subnet-2", 64, "10.0.2.0/26"),
+	)
+	privateCIDRs := []string{"10.0.0.0/16"}
+	tests := []struct {
+		name     string
+		cidrs    []string
+		hasError bool
+	}{
+		{name: "no expansion"},
+		{name: "valid CIDR", cidrs: []string{"10.0.0.0/26", "10.0.1.0/26"}},
+		{name: "invalid CIDR", cidrs: []string{"10.0.0.0/26", "10.0.1.0"}, hasError: true},
+		{name: "out of private CIDRs", cidrs: []string{"10.0.0.0/26", "192.168.0.0/26"}, hasError: true},
+		{name: "larger than private CIDRs", cidrs: []string{"10.0.0.0/26", "10.0.0.0/8"}, hasError: true},
+		{name: "overlapping with other Subnet", cidrs: []string{"10.0.0.0/26", "10.0.2.0/28"}, hasError: true},
+		{name: "removing CIDR", cidrs: []string{"10.0.1.0/26"}, hasError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.ValidateSubnetExpansion(newExpansionSubnet(64, tt.cidrs...), privateCIDRs)
+			if tt.hasError {
+				assert.ErrorAs(t, err, &nsxutil.RestrictionError{})
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	// The Public Subnet is not limited by the private CIDRs.
+	publicSubnet := newExpansionSubnet(64, "10.0.0.0/26", "192.168.0.0/26")
+	publicSubnet.Spec.AccessMode = "Public"
+	assert.NoError(t, service.ValidateSubnetExpansion(publicSubnet, privateCIDRs))
+}
+
+func TestCreateOrUpdateSubnetExpansion(t *testing.T) {
+	subnet := newExpansionSubnet(128)
+	service := newExpansionService(t, newExistingNSXSubnet((&SubnetService{}).BuildSubnetID(subnet), 64, "10.0.0.0/26"))
+	// The tags and the DHCP configuration are unchanged so that only the expansion is considered.
+	existingSubnet := service.SubnetStore.GetByKey(service.BuildSubnetID(subnet))
+	existingSubnet.Tags = service.buildBasicTags(subnet)
+	existingSubnet.DhcpConfig = service.buildDHCPConfig(&v1alpha1.Subnet{}, v1alpha1.DHCPConfig{}, 64-4)
+
+	var patched *model.VpcSubnet
+	patchErr := errors.New("subnet size cannot be changed")
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(service), "createOrUpdateSubnet", func(_ *SubnetService, _ client.Object, nsxSubnet *model.VpcSubnet, _ *common.VPCResourceInfo) (string, error) {
+		patched = nsxSubnet
+		return "", patchErr
+	})
+	defer patches.Reset()
+	_, err := service.CreateOrUpdateSubnet(subnet, common.VPCResourceInfo{}, nil)
+	assert.ErrorAs(t, err, &nsxutil.SubnetExpansionError{})
+	assert.Equal(t, int64(128), *patched.Ipv4SubnetSize)
+	assert.Equal(t, []string{"10.0.0.0/26"}, patched.IpAddresses)
+
+	// The Subnet is not updated without expansion.
+	patched = nil
+	_, err = service.CreateOrUpdateSubnet(newExpansionSubnet(64), common.VPCResourceInfo{}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, patched)
+}
+
+func TestUpdateExpansionCondition(t *testing.T) {
+	conditions := []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}}
+
+	assert.False(t, UpdateExpansionCondition(&conditions, nil))
+	assert.True(t, UpdateExpansionCondition(&conditions, nsxutil.RestrictionError{Desc: "CIDR 10.0.0.0/26 cannot be removed from Subnet"}))
+	assert.Len(t, conditions, 2)
+	assert.Equal(t, v1alpha1.ExpansionFailed, conditions[1].Type)
+	assert.Equal(t, v1.ConditionTrue, conditions[1].Status)
+	assert.Equal(t, "InvalidExpansion", conditions[1].Reason)
+	assert.False(t, UpdateExpansionCondition(&conditions, nsxutil.RestrictionError{Desc: "CIDR 10.0.0.0/26 cannot be removed from Subnet"}))
+
+	assert.True(t, UpdateExpansionCondition(&conditions, nsxutil.SubnetExpansionError{Desc: "NSX rejected expanding the Subnet in place"}))
+	assert.Equal(t, "ExpansionRejected", conditions[1].Reason)
+
+	assert.True(t, UpdateExpansionCondition(&conditions, nil))
+	assert.Equal(t, []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}}, conditions)
+}
@@ -0,0 +1,96 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnet
+
+import (
+	"fmt"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+)
+
+// hasStaticIPPool returns false for the DHCP Subnet, all the IPs of which are reserved in the DHCP pool.
+func hasStaticIPPool(nsxSubnet *model.VpcSubnet) bool {
+	return nsxSubnet.DhcpConfig == nil || nsxSubnet.DhcpConfig.EnableDhcp == nil || !*nsxSubnet.DhcpConfig.EnableDhcp
+}
+
+// GetSubnetIPUsage returns the IP usage of the static IP pool of the NSX Subnet. The IP usage is unknown and nil
+// is returned if the Subnet has no static IP pool, e.g. the DHCP Subnet, or the IP pool is not created yet.
+func (service *SubnetService) GetSubnetIPUsage(nsxSubnet *model.VpcSubnet) (*v1alpha1.IPUsage, error) {
+	if !hasStaticIPPool(nsxSubnet) {
+		return nil, nil
+	}
+	poolUsage, err := service.getIPPoolUsage(nsxSubnet)
+	if err != nil || poolUsage == nil {
+		return nil, err
+	}
+	usage := &v1alpha1.IPUsage{}
+	if poolUsage.TotalIps != nil {
+		usage.TotalIPs = *poolUsage.TotalIps
+	}
+	if poolUsage.AllocatedIpAllocations != nil {
+		usage.AllocatedIPs = *poolUsage.AllocatedIpAllocations
+	}
+	if poolUsage.AvailableIps != nil {
+		usage.AvailableIPs = *poolUsage.AvailableIps
+	}
+	return usage, nil
+}
+
+// addIPUsage adds the IP usage of a Subnet to the sum of the SubnetSet.
+func addIPUsage(sum *v1alpha1.IPUsage, usage *v1alpha1.IPUsage) {
+	sum.TotalIPs += usage.TotalIPs
+	sum.AllocatedIPs += usage.AllocatedIPs
+	sum.AvailableIPs += usage.AvailableIPs
+}
+
+// UpdateIPUtilizationCondition sets the IPUtilizationHigh condition by the IP usage and the threshold percentage.
+// The condition is removed if the IP usage is unknown. It returns true if the conditions are changed.
+func UpdateIPUtilizationCondition(conditions *[]v1alpha1.Condition, usage *v1alpha1.IPUsage, threshold int) bool {
+	index := -1
+	for i := range *conditions {
+		if (*conditions)[i].Type == v1alpha1.IPUtilizationHigh {
+			index = i
+			break
+		}
+	}
+	if usage == nil {
+		if index < 0 {
+			return false
+		}
+		*conditions = append((*conditions)[:index], (*conditions)[index+1:]...)
+		return true
+	}
+
+	newCondition := v1alpha1.Condition{
+		Type:    v1alpha1.IPUtilizationHigh,
+		Status:  v1.ConditionFalse,
+		Reason:  "IPUtilizationBelowThreshold",
+		Message: fmt.Sprintf("%d of %d IPs are allocated, below the threshold %d%%", usage.AllocatedIPs, usage.TotalIPs, threshold),
+	}
+	if usage.TotalIPs > 0 && usage.AllocatedIPs*100 >= usage.TotalIPs*int64(threshold) {
+		newCondition.Status = v1.ConditionTrue
+		newCondition.Reason = "IPUtilizationExceedsThreshold"
+		newCondition.Message = fmt.Sprintf("%d of %d IPs are allocated, exceeding the threshold %d%%", usage.AllocatedIPs, usage.TotalIPs, threshold)
+	}
+	if index < 0 {
+		newCondition.LastTransitionTime = metav1.Now()
+		*conditions = append(*conditions, newCondition)
+		return true
+	}
+	existing := &(*conditions)[index]
+	if existing.Status == newCondition.Status && existing.Reason == newCondition.Reason && existing.Message == newCondition.Message {
+		return false
+	}
+	if existing.Status != newCondition.Status {
+		existing.LastTransitionTime = metav1.Now()
+	}
+	existing.Status = newCondition.Status
+	existing.Reason = newCondition.Reason
+	existing.Message = newCondition.Message
+	return true
+}
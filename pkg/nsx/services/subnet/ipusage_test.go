@@ -0,0 +1,71 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnet
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+func TestGetSubnetIPUsage(t *testing.T) {
+	service := &SubnetService{}
+	nsxSubnet := &model.VpcSubnet{Id: common.String("subnet-1"), Path: common.String("/orgs/default/projects/p1/vpcs/v1/subnets/subnet-1")}
+
+	patches := gomonkey.ApplyPrivateMethod(reflect.TypeOf(service), "getIPPoolUsage", func(_ *SubnetService, _ *model.VpcSubnet) (*model.PolicyPoolUsage, error) {
+		total, allocated, available := int64(61), int64(10), int64(51)
+		return &model.PolicyPoolUsage{TotalIps: &total, AllocatedIpAllocations: &allocated, AvailableIps: &available}, nil
+	})
+	usage, err := service.GetSubnetIPUsage(nsxSubnet)
+	assert.NoError(t, err)
+	assert.Equal(t, &v1alpha1.IPUsage{TotalIPs: 61, AllocatedIPs: 10, AvailableIPs: 51}, usage)
+	patches.Reset()
+
+	patches = gomonkey.ApplyPrivateMethod(reflect.TypeOf(service), "getIPPoolUsage", func(_ *SubnetService, _ *model.VpcSubnet) (*model.PolicyPoolUsage, error) {
+		return nil, errors.New("ip pool not found")
+	})
+	defer patches.Reset()
+	_, err = service.GetSubnetIPUsage(nsxSubnet)
+	assert.Error(t, err)
+
+	// The IP usage of the DHCP Subnet is unknown, the IP pool is not read.
+	patches.ApplyPrivateMethod(reflect.TypeOf(service), "getIPPoolUsage", func(_ *SubnetService, _ *model.VpcSubnet) (*model.PolicyPoolUsage, error) {
+		t.Error("IP pool of the DHCP Subnet should not be read")
+		return nil, nil
+	})
+	nsxSubnet.DhcpConfig = &model.VpcSubnetDhcpConfig{EnableDhcp: common.Bool(true)}
+	usage, err = service.GetSubnetIPUsage(nsxSubnet)
+	assert.NoError(t, err)
+	assert.Nil(t, usage)
+}
+
+func TestUpdateIPUtilizationCondition(t *testing.T) {
+	conditions := []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}}
+
+	// No condition is added if the IP usage is unknown.
+	assert.False(t, UpdateIPUtilizationCondition(&conditions, nil, 80))
+	assert.Len(t, conditions, 1)
+
+	assert.True(t, UpdateIPUtilizationCondition(&conditions, &v1alpha1.IPUsage{TotalIPs: 100, AllocatedIPs: 50, AvailableIPs: 50}, 80))
+	assert.Len(t, conditions, 2)
+	assert.Equal(t, v1alpha1.IPUtilizationHigh, conditions[1].Type)
+	assert.Equal(t, v1.ConditionFalse, conditions[1].Status)
+	assert.Equal(t, "50 of 100 IPs are allocated, below the threshold 80%", conditions[1].Message)
+	assert.False(t, UpdateIPUtilizationCondition(&conditions, &v1alpha1.IPUsage{TotalIPs: 100, AllocatedIPs: 50, AvailableIPs: 50}, 80))
+
+	assert.True(t, UpdateIPUtilizationCondition(&conditions, &v1alpha1.IPUsage{TotalIPs: 100, AllocatedIPs: 80, AvailableIPs: 20}, 80))
+	assert.Equal(t, v1.ConditionTrue, conditions[1].Status)
+	assert.Equal(t, "IPUtilizationExceedsThreshold", conditions[1].Reason)
+
+	assert.True(t, UpdateIPUtilizationCondition(&conditions, nil, 80))
+	assert.Equal(t, []v1alpha1.Condition{{Type: v1alpha1.Ready, Status: v1.ConditionTrue}}, conditions)
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	vapierrors "github.com/vmware/vsphere-automation-sdk-go/lib/vapi/std/errors"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -88,12 +91,17 @@ func (service *SubnetService) CreateOrUpdateSubnet(obj client.Object, vpcInfo co
 		return "", err
 	}
 	// Only check whether needs update when obj is v1alpha1.Subnet
+	expanded := false
 	if subnet, ok := obj.(*v1alpha1.Subnet); ok {
 		existingSubnet := service.SubnetStore.GetByKey(service.BuildSubnetID(subnet))
 		changed := false
 		if existingSubnet == nil {
 			changed = true
 		} else {
+			if expanded, err = buildExpandedSubnet(subnet, existingSubnet, nsxSubnet); err != nil {
+				log.Error(err, "failed to expand Subnet", "subnet.Id", uid)
+				return "", err
+			}
 			changed = common.CompareResource(SubnetToComparable(existingSubnet), SubnetToComparable(nsxSubnet))
 		}
 		if !changed {
@@ -101,7 +109,12 @@ func (service *SubnetService) CreateOrUpdateSubnet(obj client.Object, vpcInfo co
 			return uid, nil
 		}
 	}
-	return service.createOrUpdateSubnet(obj, nsxSubnet, &vpcInfo)
+	path, err := service.createOrUpdateSubnet(obj, nsxSubnet, &vpcInfo)
+	if err != nil && expanded {
+		log.Error(err, "failed to expand Subnet in place", "subnet.Id", uid, "ipAddresses", nsxSubnet.IpAddresses, "ipv4SubnetSize", nsxSubnet.Ipv4SubnetSize)
+		return "", nsxutil.SubnetExpansionError{Desc: fmt.Sprintf("NSX rejected expanding the Subnet in place: %v", err)}
+	}
+	return path, err
 }
 
 func (service *SubnetService) createOrUpdateSubnet(obj client.Object, nsxSubnet *model.VpcSubnet, vpcInfo *common.VPCResourceInfo) (string, error) {
@@ -171,6 +184,18 @@ func (service *SubnetService) ListSubnetCreatedBySubnetSet(id string) []*model.V
 	return service.SubnetStore.GetByIndex(common.TagScopeSubnetSetCRUID, id)
 }
 
+// ListSubnetByVPCPath returns the NSX Subnets in the store which belong to the VPC.
+func (service *SubnetService) ListSubnetByVPCPath(vpcPath string) []*model.VpcSubnet {
+	subnets := []*model.VpcSubnet{}
+	for _, obj := range service.SubnetStore.List() {
+		subnet := obj.(*model.VpcSubnet)
+		if subnet.Path != nil && strings.HasPrefix(*subnet.Path, vpcPath+"/") {
+			subnets = append(subnets, subnet)
+		}
+	}
+	return subnets
+}
+
 func (service *SubnetService) ListSubnetSetID(ctx context.Context) sets.Set[string] {
 	crdSubnetSetList := &v1alpha1.SubnetSetList{}
 	subnetsetIDs := sets.New[string]()
@@ -236,6 +261,10 @@ func (service *SubnetService) getIPPoolUsage(nsxSubnet *model.VpcSubnet) (*model
 		return nil, err
 	}
 	ipPool, err := service.NSXClient.IPPoolClient.Get(param.OrgID, param.ProjectID, param.VPCID, *nsxSubnet.Id, ipPoolID)
+	if _, ok := err.(vapierrors.NotFound); ok {
+		log.V(1).Info("ip-pool not found", "Subnet", *nsxSubnet.Id)
+		return nil, nil
+	}
 	if err != nil {
 		log.Error(err, "failed to get ip-pool", "Subnet", *nsxSubnet.Id)
 		return nil, err
@@ -251,9 +280,15 @@ func (service *SubnetService) GetIPPoolUsage(subnet *v1alpha1.Subnet) (*model.Po
 	return service.getIPPoolUsage(nsxSubnets[0])
 }
 
+// UpdateSubnetSetStatus updates the Subnets, the IP usage and the IPUtilizationHigh condition in the SubnetSet status.
 func (service *SubnetService) UpdateSubnetSetStatus(obj *v1alpha1.SubnetSet) error {
 	var subnetInfoList []v1alpha1.SubnetInfo
+	var ipUsage *v1alpha1.IPUsage
 	nsxSubnets := service.SubnetStore.GetByIndex(common.TagScopeSubnetSetCRUID, string(obj.GetUID()))
+	nsxSubnets = append(nsxSubnets, service.ListImportedSubnets(obj)...)
+	sort.Slice(nsxSubnets, func(i, j int) bool {
+		return *nsxSubnets[i].Path < *nsxSubnets[j].Path
+	})
 	for _, subnet := range nsxSubnets {
 		subnet := subnet
 		statusList, err := service.GetSubnetStatus(subnet)
@@ -266,9 +301,24 @@ func (service *SubnetService) UpdateSubnetSetStatus(obj *v1alpha1.SubnetSet) err
 		for _, status := range statusList {
 			subnetInfo.IPAddresses = append(subnetInfo.IPAddresses, *status.NetworkAddress)
 		}
+		if usage, err := service.GetSubnetIPUsage(subnet); err != nil {
+			log.Error(err, "failed to get IP usage of Subnet", "Subnet", *subnet.Id)
+		} else if usage != nil {
+			subnetInfo.IPUsage = usage
+			if ipUsage == nil {
+				ipUsage = &v1alpha1.IPUsage{}
+			}
+			addIPUsage(ipUsage, usage)
+		}
 		subnetInfoList = append(subnetInfoList, subnetInfo)
 	}
+	oldStatus := obj.Status.DeepCopy()
 	obj.Status.Subnets = subnetInfoList
+	obj.Status.IPUsage = ipUsage
+	UpdateIPUtilizationCondition(&obj.Status.Conditions, ipUsage, service.NSXConfig.GetIPUtilizationThreshold())
+	if reflect.DeepEqual(oldStatus, &obj.Status) {
+		return nil
+	}
 	if err := service.Client.Status().Update(context.Background(), obj); err != nil {
 		log.Error(err, "failed to update SubnetSet status")
 		return err
@@ -294,6 +344,82 @@ func (service *SubnetService) GetSubnetByPath(path string) (*model.VpcSubnet, er
 	return nsxSubnet, err
 }
 
+// GetSubnetByCR returns the NSX Subnet created for or imported by the Subnet CR from the store.
+func (service *SubnetService) GetSubnetByCR(obj *v1alpha1.Subnet) *model.VpcSubnet {
+	if obj.Spec.NSXSubnetPath != "" {
+		nsxSubnet, _ := service.GetSubnetByPath(obj.Spec.NSXSubnetPath)
+		return nsxSubnet
+	}
+	return service.SubnetStore.GetByKey(service.BuildSubnetID(obj))
+}
+
+// ImportSubnet searches the existing NSX Subnet and adds it to the store. The NSX Subnet must be in
+// the VPC, and must not be created by the operator for a Subnet or SubnetSet CR.
+func (service *SubnetService) ImportSubnet(path string, vpcInfo common.VPCResourceInfo) (*model.VpcSubnet, error) {
+	pathInfo, err := common.ParseVPCResourcePath(path)
+	if err != nil || pathInfo.ParentID != pathInfo.VPCID || !strings.HasSuffix(path, "/subnets/"+pathInfo.ID) {
+		return nil, nsxutil.RestrictionError{Desc: fmt.Sprintf("invalid NSX Subnet path %s", path)}
+	}
+	if pathInfo.OrgID != vpcInfo.OrgID || pathInfo.ProjectID != vpcInfo.ProjectID || pathInfo.VPCID != vpcInfo.VPCID {
+		return nil, nsxutil.RestrictionError{Desc: fmt.Sprintf("NSX Subnet %s is not in the VPC %s", path, vpcInfo.VPCID)}
+	}
+	queryParam := fmt.Sprintf("%s:%s AND path:%s AND marked_for_delete:false", common.ResourceType, ResourceTypeSubnet, strings.ReplaceAll(path, "/", "\\/"))
+	if _, err := service.SearchResource(ResourceTypeSubnet, queryParam, service.SubnetStore, nil); err != nil {
+		log.Error(err, "failed to search NSX Subnet", "Path", path)
+		return nil, err
+	}
+	nsxSubnet := service.SubnetStore.GetByKey(pathInfo.ID)
+	if nsxSubnet == nil || nsxSubnet.Path == nil || *nsxSubnet.Path != path {
+		return nil, fmt.Errorf("NSX Subnet %s not found", path)
+	}
+	if nsxutil.FindTag(nsxSubnet.Tags, common.TagScopeSubnetCRUID) != "" || nsxutil.FindTag(nsxSubnet.Tags, common.TagScopeSubnetSetCRUID) != "" {
+		return nil, nsxutil.RestrictionError{Desc: fmt.Sprintf("NSX Subnet %s is managed by nsx-operator and can't be imported", path)}
+	}
+	log.Info("imported NSX Subnet", "Path", path)
+	return nsxSubnet, nil
+}
+
+// RemoveImportedSubnet removes the NSX Subnet imported by a Subnet CR from the store, the NSX Subnet itself is
+// not deleted. The NSX Subnets created by the operator are kept.
+func (service *SubnetService) RemoveImportedSubnet(path string) error {
+	nsxSubnet, err := service.GetSubnetByPath(path)
+	if err != nil || nsxSubnet.Path == nil || *nsxSubnet.Path != path {
+		return nil
+	}
+	if nsxutil.FindTag(nsxSubnet.Tags, common.TagScopeSubnetCRUID) != "" || nsxutil.FindTag(nsxSubnet.Tags, common.TagScopeSubnetSetCRUID) != "" {
+		return nil
+	}
+	subnetCopy := *nsxSubnet
+	subnetCopy.MarkedForDelete = &MarkedForDelete
+	if err := service.SubnetStore.Apply(&subnetCopy); err != nil {
+		log.Error(err, "failed to remove imported NSX Subnet from store", "Path", path)
+		return err
+	}
+	log.Info("removed imported NSX Subnet from store", "Path", path)
+	return nil
+}
+
+// ListImportedSubnets returns the NSX Subnets imported by the Subnet CRs in the ImportedSubnets of the SubnetSet.
+// The Subnet CRs which are not found, not imported yet or in another VPC are skipped.
+func (service *SubnetService) ListImportedSubnets(subnetSet *v1alpha1.SubnetSet) []*model.VpcSubnet {
+	var nsxSubnets []*model.VpcSubnet
+	for _, name := range subnetSet.Spec.ImportedSubnets {
+		obj := &v1alpha1.Subnet{}
+		if err := service.Client.Get(context.Background(), types.NamespacedName{Namespace: subnetSet.Namespace, Name: name}, obj); err != nil {
+			log.V(1).Info("failed to get imported Subnet of SubnetSet", "SubnetSet", subnetSet.Name, "Subnet", name, "error", err)
+			continue
+		}
+		if obj.Spec.NSXSubnetPath == "" || obj.Status.NSXResourcePath == "" || obj.Spec.VPCName != subnetSet.Spec.VPCName {
+			log.V(1).Info("Subnet doesn't import NSX Subnet for SubnetSet", "SubnetSet", subnetSet.Name, "Subnet", name)
+			continue
+		}
+		if nsxSubnet := service.GetSubnetByCR(obj); nsxSubnet != nil {
+			nsxSubnets = append(nsxSubnets, nsxSubnet)
+		}
+	}
+	return nsxSubnets
+}
+
 func (service *SubnetService) ListSubnetID() sets.Set[string] {
 	subnets := service.SubnetStore.ListIndexFuncValues(common.TagScopeSubnetCRUID)
 	subnetSets := service.SubnetStore.ListIndexFuncValues(common.TagScopeSubnetSetCRUID)
@@ -387,7 +513,8 @@ func (service *SubnetService) UpdateSubnetSetTags(ns string, vpcSubnets []*model
 				return err
 			}
 			newTags := append(service.buildBasicTags(subnetSet), tags...)
-			changed := common.CompareResource(SubnetToComparable(vpcSubnets[i]), SubnetToComparable(&model.VpcSubnet{Tags: newTags}))
+			// only the tags are updated, compare them without the size and CIDRs of the existing Subnet
+			changed := common.CompareResource(SubnetToComparable(&model.VpcSubnet{Tags: vpcSubnets[i].Tags}), SubnetToComparable(&model.VpcSubnet{Tags: newTags}))
 			if !changed {
 				log.Info("NSX subnet tags unchanged, skip updating")
 				continue
@@ -0,0 +1,123 @@
+package subnet
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/agiledragon/gomonkey/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+func newFakeSubnetService() *SubnetService {
+	return &SubnetService{
+		SubnetStore: &SubnetStore{ResourceStore: common.ResourceStore{
+			Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
+				common.TagScopeSubnetCRUID:    subnetIndexFunc,
+				common.TagScopeSubnetSetCRUID: subnetSetIndexFunc,
+			}),
+			BindingType: model.VpcSubnetBindingType(),
+		}},
+	}
+}
+
+func TestImportSubnet(t *testing.T) {
+	service := newFakeSubnetService()
+	vpcInfo := common.VPCResourceInfo{OrgID: "default", ProjectID: "p1", VPCID: "vpc1"}
+	path := "/orgs/default/projects/p1/vpcs/vpc1/subnets/imported"
+	nsxSubnets := map[string]*model.VpcSubnet{}
+	patches := gomonkey.ApplyMethod(reflect.TypeOf(&service.Service), "SearchResource", func(_ *common.Service, _ string, _ string, store common.Store, _ common.Filter) (uint64, error) {
+		for _, nsxSubnet := range nsxSubnets {
+			assert.NoError(t, store.Apply(nsxSubnet))
+		}
+		return uint64(len(nsxSubnets)), nil
+	})
+	defer patches.Reset()
+
+	// The path is not an NSX Subnet in the VPC.
+	for _, invalidPath := range []string{"/orgs/default/projects/p1/vpcs/vpc1", "/orgs/default/projects/p1/vpcs/vpc2/subnets/imported"} {
+		_, err := service.ImportSubnet(invalidPath, vpcInfo)
+		assert.ErrorAs(t, err, &nsxutil.RestrictionError{})
+	}
+
+	// The NSX Subnet is not found.
+	_, err := service.ImportSubnet(path, vpcInfo)
+	assert.ErrorContains(t, err, "not found")
+
+	// The NSX Subnet is created by nsx-operator.
+	nsxSubnets["imported"] = &model.VpcSubnet{
+		Id:   common.String("imported"),
+		Path: common.String(path),
+		Tags: []model.Tag{{Scope: common.String(common.TagScopeSubnetSetCRUID), Tag: common.String("subnetset-uid")}},
+	}
+	_, err = service.ImportSubnet(path, vpcInfo)
+	assert.ErrorAs(t, err, &nsxutil.RestrictionError{})
+
+	nsxSubnets["imported"] = &model.VpcSubnet{Id: common.String("imported"), Path: common.String(path)}
+	nsxSubnet, err := service.ImportSubnet(path, vpcInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, path, *nsxSubnet.Path)
+	assert.Equal(t, nsxSubnet, service.GetSubnetByCR(&v1alpha1.Subnet{Spec: v1alpha1.SubnetSpec{NSXSubnetPath: path}}))
+	// The imported NSX Subnet is not deleted by Cleanup.
+	assert.Empty(t, service.ListSubnetID())
+
+	// The imported NSX Subnet is removed from the store.
+	assert.NoError(t, service.RemoveImportedSubnet(path))
+	assert.Nil(t, service.GetSubnetByCR(&v1alpha1.Subnet{Spec: v1alpha1.SubnetSpec{NSXSubnetPath: path}}))
+	assert.NoError(t, service.RemoveImportedSubnet(path))
+}
+
+func TestListImportedSubnets(t *testing.T) {
+	path := "/orgs/default/projects/p1/vpcs/vpc1/subnets/imported"
+	imported := &v1alpha1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "imported"},
+		Spec:       v1alpha1.SubnetSpec{NSXSubnetPath: path},
+		Status:     v1alpha1.SubnetStatus{NSXResourcePath: path},
+	}
+	pending := &v1alpha1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "pending"},
+		Spec:       v1alpha1.SubnetSpec{NSXSubnetPath: "/orgs/default/projects/p1/vpcs/vpc1/subnets/pending"},
+	}
+	managed := &v1alpha1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "managed"},
+		Status:     v1alpha1.SubnetStatus{NSXResourcePath: "/orgs/default/projects/p1/vpcs/vpc1/subnets/managed"},
+	}
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	service := newFakeSubnetService()
+	service.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(imported, pending, managed).Build()
+	assert.NoError(t, service.SubnetStore.Apply(&model.VpcSubnet{Id: common.String("imported"), Path: common.String(path)}))
+
+	subnetSet := &v1alpha1.SubnetSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "subnetset1"},
+		Spec:       v1alpha1.SubnetSetSpec{ImportedSubnets: []string{"imported", "pending", "managed", "missing"}},
+	}
+	nsxSubnets := service.ListImportedSubnets(subnetSet)
+	assert.Len(t, nsxSubnets, 1)
+	assert.Equal(t, path, *nsxSubnets[0].Path)
+
+	// The imported Subnet in another VPC is skipped.
+	subnetSet.Spec.VPCName = "vpc2"
+	assert.Empty(t, service.ListImportedSubnets(subnetSet))
+}
+
+func TestListSubnetByVPCPath(t *testing.T) {
+	service := newFakeSubnetService()
+	for _, path := range []string{
+		"/orgs/default/projects/p1/vpcs/vpc1/subnets/subnet1",
+		"/orgs/default/projects/p1/vpcs/vpc1/subnets/subnet2",
+		"/orgs/default/projects/p1/vpcs/vpc10/subnets/subnet1",
+	} {
+		assert.NoError(t, service.SubnetStore.Apply(&model.VpcSubnet{Id: common.String(path), Path: common.String(path)}))
+	}
+	assert.Len(t, service.ListSubnetByVPCPath("/orgs/default/projects/p1/vpcs/vpc1"), 2)
+	assert.Len(t, service.ListSubnetByVPCPath("/orgs/default/projects/p1/vpcs/vpc2"), 0)
+}
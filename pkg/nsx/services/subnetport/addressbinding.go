@@ -0,0 +1,195 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetport
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// ipPoolID is the static IP pool of the NSX Subnet where the requested IPs are reserved.
+const ipPoolID = "static-ipv4-default"
+
+// getAddressBinding returns the address binding requested by the SubnetPort CR, or nil if no address is requested.
+func getAddressBinding(obj interface{}) *v1alpha1.AddressBinding {
+	subnetPort, ok := obj.(*v1alpha1.SubnetPort)
+	if !ok || len(subnetPort.Spec.AddressBindings) == 0 {
+		return nil
+	}
+	binding := subnetPort.Spec.AddressBindings[0]
+	if binding.IPAddress == "" && binding.MACAddress == "" {
+		return nil
+	}
+	return &binding
+}
+
+// getAllocateAddresses returns how NSX allocates the addresses which are not requested by the user.
+func getAllocateAddresses(binding *v1alpha1.AddressBinding, enableDHCP bool) string {
+	switch {
+	case binding == nil && enableDHCP:
+		return "DHCP"
+	case binding == nil:
+		return "BOTH"
+	case binding.IPAddress != "" && binding.MACAddress != "":
+		return "NONE"
+	case binding.IPAddress != "":
+		return "MAC_POOL"
+	case enableDHCP:
+		return "DHCP"
+	default:
+		return "IP_POOL"
+	}
+}
+
+func buildAddressBindings(binding *v1alpha1.AddressBinding) []model.PortAddressBindingEntry {
+	if binding == nil {
+		return nil
+	}
+	entry := model.PortAddressBindingEntry{}
+	if binding.IPAddress != "" {
+		entry.IpAddress = String(binding.IPAddress)
+	}
+	if binding.MACAddress != "" {
+		entry.MacAddress = String(binding.MACAddress)
+	}
+	return []model.PortAddressBindingEntry{entry}
+}
+
+// getBindingIP returns the IP address bound to the NSX subnet port, or empty if the IP is allocated by NSX.
+func getBindingIP(nsxSubnetPort *model.VpcSubnetPort) string {
+	if nsxSubnetPort == nil || len(nsxSubnetPort.AddressBindings) == 0 || nsxSubnetPort.AddressBindings[0].IpAddress == nil {
+		return ""
+	}
+	return *nsxSubnetPort.AddressBindings[0].IpAddress
+}
+
+// validateAddressBinding checks the requested addresses are valid for the NSX Subnet and not requested by
+// the other ports on the Subnet.
+func (service *SubnetPortService) validateAddressBinding(binding *v1alpha1.AddressBinding, nsxSubnet *model.VpcSubnet, nsxSubnetPortID string) error {
+	if binding.IPAddress != "" {
+		ip := net.ParseIP(binding.IPAddress)
+		if ip == nil {
+			return nsxutil.RestrictionError{Desc: fmt.Sprintf("invalid IP address %s", binding.IPAddress)}
+		}
+		if nsxSubnet.DhcpConfig != nil && nsxSubnet.DhcpConfig.EnableDhcp != nil && *nsxSubnet.DhcpConfig.EnableDhcp {
+			return nsxutil.RestrictionError{Desc: fmt.Sprintf("IP address %s can't be requested from the Subnet with DHCP enabled", binding.IPAddress)}
+		}
+		if len(nsxSubnet.IpAddresses) > 0 {
+			inSubnet := false
+			for _, cidr := range nsxSubnet.IpAddresses {
+				_, ipNet, err := net.ParseCIDR(cidr)
+				if err == nil && ipNet.Contains(ip) {
+					inSubnet = true
+					break
+				}
+			}
+			if !inSubnet {
+				return nsxutil.RestrictionError{Desc: fmt.Sprintf("IP address %s is not in the Subnet %v", binding.IPAddress, nsxSubnet.IpAddresses)}
+			}
+		}
+	}
+	if binding.MACAddress != "" {
+		if _, err := net.ParseMAC(binding.MACAddress); err != nil {
+			return nsxutil.RestrictionError{Desc: fmt.Sprintf("invalid MAC address %s", binding.MACAddress)}
+		}
+	}
+	if nsxSubnet.Id == nil {
+		return nil
+	}
+	for _, port := range service.GetPortsOfSubnet(*nsxSubnet.Id) {
+		if port.Id == nil || *port.Id == nsxSubnetPortID {
+			continue
+		}
+		for _, entry := range port.AddressBindings {
+			if binding.IPAddress != "" && entry.IpAddress != nil && net.ParseIP(*entry.IpAddress).Equal(net.ParseIP(binding.IPAddress)) {
+				return nsxutil.AddressUnavailableError{Desc: fmt.Sprintf("requested IP address %s is unavailable, it's bound to the port %s", binding.IPAddress, *port.Id)}
+			}
+			if binding.MACAddress != "" && entry.MacAddress != nil && strings.EqualFold(*entry.MacAddress, binding.MACAddress) {
+				return nsxutil.AddressUnavailableError{Desc: fmt.Sprintf("requested MAC address %s is unavailable, it's bound to the port %s", binding.MACAddress, *port.Id)}
+			}
+		}
+	}
+	return nil
+}
+
+// buildIPAllocationID returns the ID of the IP allocation reserving the IP bound to the NSX subnet port. The IP is
+// part of the ID, so that the new IP can be reserved before the IP reserved for the existing port is released.
+func buildIPAllocationID(nsxSubnetPort *model.VpcSubnetPort, ip string) string {
+	return fmt.Sprintf("%s_%s", *nsxSubnetPort.Id, strings.NewReplacer(".", "-", ":", "-").Replace(ip))
+}
+
+// reserveIP reserves the IP address bound to the NSX subnet port from the static IP pool of the Subnet, so that
+// it's not allocated to the other ports. It returns true if a new IP is reserved.
+func (service *SubnetPortService) reserveIP(nsxSubnetPort *model.VpcSubnetPort, existingSubnetPort *model.VpcSubnetPort) (bool, error) {
+	ip := getBindingIP(nsxSubnetPort)
+	if ip == "" || ip == getBindingIP(existingSubnetPort) {
+		return false, nil
+	}
+	subnetInfo, err := servicecommon.ParseVPCResourcePath(*nsxSubnetPort.ParentPath)
+	if err != nil {
+		return false, err
+	}
+	allocationID := buildIPAllocationID(nsxSubnetPort, ip)
+	allocation := model.IpAddressAllocation{
+		Id:           String(allocationID),
+		DisplayName:  nsxSubnetPort.DisplayName,
+		AllocationIp: String(ip),
+		Tags:         nsxSubnetPort.Tags,
+	}
+	if err := service.NSXClient.IPAllocationClient.Patch(subnetInfo.OrgID, subnetInfo.ProjectID, subnetInfo.VPCID, subnetInfo.ID, ipPoolID, allocationID, allocation); err != nil {
+		log.Error(err, "failed to reserve IP address", "nsxSubnetPort.Id", *nsxSubnetPort.Id, "ip", ip)
+		return false, nsxutil.AddressUnavailableError{Desc: fmt.Sprintf("requested IP address %s is unavailable: %v", ip, err)}
+	}
+	log.Info("reserved IP address for subnet port", "nsxSubnetPort.Id", *nsxSubnetPort.Id, "ip", ip)
+	return true, nil
+}
+
+// patchSubnetPort patches the NSX subnet port with the IP address bound to it reserved. The IP is reserved before
+// the port is patched, and the IP reserved for the existing port is released only after the port is patched, so
+// that the IP bound to the port is always reserved. The new reservation is rolled back if the port is not patched.
+func (service *SubnetPortService) patchSubnetPort(nsxSubnetPort *model.VpcSubnetPort, existingSubnetPort *model.VpcSubnetPort) error {
+	subnetInfo, err := servicecommon.ParseVPCResourcePath(*nsxSubnetPort.ParentPath)
+	if err != nil {
+		return err
+	}
+	reserved, err := service.reserveIP(nsxSubnetPort, existingSubnetPort)
+	if err != nil {
+		return err
+	}
+	if err := service.NSXClient.PortClient.Patch(subnetInfo.OrgID, subnetInfo.ProjectID, subnetInfo.VPCID, subnetInfo.ID, *nsxSubnetPort.Id, *nsxSubnetPort); err != nil {
+		log.Error(err, "failed to create or update subnet port", "nsxSubnetPort.Id", *nsxSubnetPort.Id, "nsxSubnetPath", *nsxSubnetPort.ParentPath)
+		if reserved {
+			if err := service.releaseIPReservation(nsxSubnetPort); err != nil {
+				log.Error(err, "failed to roll back the IP reservation", "nsxSubnetPort.Id", *nsxSubnetPort.Id)
+			}
+		}
+		return err
+	}
+	if existingIP := getBindingIP(existingSubnetPort); existingIP != "" && existingIP != getBindingIP(nsxSubnetPort) {
+		return service.releaseIPReservation(existingSubnetPort)
+	}
+	return nil
+}
+
+// releaseIPReservation releases the IP address reserved for the NSX subnet port if any.
+func (service *SubnetPortService) releaseIPReservation(nsxSubnetPort *model.VpcSubnetPort) error {
+	ip := getBindingIP(nsxSubnetPort)
+	if ip == "" {
+		return nil
+	}
+	nsxOrgID, nsxProjectID, nsxVPCID, nsxSubnetID := nsxutil.ParseVPCPath(*nsxSubnetPort.Path)
+	if err := service.NSXClient.IPAllocationClient.Delete(nsxOrgID, nsxProjectID, nsxVPCID, nsxSubnetID, ipPoolID, buildIPAllocationID(nsxSubnetPort, ip)); err != nil {
+		log.Error(err, "failed to release IP address", "nsxSubnetPort.Id", *nsxSubnetPort.Id)
+		return err
+	}
+	log.Info("released IP address of subnet port", "nsxSubnetPort.Id", *nsxSubnetPort.Id, "ip", ip)
+	return nil
+}
@@ -0,0 +1,153 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetport
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/subnets"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/subnets/ip_pools"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+type fakeIPAllocationClient struct {
+	ip_pools.IpAllocationsClient
+	allocations map[string]string
+	patchErr    error
+}
+
+func (f *fakeIPAllocationClient) Patch(_ string, _ string, _ string, _ string, _ string, allocationID string, allocation model.IpAddressAllocation) error {
+	if f.patchErr != nil {
+		return f.patchErr
+	}
+	f.allocations[allocationID] = *allocation.AllocationIp
+	return nil
+}
+
+func (f *fakeIPAllocationClient) Delete(_ string, _ string, _ string, _ string, _ string, allocationID string) error {
+	delete(f.allocations, allocationID)
+	return nil
+}
+
+func TestGetAllocateAddresses(t *testing.T) {
+	assert.Equal(t, "DHCP", getAllocateAddresses(nil, true))
+	assert.Equal(t, "BOTH", getAllocateAddresses(nil, false))
+	assert.Equal(t, "NONE", getAllocateAddresses(&v1alpha1.AddressBinding{IPAddress: "10.0.0.5", MACAddress: "aa:bb:cc:dd:ee:ff"}, false))
+	assert.Equal(t, "MAC_POOL", getAllocateAddresses(&v1alpha1.AddressBinding{IPAddress: "10.0.0.5"}, false))
+	assert.Equal(t, "IP_POOL", getAllocateAddresses(&v1alpha1.AddressBinding{MACAddress: "aa:bb:cc:dd:ee:ff"}, false))
+	assert.Equal(t, "DHCP", getAllocateAddresses(&v1alpha1.AddressBinding{MACAddress: "aa:bb:cc:dd:ee:ff"}, true))
+}
+
+func TestValidateAddressBinding(t *testing.T) {
+	service := &SubnetPortService{
+		SubnetPortStore: &SubnetPortStore{ResourceStore: common.ResourceStore{
+			Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
+				common.IndexKeySubnetID: subnetPortIndexBySubnetID,
+			}),
+			BindingType: model.VpcSubnetPortBindingType(),
+		}},
+	}
+	subnetPath := "/orgs/default/projects/p1/vpcs/v1/subnets/subnet-1"
+	assert.NoError(t, service.SubnetPortStore.Apply(&model.VpcSubnetPort{
+		Id:              common.String("port-1"),
+		Path:            common.String(subnetPath + "/ports/port-1"),
+		ParentPath:      common.String(subnetPath),
+		AddressBindings: []model.PortAddressBindingEntry{{IpAddress: common.String("10.0.0.5"), MacAddress: common.String("aa:bb:cc:dd:ee:01")}},
+	}))
+	nsxSubnet := &model.VpcSubnet{
+		Id:          common.String("subnet-1"),
+		Path:        common.String(subnetPath),
+		IpAddresses: []string{"10.0.0.0/28"},
+		DhcpConfig:  &model.VpcSubnetDhcpConfig{EnableDhcp: common.Bool(false)},
+	}
+	dhcpSubnet := &model.VpcSubnet{
+		Id:         common.String("subnet-2"),
+		DhcpConfig: &model.VpcSubnetDhcpConfig{EnableDhcp: common.Bool(true)},
+	}
+
+	tests := []struct {
+		name        string
+		binding     v1alpha1.AddressBinding
+		nsxSubnet   *model.VpcSubnet
+		portID      string
+		restriction bool
+		unavailable bool
+	}{
+		{name: "valid", binding: v1alpha1.AddressBinding{IPAddress: "10.0.0.6", MACAddress: "aa:bb:cc:dd:ee:02"}, nsxSubnet: nsxSubnet, portID: "port-2"},
+		{name: "same port", binding: v1alpha1.AddressBinding{IPAddress: "10.0.0.5"}, nsxSubnet: nsxSubnet, portID: "port-1"},
+		{name: "invalid IP", binding: v1alpha1.AddressBinding{IPAddress: "10.0.0"}, nsxSubnet: nsxSubnet, portID: "port-2", restriction: true},
+		{name: "IP out of Subnet", binding: v1alpha1.AddressBinding{IPAddress: "10.0.1.5"}, nsxSubnet: nsxSubnet, portID: "port-2", restriction: true},
+		{name: "IP with DHCP", binding: v1alpha1.AddressBinding{IPAddress: "10.0.0.6"}, nsxSubnet: dhcpSubnet, portID: "port-2", restriction: true},
+		{name: "MAC with DHCP", binding: v1alpha1.AddressBinding{MACAddress: "aa:bb:cc:dd:ee:02"}, nsxSubnet: dhcpSubnet, portID: "port-2"},
+		{name: "invalid MAC", binding: v1alpha1.AddressBinding{MACAddress: "aa:bb:cc"}, nsxSubnet: nsxSubnet, portID: "port-2", restriction: true},
+		{name: "IP conflict", binding: v1alpha1.AddressBinding{IPAddress: "10.0.0.5"}, nsxSubnet: nsxSubnet, portID: "port-2", unavailable: true},
+		{name: "MAC conflict", binding: v1alpha1.AddressBinding{MACAddress: "AA:BB:CC:DD:EE:01"}, nsxSubnet: nsxSubnet, portID: "port-2", unavailable: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.validateAddressBinding(&tt.binding, tt.nsxSubnet, tt.portID)
+			assert.Equal(t, tt.restriction, errors.As(err, &nsxutil.RestrictionError{}))
+			assert.Equal(t, tt.unavailable, errors.As(err, &nsxutil.AddressUnavailableError{}))
+			if !tt.restriction && !tt.unavailable {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type fakePortsClient struct {
+	subnets.PortsClient
+	patchErr error
+}
+
+func (f *fakePortsClient) Patch(_ string, _ string, _ string, _ string, _ string, _ model.VpcSubnetPort) error {
+	return f.patchErr
+}
+
+func TestPatchSubnetPort(t *testing.T) {
+	allocationClient := &fakeIPAllocationClient{allocations: map[string]string{}}
+	portsClient := &fakePortsClient{}
+	service := &SubnetPortService{Service: common.Service{NSXClient: &nsx.Client{IPAllocationClient: allocationClient, PortClient: portsClient}}}
+	subnetPath := "/orgs/default/projects/p1/vpcs/v1/subnets/subnet-1"
+	newPort := func(ip string) *model.VpcSubnetPort {
+		port := &model.VpcSubnetPort{
+			Id:         common.String("port-1"),
+			Path:       common.String(subnetPath + "/ports/port-1"),
+			ParentPath: common.String(subnetPath),
+		}
+		if ip != "" {
+			port.AddressBindings = []model.PortAddressBindingEntry{{IpAddress: common.String(ip)}}
+		}
+		return port
+	}
+
+	assert.NoError(t, service.patchSubnetPort(newPort("10.0.0.5"), nil))
+	assert.Equal(t, map[string]string{"port-1_10-0-0-5": "10.0.0.5"}, allocationClient.allocations)
+
+	// The new IP is rolled back if the port fails to be patched, the existing IP is kept.
+	portsClient.patchErr = errors.New("port is in use")
+	assert.Error(t, service.patchSubnetPort(newPort("10.0.0.6"), newPort("10.0.0.5")))
+	assert.Equal(t, map[string]string{"port-1_10-0-0-5": "10.0.0.5"}, allocationClient.allocations)
+	portsClient.patchErr = nil
+
+	// The existing IP is released after the port is patched with the new IP.
+	assert.NoError(t, service.patchSubnetPort(newPort("10.0.0.6"), newPort("10.0.0.5")))
+	assert.Equal(t, map[string]string{"port-1_10-0-0-6": "10.0.0.6"}, allocationClient.allocations)
+
+	// The IP is allocated by NSX now.
+	assert.NoError(t, service.patchSubnetPort(newPort(""), newPort("10.0.0.6")))
+	assert.Empty(t, allocationClient.allocations)
+
+	allocationClient.patchErr = errors.New("IP is allocated")
+	err := service.patchSubnetPort(newPort("10.0.0.5"), nil)
+	assert.True(t, errors.As(err, &nsxutil.AddressUnavailableError{}))
+}
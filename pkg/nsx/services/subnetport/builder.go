@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
@@ -21,23 +22,24 @@ var (
 )
 
 func (service *SubnetPortService) buildSubnetPort(obj interface{}, nsxSubnet *model.VpcSubnet, contextID string, labelTags *map[string]string) (*model.VpcSubnetPort, error) {
-	var objName, objNamespace, uid, appId, allocateAddresses string
+	var objName, objNamespace, uid, appId string
 	switch o := obj.(type) {
 	case *v1alpha1.SubnetPort:
 		objName = o.Name
 		objNamespace = o.Namespace
 		uid = string(o.UID)
+		if owner := metav1.GetControllerOf(o); owner != nil && owner.Kind == "Pod" {
+			// The SubnetPort of a Pod interface is a container interface on the node as the Pod port.
+			appId = string(o.UID)
+		}
 	case *corev1.Pod:
 		objName = o.Name
 		objNamespace = o.Namespace
 		uid = string(o.UID)
 		appId = string(o.UID)
 	}
-	if *nsxSubnet.DhcpConfig.EnableDhcp {
-		allocateAddresses = "DHCP"
-	} else {
-		allocateAddresses = "BOTH"
-	}
+	addressBinding := getAddressBinding(obj)
+	allocateAddresses := getAllocateAddresses(addressBinding, *nsxSubnet.DhcpConfig.EnableDhcp)
 	nsxSubnetPortName := util.GenerateDisplayName(objName, "port", "", "", "")
 	nsxSubnetPortID := util.GenerateID(uid, "", "", "")
 	// use the subnetPort CR UID as the attachment uid generation to ensure the latter stable
@@ -72,9 +74,10 @@ func (service *SubnetPortService) buildSubnetPort(obj interface{}, nsxSubnet *mo
 			TrafficTag:        common.Int64(0),
 			Type_:             String("STATIC"),
 		},
-		Tags:       tags,
-		Path:       &nsxSubnetPortPath,
-		ParentPath: nsxSubnet.Path,
+		AddressBindings: buildAddressBindings(addressBinding),
+		Tags:            tags,
+		Path:            &nsxSubnetPortPath,
+		ParentPath:      nsxSubnet.Path,
 	}
 	if appId != "" {
 		nsxSubnetPort.Attachment.AppId = &appId
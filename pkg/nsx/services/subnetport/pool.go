@@ -0,0 +1,200 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetport
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	servicecommon "github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/realizestate"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+// SubnetPortPoolKey identifies the pool of the pre-created subnet ports of a SubnetSet on a node.
+type SubnetPortPoolKey struct {
+	SubnetSetUID string
+	ContextID    string
+}
+
+// getPoolKey returns the pool key of the subnet port, and false if the port is not a pre-created one.
+func getPoolKey(nsxSubnetPort *model.VpcSubnetPort) (SubnetPortPoolKey, bool) {
+	subnetSetUIDs := filterTag(nsxSubnetPort.Tags, servicecommon.TagScopeSubnetPortPoolSubnetSetUID)
+	nodes := filterTag(nsxSubnetPort.Tags, servicecommon.TagScopeSubnetPortPoolNode)
+	if len(subnetSetUIDs) == 0 || len(nodes) == 0 {
+		return SubnetPortPoolKey{}, false
+	}
+	return SubnetPortPoolKey{SubnetSetUID: subnetSetUIDs[0], ContextID: nodes[0]}, true
+}
+
+// isClaimed returns true if the pre-created subnet port is claimed by a Pod. The caller should hold the poolLock.
+func (service *SubnetPortService) isClaimed(nsxSubnetPort *model.VpcSubnetPort) bool {
+	for _, claimed := range service.claimedPorts {
+		if *claimed.Id == *nsxSubnetPort.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// ListSubnetPortPools returns the pre-created subnet ports which are not claimed, grouped by the pool.
+func (service *SubnetPortService) ListSubnetPortPools() map[SubnetPortPoolKey][]*model.VpcSubnetPort {
+	service.poolLock.Lock()
+	defer service.poolLock.Unlock()
+	pools := map[SubnetPortPoolKey][]*model.VpcSubnetPort{}
+	for _, subnetSetUID := range service.SubnetPortStore.ListIndexFuncValues(servicecommon.TagScopeSubnetPortPoolSubnetSetUID).UnsortedList() {
+		for _, nsxSubnetPort := range service.SubnetPortStore.GetByIndex(servicecommon.TagScopeSubnetPortPoolSubnetSetUID, subnetSetUID) {
+			key, ok := getPoolKey(nsxSubnetPort)
+			if !ok || service.isClaimed(nsxSubnetPort) {
+				continue
+			}
+			pools[key] = append(pools[key], nsxSubnetPort)
+		}
+	}
+	return pools
+}
+
+// ListPooledSubnetPorts returns the pre-created subnet ports of the SubnetSet on the node which are not claimed.
+func (service *SubnetPortService) ListPooledSubnetPorts(subnetSetUID string, contextID string) []*model.VpcSubnetPort {
+	service.poolLock.Lock()
+	defer service.poolLock.Unlock()
+	return service.listPooledSubnetPorts(subnetSetUID, contextID)
+}
+
+func (service *SubnetPortService) listPooledSubnetPorts(subnetSetUID string, contextID string) []*model.VpcSubnetPort {
+	var ports []*model.VpcSubnetPort
+	for _, nsxSubnetPort := range service.SubnetPortStore.GetByIndex(servicecommon.TagScopeSubnetPortPoolSubnetSetUID, subnetSetUID) {
+		key, ok := getPoolKey(nsxSubnetPort)
+		if !ok || key.ContextID != contextID || service.isClaimed(nsxSubnetPort) {
+			continue
+		}
+		ports = append(ports, nsxSubnetPort)
+	}
+	return ports
+}
+
+// ClaimPooledSubnetPort claims a pre-created subnet port of the SubnetSet on the node for the Pod, and returns
+// the path of its Subnet, or empty if the pool is empty. The port is bound to the Pod by CreateOrUpdateSubnetPort.
+func (service *SubnetPortService) ClaimPooledSubnetPort(podUID types.UID, subnetSetUID string, contextID string) string {
+	service.poolLock.Lock()
+	defer service.poolLock.Unlock()
+	if claimed, ok := service.claimedPorts[podUID]; ok {
+		return *claimed.ParentPath
+	}
+	ports := service.listPooledSubnetPorts(subnetSetUID, contextID)
+	if len(ports) == 0 {
+		return ""
+	}
+	if service.claimedPorts == nil {
+		service.claimedPorts = map[types.UID]*model.VpcSubnetPort{}
+	}
+	service.claimedPorts[podUID] = ports[0]
+	log.Info("claimed pre-created subnet port for pod", "podUID", podUID, "nsxSubnetPort.Id", *ports[0].Id)
+	return *ports[0].ParentPath
+}
+
+// unclaimSubnetPort removes the claimed port of the Pod after it's bound or deleted.
+func (service *SubnetPortService) unclaimSubnetPort(podUID types.UID) {
+	service.poolLock.Lock()
+	defer service.poolLock.Unlock()
+	delete(service.claimedPorts, podUID)
+}
+
+// getSubnetPortByUID returns the NSX subnet port of the SubnetPort CR or Pod. The port claimed from the pool
+// has a different ID from the UID, so it's looked up in the claimed ports and by the Pod UID tag.
+func (service *SubnetPortService) getSubnetPortByUID(uid types.UID) *model.VpcSubnetPort {
+	if nsxSubnetPort := service.SubnetPortStore.GetByKey(string(uid)); nsxSubnetPort != nil {
+		return nsxSubnetPort
+	}
+	service.poolLock.Lock()
+	claimed, ok := service.claimedPorts[uid]
+	service.poolLock.Unlock()
+	if ok {
+		return claimed
+	}
+	if ports := service.SubnetPortStore.GetByIndex(servicecommon.TagScopePodUID, string(uid)); len(ports) > 0 {
+		return ports[0]
+	}
+	return nil
+}
+
+// CreatePooledSubnetPort pre-creates an unbound subnet port of the SubnetSet on the NSX Subnet for the node,
+// and waits for its realization, so that the Pod claiming it doesn't need to wait.
+func (service *SubnetPortService) CreatePooledSubnetPort(nsxSubnet *model.VpcSubnet, subnetSetUID string, contextID string) error {
+	nsxSubnetPortID := uuid.NewString()
+	enableDHCP := nsxSubnet.DhcpConfig != nil && nsxSubnet.DhcpConfig.EnableDhcp != nil && *nsxSubnet.DhcpConfig.EnableDhcp
+	allocateAddresses := getAllocateAddresses(nil, enableDHCP)
+	nsxSubnetPort := &model.VpcSubnetPort{
+		DisplayName: String(fmt.Sprintf("pool-port-%s", strings.Split(nsxSubnetPortID, "-")[0])),
+		Id:          String(nsxSubnetPortID),
+		Attachment: &model.PortAttachment{
+			AllocateAddresses: &allocateAddresses,
+			AppId:             String(nsxSubnetPortID),
+			ContextId:         String(contextID),
+			Id:                String(uuid.NewString()),
+			TrafficTag:        servicecommon.Int64(0),
+			Type_:             String("STATIC"),
+		},
+		Tags: []model.Tag{
+			{Scope: String(servicecommon.TagScopeCluster), Tag: String(getCluster(service))},
+			{Scope: String(servicecommon.TagScopeVersion), Tag: String(strings.Join(servicecommon.TagValueVersion, "."))},
+			{Scope: String(servicecommon.TagScopeSubnetPortPoolSubnetSetUID), Tag: String(subnetSetUID)},
+			{Scope: String(servicecommon.TagScopeSubnetPortPoolNode), Tag: String(contextID)},
+		},
+		Path:       String(fmt.Sprintf("%s/ports/%s", *nsxSubnet.Path, nsxSubnetPortID)),
+		ParentPath: nsxSubnet.Path,
+	}
+	subnetInfo, err := servicecommon.ParseVPCResourcePath(*nsxSubnet.Path)
+	if err != nil {
+		return err
+	}
+	if err := service.NSXClient.PortClient.Patch(subnetInfo.OrgID, subnetInfo.ProjectID, subnetInfo.VPCID, subnetInfo.ID, nsxSubnetPortID, *nsxSubnetPort); err != nil {
+		log.Error(err, "failed to create pre-created subnet port", "nsxSubnetPort.Id", nsxSubnetPortID, "nsxSubnetPath", *nsxSubnet.Path)
+		return err
+	}
+	realizeService := realizestate.InitializeRealizeState(service.Service)
+	backoff := wait.Backoff{
+		Duration: 1 * time.Second,
+		Factor:   2.0,
+		Jitter:   0,
+		Steps:    6,
+	}
+	if err := realizeService.CheckRealizeState(backoff, *nsxSubnetPort.Path, "RealizedLogicalPort"); err != nil {
+		log.Error(err, "pre-created subnet port is not realized, deleting it", "nsxSubnetPort.Id", nsxSubnetPortID)
+		if err := service.NSXClient.PortClient.Delete(subnetInfo.OrgID, subnetInfo.ProjectID, subnetInfo.VPCID, subnetInfo.ID, nsxSubnetPortID); err != nil {
+			log.Error(err, "failed to delete pre-created subnet port", "nsxSubnetPort.Id", nsxSubnetPortID)
+		}
+		return err
+	}
+	if err := service.SubnetPortStore.Apply(nsxSubnetPort); err != nil {
+		return err
+	}
+	log.Info("pre-created subnet port", "nsxSubnetPort.Id", nsxSubnetPortID, "subnetSetUID", subnetSetUID, "contextID", contextID)
+	return nil
+}
+
+// DeletePooledSubnetPort deletes the pre-created subnet port which is not claimed.
+func (service *SubnetPortService) DeletePooledSubnetPort(nsxSubnetPort *model.VpcSubnetPort) error {
+	service.poolLock.Lock()
+	defer service.poolLock.Unlock()
+	if service.isClaimed(nsxSubnetPort) {
+		return nil
+	}
+	nsxOrgID, nsxProjectID, nsxVPCID, nsxSubnetID := nsxutil.ParseVPCPath(*nsxSubnetPort.Path)
+	if err := service.NSXClient.PortClient.Delete(nsxOrgID, nsxProjectID, nsxVPCID, nsxSubnetID, *nsxSubnetPort.Id); err != nil {
+		log.Error(err, "failed to delete pre-created subnet port", "nsxSubnetPort.Id", *nsxSubnetPort.Id)
+		return err
+	}
+	if err := service.SubnetPortStore.Delete(nsxSubnetPort); err != nil {
+		return err
+	}
+	log.Info("deleted pre-created subnet port", "nsxSubnetPort.Id", *nsxSubnetPort.Id)
+	return nil
+}
@@ -0,0 +1,94 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package subnetport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/subnets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+type fakePortClient struct {
+	subnets.PortsClient
+	deleted []string
+}
+
+func (f *fakePortClient) Delete(_ string, _ string, _ string, _ string, portID string) error {
+	f.deleted = append(f.deleted, portID)
+	return nil
+}
+
+func newPooledSubnetPort(id string, subnetSetUID string, contextID string) *model.VpcSubnetPort {
+	subnetPath := "/orgs/default/projects/p1/vpcs/v1/subnets/subnet-1"
+	return &model.VpcSubnetPort{
+		Id:         common.String(id),
+		Path:       common.String(subnetPath + "/ports/" + id),
+		ParentPath: common.String(subnetPath),
+		Attachment: &model.PortAttachment{Id: common.String("attachment-" + id)},
+		Tags: []model.Tag{
+			{Scope: common.String(common.TagScopeSubnetPortPoolSubnetSetUID), Tag: common.String(subnetSetUID)},
+			{Scope: common.String(common.TagScopeSubnetPortPoolNode), Tag: common.String(contextID)},
+		},
+	}
+}
+
+func TestSubnetPortPool(t *testing.T) {
+	portClient := &fakePortClient{}
+	service := &SubnetPortService{
+		Service: common.Service{NSXClient: &nsx.Client{PortClient: portClient}},
+		SubnetPortStore: &SubnetPortStore{ResourceStore: common.ResourceStore{
+			Indexer: cache.NewIndexer(keyFunc, cache.Indexers{
+				common.TagScopePodUID:                     subnetPortIndexByPodUID,
+				common.IndexKeySubnetID:                   subnetPortIndexBySubnetID,
+				common.TagScopeSubnetPortPoolSubnetSetUID: subnetPortIndexByPoolSubnetSetUID,
+			}),
+			BindingType: model.VpcSubnetPortBindingType(),
+		}},
+	}
+	for _, port := range []*model.VpcSubnetPort{
+		newPooledSubnetPort("port-1", "subnetset-1", "node-1"),
+		newPooledSubnetPort("port-2", "subnetset-1", "node-2"),
+		newPooledSubnetPort("port-3", "subnetset-2", "node-1"),
+	} {
+		assert.NoError(t, service.SubnetPortStore.Apply(port))
+	}
+	assert.Len(t, service.ListSubnetPortPools(), 3)
+	assert.Len(t, service.ListPooledSubnetPorts("subnetset-1", "node-1"), 1)
+
+	// The pool is empty on the other node.
+	assert.Equal(t, "", service.ClaimPooledSubnetPort("pod-1", "subnetset-1", "node-3"))
+	subnetPath := service.ClaimPooledSubnetPort("pod-1", "subnetset-1", "node-1")
+	assert.Equal(t, "/orgs/default/projects/p1/vpcs/v1/subnets/subnet-1", subnetPath)
+	// The claim is idempotent.
+	assert.Equal(t, subnetPath, service.ClaimPooledSubnetPort("pod-1", "subnetset-1", "node-1"))
+	assert.Empty(t, service.ListPooledSubnetPorts("subnetset-1", "node-1"))
+	assert.Equal(t, "port-1", *service.getSubnetPortByUID("pod-1").Id)
+	assert.Equal(t, subnetPath, service.GetSubnetPathForSubnetPortFromStore("pod-1"))
+
+	// The claimed port is not deleted from the pool.
+	assert.NoError(t, service.DeletePooledSubnetPort(service.getSubnetPortByUID("pod-1")))
+	assert.Empty(t, portClient.deleted)
+
+	// The port is found by the Pod UID tag after it's bound.
+	bound := newPooledSubnetPort("port-1", "", "")
+	bound.Tags = []model.Tag{{Scope: common.String(common.TagScopePodUID), Tag: common.String("pod-1")}}
+	assert.NoError(t, service.SubnetPortStore.Apply(bound))
+	service.unclaimSubnetPort("pod-1")
+	assert.Equal(t, "port-1", *service.getSubnetPortByUID("pod-1").Id)
+	assert.NotContains(t, service.ListSubnetPortPools(), SubnetPortPoolKey{SubnetSetUID: "subnetset-1", ContextID: "node-1"})
+
+	assert.NoError(t, service.DeleteSubnetPort("pod-1"))
+	assert.Equal(t, []string{"port-1"}, portClient.deleted)
+	assert.Nil(t, service.getSubnetPortByUID("pod-1"))
+
+	assert.NoError(t, service.DeletePooledSubnetPort(service.ListPooledSubnetPorts("subnetset-2", "node-1")[0]))
+	assert.Equal(t, []string{"port-1", "port-3"}, portClient.deleted)
+	assert.Len(t, service.ListSubnetPortPools(), 1)
+}
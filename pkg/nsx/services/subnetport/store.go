@@ -51,6 +51,15 @@ func subnetPortIndexByPodUID(obj interface{}) ([]string, error) {
 	}
 }
 
+func subnetPortIndexByPoolSubnetSetUID(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.VpcSubnetPort:
+		return filterTag(o.Tags, common.TagScopeSubnetPortPoolSubnetSetUID), nil
+	default:
+		return nil, errors.New("subnetPortIndexByPoolSubnetSetUID doesn't support unknown type")
+	}
+}
+
 func subnetPortIndexBySubnetID(obj interface{}) ([]string, error) {
 	switch o := obj.(type) {
 	case *model.VpcSubnetPort:
@@ -6,6 +6,7 @@ package subnetport
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync"
 	"time"
 
@@ -33,6 +34,9 @@ var (
 type SubnetPortService struct {
 	servicecommon.Service
 	SubnetPortStore *SubnetPortStore
+	// claimedPorts holds the pooled subnet ports claimed by the Pods which are not bound yet, keyed by the Pod UID.
+	claimedPorts map[types.UID]*model.VpcSubnetPort
+	poolLock     sync.Mutex
 }
 
 // InitializeSubnetPort sync NSX resources.
@@ -43,15 +47,16 @@ func InitializeSubnetPort(service servicecommon.Service) (*SubnetPortService, er
 
 	wg.Add(1)
 
-	subnetPortService := &SubnetPortService{Service: service}
+	subnetPortService := &SubnetPortService{Service: service, claimedPorts: map[types.UID]*model.VpcSubnetPort{}}
 
 	subnetPortService.SubnetPortStore = &SubnetPortStore{ResourceStore: servicecommon.ResourceStore{
 		Indexer: cache.NewIndexer(
 			keyFunc,
 			cache.Indexers{
-				servicecommon.TagScopeSubnetPortCRUID: subnetPortIndexByCRUID,
-				servicecommon.TagScopePodUID:          subnetPortIndexByPodUID,
-				servicecommon.IndexKeySubnetID:        subnetPortIndexBySubnetID,
+				servicecommon.TagScopeSubnetPortCRUID:            subnetPortIndexByCRUID,
+				servicecommon.TagScopePodUID:                     subnetPortIndexByPodUID,
+				servicecommon.IndexKeySubnetID:                   subnetPortIndexBySubnetID,
+				servicecommon.TagScopeSubnetPortPoolSubnetSetUID: subnetPortIndexByPoolSubnetSetUID,
 			}),
 		BindingType: model.VpcSubnetPortBindingType(),
 	}}
@@ -83,11 +88,23 @@ func (service *SubnetPortService) CreateOrUpdateSubnetPort(obj interface{}, nsxS
 		uid = string(o.UID)
 	}
 	log.Info("creating or updating subnetport", "nsxSubnetPort.Id", uid, "nsxSubnetPath", *nsxSubnet.Path)
+	if addressBinding := getAddressBinding(obj); addressBinding != nil {
+		if err := service.validateAddressBinding(addressBinding, nsxSubnet, uid); err != nil {
+			log.Error(err, "invalid address binding", "nsxSubnetPort.Id", uid, "addressBinding", addressBinding)
+			return nil, err
+		}
+	}
 	nsxSubnetPort, err := service.buildSubnetPort(obj, nsxSubnet, contextID, tags)
 	if err != nil {
 		log.Error(err, "failed to build NSX subnet port", "nsxSubnetPort.Id", uid, "*nsxSubnet.Path", *nsxSubnet.Path, "contextID", contextID)
 		return nil, err
 	}
+	if boundPort := service.getSubnetPortByUID(types.UID(uid)); boundPort != nil && *boundPort.Id != *nsxSubnetPort.Id {
+		// The port is claimed from the pool, keep its ID and attachment to skip the realization of a new port.
+		nsxSubnetPort.Id = boundPort.Id
+		nsxSubnetPort.Path = boundPort.Path
+		nsxSubnetPort.Attachment.Id = boundPort.Attachment.Id
+	}
 	existingSubnetPort := service.SubnetPortStore.GetByKey(*nsxSubnetPort.Id)
 	isChanged := true
 	if existingSubnetPort != nil {
@@ -98,19 +115,14 @@ func (service *SubnetPortService) CreateOrUpdateSubnetPort(obj interface{}, nsxS
 		// We don't need to update it but still need to check realized state.
 	} else {
 		log.Info("updating the NSX subnet port", "existingSubnetPort", existingSubnetPort, "desiredSubnetPort", nsxSubnetPort)
-		subnetInfo, err := servicecommon.ParseVPCResourcePath(*nsxSubnet.Path)
-		if err != nil {
-			return nil, err
-		}
-		err = service.NSXClient.PortClient.Patch(subnetInfo.OrgID, subnetInfo.ProjectID, subnetInfo.VPCID, subnetInfo.ID, *nsxSubnetPort.Id, *nsxSubnetPort)
-		if err != nil {
-			log.Error(err, "failed to create or update subnet port", "nsxSubnetPort.Id", *nsxSubnetPort.Id, "nsxSubnetPath", *nsxSubnet.Path)
+		if err := service.patchSubnetPort(nsxSubnetPort, existingSubnetPort); err != nil {
 			return nil, err
 		}
 		err = service.SubnetPortStore.Apply(nsxSubnetPort)
 		if err != nil {
 			return nil, err
 		}
+		service.unclaimSubnetPort(types.UID(uid))
 		if existingSubnetPort != nil {
 			log.Info("updated NSX subnet port", "nsxSubnetPort.Path", *nsxSubnetPort.Path)
 		} else {
@@ -143,7 +155,7 @@ func (service *SubnetPortService) CheckSubnetPortState(obj interface{}, nsxSubne
 	case *v1.Pod:
 		uid = o.UID
 	}
-	nsxSubnetPort := service.SubnetPortStore.GetByKey(string(uid))
+	nsxSubnetPort := service.getSubnetPortByUID(uid)
 	if nsxSubnetPort == nil {
 		return nil, errors.New("failed to get subnet port from store")
 	}
@@ -186,8 +198,12 @@ func (service *SubnetPortService) GetSubnetPortState(obj interface{}, nsxSubnetP
 	case *v1.Pod:
 		uid = o.UID
 	}
+	nsxSubnetPortID := string(uid)
+	if nsxSubnetPort := service.getSubnetPortByUID(uid); nsxSubnetPort != nil {
+		nsxSubnetPortID = *nsxSubnetPort.Id
+	}
 	nsxOrgID, nsxProjectID, nsxVPCID, nsxSubnetID := nsxutil.ParseVPCPath(nsxSubnetPath)
-	nsxSubnetPortState, err := service.NSXClient.PortStateClient.Get(nsxOrgID, nsxProjectID, nsxVPCID, nsxSubnetID, string(uid), nil, nil)
+	nsxSubnetPortState, err := service.NSXClient.PortStateClient.Get(nsxOrgID, nsxProjectID, nsxVPCID, nsxSubnetID, nsxSubnetPortID, nil, nil)
 	if err != nil {
 		log.Error(err, "failed to get subnet port state", "nsxSubnetPortID", uid, "nsxSubnetPath", nsxSubnetPath)
 		return nil, err
@@ -196,20 +212,24 @@ func (service *SubnetPortService) GetSubnetPortState(obj interface{}, nsxSubnetP
 }
 
 func (service *SubnetPortService) DeleteSubnetPort(uid types.UID) error {
-	nsxSubnetPort := service.SubnetPortStore.GetByKey(string(uid))
+	nsxSubnetPort := service.getSubnetPortByUID(uid)
 	if nsxSubnetPort == nil || nsxSubnetPort.Id == nil {
 		log.Info("NSX subnet port is not found in store, skip deleting it", "uid", uid)
 		return nil
 	}
 	nsxOrgID, nsxProjectID, nsxVPCID, nsxSubnetID := nsxutil.ParseVPCPath(*nsxSubnetPort.Path)
-	err := service.NSXClient.PortClient.Delete(nsxOrgID, nsxProjectID, nsxVPCID, nsxSubnetID, string(uid))
+	err := service.NSXClient.PortClient.Delete(nsxOrgID, nsxProjectID, nsxVPCID, nsxSubnetID, *nsxSubnetPort.Id)
 	if err != nil {
 		log.Error(err, "failed to delete subnetport", "nsxSubnetPort.Path", *nsxSubnetPort.Path)
 		return err
 	}
-	if err = service.SubnetPortStore.Delete(uid); err != nil {
+	if err = service.releaseIPReservation(nsxSubnetPort); err != nil {
+		return err
+	}
+	if err = service.SubnetPortStore.Delete(nsxSubnetPort); err != nil {
 		return err
 	}
+	service.unclaimSubnetPort(uid)
 	log.Info("successfully deleted nsxSubnetPort", "nsxSubnetPortID", uid)
 	return nil
 }
@@ -226,6 +246,18 @@ func (service *SubnetPortService) ListNSXSubnetPortIDForPod() sets.Set[string] {
 	return subnetPortSet
 }
 
+// ListSubnetPortByVPCPath returns the NSX SubnetPorts in the store which belong to the Subnets of the VPC.
+func (service *SubnetPortService) ListSubnetPortByVPCPath(vpcPath string) []*model.VpcSubnetPort {
+	subnetPorts := []*model.VpcSubnetPort{}
+	for _, obj := range service.SubnetPortStore.List() {
+		subnetPort := obj.(*model.VpcSubnetPort)
+		if subnetPort.Path != nil && strings.HasPrefix(*subnetPort.Path, vpcPath+"/") {
+			subnetPorts = append(subnetPorts, subnetPort)
+		}
+	}
+	return subnetPorts
+}
+
 func (service *SubnetPortService) GetGatewayNetmaskForSubnetPort(obj *v1alpha1.SubnetPort, nsxSubnetPath string) (string, string, error) {
 	// TODO: merge the logic to subnet service when subnet implementation is done.
 	subnetInfo, err := servicecommon.ParseVPCResourcePath(nsxSubnetPath)
@@ -260,7 +292,7 @@ func (service *SubnetPortService) GetGatewayNetmaskForSubnetPort(obj *v1alpha1.S
 }
 
 func (service *SubnetPortService) GetSubnetPathForSubnetPortFromStore(nsxSubnetPortID string) string {
-	existingSubnetPort := service.SubnetPortStore.GetByKey(nsxSubnetPortID)
+	existingSubnetPort := service.getSubnetPortByUID(types.UID(nsxSubnetPortID))
 	if existingSubnetPort == nil {
 		log.Info("subnetport is not found in store", "nsxSubnetPortID", nsxSubnetPortID)
 		return ""
@@ -2,6 +2,7 @@ package vpc
 
 import (
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
 )
@@ -19,3 +20,27 @@ func IsVPCChanged(nc common.VPCNetworkConfigInfo, vpc *model.Vpc) bool {
 
 	return false
 }
+
+// Fields of the VPC network config which can be applied to an existing NSX VPC, named by
+// the json names in VPCNetworkConfigurationSpec and listed in the order they are applied.
+const (
+	FieldPrivateIPv4CIDRs   = "privateIPv4CIDRs"
+	FieldExternalIPv4Blocks = "externalIPv4Blocks"
+	FieldDefaultGatewayPath = "defaultGatewayPath"
+	FieldEdgeClusterPath    = "edgeClusterPath"
+)
+
+var vpcNetworkConfigFields = []string{FieldPrivateIPv4CIDRs, FieldExternalIPv4Blocks, FieldDefaultGatewayPath, FieldEdgeClusterPath}
+
+func isStringSetChanged(expected, existing []string) bool {
+	return !sets.New[string](expected...).Equal(sets.New[string](existing...))
+}
+
+// GetEdgeClusterPath returns the edge cluster path of the NSX VPC, it returns an empty string if the
+// VPC is not bound to an edge cluster.
+func GetEdgeClusterPath(vpc *model.Vpc) string {
+	if len(vpc.SiteInfos) == 0 || len(vpc.SiteInfos[0].EdgeClusterPaths) == 0 {
+		return ""
+	}
+	return vpc.SiteInfos[0].EdgeClusterPaths[0]
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"sort"
 	"strings"
 	"sync"
 
@@ -43,6 +44,10 @@ var (
 
 	MarkedForDelete    = true
 	enableAviAllowRule = false
+
+	// adoptedVPCTagScopes are the scopes of the tags added to the NSX VPC when adopting it.
+	adoptedVPCTagScopes = sets.New[string](common.TagScopeCluster, common.TagScopeVersion, common.TagScopeNamespace,
+		common.TagScopeVPCCRName, common.TagScopeVPCCRUID, common.TagScopeVPCAdopted)
 )
 
 type VPCService struct {
@@ -77,6 +82,9 @@ func (s *VPCService) RegisterVPCNetworkConfig(ncCRName string, info common.VPCNe
 
 func (s *VPCService) UnregisterVPCNetworkConfig(ncCRName string) {
 	delete(s.VPCNetworkConfigMap, ncCRName)
+	if s.defaultNetworkConfigCR != nil && s.defaultNetworkConfigCR.Name == ncCRName {
+		s.defaultNetworkConfigCR = nil
+	}
 }
 
 func (s *VPCService) GetVPCNetworkConfig(ncCRName string) (common.VPCNetworkConfigInfo, bool) {
@@ -197,13 +205,40 @@ func InitializeVPC(service common.Service) (*VPCService, error) {
 	return VPCService, nil
 }
 
+// GetVPCsByNamespace returns the VPCs of the namespace, or of the shared VPC namespace if the namespace
+// shares the VPC of another one. The VPCs are sorted by creation time, so the default VPC, which is the
+// one created with the namespace, comes first.
 func (s *VPCService) GetVPCsByNamespace(namespace string) []*model.Vpc {
 	sns, err := s.getSharedVPCNamespaceFromNS(namespace)
 	if err != nil {
 		log.Error(err, "Failed to get namespace.")
 		return nil
 	}
-	return s.VpcStore.GetVPCsByNamespace(util.If(sns == "", namespace, sns).(string))
+	vpcs := s.VpcStore.GetVPCsByNamespace(util.If(sns == "", namespace, sns).(string))
+	sort.SliceStable(vpcs, func(i, j int) bool {
+		return getCreateTime(vpcs[i]) < getCreateTime(vpcs[j])
+	})
+	return vpcs
+}
+
+func getCreateTime(vpc *model.Vpc) int64 {
+	// the VPC not realized yet has no create time, consider it as the latest one
+	if vpc.CreateTime == nil {
+		return math.MaxInt64
+	}
+	return *vpc.CreateTime
+}
+
+// GetVPCByCRUID returns the NSX VPC created for or adopted by the VPC CR.
+func (s *VPCService) GetVPCByCRUID(uid string) *model.Vpc {
+	if vpc := s.VpcStore.GetByKey(uid); vpc != nil {
+		return vpc
+	}
+	// the adopted VPC keeps its own ID, search it with the VPC CR UID tag
+	for _, obj := range s.VpcStore.GetByIndex(common.TagScopeVPCCRUID, uid) {
+		return obj.(*model.Vpc)
+	}
+	return nil
 }
 
 func (s *VPCService) ListVPC() []model.Vpc {
@@ -226,7 +261,12 @@ func (s *VPCService) DeleteVPC(path string) error {
 		return nil
 	}
 
-	if err := vpcClient.Delete(pathInfo.OrgID, pathInfo.ProjectID, pathInfo.VPCID); err != nil {
+	// the adopted VPC is pre-provisioned, only remove the tags added by the operator
+	if IsAdoptedVPC(vpc) {
+		if err := s.releaseVPC(pathInfo, vpc); err != nil {
+			return err
+		}
+	} else if err := vpcClient.Delete(pathInfo.OrgID, pathInfo.ProjectID, pathInfo.VPCID); err != nil {
 		return err
 	}
 	vpc.MarkedForDelete = &MarkedForDelete
@@ -238,6 +278,23 @@ func (s *VPCService) DeleteVPC(path string) error {
 	return nil
 }
 
+// releaseVPC removes the tags added when adopting the NSX VPC, and keeps the tags of the VPC owner.
+func (s *VPCService) releaseVPC(pathInfo common.VPCResourceInfo, vpc *model.Vpc) error {
+	tags := []model.Tag{}
+	for _, tag := range vpc.Tags {
+		if tag.Scope != nil && adoptedVPCTagScopes.Has(*tag.Scope) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	if err := s.NSXClient.VPCClient.Patch(pathInfo.OrgID, pathInfo.ProjectID, pathInfo.VPCID, model.Vpc{Tags: tags}); err != nil {
+		log.Error(err, "failed to release adopted NSX VPC", "VPC", pathInfo.VPCID)
+		return err
+	}
+	log.Info("released adopted NSX VPC", "VPC", pathInfo.VPCID)
+	return nil
+}
+
 func (s *VPCService) deleteIPBlock(path string) error {
 	ipblockClient := s.NSXClient.IPBlockClient
 	parts := strings.Split(path, "/")
@@ -250,6 +307,10 @@ func (s *VPCService) deleteIPBlock(path string) error {
 }
 
 func (s *VPCService) DeleteIPBlockInVPC(vpc model.Vpc) error {
+	if IsAdoptedVPC(&vpc) {
+		log.Info("private ip blocks are not created for adopted VPC, skip deleting private ip blocks", "VPC", *vpc.Id)
+		return nil
+	}
 	blocks := vpc.PrivateIpv4Blocks
 	if len(blocks) == 0 {
 		log.Info("no private cidr list, skip deleting private ip blocks")
@@ -451,13 +512,40 @@ func (s *VPCService) GetAVISubnetInfo(vpc model.Vpc) (string, string, error) {
 	return path, cidr, nil
 }
 
+// GetVPCRealizedState returns the realization state of the RealizedLogicalRouter of the NSX VPC and
+// its last error. An empty state is returned if the VPC is not realized yet.
+func (s *VPCService) GetVPCRealizedState(vpc model.Vpc) (string, string, error) {
+	realizeService := realizestate.InitializeRealizeState(s.Service)
+	realized, err := realizeService.GetRealizedResource(*vpc.Path, "RealizedLogicalRouter")
+	if err != nil {
+		log.Error(err, "failed to read VPC realized state", "VPC", vpc.Id)
+		return "", "", err
+	}
+	if realized == nil || realized.State == nil {
+		return "", "", nil
+	}
+	if *realized.State == model.GenericPolicyRealizedResource_STATE_REALIZED {
+		return *realized.State, "", nil
+	}
+	for _, alarm := range realized.Alarms {
+		if alarm.Message != nil && *alarm.Message != "" {
+			return *realized.State, *alarm.Message, nil
+		}
+	}
+	if realized.RuntimeError != nil {
+		return *realized.State, *realized.RuntimeError, nil
+	}
+	return *realized.State, "", nil
+}
+
 func (s *VPCService) CreateorUpdateVPC(obj *v1alpha1.VPC) (*model.Vpc, *common.VPCNetworkConfigInfo, error) {
-	// check from VPC store if vpc already exist
+	// check from VPC store if vpc already exist, the NSX VPC is identified by the VPC CR UID
+	// as a namespace could have multiple VPCs
 	updateVpc := false
-	existingVPC := s.VpcStore.GetVPCsByNamespace(obj.Namespace)
-	if len(existingVPC) != 0 { // We now consider only one VPC for one namespace
+	existingVPC := s.GetVPCByCRUID(string(obj.UID))
+	if existingVPC != nil {
 		updateVpc = true
-		log.Info("VPC already exist, updating NSX VPC object", "VPC", existingVPC[0].Id)
+		log.Info("VPC already exist, updating NSX VPC object", "VPC", existingVPC.Id)
 	}
 
 	// read corresponding vpc network config from store
@@ -475,6 +563,10 @@ func (s *VPCService) CreateorUpdateVPC(obj *v1alpha1.VPC) (*model.Vpc, *common.V
 
 	log.Info("read network config from store", "NetworkConfig", ncName)
 
+	if obj.Spec.NSXVPCPath != "" {
+		return s.adoptVPC(obj, nc)
+	}
+
 	paths, err := s.CreatOrUpdatePrivateIPBlock(obj, nc)
 	if err != nil {
 		log.Error(err, "failed to process private ip blocks, push event back to queue")
@@ -484,8 +576,8 @@ func (s *VPCService) CreateorUpdateVPC(obj *v1alpha1.VPC) (*model.Vpc, *common.V
 	// if all private ip blocks are created, then create nsx vpc resource.
 	nsxVPC := &model.Vpc{}
 	if updateVpc {
-		log.Info("VPC resource already exist on NSX, updating VPC", "VPC", existingVPC[0].DisplayName)
-		nsxVPC = existingVPC[0]
+		log.Info("VPC resource already exist on NSX, updating VPC", "VPC", existingVPC.DisplayName)
+		nsxVPC = existingVPC
 	} else {
 		log.Info("VPC does not exist on NSX, creating VPC", "VPC", obj.Name)
 		nsxVPC = nil
@@ -500,7 +592,7 @@ func (s *VPCService) CreateorUpdateVPC(obj *v1alpha1.VPC) (*model.Vpc, *common.V
 	// if there is not change in public cidr and private cidr, build partial vpc will return nil
 	if createdVpc == nil {
 		log.Info("no VPC changes detect, skip creating or updating process")
-		return existingVPC[0], &nc, nil
+		return existingVPC, &nc, nil
 	}
 
 	log.Info("creating NSX VPC", "VPC", *createdVpc.Id)
@@ -550,6 +642,175 @@ func (s *VPCService) CreateorUpdateVPC(obj *v1alpha1.VPC) (*model.Vpc, *common.V
 	return &newVpc, &nc, nil
 }
 
+// adoptVPC adopts the existing NSX VPC referenced by the VPC CR instead of creating one. The NSX VPC
+// is tagged for the cluster so that it is synced into the VPC store, and the private CIDRs are read from
+// the private ip blocks of the NSX VPC instead of the network config.
+func (s *VPCService) adoptVPC(obj *v1alpha1.VPC, nc common.VPCNetworkConfigInfo) (*model.Vpc, *common.VPCNetworkConfigInfo, error) {
+	pathInfo, err := common.ParseVPCResourcePath(obj.Spec.NSXVPCPath)
+	if err != nil {
+		log.Error(err, "invalid NSX VPC path", "VPC", obj.Name, "Path", obj.Spec.NSXVPCPath)
+		return nil, nil, err
+	}
+	nsxVPC, err := s.NSXClient.VPCClient.Get(pathInfo.OrgID, pathInfo.ProjectID, pathInfo.VPCID)
+	if err != nil {
+		log.Error(err, "failed to read NSX VPC to adopt", "VPC", obj.Name, "Path", obj.Spec.NSXVPCPath)
+		return nil, nil, err
+	}
+
+	crUID := GetVPCCRUID(&nsxVPC)
+	if crUID != "" && crUID != string(obj.UID) {
+		return nil, nil, fmt.Errorf("NSX VPC %s is already used by another VPC CR", obj.Spec.NSXVPCPath)
+	}
+	if crUID == "" {
+		log.Info("adopting NSX VPC", "VPC", obj.Name, "Namespace", obj.Namespace, "Path", obj.Spec.NSXVPCPath)
+		tags := append(nsxVPC.Tags, util.BuildBasicTags(s.NSXConfig.Cluster, obj, "")...)
+		tags = append(tags, model.Tag{Scope: common.String(common.TagScopeVPCAdopted), Tag: common.String("true")})
+		if err := s.NSXClient.VPCClient.Patch(pathInfo.OrgID, pathInfo.ProjectID, pathInfo.VPCID, model.Vpc{Tags: tags}); err != nil {
+			log.Error(err, "failed to tag NSX VPC", "Path", obj.Spec.NSXVPCPath)
+			return nil, nil, err
+		}
+		if nsxVPC, err = s.NSXClient.VPCClient.Get(pathInfo.OrgID, pathInfo.ProjectID, pathInfo.VPCID); err != nil {
+			log.Error(err, "failed to read NSX VPC after adopting", "Path", obj.Spec.NSXVPCPath)
+			return nil, nil, err
+		}
+	}
+
+	cidrs, err := s.getPrivateIPBlockCIDRs(nsxVPC.PrivateIpv4Blocks)
+	if err != nil {
+		log.Error(err, "failed to read private ip blocks of adopted NSX VPC", "Path", obj.Spec.NSXVPCPath)
+		return nil, nil, err
+	}
+	nc.PrivateIPv4CIDRs = cidrs
+	s.VpcStore.Add(&nsxVPC)
+	return &nsxVPC, &nc, nil
+}
+
+// getPrivateIPBlockCIDRs reads the CIDRs of the private ip blocks in the project.
+func (s *VPCService) getPrivateIPBlockCIDRs(blocks []string) ([]string, error) {
+	var cidrs []string
+	for _, block := range blocks {
+		if ipblock := s.IpblockStore.GetByIndex(common.IndexKeyPathPath, block); ipblock != nil {
+			cidrs = append(cidrs, *ipblock.Cidr)
+			continue
+		}
+		parts := strings.Split(block, "/")
+		if len(parts) != 8 {
+			return nil, fmt.Errorf("invalid private ip block path %s", block)
+		}
+		ipblock, err := s.NSXClient.IPBlockClient.Get(parts[2], parts[4], parts[7], nil)
+		if err != nil {
+			return nil, err
+		}
+		cidrs = append(cidrs, *ipblock.Cidr)
+	}
+	return cidrs, nil
+}
+
+// ApplyVPCNetworkConfig applies the VPC network config to the existing NSX VPC of the VPC CR.
+// The changed fields are patched one by one, so that a field which fails to apply doesn't block
+// the others, and the errors are returned keyed by the field name. It returns a nil VPC if the
+// NSX VPC is not created yet, as the VPC will be created with the network config when the VPC
+// CR is reconciled.
+func (s *VPCService) ApplyVPCNetworkConfig(obj *v1alpha1.VPC, nc common.VPCNetworkConfigInfo) (*model.Vpc, map[string]error) {
+	existingVPC := s.GetVPCByCRUID(string(obj.UID))
+	if existingVPC == nil {
+		log.V(1).Info("NSX VPC not found, skip applying network config", "VPC", obj.Name, "Namespace", obj.Namespace)
+		return nil, nil
+	}
+	if IsAdoptedVPC(existingVPC) {
+		log.V(1).Info("NSX VPC is adopted, skip applying network config", "VPC", obj.Name, "Namespace", obj.Namespace)
+		return existingVPC, nil
+	}
+
+	fieldErrors := map[string]error{}
+	current := *existingVPC
+	updated := false
+	for _, field := range vpcNetworkConfigFields {
+		vpc := current
+		changed, err := s.setVPCNetworkConfigField(obj, nc, field, &vpc)
+		if err != nil {
+			fieldErrors[field] = err
+			continue
+		}
+		if !changed {
+			continue
+		}
+		log.Info("applying network config field to NSX VPC", "Field", field, "VPC", *vpc.Id)
+		if err := s.NSXClient.VPCClient.Patch(nc.Org, nc.NsxtProject, *vpc.Id, vpc); err != nil {
+			log.Error(err, "failed to apply network config field to NSX VPC", "Field", field, "VPC", *vpc.Id)
+			fieldErrors[field] = err
+			continue
+		}
+		if field == FieldPrivateIPv4CIDRs {
+			s.deleteUnusedPrivateIPBlocks(current, vpc.PrivateIpv4Blocks)
+		}
+		current = vpc
+		updated = true
+	}
+
+	if updated {
+		newVpc, err := s.NSXClient.VPCClient.Get(nc.Org, nc.NsxtProject, *current.Id)
+		if err != nil {
+			log.Error(err, "failed to read VPC object after updating", "VPC", *current.Id)
+		} else {
+			current = newVpc
+		}
+		s.VpcStore.Add(&current)
+	}
+	return &current, fieldErrors
+}
+
+// setVPCNetworkConfigField sets the field of the network config on the NSX VPC, it returns
+// false if the field is not changed.
+func (s *VPCService) setVPCNetworkConfigField(obj *v1alpha1.VPC, nc common.VPCNetworkConfigInfo, field string, vpc *model.Vpc) (bool, error) {
+	switch field {
+	case FieldPrivateIPv4CIDRs:
+		paths, err := s.CreatOrUpdatePrivateIPBlock(obj, nc)
+		if err != nil {
+			return false, err
+		}
+		blocks := util.GetMapValues(paths)
+		if !isStringSetChanged(blocks, vpc.PrivateIpv4Blocks) {
+			return false, nil
+		}
+		vpc.PrivateIpv4Blocks = blocks
+	case FieldExternalIPv4Blocks:
+		if !isStringSetChanged(nc.ExternalIPv4Blocks, vpc.ExternalIpv4Blocks) {
+			return false, nil
+		}
+		vpc.ExternalIpv4Blocks = nc.ExternalIPv4Blocks
+	case FieldDefaultGatewayPath:
+		if vpc.DefaultGatewayPath != nil && *vpc.DefaultGatewayPath == nc.DefaultGatewayPath {
+			return false, nil
+		}
+		vpc.DefaultGatewayPath = common.String(nc.DefaultGatewayPath)
+	case FieldEdgeClusterPath:
+		if GetEdgeClusterPath(vpc) == nc.EdgeClusterPath {
+			return false, nil
+		}
+		siteInfos := append([]model.SiteInfo{}, vpc.SiteInfos...)
+		if len(siteInfos) == 0 {
+			siteInfos = append(siteInfos, model.SiteInfo{})
+		}
+		siteInfos[0].EdgeClusterPaths = []string{nc.EdgeClusterPath}
+		vpc.SiteInfos = siteInfos
+	}
+	return true, nil
+}
+
+// deleteUnusedPrivateIPBlocks deletes the private ip blocks removed from the NSX VPC, failures
+// are only logged as the blocks are not referenced by the VPC anymore.
+func (s *VPCService) deleteUnusedPrivateIPBlocks(vpc model.Vpc, blocks []string) {
+	unused := sets.New[string](vpc.PrivateIpv4Blocks...).Difference(sets.New[string](blocks...))
+	if unused.Len() == 0 {
+		return
+	}
+	vpc.PrivateIpv4Blocks = sets.List(unused)
+	if err := s.DeleteIPBlockInVPC(vpc); err != nil {
+		log.Error(err, "failed to delete unused private ip blocks", "VPC", *vpc.Id)
+	}
+}
+
 func (s *VPCService) Cleanup(ctx context.Context) error {
 	vpcs := s.ListVPC()
 	log.Info("cleaning up vpcs", "Count", len(vpcs))
@@ -621,6 +882,11 @@ func (service *VPCService) getIpblockCidr(blocks []string) (result []string, err
 	return
 }
 
+// GetExternalIPv4BlockCIDRs returns the CIDRs of the external IP blocks by their paths.
+func (service *VPCService) GetExternalIPv4BlockCIDRs(blocks []string) ([]string, error) {
+	return service.getIpblockCidr(blocks)
+}
+
 func (service *VPCService) CreateOrUpdateAVIRule(vpc *model.Vpc, namespace string) error {
 	if !enableAviAllowRule {
 		return nil
@@ -629,6 +895,11 @@ func (service *VPCService) CreateOrUpdateAVIRule(vpc *model.Vpc, namespace strin
 		log.Info("avi rule cannot be created or updated due to no DFW license")
 		return nil
 	}
+	// the adopted VPC is pre-provisioned, the firewall rules in it are managed by the VPC owner
+	if IsAdoptedVPC(vpc) {
+		log.V(1).Info("NSX VPC is adopted, skip creating avi rule", "VPC", *vpc.Id)
+		return nil
+	}
 	vpcInfo, err := common.ParseVPCResourcePath(*vpc.Path)
 	if err != nil {
 		log.Error(err, "failed to parse VPC Resource Path: ", *vpc.Path)
@@ -819,17 +1090,63 @@ func (service *VPCService) checkAVISecurityPolicyExist(orgId string, projectId s
 	return true
 }
 
+// ListVPCInfo returns the VPCs of the namespace, the default VPC comes first.
 func (service *VPCService) ListVPCInfo(ns string) []common.VPCResourceInfo {
 	var VPCInfoList []common.VPCResourceInfo
 	vpcs := service.GetVPCsByNamespace(ns) // Transparently call the VPCService.GetVPCsByNamespace method
 	for _, v := range vpcs {
-		vpcResourceInfo, err := common.ParseVPCResourcePath(*v.Path)
-		if err != nil {
-			log.Error(err, "Failed to get vpc info from vpc path", "vpc path", *v.Path)
-		}
-		vpcResourceInfo.ExternalIPv4Blocks = v.ExternalIpv4Blocks
-		vpcResourceInfo.PrivateIpv4Blocks = v.PrivateIpv4Blocks
-		VPCInfoList = append(VPCInfoList, vpcResourceInfo)
+		VPCInfoList = append(VPCInfoList, buildVPCResourceInfo(v))
 	}
 	return VPCInfoList
 }
+
+// GetVPCInfo returns the VPC created for the VPC CR vpcName in the namespace, or the default VPC of the
+// namespace if vpcName is empty.
+func (service *VPCService) GetVPCInfo(ns string, vpcName string) (common.VPCResourceInfo, bool) {
+	for _, v := range service.GetVPCsByNamespace(ns) {
+		if vpcName != "" && getVPCCRName(v) != vpcName {
+			continue
+		}
+		return buildVPCResourceInfo(v), true
+	}
+	return common.VPCResourceInfo{}, false
+}
+
+func buildVPCResourceInfo(v *model.Vpc) common.VPCResourceInfo {
+	vpcResourceInfo, err := common.ParseVPCResourcePath(*v.Path)
+	if err != nil {
+		log.Error(err, "Failed to get vpc info from vpc path", "vpc path", *v.Path)
+	}
+	vpcResourceInfo.ExternalIPv4Blocks = v.ExternalIpv4Blocks
+	vpcResourceInfo.PrivateIpv4Blocks = v.PrivateIpv4Blocks
+	return vpcResourceInfo
+}
+
+func getVPCCRName(v *model.Vpc) string {
+	for _, tag := range v.Tags {
+		if tag.Scope != nil && *tag.Scope == common.TagScopeVPCCRName && tag.Tag != nil {
+			return *tag.Tag
+		}
+	}
+	return ""
+}
+
+// IsAdoptedVPC returns true if the NSX VPC is pre-provisioned and adopted by a VPC CR.
+func IsAdoptedVPC(v *model.Vpc) bool {
+	for _, tag := range v.Tags {
+		if tag.Scope != nil && *tag.Scope == common.TagScopeVPCAdopted {
+			return true
+		}
+	}
+	return false
+}
+
+// GetVPCCRUID returns the UID of the VPC CR which the NSX VPC is created for or adopted by.
+func GetVPCCRUID(v *model.Vpc) string {
+	for _, tag := range v.Tags {
+		if tag.Scope != nil && *tag.Scope == common.TagScopeVPCCRUID && tag.Tag != nil {
+			return *tag.Tag
+		}
+	}
+	return ""
+}
@@ -11,10 +11,13 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/infra"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/infra/realized_state"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
 	"github.com/vmware-tanzu/nsx-operator/pkg/config"
 	mock_client "github.com/vmware-tanzu/nsx-operator/pkg/mock/controller-runtime/client"
 	mocks "github.com/vmware-tanzu/nsx-operator/pkg/mock/vpcclient"
@@ -52,7 +55,7 @@ func createService(t *testing.T) (*VPCService, *gomock.Controller, *mocks.MockVp
 	k8sClient := mock_client.NewMockClient(mockCtrl)
 
 	vpcStore := &VPCStore{ResourceStore: common.ResourceStore{
-		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeStaticRouteCRUID: indexFunc}),
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeVPCCRUID: indexFunc}),
 		BindingType: model.VpcBindingType(),
 	}}
 
@@ -317,6 +320,52 @@ func TestListVPCInfo(t *testing.T) {
 
 }
 
+func TestGetVPCInfo(t *testing.T) {
+	service, mockCtrl, _ := createService(t)
+	defer mockCtrl.Finish()
+	patch := gomonkey.ApplyPrivateMethod(reflect.TypeOf(service), "getSharedVPCNamespaceFromNS", func(_ *VPCService, ns string) (string, error) {
+		return "", nil
+	})
+	defer patch.Reset()
+
+	ns := "ns1"
+	newVPC := func(id, crName string, createTime int64) *model.Vpc {
+		path := fmt.Sprintf("/orgs/default/projects/project-1/vpcs/%s", id)
+		return &model.Vpc{
+			Id:         common.String(id),
+			Path:       &path,
+			CreateTime: &createTime,
+			Tags: []model.Tag{
+				{Scope: common.String(common.TagScopeNamespace), Tag: &ns},
+				{Scope: common.String(common.TagScopeVPCCRName), Tag: common.String(crName)},
+				{Scope: common.String(common.TagScopeVPCCRUID), Tag: common.String(id)},
+			},
+		}
+	}
+	// the default VPC is the one created first
+	service.VpcStore.Add(newVPC("vpc-uid-2", "frontend", 200))
+	service.VpcStore.Add(newVPC("vpc-uid-1", "vpc-default", 100))
+	service.VpcStore.Add(newVPC("vpc-uid-3", "data", 300))
+
+	infos := service.ListVPCInfo(ns)
+	assert.Equal(t, 3, len(infos))
+	assert.Equal(t, "vpc-uid-1", infos[0].VPCID)
+
+	info, found := service.GetVPCInfo(ns, "")
+	assert.True(t, found)
+	assert.Equal(t, "vpc-uid-1", info.VPCID)
+
+	info, found = service.GetVPCInfo(ns, "data")
+	assert.True(t, found)
+	assert.Equal(t, "vpc-uid-3", info.VPCID)
+	assert.Equal(t, "project-1", info.ProjectID)
+
+	_, found = service.GetVPCInfo(ns, "dummy")
+	assert.False(t, found)
+	_, found = service.GetVPCInfo("ns2", "")
+	assert.False(t, found)
+}
+
 type MockSecurityPoliciesClient struct {
 	SP  model.SecurityPolicy
 	Err error
@@ -540,4 +589,173 @@ func TestCreateOrUpdateAVIRule(t *testing.T) {
 	ruleClient.Err = nil
 	err = service.CreateOrUpdateAVIRule(&vpc1, ns1)
 	assert.Equal(t, err, nil)
+
+	// the rule is not created in the adopted VPC
+	ruleClient.Err = errors.New("create avi rule error")
+	vpc1.Tags = append(vpc1.Tags, model.Tag{Scope: common.String(common.TagScopeVPCAdopted), Tag: common.String("true")})
+	err = service.CreateOrUpdateAVIRule(&vpc1, ns1)
+	assert.Equal(t, err, nil)
+}
+
+func TestApplyVPCNetworkConfig(t *testing.T) {
+	service, mockCtrl, mockVpcclient := createService(t)
+	defer mockCtrl.Finish()
+
+	vpcCR := &v1alpha1.VPC{}
+	vpcCR.Name, vpcCR.Namespace, vpcCR.UID = "vpc-1", "ns-1", "vpc-uid-1"
+	nc := common.VPCNetworkConfigInfo{
+		Org:                "default",
+		NsxtProject:        "project-1",
+		ExternalIPv4Blocks: []string{"/infra/ip-blocks/block-1", "/infra/ip-blocks/block-2"},
+		DefaultGatewayPath: "/infra/tier-0s/t0-2",
+		EdgeClusterPath:    "/infra/sites/default/enforcement-points/default/edge-clusters/ec-1",
+	}
+
+	// NSX VPC not created yet
+	vpc, errs := service.ApplyVPCNetworkConfig(vpcCR, nc)
+	assert.Nil(t, vpc)
+	assert.Nil(t, errs)
+
+	path := "/orgs/default/projects/project-1/vpcs/vpc-uid-1"
+	service.VpcStore.Add(&model.Vpc{
+		Id:                 common.String("vpc-uid-1"),
+		Path:               &path,
+		ExternalIpv4Blocks: []string{"/infra/ip-blocks/block-1"},
+		DefaultGatewayPath: common.String("/infra/tier-0s/t0-1"),
+		SiteInfos:          []model.SiteInfo{{EdgeClusterPaths: []string{nc.EdgeClusterPath}}},
+	})
+	patchErr := errors.New("gateway is in use")
+	mockVpcclient.EXPECT().Patch("default", "project-1", "vpc-uid-1", gomock.Any()).DoAndReturn(
+		func(_, _, _ string, vpc model.Vpc) error {
+			if *vpc.DefaultGatewayPath != "/infra/tier-0s/t0-1" {
+				return patchErr
+			}
+			assert.Equal(t, nc.ExternalIPv4Blocks, vpc.ExternalIpv4Blocks)
+			return nil
+		}).Times(2)
+	mockVpcclient.EXPECT().Get("default", "project-1", "vpc-uid-1").Return(model.Vpc{
+		Id:                 common.String("vpc-uid-1"),
+		Path:               &path,
+		ExternalIpv4Blocks: nc.ExternalIPv4Blocks,
+		DefaultGatewayPath: common.String("/infra/tier-0s/t0-1"),
+		SiteInfos:          []model.SiteInfo{{EdgeClusterPaths: []string{nc.EdgeClusterPath}}},
+	}, nil)
+
+	vpc, errs = service.ApplyVPCNetworkConfig(vpcCR, nc)
+	assert.Equal(t, path, *vpc.Path)
+	assert.Equal(t, map[string]error{FieldDefaultGatewayPath: patchErr}, errs)
+	assert.Equal(t, nc.ExternalIPv4Blocks, service.VpcStore.GetByKey("vpc-uid-1").ExternalIpv4Blocks)
+}
+
+type fakeIPBlocksClient struct {
+	infra.IpBlocksClient
+}
+
+func (c *fakeIPBlocksClient) Get(_ string, _ string, ipBlockID string, _ *bool) (model.IpAddressBlock, error) {
+	return model.IpAddressBlock{Id: &ipBlockID, Cidr: common.String("172.26.0.0/16")}, nil
+}
+
+func TestCreateorUpdateVPCAdopt(t *testing.T) {
+	service, mockCtrl, mockVpcclient := createService(t)
+	defer mockCtrl.Finish()
+	service.NSXClient.IPBlockClient = &fakeIPBlocksClient{}
+	service.IpblockStore = &IPBlockStore{ResourceStore: common.ResourceStore{
+		Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.IndexKeyPathPath: indexPathFunc}),
+		BindingType: model.IpAddressBlockBindingType(),
+	}}
+	service.RegisterVPCNetworkConfig("default", common.VPCNetworkConfigInfo{IsDefault: true, Name: "default", Org: "default", NsxtProject: "project-1"})
+	k8sClient := service.Client.(*mock_client.MockClient)
+	k8sClient.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	path := "/orgs/default/projects/project-1/vpcs/pre-vpc"
+	vpcCR := &v1alpha1.VPC{Spec: v1alpha1.VPCSpec{NSXVPCPath: path}}
+	vpcCR.Name, vpcCR.Namespace, vpcCR.UID = "vpc-1", "ns-1", "vpc-uid-1"
+	ownerTag := model.Tag{Scope: common.String("owner"), Tag: common.String("network-team")}
+	preVPC := model.Vpc{
+		Id:                common.String("pre-vpc"),
+		Path:              &path,
+		Tags:              []model.Tag{ownerTag},
+		PrivateIpv4Blocks: []string{"/orgs/default/projects/project-1/infra/ip-blocks/block-1"},
+	}
+
+	// the NSX VPC is used by another VPC CR
+	usedVPC := preVPC
+	usedVPC.Tags = []model.Tag{{Scope: common.String(common.TagScopeVPCCRUID), Tag: common.String("vpc-uid-2")}}
+	mockVpcclient.EXPECT().Get("default", "project-1", "pre-vpc").Return(usedVPC, nil)
+	_, _, err := service.CreateorUpdateVPC(vpcCR)
+	assert.ErrorContains(t, err, "already used by another VPC CR")
+
+	// the NSX VPC is tagged and added to the store
+	mockVpcclient.EXPECT().Get("default", "project-1", "pre-vpc").Return(preVPC, nil)
+	var adoptedVPC model.Vpc
+	mockVpcclient.EXPECT().Patch("default", "project-1", "pre-vpc", gomock.Any()).DoAndReturn(
+		func(_, _, _ string, vpc model.Vpc) error {
+			assert.Contains(t, vpc.Tags, ownerTag)
+			adoptedVPC = preVPC
+			adoptedVPC.Tags = vpc.Tags
+			return nil
+		})
+	mockVpcclient.EXPECT().Get("default", "project-1", "pre-vpc").DoAndReturn(func(_, _, _ string) (model.Vpc, error) {
+		return adoptedVPC, nil
+	})
+	vpc, nc, err := service.CreateorUpdateVPC(vpcCR)
+	assert.NoError(t, err)
+	assert.Equal(t, path, *vpc.Path)
+	assert.True(t, IsAdoptedVPC(vpc))
+	assert.Equal(t, "vpc-uid-1", GetVPCCRUID(vpc))
+	assert.Equal(t, []string{"172.26.0.0/16"}, nc.PrivateIPv4CIDRs)
+	assert.Equal(t, vpc, service.GetVPCByCRUID("vpc-uid-1"))
+
+	// the network config is not applied to the adopted VPC
+	vpc, errs := service.ApplyVPCNetworkConfig(vpcCR, common.VPCNetworkConfigInfo{DefaultGatewayPath: "/infra/tier-0s/t0-1"})
+	assert.Nil(t, errs)
+	assert.Nil(t, vpc.DefaultGatewayPath)
+
+	// the adopted VPC is released instead of deleted
+	mockVpcclient.EXPECT().Patch("default", "project-1", "pre-vpc", model.Vpc{Tags: []model.Tag{ownerTag}}).Return(nil)
+	assert.NoError(t, service.DeleteIPBlockInVPC(*vpc))
+	assert.NoError(t, service.DeleteVPC(path))
+	assert.Nil(t, service.GetVPCByCRUID("vpc-uid-1"))
+}
+
+type fakeRealizedEntitiesClient struct {
+	realized_state.RealizedEntitiesClient
+	results []model.GenericPolicyRealizedResource
+}
+
+func (c *fakeRealizedEntitiesClient) List(_ string, _ string, _ string, _ *string) (model.GenericPolicyRealizedResourceListResult, error) {
+	return model.GenericPolicyRealizedResourceListResult{Results: c.results}, nil
+}
+
+func TestGetVPCRealizedState(t *testing.T) {
+	service, mockCtrl, _ := createService(t)
+	defer mockCtrl.Finish()
+	realizedClient := &fakeRealizedEntitiesClient{}
+	service.NSXClient.RealizedEntitiesClient = realizedClient
+	vpc := model.Vpc{Id: common.String("vpc-1"), Path: common.String("/orgs/default/projects/project-1/vpcs/vpc-1")}
+
+	// the VPC is not realized yet
+	state, realizedErr, err := service.GetVPCRealizedState(vpc)
+	assert.NoError(t, err)
+	assert.Empty(t, state)
+	assert.Empty(t, realizedErr)
+
+	realizedClient.results = []model.GenericPolicyRealizedResource{
+		{EntityType: common.String("RealizedLogicalRouterPort"), State: common.String(model.GenericPolicyRealizedResource_STATE_REALIZED)},
+		{
+			EntityType: common.String("RealizedLogicalRouter"),
+			State:      common.String(model.GenericPolicyRealizedResource_STATE_ERROR),
+			Alarms:     []model.PolicyAlarmResource{{Message: common.String("edge cluster is not available")}},
+		},
+	}
+	state, realizedErr, err = service.GetVPCRealizedState(vpc)
+	assert.NoError(t, err)
+	assert.Equal(t, model.GenericPolicyRealizedResource_STATE_ERROR, state)
+	assert.Equal(t, "edge cluster is not available", realizedErr)
+
+	realizedClient.results[1].State = common.String(model.GenericPolicyRealizedResource_STATE_REALIZED)
+	state, realizedErr, err = service.GetVPCRealizedState(vpc)
+	assert.NoError(t, err)
+	assert.Equal(t, model.GenericPolicyRealizedResource_STATE_REALIZED, state)
+	assert.Empty(t, realizedErr)
 }
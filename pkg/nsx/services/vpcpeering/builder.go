@@ -0,0 +1,161 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpcpeering
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"strings"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
+	"github.com/vmware-tanzu/nsx-operator/pkg/util"
+)
+
+const (
+	// SideLocal marks the resources created in the local VPC of the VPCPeering.
+	SideLocal = "local"
+	// SidePeer marks the resources created in the peer VPC of the VPCPeering.
+	SidePeer = "peer"
+
+	// The rules of VPCPeering are placed before the default rules of the VPC, and the allow rule
+	// is evaluated before the drop rule.
+	ruleSequenceAllow = math.MaxInt32 - 3
+	ruleSequenceDrop  = math.MaxInt32 - 2
+)
+
+func validateNextHops(obj *v1alpha1.VPCPeering) error {
+	for _, nextHops := range [][]v1alpha1.NextHop{obj.Spec.NextHops, obj.Spec.PeerNextHops} {
+		ipSet := sets.New[string]()
+		for _, nextHop := range nextHops {
+			if ipSet.Has(nextHop.IPAddress) {
+				return fmt.Errorf("duplicate next hop IP address %s", nextHop.IPAddress)
+			}
+			if net.ParseIP(nextHop.IPAddress) == nil {
+				return fmt.Errorf("invalid next hop IP address %s", nextHop.IPAddress)
+			}
+			ipSet.Insert(nextHop.IPAddress)
+		}
+	}
+	return nil
+}
+
+// getNextHops returns the next hops of the routes injected into the VPC of the side.
+func getNextHops(obj *v1alpha1.VPCPeering, side string) []v1alpha1.NextHop {
+	if side == SidePeer && len(obj.Spec.PeerNextHops) > 0 {
+		return obj.Spec.PeerNextHops
+	}
+	return obj.Spec.NextHops
+}
+
+func nextHopSet(nextHops []v1alpha1.NextHop) sets.Set[string] {
+	ipSet := sets.New[string]()
+	for _, nextHop := range nextHops {
+		ipSet.Insert(nextHop.IPAddress)
+	}
+	return ipSet
+}
+
+// CheckMirrorVPCPeering returns an error if the VPCPeering peering back from the peer Namespace doesn't use
+// the same next hops for the routes injected into each VPC, or the same firewall.
+func CheckMirrorVPCPeering(obj *v1alpha1.VPCPeering, mirror *v1alpha1.VPCPeering) error {
+	if !nextHopSet(getNextHops(obj, SideLocal)).Equal(nextHopSet(getNextHops(mirror, SidePeer))) {
+		return fmt.Errorf("next hops of the local VPC conflict with the peer next hops of VPCPeering %s/%s", mirror.Namespace, mirror.Name)
+	}
+	if !nextHopSet(getNextHops(obj, SidePeer)).Equal(nextHopSet(getNextHops(mirror, SideLocal))) {
+		return fmt.Errorf("next hops of the peer VPC conflict with the next hops of VPCPeering %s/%s", mirror.Namespace, mirror.Name)
+	}
+	if !reflect.DeepEqual(obj.Spec.Firewall, mirror.Spec.Firewall) {
+		return fmt.Errorf("firewall conflicts with the firewall of VPCPeering %s/%s", mirror.Namespace, mirror.Name)
+	}
+	return nil
+}
+
+// BuildVPCPath returns the NSX path of the VPC.
+func BuildVPCPath(vpcInfo common.VPCResourceInfo) string {
+	return fmt.Sprintf("/orgs/%s/projects/%s/vpcs/%s", vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID)
+}
+
+func (service *VPCPeeringService) buildStaticRoute(obj *v1alpha1.VPCPeering, vpcInfo common.VPCResourceInfo, side string, network string) *model.StaticRoutes {
+	id := util.GenerateID(string(obj.UID), "vpcpeering", side, strings.ReplaceAll(network, "/", "_"))
+	route := &model.StaticRoutes{
+		Id:          String(id),
+		Path:        String(fmt.Sprintf("%s/static-routes/%s", BuildVPCPath(vpcInfo), id)),
+		DisplayName: String(util.GenerateTruncName(common.MaxNameLength, obj.Name, "vpcpeering", side, "", "")),
+		Network:     String(network),
+		Tags:        service.buildTags(obj),
+	}
+	distance := int64(1)
+	nextHops := getNextHops(obj, side)
+	for i := range nextHops {
+		route.NextHops = append(route.NextHops, model.RouterNexthop{
+			AdminDistance: &distance,
+			IpAddress:     String(nextHops[i].IPAddress),
+		})
+	}
+	return route
+}
+
+// buildRules builds the rules in the default security policy of the VPC, which allow the traffic
+// from the peer CIDRs to the ports of the firewall and drop the other traffic from the peer CIDRs.
+func (service *VPCPeeringService) buildRules(obj *v1alpha1.VPCPeering, vpcInfo common.VPCResourceInfo, side string, peerCIDRs []string) []*model.Rule {
+	policyPath := fmt.Sprintf("%s/security-policies/%s", BuildVPCPath(vpcInfo), vpc.VpcDefaultSecurityPolicyId)
+	var serviceEntries []*data.StructValue
+	for _, port := range obj.Spec.Firewall.Ports {
+		serviceEntries = append(serviceEntries, buildServiceEntry(port))
+	}
+	allowRule := &model.Rule{
+		Action:         String(model.Rule_ACTION_ALLOW),
+		SequenceNumber: common.Int64(ruleSequenceAllow),
+		ServiceEntries: serviceEntries,
+	}
+	dropRule := &model.Rule{
+		Action:         String(model.Rule_ACTION_DROP),
+		SequenceNumber: common.Int64(ruleSequenceDrop),
+	}
+	rules := []*model.Rule{allowRule, dropRule}
+	for _, rule := range rules {
+		action := strings.ToLower(*rule.Action)
+		id := util.GenerateID(string(obj.UID), "vpcpeering", side, action)
+		rule.Id = String(id)
+		rule.Path = String(fmt.Sprintf("%s/rules/%s", policyPath, id))
+		rule.DisplayName = String(util.GenerateTruncName(common.MaxNameLength, obj.Name, "vpcpeering", side+"-"+action, "", ""))
+		rule.Direction = String(model.Rule_DIRECTION_IN)
+		rule.SourceGroups = peerCIDRs
+		rule.DestinationGroups = []string{"ANY"}
+		rule.Services = []string{"ANY"}
+		rule.Scope = []string{"ANY"}
+		rule.Tags = service.buildTags(obj)
+	}
+	return rules
+}
+
+func buildServiceEntry(port v1alpha1.VPCPeeringPort) *data.StructValue {
+	protocol := port.Protocol
+	if protocol == "" {
+		protocol = "TCP"
+	}
+	destinationPorts := data.NewListValue()
+	destinationPorts.Add(data.NewStringValue(fmt.Sprint(port.Port)))
+	return data.NewStructValue(
+		"",
+		map[string]data.DataValue{
+			"source_ports":      data.NewListValue(),
+			"destination_ports": destinationPorts,
+			"l4_protocol":       data.NewStringValue(string(protocol)),
+			"resource_type":     data.NewStringValue("L4PortSetServiceEntry"),
+		},
+	)
+}
+
+func (service *VPCPeeringService) buildTags(obj *v1alpha1.VPCPeering) []model.Tag {
+	return util.BuildBasicTags(service.NSXConfig.Cluster, obj, "")
+}
@@ -0,0 +1,68 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpcpeering
+
+import (
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// compareStaticRoute returns true if the network and the next hops of the static routes are equal.
+func compareStaticRoute(existing *model.StaticRoutes, expected *model.StaticRoutes) bool {
+	if existing.Network == nil || *existing.Network != *expected.Network {
+		return false
+	}
+	existingHops := sets.New[string]()
+	for _, hop := range existing.NextHops {
+		if hop.IpAddress != nil {
+			existingHops.Insert(*hop.IpAddress)
+		}
+	}
+	expectedHops := sets.New[string]()
+	for _, hop := range expected.NextHops {
+		expectedHops.Insert(*hop.IpAddress)
+	}
+	return existingHops.Equal(expectedHops)
+}
+
+// compareRule returns true if the action, the source CIDRs and the ports of the rules are equal.
+func compareRule(existing *model.Rule, expected *model.Rule) bool {
+	if existing.Action == nil || *existing.Action != *expected.Action {
+		return false
+	}
+	if !sets.New(existing.SourceGroups...).Equal(sets.New(expected.SourceGroups...)) {
+		return false
+	}
+	existingPorts, ok := serviceEntryPorts(existing.ServiceEntries)
+	if !ok {
+		return false
+	}
+	expectedPorts, _ := serviceEntryPorts(expected.ServiceEntries)
+	return existingPorts.Equal(expectedPorts)
+}
+
+// serviceEntryPorts returns the "<protocol>/<ports>" of the L4 service entries, ok is false if any
+// of the service entries can't be parsed.
+func serviceEntryPorts(entries []*data.StructValue) (sets.Set[string], bool) {
+	ports := sets.New[string]()
+	for _, entry := range entries {
+		protocol, err := entry.String("l4_protocol")
+		if err != nil {
+			return nil, false
+		}
+		destinationPorts, err := entry.List("destination_ports")
+		if err != nil {
+			return nil, false
+		}
+		for _, port := range destinationPorts.List() {
+			value, ok := port.(*data.StringValue)
+			if !ok {
+				return nil, false
+			}
+			ports.Insert(protocol + "/" + value.Value())
+		}
+	}
+	return ports, true
+}
@@ -0,0 +1,127 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpcpeering
+
+import (
+	"errors"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+)
+
+// keyFunc is used to get the key of a resource, which is the path of the resource since the
+// routes and rules of a VPCPeering are created in two VPCs.
+func keyFunc(obj interface{}) (string, error) {
+	switch v := obj.(type) {
+	case *model.StaticRoutes:
+		return *v.Path, nil
+	case *model.Rule:
+		return *v.Path, nil
+	default:
+		return "", errors.New("keyFunc doesn't support unknown type")
+	}
+}
+
+func filterTag(tags []model.Tag, tagScope string) []string {
+	var res []string
+	for _, tag := range tags {
+		if *tag.Scope == tagScope {
+			res = append(res, *tag.Tag)
+		}
+	}
+	return res
+}
+
+// indexFunc is used to filter out NSX resources which are tagged with VPCPeering CR UID.
+func indexFunc(obj interface{}) ([]string, error) {
+	switch o := obj.(type) {
+	case *model.StaticRoutes:
+		return filterTag(o.Tags, common.TagScopeVPCPeeringCRUID), nil
+	case *model.Rule:
+		return filterTag(o.Tags, common.TagScopeVPCPeeringCRUID), nil
+	default:
+		return nil, errors.New("indexFunc doesn't support unknown type")
+	}
+}
+
+// StaticRouteStore is a store for the static routes injected by VPCPeering.
+type StaticRouteStore struct {
+	common.ResourceStore
+}
+
+func (routeStore *StaticRouteStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	route := i.(*model.StaticRoutes)
+	if route.MarkedForDelete != nil && *route.MarkedForDelete {
+		if err := routeStore.Delete(route); err != nil {
+			return err
+		}
+		log.V(1).Info("static route deleted from store", "route", route)
+	} else {
+		if err := routeStore.Add(route); err != nil {
+			return err
+		}
+		log.V(1).Info("static route added to store", "route", route)
+	}
+	return nil
+}
+
+func (routeStore *StaticRouteStore) GetByIndex(key string, value string) []*model.StaticRoutes {
+	routes := make([]*model.StaticRoutes, 0)
+	for _, obj := range routeStore.ResourceStore.GetByIndex(key, value) {
+		routes = append(routes, obj.(*model.StaticRoutes))
+	}
+	return routes
+}
+
+func (routeStore *StaticRouteStore) GetByKey(key string) *model.StaticRoutes {
+	obj := routeStore.ResourceStore.GetByKey(key)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*model.StaticRoutes)
+}
+
+// RuleStore is a store for the firewall rules scoping the traffic of VPCPeering.
+type RuleStore struct {
+	common.ResourceStore
+}
+
+func (ruleStore *RuleStore) Apply(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	rule := i.(*model.Rule)
+	if rule.MarkedForDelete != nil && *rule.MarkedForDelete {
+		if err := ruleStore.Delete(rule); err != nil {
+			return err
+		}
+		log.V(1).Info("rule deleted from store", "rule", rule)
+	} else {
+		if err := ruleStore.Add(rule); err != nil {
+			return err
+		}
+		log.V(1).Info("rule added to store", "rule", rule)
+	}
+	return nil
+}
+
+func (ruleStore *RuleStore) GetByIndex(key string, value string) []*model.Rule {
+	rules := make([]*model.Rule, 0)
+	for _, obj := range ruleStore.ResourceStore.GetByIndex(key, value) {
+		rules = append(rules, obj.(*model.Rule))
+	}
+	return rules
+}
+
+func (ruleStore *RuleStore) GetByKey(key string) *model.Rule {
+	obj := ruleStore.ResourceStore.GetByKey(key)
+	if obj == nil {
+		return nil
+	}
+	return obj.(*model.Rule)
+}
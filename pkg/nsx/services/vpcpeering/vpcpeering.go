@@ -0,0 +1,317 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpcpeering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/logger"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/vpc"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+var (
+	log                     = logger.Log
+	String                  = common.String
+	MarkedForDelete         = true
+	resourceTypeStaticRoute = "StaticRoutes"
+)
+
+// VPCPeeringService connects two VPCs by injecting the static routes to the private CIDRs of each
+// VPC into the other one, and optionally scopes the traffic between them with firewall rules.
+type VPCPeeringService struct {
+	common.Service
+	StaticRouteStore *StaticRouteStore
+	RuleStore        *RuleStore
+}
+
+// VPCPeeringEnd is a VPC connected by the VPCPeering.
+type VPCPeeringEnd struct {
+	VPCInfo common.VPCResourceInfo
+	// CIDRs are the private CIDRs of the VPC.
+	CIDRs []string
+}
+
+// InitializeVPCPeering sync NSX resources
+func InitializeVPCPeering(service common.Service) (*VPCPeeringService, error) {
+	wg := sync.WaitGroup{}
+	wgDone := make(chan bool)
+	fatalErrors := make(chan error)
+
+	wg.Add(2)
+	peeringService := &VPCPeeringService{
+		Service: service,
+		StaticRouteStore: &StaticRouteStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeVPCPeeringCRUID: indexFunc}),
+			BindingType: model.StaticRoutesBindingType(),
+		}},
+		RuleStore: &RuleStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeVPCPeeringCRUID: indexFunc}),
+			BindingType: model.RuleBindingType(),
+		}},
+	}
+	tags := []model.Tag{
+		{Scope: String(common.TagScopeVPCPeeringCRUID)},
+	}
+	go peeringService.InitializeResourceStore(&wg, fatalErrors, resourceTypeStaticRoute, tags, peeringService.StaticRouteStore)
+	go peeringService.InitializeResourceStore(&wg, fatalErrors, common.ResourceTypeRule, tags, peeringService.RuleStore)
+
+	go func() {
+		wg.Wait()
+		close(wgDone)
+	}()
+	select {
+	case <-wgDone:
+		break
+	case err := <-fatalErrors:
+		close(fatalErrors)
+		return peeringService, err
+	}
+	return peeringService, nil
+}
+
+// CreateOrUpdateVPCPeering injects the routes to the private CIDRs of the peer VPC into the local VPC
+// and vice versa, creates or removes the firewall rules according to the spec, and returns the
+// injected routes with their realization state.
+func (service *VPCPeeringService) CreateOrUpdateVPCPeering(obj *v1alpha1.VPCPeering, local VPCPeeringEnd, peer VPCPeeringEnd) ([]v1alpha1.VPCPeeringRoute, error) {
+	if err := validateNextHops(obj); err != nil {
+		return nil, err
+	}
+	if local.VPCInfo.VPCID == peer.VPCInfo.VPCID {
+		return nil, fmt.Errorf("VPC %s can't be peered with itself", local.VPCInfo.VPCID)
+	}
+	for _, end := range []VPCPeeringEnd{local, peer} {
+		if len(end.CIDRs) == 0 {
+			return nil, fmt.Errorf("no private CIDR found for VPC %s", end.VPCInfo.VPCID)
+		}
+	}
+
+	var expectedRoutes []*model.StaticRoutes
+	for _, cidr := range peer.CIDRs {
+		expectedRoutes = append(expectedRoutes, service.buildStaticRoute(obj, local.VPCInfo, SideLocal, cidr))
+	}
+	for _, cidr := range local.CIDRs {
+		expectedRoutes = append(expectedRoutes, service.buildStaticRoute(obj, peer.VPCInfo, SidePeer, cidr))
+	}
+	var expectedRules []*model.Rule
+	if obj.Spec.Firewall != nil {
+		if !nsxutil.IsLicensed(nsxutil.FeatureDFW) {
+			return nil, errors.New("firewall of VPCPeering cannot be applied due to no DFW license")
+		}
+		expectedRules = append(expectedRules, service.buildRules(obj, local.VPCInfo, SideLocal, peer.CIDRs)...)
+		expectedRules = append(expectedRules, service.buildRules(obj, peer.VPCInfo, SidePeer, local.CIDRs)...)
+	}
+
+	if err := service.applyStaticRoutes(obj.UID, expectedRoutes); err != nil {
+		return nil, err
+	}
+	if err := service.applyRules(obj.UID, expectedRules); err != nil {
+		return nil, err
+	}
+
+	routes := make([]v1alpha1.VPCPeeringRoute, 0, len(expectedRoutes))
+	for _, route := range expectedRoutes {
+		vpcInfo, _ := common.ParseVPCResourcePath(*route.Path)
+		routes = append(routes, v1alpha1.VPCPeeringRoute{
+			VPCPath:          BuildVPCPath(vpcInfo),
+			Network:          *route.Network,
+			Path:             *route.Path,
+			RealizationState: service.getRealizationState(*route.Path),
+		})
+	}
+	return routes, nil
+}
+
+func (service *VPCPeeringService) applyStaticRoutes(uid types.UID, expectedRoutes []*model.StaticRoutes) error {
+	existingRoutes := map[string]*model.StaticRoutes{}
+	for _, route := range service.StaticRouteStore.GetByIndex(common.TagScopeVPCPeeringCRUID, string(uid)) {
+		existingRoutes[*route.Path] = route
+	}
+	for _, expected := range expectedRoutes {
+		existing, ok := existingRoutes[*expected.Path]
+		delete(existingRoutes, *expected.Path)
+		if ok && compareStaticRoute(existing, expected) {
+			continue
+		}
+		vpcInfo, err := common.ParseVPCResourcePath(*expected.Path)
+		if err != nil {
+			return err
+		}
+		client := service.NSXClient.StaticRouteClient
+		if err := client.Patch(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *expected.Id, *expected); err != nil {
+			log.Error(err, "failed to patch NSX static route", "path", *expected.Path)
+			return err
+		}
+		route, err := client.Get(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *expected.Id)
+		if err != nil {
+			return err
+		}
+		if err := service.StaticRouteStore.Apply(&route); err != nil {
+			return err
+		}
+		log.Info("injected static route", "path", *expected.Path, "network", *expected.Network)
+	}
+	// Remove the routes to the CIDRs which are no longer used by the VPCs.
+	for _, stale := range existingRoutes {
+		if err := service.deleteStaticRoute(stale); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *VPCPeeringService) applyRules(uid types.UID, expectedRules []*model.Rule) error {
+	existingRules := map[string]*model.Rule{}
+	for _, rule := range service.RuleStore.GetByIndex(common.TagScopeVPCPeeringCRUID, string(uid)) {
+		existingRules[*rule.Path] = rule
+	}
+	for _, expected := range expectedRules {
+		existing, ok := existingRules[*expected.Path]
+		delete(existingRules, *expected.Path)
+		if ok && compareRule(existing, expected) {
+			continue
+		}
+		vpcInfo, err := common.ParseVPCResourcePath(*expected.Path)
+		if err != nil {
+			return err
+		}
+		client := service.NSXClient.VPCRuleClient
+		if err := client.Patch(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, vpc.VpcDefaultSecurityPolicyId, *expected.Id, *expected); err != nil {
+			log.Error(err, "failed to patch NSX rule", "path", *expected.Path)
+			return err
+		}
+		rule, err := client.Get(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, vpc.VpcDefaultSecurityPolicyId, *expected.Id)
+		if err != nil {
+			return err
+		}
+		if err := service.RuleStore.Apply(&rule); err != nil {
+			return err
+		}
+		log.Info("applied firewall rule", "path", *expected.Path)
+	}
+	// Remove the rules if the firewall is removed from the spec.
+	for _, stale := range existingRules {
+		if err := service.deleteRule(stale); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRealizationState returns the realization state of the NSX resource, or UNAVAILABLE if it
+// can't be retrieved.
+func (service *VPCPeeringService) getRealizationState(path string) string {
+	vpcInfo, err := common.ParseVPCResourcePath(path)
+	if err != nil {
+		return model.GenericPolicyRealizedResource_STATE_UNAVAILABLE
+	}
+	results, err := service.NSXClient.RealizedEntitiesClient.List(vpcInfo.OrgID, vpcInfo.ProjectID, path, nil)
+	if err != nil {
+		log.Error(err, "failed to get realization state", "path", path)
+		return model.GenericPolicyRealizedResource_STATE_UNAVAILABLE
+	}
+	for _, result := range results.Results {
+		if result.State != nil {
+			return *result.State
+		}
+	}
+	return model.GenericPolicyRealizedResource_STATE_UNREALIZED
+}
+
+func (service *VPCPeeringService) deleteStaticRoute(route *model.StaticRoutes) error {
+	vpcInfo, err := common.ParseVPCResourcePath(*route.Path)
+	if err != nil {
+		return err
+	}
+	if err := service.NSXClient.StaticRouteClient.Delete(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, *route.Id); err != nil {
+		log.Error(err, "failed to delete NSX static route", "path", *route.Path)
+		return err
+	}
+	routeCopy := *route
+	routeCopy.MarkedForDelete = &MarkedForDelete
+	if err := service.StaticRouteStore.Apply(&routeCopy); err != nil {
+		return err
+	}
+	log.Info("deleted static route", "path", *route.Path)
+	return nil
+}
+
+func (service *VPCPeeringService) deleteRule(rule *model.Rule) error {
+	vpcInfo, err := common.ParseVPCResourcePath(*rule.Path)
+	if err != nil {
+		return err
+	}
+	if err := service.NSXClient.VPCRuleClient.Delete(vpcInfo.OrgID, vpcInfo.ProjectID, vpcInfo.VPCID, vpc.VpcDefaultSecurityPolicyId, *rule.Id); err != nil {
+		log.Error(err, "failed to delete NSX rule", "path", *rule.Path)
+		return err
+	}
+	ruleCopy := *rule
+	ruleCopy.MarkedForDelete = &MarkedForDelete
+	if err := service.RuleStore.Apply(&ruleCopy); err != nil {
+		return err
+	}
+	log.Info("deleted firewall rule", "path", *rule.Path)
+	return nil
+}
+
+// DeleteVPCPeering removes the routes and the firewall rules of the VPCPeering CR from both VPCs.
+func (service *VPCPeeringService) DeleteVPCPeering(uid types.UID) error {
+	for _, route := range service.StaticRouteStore.GetByIndex(common.TagScopeVPCPeeringCRUID, string(uid)) {
+		if err := service.deleteStaticRoute(route); err != nil {
+			return err
+		}
+	}
+	for _, rule := range service.RuleStore.GetByIndex(common.TagScopeVPCPeeringCRUID, string(uid)) {
+		if err := service.deleteRule(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListStaticRouteByVPCPath returns the NSX static routes injected by VPCPeering into the VPC.
+func (service *VPCPeeringService) ListStaticRouteByVPCPath(vpcPath string) []*model.StaticRoutes {
+	var routes []*model.StaticRoutes
+	for _, obj := range service.StaticRouteStore.List() {
+		route := obj.(*model.StaticRoutes)
+		if route.Path != nil && strings.HasPrefix(*route.Path, vpcPath+"/") {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// ListVPCPeeringID returns the UIDs of the VPCPeering CRs which have NSX resources.
+func (service *VPCPeeringService) ListVPCPeeringID() sets.Set[string] {
+	routeSet := service.StaticRouteStore.ListIndexFuncValues(common.TagScopeVPCPeeringCRUID)
+	ruleSet := service.RuleStore.ListIndexFuncValues(common.TagScopeVPCPeeringCRUID)
+	return routeSet.Union(ruleSet)
+}
+
+func (service *VPCPeeringService) Cleanup(ctx context.Context) error {
+	uids := service.ListVPCPeeringID()
+	log.Info("cleaning up vpcpeering", "count", len(uids))
+	for uid := range uids {
+		select {
+		case <-ctx.Done():
+			return errors.Join(nsxutil.TimeoutFailed, ctx.Err())
+		default:
+			if err := service.DeleteVPCPeering(types.UID(uid)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+/* Copyright © 2024 VMware, Inc. All Rights Reserved.
+   SPDX-License-Identifier: Apache-2.0 */
+
+package vpcpeering
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/infra/realized_state"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs"
+	vpc_sp "github.com/vmware/vsphere-automation-sdk-go/services/nsxt/orgs/projects/vpcs/security_policies"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/vmware-tanzu/nsx-operator/pkg/apis/v1alpha1"
+	"github.com/vmware-tanzu/nsx-operator/pkg/config"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx"
+	"github.com/vmware-tanzu/nsx-operator/pkg/nsx/services/common"
+	nsxutil "github.com/vmware-tanzu/nsx-operator/pkg/nsx/util"
+)
+
+type fakeStaticRoutesClient struct {
+	vpcs.StaticRoutesClient
+	routes  map[string]model.StaticRoutes
+	patched int
+}
+
+func (f *fakeStaticRoutesClient) Patch(orgId string, projectId string, vpcId string, staticRoutesId string, route model.StaticRoutes) error {
+	f.patched++
+	f.routes["/orgs/"+orgId+"/projects/"+projectId+"/vpcs/"+vpcId+"/static-routes/"+staticRoutesId] = route
+	return nil
+}
+
+func (f *fakeStaticRoutesClient) Get(orgId string, projectId string, vpcId string, staticRoutesId string) (model.StaticRoutes, error) {
+	return f.routes["/orgs/"+orgId+"/projects/"+projectId+"/vpcs/"+vpcId+"/static-routes/"+staticRoutesId], nil
+}
+
+func (f *fakeStaticRoutesClient) Delete(orgId string, projectId string, vpcId string, staticRoutesId string) error {
+	delete(f.routes, "/orgs/"+orgId+"/projects/"+projectId+"/vpcs/"+vpcId+"/static-routes/"+staticRoutesId)
+	return nil
+}
+
+type fakeRulesClient struct {
+	vpc_sp.RulesClient
+	rules map[string]model.Rule
+}
+
+func (f *fakeRulesClient) rulePath(orgId string, projectId string, vpcId string, policyId string, ruleId string) string {
+	return "/orgs/" + orgId + "/projects/" + projectId + "/vpcs/" + vpcId + "/security-policies/" + policyId + "/rules/" + ruleId
+}
+
+func (f *fakeRulesClient) Patch(orgId string, projectId string, vpcId string, policyId string, ruleId string, rule model.Rule) error {
+	f.rules[f.rulePath(orgId, projectId, vpcId, policyId, ruleId)] = rule
+	return nil
+}
+
+func (f *fakeRulesClient) Get(orgId string, projectId string, vpcId string, policyId string, ruleId string) (model.Rule, error) {
+	return f.rules[f.rulePath(orgId, projectId, vpcId, policyId, ruleId)], nil
+}
+
+func (f *fakeRulesClient) Delete(orgId string, projectId string, vpcId string, policyId string, ruleId string) error {
+	delete(f.rules, f.rulePath(orgId, projectId, vpcId, policyId, ruleId))
+	return nil
+}
+
+type fakeRealizedEntitiesClient struct {
+	realized_state.RealizedEntitiesClient
+	state string
+}
+
+func (f *fakeRealizedEntitiesClient) List(_ string, _ string, _ string, _ *string) (model.GenericPolicyRealizedResourceListResult, error) {
+	return model.GenericPolicyRealizedResourceListResult{
+		Results: []model.GenericPolicyRealizedResource{{State: String(f.state)}},
+	}, nil
+}
+
+func createService() (*VPCPeeringService, *fakeStaticRoutesClient, *fakeRulesClient) {
+	routesClient := &fakeStaticRoutesClient{routes: map[string]model.StaticRoutes{}}
+	rulesClient := &fakeRulesClient{rules: map[string]model.Rule{}}
+	service := &VPCPeeringService{
+		Service: common.Service{
+			NSXClient: &nsx.Client{
+				StaticRouteClient:      routesClient,
+				VPCRuleClient:          rulesClient,
+				RealizedEntitiesClient: &fakeRealizedEntitiesClient{state: model.GenericPolicyRealizedResource_STATE_REALIZED},
+			},
+			NSXConfig: &config.NSXOperatorConfig{CoeConfig: &config.CoeConfig{Cluster: "k8scl-one:test"}},
+		},
+		StaticRouteStore: &StaticRouteStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeVPCPeeringCRUID: indexFunc}),
+			BindingType: model.StaticRoutesBindingType(),
+		}},
+		RuleStore: &RuleStore{ResourceStore: common.ResourceStore{
+			Indexer:     cache.NewIndexer(keyFunc, cache.Indexers{common.TagScopeVPCPeeringCRUID: indexFunc}),
+			BindingType: model.RuleBindingType(),
+		}},
+	}
+	return service, routesClient, rulesClient
+}
+
+func TestCreateOrUpdateVPCPeering(t *testing.T) {
+	service, routesClient, rulesClient := createService()
+	obj := &v1alpha1.VPCPeering{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "peering1", UID: "peering-uid-1"},
+		Spec: v1alpha1.VPCPeeringSpec{
+			PeerVPC:  v1alpha1.PeerVPCReference{Namespace: "ns2"},
+			NextHops: []v1alpha1.NextHop{{IPAddress: "172.10.0.1"}},
+		},
+	}
+	local := VPCPeeringEnd{
+		VPCInfo: common.VPCResourceInfo{OrgID: "default", ProjectID: "p1", VPCID: "vpc1"},
+		CIDRs:   []string{"10.1.0.0/16"},
+	}
+	peer := VPCPeeringEnd{
+		VPCInfo: common.VPCResourceInfo{OrgID: "default", ProjectID: "p1", VPCID: "vpc2"},
+		CIDRs:   []string{"10.2.0.0/16", "10.3.0.0/16"},
+	}
+
+	// The routes to the CIDRs of the other VPC are injected into both VPCs.
+	routes, err := service.CreateOrUpdateVPCPeering(obj, local, peer)
+	assert.NoError(t, err)
+	assert.Len(t, routes, 3)
+	assert.Len(t, routesClient.routes, 3)
+	assert.Equal(t, "/orgs/default/projects/p1/vpcs/vpc1", routes[0].VPCPath)
+	assert.Equal(t, "10.2.0.0/16", routes[0].Network)
+	assert.Equal(t, "/orgs/default/projects/p1/vpcs/vpc2", routes[2].VPCPath)
+	assert.Equal(t, "10.1.0.0/16", routes[2].Network)
+	assert.Equal(t, model.GenericPolicyRealizedResource_STATE_REALIZED, routes[2].RealizationState)
+	assert.Empty(t, rulesClient.rules)
+	assert.Len(t, service.ListStaticRouteByVPCPath("/orgs/default/projects/p1/vpcs/vpc1"), 2)
+	assert.Len(t, service.ListStaticRouteByVPCPath("/orgs/default/projects/p1/vpcs/vpc2"), 1)
+
+	// The unchanged routes are not patched again.
+	_, err = service.CreateOrUpdateVPCPeering(obj, local, peer)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, routesClient.patched)
+
+	// The route to the removed CIDR is deleted.
+	peer.CIDRs = []string{"10.2.0.0/16"}
+	routes, err = service.CreateOrUpdateVPCPeering(obj, local, peer)
+	assert.NoError(t, err)
+	assert.Len(t, routes, 2)
+	assert.Len(t, routesClient.routes, 2)
+	assert.Equal(t, 3, routesClient.patched)
+
+	// The firewall rules are not created without DFW license.
+	obj.Spec.Firewall = &v1alpha1.VPCPeeringFirewall{Ports: []v1alpha1.VPCPeeringPort{{Protocol: "TCP", Port: 443}}}
+	_, err = service.CreateOrUpdateVPCPeering(obj, local, peer)
+	assert.Error(t, err)
+
+	// The allow and drop rules are created in both VPCs.
+	nsxutil.UpdateLicense(nsxutil.FeatureDFW, true)
+	defer nsxutil.UpdateLicense(nsxutil.FeatureDFW, false)
+	_, err = service.CreateOrUpdateVPCPeering(obj, local, peer)
+	assert.NoError(t, err)
+	assert.Len(t, rulesClient.rules, 4)
+	allowRule := rulesClient.rules["/orgs/default/projects/p1/vpcs/vpc1/security-policies/default-layer3-section/rules/vpcpeering_peering-uid-1_allow_local"]
+	assert.Equal(t, model.Rule_ACTION_ALLOW, *allowRule.Action)
+	assert.Equal(t, []string{"10.2.0.0/16"}, allowRule.SourceGroups)
+	ports, ok := serviceEntryPorts(allowRule.ServiceEntries)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"TCP/443"}, ports.UnsortedList())
+
+	// The rules are removed with the firewall.
+	obj.Spec.Firewall = nil
+	_, err = service.CreateOrUpdateVPCPeering(obj, local, peer)
+	assert.NoError(t, err)
+	assert.Empty(t, rulesClient.rules)
+
+	// The routes in the peer VPC use the peer next hops.
+	obj.Spec.PeerNextHops = []v1alpha1.NextHop{{IPAddress: "172.20.0.1"}}
+	routes, err = service.CreateOrUpdateVPCPeering(obj, local, peer)
+	assert.NoError(t, err)
+	assert.Equal(t, "172.10.0.1", *routesClient.routes[routes[0].Path].NextHops[0].IpAddress)
+	assert.Equal(t, "172.20.0.1", *routesClient.routes[routes[1].Path].NextHops[0].IpAddress)
+
+	// Invalid next hop.
+	obj.Spec.NextHops = []v1alpha1.NextHop{{IPAddress: "172.10.0.300"}}
+	_, err = service.CreateOrUpdateVPCPeering(obj, local, peer)
+	assert.Error(t, err)
+}
+
+func TestDeleteVPCPeering(t *testing.T) {
+	nsxutil.UpdateLicense(nsxutil.FeatureDFW, true)
+	defer nsxutil.UpdateLicense(nsxutil.FeatureDFW, false)
+	service, routesClient, rulesClient := createService()
+	obj := &v1alpha1.VPCPeering{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "peering1", UID: "peering-uid-1"},
+		Spec: v1alpha1.VPCPeeringSpec{
+			NextHops: []v1alpha1.NextHop{{IPAddress: "172.10.0.1"}},
+			Firewall: &v1alpha1.VPCPeeringFirewall{Ports: []v1alpha1.VPCPeeringPort{{Port: 53}}},
+		},
+	}
+	local := VPCPeeringEnd{VPCInfo: common.VPCResourceInfo{OrgID: "default", ProjectID: "p1", VPCID: "vpc1"}, CIDRs: []string{"10.1.0.0/16"}}
+	peer := VPCPeeringEnd{VPCInfo: common.VPCResourceInfo{OrgID: "default", ProjectID: "p1", VPCID: "vpc2"}, CIDRs: []string{"10.2.0.0/16"}}
+	_, err := service.CreateOrUpdateVPCPeering(obj, local, peer)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"peering-uid-1"}, service.ListVPCPeeringID().UnsortedList())
+
+	assert.NoError(t, service.Cleanup(context.TODO()))
+	assert.Empty(t, routesClient.routes)
+	assert.Empty(t, rulesClient.rules)
+	assert.Empty(t, service.ListVPCPeeringID())
+
+	// The VPC can't be peered with itself.
+	_, err = service.CreateOrUpdateVPCPeering(obj, local, local)
+	assert.Error(t, err)
+}
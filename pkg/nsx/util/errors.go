@@ -571,6 +571,24 @@ func (err RestrictionError) Error() string {
 	return err.Desc
 }
 
+// AddressUnavailableError means the address requested by the user is allocated to others.
+type AddressUnavailableError struct {
+	Desc string
+}
+
+func (err AddressUnavailableError) Error() string {
+	return err.Desc
+}
+
+// SubnetExpansionError means NSX rejects expanding the existing Subnet in place.
+type SubnetExpansionError struct {
+	Desc string
+}
+
+func (err SubnetExpansionError) Error() string {
+	return err.Desc
+}
+
 type IPBlockAllExhaustedError struct {
 	Desc string
 }
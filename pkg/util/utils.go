@@ -47,6 +47,10 @@ var (
 		common.TagScopeVPCCRName, common.TagScopeVPCCRUID,
 		common.TagScopeIPPoolCRName, common.TagScopeIPPoolCRUID,
 		common.TagScopeSubnetSetCRName, common.TagScopeSubnetSetCRUID,
+		common.TagScopeIPAddressAllocationCRName, common.TagScopeIPAddressAllocationCRUID,
+		common.TagScopeVPCPeeringCRName, common.TagScopeVPCPeeringCRUID,
+		common.TagScopeNATRuleCRName, common.TagScopeNATRuleCRUID,
+		common.TagScopeEgressIPCRName, common.TagScopeEgressIPCRUID,
 	}
 	tagsScopeSet = sets.New[string]()
 )
@@ -480,6 +484,22 @@ func BuildBasicTags(cluster string, obj interface{}, namespaceID types.UID) []mo
 		tags = append(tags, model.Tag{Scope: String(common.TagScopeNamespace), Tag: String(i.ObjectMeta.Namespace)})
 		tags = append(tags, model.Tag{Scope: String(common.TagScopeIPPoolCRName), Tag: String(i.ObjectMeta.Name)})
 		tags = append(tags, model.Tag{Scope: String(common.TagScopeIPPoolCRUID), Tag: String(string(i.UID))})
+	case *v1alpha1.IPAddressAllocation:
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeNamespace), Tag: String(i.ObjectMeta.Namespace)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeIPAddressAllocationCRName), Tag: String(i.ObjectMeta.Name)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeIPAddressAllocationCRUID), Tag: String(string(i.UID))})
+	case *v1alpha1.VPCPeering:
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeNamespace), Tag: String(i.ObjectMeta.Namespace)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeVPCPeeringCRName), Tag: String(i.ObjectMeta.Name)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeVPCPeeringCRUID), Tag: String(string(i.UID))})
+	case *v1alpha1.NATRule:
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeNamespace), Tag: String(i.ObjectMeta.Namespace)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeNATRuleCRName), Tag: String(i.ObjectMeta.Name)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeNATRuleCRUID), Tag: String(string(i.UID))})
+	case *v1alpha1.EgressIP:
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeNamespace), Tag: String(i.ObjectMeta.Namespace)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeEgressIPCRName), Tag: String(i.ObjectMeta.Name)})
+		tags = append(tags, model.Tag{Scope: String(common.TagScopeEgressIPCRUID), Tag: String(string(i.UID))})
 	default:
 		log.Info("unknown obj type", "obj", obj)
 	}